@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
 	"contrib.go.opencensus.io/exporter/stackdriver"
 	"github.com/golang/glog"
@@ -16,10 +20,17 @@ import (
 	"github.com/ottogroup/penelope/pkg/http/server"
 	"github.com/ottogroup/penelope/pkg/processor"
 	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/tasks"
 	"go.opencensus.io/trace"
 )
 
+const (
+	defaultEmbeddedSchedulerWorkers = 4
+	embeddedSchedulerLeaderLockName = "penelope_embedded_scheduler"
+)
+
 var envKeys = []config.EnvKey{
 	config.GCPProjectId,
 	config.PgUserEnv,
@@ -34,6 +45,12 @@ type AppStartArguments struct {
 	TargetPrincipalForProjectProvider impersonate.TargetPrincipalForProjectProvider
 	SecretProvider                    secret.SecretProvider
 	PrincipalProvider                 provider.PrincipalProvider
+	// NotificationChannelProvider is optional, without it only the default notification channels and data owners are notified
+	NotificationChannelProvider provider.NotificationChannelProvider
+	// SinkKMSKeyProvider is optional, without it backups are only encrypted with a customer-managed key given in the request
+	SinkKMSKeyProvider provider.SinkKMSKeyProvider
+	// PolicyRuleProvider is optional, without it no policy rules are enforced on creating and updating backups
+	PolicyRuleProvider provider.PolicyRuleProvider
 }
 
 // Run penelope app and starts rest api
@@ -72,14 +89,20 @@ func Run(args AppStartArguments) {
 	}
 
 	api := rest.NewAPI(rest.NewAPIArgs{
-		ProcessorBuilder:    createBuilder(args),
-		AuthMiddleware:      authenticationMiddleware,
-		TokenSourceProvider: args.TargetPrincipalForProjectProvider,
-		CredentialsProvider: args.SecretProvider,
+		ProcessorBuilder:            createBuilder(args),
+		AuthMiddleware:              authenticationMiddleware,
+		TokenSourceProvider:         args.TargetPrincipalForProjectProvider,
+		CredentialsProvider:         args.SecretProvider,
+		SourceGCPProjectProvider:    args.SourceGCPProjectProvider,
+		NotificationChannelProvider: args.NotificationChannelProvider,
 	})
 
 	api.Register()
 
+	if config.EmbeddedScheduler.GetBoolOrDefault(false) {
+		startEmbeddedScheduler(args)
+	}
+
 	s := server.CreateServer(api)
 
 	err = s.Run()
@@ -90,6 +113,47 @@ func Run(args AppStartArguments) {
 	}
 }
 
+// startEmbeddedScheduler runs the tasks in process instead of cron, on SIGINT or SIGTERM the app exits once the running tasks finished
+func startEmbeddedScheduler(args AppStartArguments) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+
+	schedules, err := tasks.ParseTaskSchedules(config.EmbeddedSchedulerTaskIntervals.GetOrDefault(""), tasks.DefaultTaskSchedules)
+	if err != nil {
+		glog.Errorf("could not parse environment variable %s: %s", config.EmbeddedSchedulerTaskIntervals, err)
+		os.Exit(1)
+	}
+
+	workers := defaultEmbeddedSchedulerWorkers
+	if config.EmbeddedSchedulerWorkers.Exist() {
+		workers, err = strconv.Atoi(config.EmbeddedSchedulerWorkers.MustGet())
+		if err != nil {
+			glog.Errorf("could not parse environment variable %s: %s", config.EmbeddedSchedulerWorkers, err)
+			os.Exit(1)
+		}
+	}
+
+	leaderLock, err := repository.NewLeaderLock(ctx, args.SecretProvider, embeddedSchedulerLeaderLockName)
+	if err != nil {
+		glog.Errorf("could not create leader lock of embedded scheduler: %s", err)
+		os.Exit(1)
+	}
+
+	scheduler := tasks.NewScheduler(schedules, workers, leaderLock, func(task string) {
+		if _, err := tasks.RunTask(task, args.TargetPrincipalForProjectProvider, args.SecretProvider, args.SourceGCPProjectProvider, args.NotificationChannelProvider); err != nil {
+			glog.Errorf("could not run task %s: %s", task, err)
+		}
+	})
+	scheduler.Start(ctx)
+
+	go func() {
+		<-ctx.Done()
+		stop()
+		glog.Infoln("Stopping penelope, waiting for running tasks...")
+		scheduler.Wait()
+		os.Exit(0)
+	}()
+}
+
 func validateEnvironmentVariables() {
 	for _, envKey := range envKeys {
 		if !envKey.Exist() {
@@ -101,19 +165,29 @@ func validateEnvironmentVariables() {
 
 func createBuilder(provider AppStartArguments) *builder.ProcessorBuilder {
 	return builder.NewProcessorBuilder(
-		processor.NewCreatingProcessorFactory(provider.SinkGCPProjectProvider, provider.TargetPrincipalForProjectProvider, provider.SecretProvider, provider.SourceGCPProjectProvider),
+		processor.NewCreatingProcessorFactory(provider.SinkGCPProjectProvider, provider.TargetPrincipalForProjectProvider, provider.SecretProvider, provider.SourceGCPProjectProvider, provider.SinkKMSKeyProvider, provider.PolicyRuleProvider),
 		processor.NewGettingProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SecretProvider, provider.SourceGCPProjectProvider),
 		processor.NewListingProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SecretProvider, provider.SourceGCPProjectProvider),
-		processor.NewUpdatingProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SecretProvider),
+		processor.NewUpdatingProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SecretProvider, provider.SourceGCPProjectProvider, provider.PolicyRuleProvider),
 		processor.NewRestoringProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SecretProvider),
 		processor.NewCalculatingProcessorFactory(provider.SinkGCPProjectProvider, provider.TargetPrincipalForProjectProvider),
-		processor.NewComplianceProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SinkGCPProjectProvider),
+		processor.NewComplianceProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SinkGCPProjectProvider, provider.SinkKMSKeyProvider),
 		processor.NewBucketListingProcessorFactory(provider.SinkGCPProjectProvider, provider.TargetPrincipalForProjectProvider),
 		processor.NewDatasetListingProcessorFactory(provider.SinkGCPProjectProvider, provider.TargetPrincipalForProjectProvider),
 		processor.NewConfigRegionsProcessorFactory(),
 		processor.NewConfigStorageClassesProcessorFactory(),
 		processor.NewSourceProjectGetProcessorFactory(provider.SourceGCPProjectProvider, provider.TargetPrincipalForProjectProvider),
 		processor.NewTrashcanCleanUpProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SecretProvider),
+		processor.NewRestoreExecutingProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SecretProvider),
+		processor.NewRestoreStatusProcessorFactory(provider.SecretProvider),
+		processor.NewJobEventsProcessorFactory(provider.SecretProvider),
+		processor.NewRunProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SecretProvider),
+		processor.NewTaskRunListingProcessorFactory(provider.SecretProvider, tasks.Tasks),
+		processor.NewTaskRunGettingProcessorFactory(provider.SecretProvider),
+		processor.NewRPOViolationsProcessorFactory(provider.SecretProvider),
+		processor.NewSinkComplianceProcessorFactory(provider.SecretProvider),
+		processor.NewRetentionLockProcessorFactory(provider.TargetPrincipalForProjectProvider, provider.SecretProvider),
+		processor.NewAvailabilityClassViolationsProcessorFactory(provider.SecretProvider, provider.SourceGCPProjectProvider),
 	)
 }
 
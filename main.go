@@ -36,6 +36,33 @@ func main() {
 		os.Exit(1)
 	}
 
+	var notificationChannelProvider provider.NotificationChannelProvider
+	if config.DefaultProviderNotificationChannelsPathEnv.Exist() {
+		notificationChannelProvider, err = provider.NewDefaultNotificationChannelProvider(bgContext, gcsClient)
+		if err != nil {
+			glog.Errorf("could not create NotificationChannelProvider: %s", err)
+			os.Exit(1)
+		}
+	}
+
+	var sinkKMSKeyProvider provider.SinkKMSKeyProvider
+	if config.DefaultProviderSinkKMSKeyPathEnv.Exist() {
+		sinkKMSKeyProvider, err = provider.NewDefaultSinkKMSKeyProvider(bgContext, gcsClient)
+		if err != nil {
+			glog.Errorf("could not create SinkKMSKeyProvider: %s", err)
+			os.Exit(1)
+		}
+	}
+
+	var policyRuleProvider provider.PolicyRuleProvider
+	if config.DefaultProviderPolicyRulesPathEnv.Exist() {
+		policyRuleProvider, err = provider.NewDefaultPolicyRuleProvider(bgContext, gcsClient)
+		if err != nil {
+			glog.Errorf("could not create PolicyRuleProvider: %s", err)
+			os.Exit(1)
+		}
+	}
+
 	secretProvider := secret.NewEnvSecretProvider()
 
 	appStartArguments := app.AppStartArguments{
@@ -43,6 +70,9 @@ func main() {
 		SinkGCPProjectProvider:            sinkGCPProjectProvider,
 		TargetPrincipalForProjectProvider: targetPrincipalForProjectProvider,
 		SecretProvider:                    secretProvider,
+		NotificationChannelProvider:       notificationChannelProvider,
+		SinkKMSKeyProvider:                sinkKMSKeyProvider,
+		PolicyRuleProvider:                policyRuleProvider,
 	}
 
 	app.Run(appStartArguments)
@@ -23,6 +23,16 @@ type ProcessorBuilder struct {
 	configRegionsProcessorFactory        processor.ConfigRegionsProcessorFactory
 	configStorageClassesProcessorFactory processor.ConfigStorageClassesProcessorFactory
 	trashcanCleanUpProcessorFactory      processor.TrashcanCleanUpProcessorFactory
+	restoreExecutingProcessorFactory     processor.RestoreExecutingProcessorFactory
+	restoreStatusProcessorFactory        processor.RestoreStatusProcessorFactory
+	jobEventsProcessorFactory            processor.JobEventsProcessorFactory
+	runProcessorFactory                  processor.RunProcessorFactory
+	taskRunListingProcessorFactory       processor.TaskRunListingProcessorFactory
+	taskRunGettingProcessorFactory       processor.TaskRunGettingProcessorFactory
+	rpoViolationsProcessorFactory        processor.RPOViolationsProcessorFactory
+	sinkComplianceProcessorFactory       processor.SinkComplianceProcessorFactory
+	retentionLockProcessorFactory        processor.RetentionLockProcessorFactory
+	availabilityClassViolationsFactory   processor.AvailabilityClassViolationsProcessorFactory
 }
 
 // NewProcessorBuilder created a new ProcessorBuilder
@@ -39,7 +49,17 @@ func NewProcessorBuilder(
 	configRegionsProcessorFactory processor.ConfigRegionsProcessorFactory,
 	configStorageClassesProcessorFactory processor.ConfigStorageClassesProcessorFactory,
 	sourceProjectGetProcessorFactory processor.SourceProjectGetProcessorFactory,
-	trashcanCleanUpProcessorFactory processor.TrashcanCleanUpProcessorFactory) *ProcessorBuilder {
+	trashcanCleanUpProcessorFactory processor.TrashcanCleanUpProcessorFactory,
+	restoreExecutingProcessorFactory processor.RestoreExecutingProcessorFactory,
+	restoreStatusProcessorFactory processor.RestoreStatusProcessorFactory,
+	jobEventsProcessorFactory processor.JobEventsProcessorFactory,
+	runProcessorFactory processor.RunProcessorFactory,
+	taskRunListingProcessorFactory processor.TaskRunListingProcessorFactory,
+	taskRunGettingProcessorFactory processor.TaskRunGettingProcessorFactory,
+	rpoViolationsProcessorFactory processor.RPOViolationsProcessorFactory,
+	sinkComplianceProcessorFactory processor.SinkComplianceProcessorFactory,
+	retentionLockProcessorFactory processor.RetentionLockProcessorFactory,
+	availabilityClassViolationsFactory processor.AvailabilityClassViolationsProcessorFactory) *ProcessorBuilder {
 	return &ProcessorBuilder{
 		creatingProcessorFactory:             creatingProcessorFactory,
 		gettingProcessorFactory:              gettingProcessorFactory,
@@ -54,6 +74,16 @@ func NewProcessorBuilder(
 		configStorageClassesProcessorFactory: configStorageClassesProcessorFactory,
 		sourceProjectGetProcessorFactory:     sourceProjectGetProcessorFactory,
 		trashcanCleanUpProcessorFactory:      trashcanCleanUpProcessorFactory,
+		restoreExecutingProcessorFactory:     restoreExecutingProcessorFactory,
+		restoreStatusProcessorFactory:        restoreStatusProcessorFactory,
+		jobEventsProcessorFactory:            jobEventsProcessorFactory,
+		runProcessorFactory:                  runProcessorFactory,
+		taskRunListingProcessorFactory:       taskRunListingProcessorFactory,
+		taskRunGettingProcessorFactory:       taskRunGettingProcessorFactory,
+		rpoViolationsProcessorFactory:        rpoViolationsProcessorFactory,
+		sinkComplianceProcessorFactory:       sinkComplianceProcessorFactory,
+		retentionLockProcessorFactory:        retentionLockProcessorFactory,
+		availabilityClassViolationsFactory:   availabilityClassViolationsFactory,
 	}
 }
 
@@ -147,3 +177,73 @@ func (p *ProcessorBuilder) ProcessorForTrashcanCleanUp(ctx context.Context) (pro
 	}
 	return p.trashcanCleanUpProcessorFactory.CreateProcessor(ctx)
 }
+
+func (p *ProcessorBuilder) ProcessorForRestoreExecuting(ctx context.Context) (processor.Operation[requestobjects.RestoreExecutionRequest, requestobjects.RestoreJobsResponse], error) {
+	if p.restoreExecutingProcessorFactory == nil {
+		return nil, errors.New("factory not found")
+	}
+	return p.restoreExecutingProcessorFactory.CreateProcessor(ctx)
+}
+
+func (p *ProcessorBuilder) ProcessorForRestoreStatus(ctx context.Context) (processor.Operation[requestobjects.RestoreStatusRequest, requestobjects.RestoreJobsResponse], error) {
+	if p.restoreStatusProcessorFactory == nil {
+		return nil, errors.New("factory not found")
+	}
+	return p.restoreStatusProcessorFactory.CreateProcessor(ctx)
+}
+
+func (p *ProcessorBuilder) ProcessorForJobEvents(ctx context.Context) (processor.Operation[requestobjects.JobEventsRequest, requestobjects.JobEventsResponse], error) {
+	if p.jobEventsProcessorFactory == nil {
+		return nil, errors.New("factory not found")
+	}
+	return p.jobEventsProcessorFactory.CreateProcessor(ctx)
+}
+
+func (p *ProcessorBuilder) ProcessorForRun(ctx context.Context) (processor.Operation[requestobjects.RunRequest, requestobjects.RunResponse], error) {
+	if p.runProcessorFactory == nil {
+		return nil, errors.New("factory not found")
+	}
+	return p.runProcessorFactory.CreateProcessor(ctx)
+}
+
+func (p *ProcessorBuilder) ProcessorForTaskRunListing(ctx context.Context) (processor.Operation[requestobjects.TaskRunListingRequest, requestobjects.TaskRunListingResponse], error) {
+	if p.taskRunListingProcessorFactory == nil {
+		return nil, errors.New("factory not found")
+	}
+	return p.taskRunListingProcessorFactory.CreateProcessor(ctx)
+}
+
+func (p *ProcessorBuilder) ProcessorForTaskRunGetting(ctx context.Context) (processor.Operation[requestobjects.TaskRunGettingRequest, requestobjects.TaskRunResponse], error) {
+	if p.taskRunGettingProcessorFactory == nil {
+		return nil, errors.New("factory not found")
+	}
+	return p.taskRunGettingProcessorFactory.CreateProcessor(ctx)
+}
+
+func (p *ProcessorBuilder) ProcessorForRPOViolations(ctx context.Context) (processor.Operation[requestobjects.EmptyRequest, requestobjects.RPOViolationsResponse], error) {
+	if p.rpoViolationsProcessorFactory == nil {
+		return nil, errors.New("factory not found")
+	}
+	return p.rpoViolationsProcessorFactory.CreateProcessor(ctx)
+}
+
+func (p *ProcessorBuilder) ProcessorForSinkCompliance(ctx context.Context) (processor.Operation[requestobjects.ProjectSinkComplianceRequest, requestobjects.ProjectSinkComplianceResponse], error) {
+	if p.sinkComplianceProcessorFactory == nil {
+		return nil, errors.New("factory not found")
+	}
+	return p.sinkComplianceProcessorFactory.CreateProcessor(ctx)
+}
+
+func (p *ProcessorBuilder) ProcessorForRetentionLock(ctx context.Context) (processor.Operation[requestobjects.RetentionLockRequest, requestobjects.RetentionLockResponse], error) {
+	if p.retentionLockProcessorFactory == nil {
+		return nil, errors.New("factory not found")
+	}
+	return p.retentionLockProcessorFactory.CreateProcessor(ctx)
+}
+
+func (p *ProcessorBuilder) ProcessorForAvailabilityClassViolations(ctx context.Context) (processor.Operation[requestobjects.EmptyRequest, requestobjects.AvailabilityClassViolationsResponse], error) {
+	if p.availabilityClassViolationsFactory == nil {
+		return nil, errors.New("factory not found")
+	}
+	return p.availabilityClassViolationsFactory.CreateProcessor(ctx)
+}
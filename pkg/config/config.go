@@ -44,6 +44,29 @@ const (
 	TasksValidationHTTPHeaderValue                    EnvKey = "TASKS_VALIDATION_HTTP_HEADER_VALUE"
 	TasksValidationAllowedIPAddresses                 EnvKey = "TASKS_VALIDATION_ALLOWED_IP_ADDRESSES"
 	UniformBucketLevelAccess                          EnvKey = "UNIFORM_BUCKET_LEVEL_ACCESS"
+	RestoreDrillProject                               EnvKey = "RESTORE_DRILL_PROJECT"
+	RestoreDrillDataset                               EnvKey = "RESTORE_DRILL_DATASET"
+	RestoreDrillBucket                                EnvKey = "RESTORE_DRILL_BUCKET"
+	JobRetryMaxAttempts                               EnvKey = "JOB_RETRY_MAX_ATTEMPTS"
+	JobRetryBaseDelayMinutes                          EnvKey = "JOB_RETRY_BASE_DELAY_MINUTES"
+	MaxConcurrentJobs                                 EnvKey = "MAX_CONCURRENT_JOBS"
+	MaxConcurrentJobsPerSourceProject                 EnvKey = "MAX_CONCURRENT_JOBS_PER_SOURCE_PROJECT"
+	MaxConcurrentJobsPerSinkProject                   EnvKey = "MAX_CONCURRENT_JOBS_PER_SINK_PROJECT"
+	EmbeddedScheduler                                 EnvKey = "EMBEDDED_SCHEDULER"
+	EmbeddedSchedulerWorkers                          EnvKey = "EMBEDDED_SCHEDULER_WORKERS"
+	EmbeddedSchedulerTaskIntervals                    EnvKey = "EMBEDDED_SCHEDULER_TASK_INTERVALS"
+	DefaultProviderNotificationChannelsPathEnv        EnvKey = "DEFAULT_NOTIFICATION_CHANNEL_PROVIDER_FILE_PATH"
+	DefaultProviderSinkKMSKeyPathEnv                  EnvKey = "DEFAULT_SINK_KMS_KEY_PROVIDER_FILE_PATH"
+	DefaultProviderPolicyRulesPathEnv                 EnvKey = "DEFAULT_POLICY_RULE_PROVIDER_FILE_PATH"
+	NotificationWebhookURL                            EnvKey = "NOTIFICATION_WEBHOOK_URL"
+	NotificationSlackWebhookURL                       EnvKey = "NOTIFICATION_SLACK_WEBHOOK_URL"
+	NotificationSMTPHost                              EnvKey = "NOTIFICATION_SMTP_HOST"
+	NotificationSMTPPort                              EnvKey = "NOTIFICATION_SMTP_PORT"
+	NotificationSMTPUser                              EnvKey = "NOTIFICATION_SMTP_USER"
+	NotificationSMTPPassword                          EnvKey = "NOTIFICATION_SMTP_PASSWORD"
+	NotificationEmailFrom                             EnvKey = "NOTIFICATION_EMAIL_FROM"
+	NotificationEmailDomain                           EnvKey = "NOTIFICATION_EMAIL_DOMAIN"
+	NotificationThrottleMinutes                       EnvKey = "NOTIFICATION_THROTTLE_MINUTES"
 )
 
 func (e EnvKey) String() string {
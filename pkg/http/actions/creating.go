@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/ottogroup/penelope/pkg/builder"
 	"github.com/ottogroup/penelope/pkg/processor"
@@ -176,16 +177,75 @@ func checkStorageClassIsValid(w http.ResponseWriter, storageClass string, body s
 }
 
 func checkSourceOptionsAreValid(w http.ResponseWriter, request requestobjects.CreateRequest) bool {
-	if repository.BigQuery.EqualTo(request.Type) && request.BigQueryOptions.Dataset == "" {
+	if repository.TableSnapshot.EqualTo(request.Strategy) && !repository.BigQuery.EqualTo(request.Type) {
+		logMsg := fmt.Sprintf("Error %s backup type does not support table snapshot strategy", request.Type)
+		respMsg := "Table snapshot strategy is only supported for bigquery backups"
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
+	} else if repository.TableSnapshot.EqualTo(request.Strategy) && request.SnapshotOptions.LifetimeInDays == 0 {
+		logMsg := "Error table snapshot strategy missing mandatory lifetime_in_days field"
+		respMsg := "Missing mandatory snapshot lifetime in days for table snapshot strategy"
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
+	} else if err := processor.ValidateScheduleOptions(request.Strategy, request.SnapshotOptions.FrequencyInHours, request.ScheduleOptions); err != nil {
+		logMsg := fmt.Sprintf("Error invalid schedule: %s", err)
+		respMsg := fmt.Sprintf("Provided invalid schedule: %s", err)
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
+	} else if repository.BigQuery.EqualTo(request.Type) && request.BigQueryOptions.Dataset == "" {
 		logMsg := "Error bigquery backup type missing mandatory dataset field"
 		respMsg := "Missing mandatory bigquery dataset name"
 		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
 		return false
+	} else if repository.BigQuery.EqualTo(request.Type) && !isSupportedExportFormat(request.BigQueryOptions) {
+		logMsg := fmt.Sprintf("Error bigquery backup type with unsupported export format %s and compression %s", request.BigQueryOptions.ExportFormat, request.BigQueryOptions.ExportCompression)
+		respMsg := fmt.Sprintf("Provided unsupported export format %s with compression %s", request.BigQueryOptions.ExportFormat, request.BigQueryOptions.ExportCompression)
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
 	} else if repository.CloudStorage.EqualTo(request.Type) && request.GCSOptions.Bucket == "" {
 		logMsg := "Error cloudstorage backup type missing mandatory bucket field"
 		respMsg := "Missing mandatory cloudstorage bucket name"
 		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
 		return false
+	} else if repository.Firestore.EqualTo(request.Type) && repository.Mirror.EqualTo(request.Strategy) {
+		logMsg := "Error firestore backup type does not support mirror strategy"
+		respMsg := "Firestore backups only support the snapshot strategy"
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
+	} else if err := processor.ValidateImmutability(request); err != nil {
+		logMsg := fmt.Sprintf("Error invalid immutability: %s", err)
+		respMsg := fmt.Sprintf("Provided invalid immutability: %s", err)
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
+	} else if repository.CloudSQL.EqualTo(request.Type) {
+		return checkCloudSQLOptionsAreValid(w, request)
+	}
+
+	return true
+}
+
+func checkCloudSQLOptionsAreValid(w http.ResponseWriter, request requestobjects.CreateRequest) bool {
+	fileType := request.CloudSQLOptions.FileType
+	if request.CloudSQLOptions.Instance == "" {
+		logMsg := "Error cloudsql backup type missing mandatory instance field"
+		respMsg := "Missing mandatory cloudsql instance name"
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
+	} else if repository.Mirror.EqualTo(request.Strategy) {
+		logMsg := "Error cloudsql backup type does not support mirror strategy"
+		respMsg := "CloudSQL backups only support the snapshot strategy"
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
+	} else if fileType != "" && fileType != repository.CloudSQLExportSQL.String() && fileType != repository.CloudSQLExportCSV.String() {
+		logMsg := fmt.Sprintf("Error cloudsql backup type with invalid file type %s", fileType)
+		respMsg := "Provided invalid cloudsql file type: " + fileType
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
+	} else if fileType == repository.CloudSQLExportCSV.String() && request.CloudSQLOptions.CSVQuery == "" {
+		logMsg := "Error cloudsql backup type with CSV file type missing mandatory csv_query field"
+		respMsg := "Missing mandatory cloudsql csv query for CSV exports"
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
 	}
 
 	return true
@@ -206,6 +266,17 @@ func checkRecoveryPointsAreValid(w http.ResponseWriter, recoveryPointObjective,
 	return true
 }
 
+func checkMaxRetryAttemptsIsValid(w http.ResponseWriter, maxRetryAttempts int) bool {
+	if maxRetryAttempts < 0 {
+		logMsg := fmt.Sprintf("Error invalid max retry attempts %d", maxRetryAttempts)
+		respMsg := "Max retry attempts must not be negative"
+		prepareResponse(w, logMsg, respMsg, http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
 func validateCreateRequest(w http.ResponseWriter, request requestobjects.CreateRequest, body string) bool {
 	if !checkMandatoryFieldsAreSet(w, getUnsetMandatoryFields(request), body) {
 		return false
@@ -238,5 +309,17 @@ func validateCreateRequest(w http.ResponseWriter, request requestobjects.CreateR
 	if !checkRecoveryPointsAreValid(w, request.RecoveryPointObjective, request.RecoveryTimeObjective) {
 		return false
 	}
+
+	if !checkMaxRetryAttemptsIsValid(w, request.MaxRetryAttempts) {
+		return false
+	}
 	return true
 }
+
+func isSupportedExportFormat(options requestobjects.BigQueryOptions) bool {
+	bigQueryOptions := repository.BigQueryOptions{
+		ExportFormat:      repository.ExportFormat(strings.ToUpper(options.ExportFormat)),
+		ExportCompression: repository.ExportCompression(strings.ToUpper(options.ExportCompression)),
+	}
+	return repository.IsSupportedExportFormat(bigQueryOptions.GetExportFormat(), bigQueryOptions.GetExportCompression())
+}
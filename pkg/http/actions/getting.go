@@ -60,6 +60,69 @@ func (dl *GettingBackupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	handleRequestByProcessor(ctx, w, r, request, http.StatusOK, dl.processorBuilder.ProcessorForGetting)
 }
 
+type JobEventsHandler struct {
+	processorBuilder *builder.ProcessorBuilder
+}
+
+func NewJobEventsHandler(processorBuilder *builder.ProcessorBuilder) *JobEventsHandler {
+	return &JobEventsHandler{processorBuilder: processorBuilder}
+}
+
+// ServeHTTP will handle getting the event history of a backup job
+func (je *JobEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "JobEventsHandler.ServeHTTP")
+	defer span.End()
+
+	vars := mux.Vars(r)
+	backupID, ok := vars["backup_id"]
+	if !ok {
+		msg := "Bad request missing parameter: backup_id"
+		prepareResponse(w, msg, msg, http.StatusBadRequest)
+		return
+	}
+	jobID, ok := vars["job_id"]
+	if !ok {
+		msg := "Bad request missing parameter: job_id"
+		prepareResponse(w, msg, msg, http.StatusBadRequest)
+		return
+	}
+
+	request := requestobjects.JobEventsRequest{BackupID: backupID, JobID: jobID}
+	handleRequestByProcessor(ctx, w, r, request, http.StatusOK, je.processorBuilder.ProcessorForJobEvents)
+}
+
+type RPOViolationsHandler struct {
+	processorBuilder *builder.ProcessorBuilder
+}
+
+func NewRPOViolationsHandler(processorBuilder *builder.ProcessorBuilder) *RPOViolationsHandler {
+	return &RPOViolationsHandler{processorBuilder: processorBuilder}
+}
+
+// ServeHTTP will handle listing the backups violating their recovery point objective
+func (rv *RPOViolationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "RPOViolationsHandler.ServeHTTP")
+	defer span.End()
+
+	handleRequestByProcessor(ctx, w, r, requestobjects.EmptyRequest{}, http.StatusOK, rv.processorBuilder.ProcessorForRPOViolations)
+}
+
+type AvailabilityClassViolationsHandler struct {
+	processorBuilder *builder.ProcessorBuilder
+}
+
+func NewAvailabilityClassViolationsHandler(processorBuilder *builder.ProcessorBuilder) *AvailabilityClassViolationsHandler {
+	return &AvailabilityClassViolationsHandler{processorBuilder: processorBuilder}
+}
+
+// ServeHTTP will handle listing the backups not satisfying the availability class of their project
+func (av *AvailabilityClassViolationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "AvailabilityClassViolationsHandler.ServeHTTP")
+	defer span.End()
+
+	handleRequestByProcessor(ctx, w, r, requestobjects.EmptyRequest{}, http.StatusOK, av.processorBuilder.ProcessorForAvailabilityClassViolations)
+}
+
 func BadRequestResponse(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusBadRequest)
 	if _, err := fmt.Fprintf(w, "Unkown api endpoint %s", r.URL.Path); err != nil {
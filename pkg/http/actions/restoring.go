@@ -1,7 +1,11 @@
 package actions
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/ottogroup/penelope/pkg/builder"
@@ -29,9 +33,88 @@ func (rb *RestoringBackupHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	var err error
 	var request requestobjects.RestoreRequest
 	request.BackupID = backupID
 	request.JobIDForTimestamp = r.URL.Query().Get("jobIDForTimestamp")
+	request.TargetProject = r.URL.Query().Get("targetProject")
+	request.TargetDataset = r.URL.Query().Get("targetDataset")
+	request.TargetBucket = r.URL.Query().Get("targetBucket")
+	request.TablePrefix = r.URL.Query().Get("tablePrefix")
+	request.TableSuffix = r.URL.Query().Get("tableSuffix")
+	request.WriteMode = requestobjects.RestoreWriteMode(r.URL.Query().Get("writeMode"))
+	if timestamp := r.URL.Query().Get("timestamp"); timestamp != "" {
+		request.Timestamp, err = time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			msg := fmt.Sprintf("Bad request parameter timestamp %q is not RFC3339", timestamp)
+			prepareResponse(w, msg, msg, http.StatusBadRequest)
+			return
+		}
+	}
 
 	handleRequestByProcessor(ctx, w, r, request, http.StatusOK, rb.processorBuilder.ProcessorForRestoring)
 }
+
+type RestoreExecutingHandler struct {
+	processorBuilder *builder.ProcessorBuilder
+}
+
+func NewRestoreExecutingHandler(processorBuilder *builder.ProcessorBuilder) *RestoreExecutingHandler {
+	return &RestoreExecutingHandler{processorBuilder: processorBuilder}
+}
+
+// ServeHTTP will handle starting a restore
+func (rb *RestoreExecutingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "RestoreExecutingHandler.ServeHTTP")
+	defer span.End()
+
+	backupID, exist := mux.Vars(r)["backup_id"]
+	if !exist {
+		msg := "Bad request missing parameter: backup_id"
+		prepareResponse(w, msg, msg, http.StatusBadRequest)
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+	if !checkRequestBodyIsValid(w, err) {
+		return
+	}
+
+	var request requestobjects.RestoreExecutionRequest
+	if len(bodyBytes) > 0 {
+		err = json.Unmarshal(bodyBytes, &request)
+		if !checkParsingBodyIsValid(w, err, string(bodyBytes)) {
+			return
+		}
+	}
+	request.BackupID = backupID
+
+	handleRequestByProcessor(ctx, w, r, request, http.StatusCreated, rb.processorBuilder.ProcessorForRestoreExecuting)
+}
+
+type RestoreStatusHandler struct {
+	processorBuilder *builder.ProcessorBuilder
+}
+
+func NewRestoreStatusHandler(processorBuilder *builder.ProcessorBuilder) *RestoreStatusHandler {
+	return &RestoreStatusHandler{processorBuilder: processorBuilder}
+}
+
+// ServeHTTP will handle getting restore progress
+func (rb *RestoreStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "RestoreStatusHandler.ServeHTTP")
+	defer span.End()
+
+	backupID, exist := mux.Vars(r)["backup_id"]
+	if !exist {
+		msg := "Bad request missing parameter: backup_id"
+		prepareResponse(w, msg, msg, http.StatusBadRequest)
+		return
+	}
+
+	var request requestobjects.RestoreStatusRequest
+	request.BackupID = backupID
+	request.RestoreID = r.URL.Query().Get("restore_id")
+
+	handleRequestByProcessor(ctx, w, r, request, http.StatusOK, rb.processorBuilder.ProcessorForRestoreStatus)
+}
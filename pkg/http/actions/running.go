@@ -0,0 +1,49 @@
+package actions
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ottogroup/penelope/pkg/builder"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"go.opencensus.io/trace"
+)
+
+type RunBackupHandler struct {
+	processorBuilder *builder.ProcessorBuilder
+}
+
+func NewRunBackupHandler(processorBuilder *builder.ProcessorBuilder) *RunBackupHandler {
+	return &RunBackupHandler{processorBuilder: processorBuilder}
+}
+
+// ServeHTTP will handle running a backup on demand
+func (rb *RunBackupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "RunBackupHandler.ServeHTTP")
+	defer span.End()
+
+	backupID, exist := mux.Vars(r)["backup_id"]
+	if !exist {
+		msg := "Bad request missing parameter: backup_id"
+		prepareResponse(w, msg, msg, http.StatusBadRequest)
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+	if !checkRequestBodyIsValid(w, err) {
+		return
+	}
+
+	var request requestobjects.RunRequest
+	if len(bodyBytes) > 0 {
+		err = json.Unmarshal(bodyBytes, &request)
+		if !checkParsingBodyIsValid(w, err, string(bodyBytes)) {
+			return
+		}
+	}
+	request.BackupID = backupID
+
+	handleRequestByProcessor(ctx, w, r, request, http.StatusCreated, rb.processorBuilder.ProcessorForRun)
+}
@@ -0,0 +1,46 @@
+package actions
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ottogroup/penelope/pkg/builder"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"go.opencensus.io/trace"
+)
+
+type SinkComplianceHandler struct {
+	processorBuilder *builder.ProcessorBuilder
+}
+
+func NewSinkComplianceHandler(processorBuilder *builder.ProcessorBuilder) *SinkComplianceHandler {
+	return &SinkComplianceHandler{processorBuilder: processorBuilder}
+}
+
+// ServeHTTP will handle listing the compliance checks of sink projects
+func (sc *SinkComplianceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "SinkComplianceHandler.ServeHTTP")
+	defer span.End()
+
+	request := requestobjects.ProjectSinkComplianceRequest{}
+	q := r.URL.Query()
+	request.Project = q.Get("project")
+	if q.Get("size") != "" {
+		i, err := strconv.Atoi(q.Get("size"))
+		if err != nil {
+			BadRequestResponse(w, r)
+			return
+		}
+		request.Page.Size = i
+	}
+	if q.Get("page") != "" {
+		i, err := strconv.Atoi(q.Get("page"))
+		if err != nil {
+			BadRequestResponse(w, r)
+			return
+		}
+		request.Page.Number = i
+	}
+
+	handleRequestByProcessor(ctx, w, r, request, http.StatusOK, sc.processorBuilder.ProcessorForSinkCompliance)
+}
@@ -1,11 +1,15 @@
 package actions
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/golang/glog"
 	"github.com/gorilla/mux"
 	"github.com/ottogroup/penelope/pkg/config"
 	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
 	"github.com/ottogroup/penelope/pkg/secret"
 	"github.com/ottogroup/penelope/pkg/tasks"
 	"go.opencensus.io/trace"
@@ -14,13 +18,20 @@ import (
 	"strings"
 )
 
+const (
+	taskAccepted = "accepted"
+	taskSkipped  = "skipped"
+)
+
 type TaskRunHandler struct {
-	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
-	credentialsProvider secret.SecretProvider
+	tokenSourceProvider         impersonate.TargetPrincipalForProjectProvider
+	credentialsProvider         secret.SecretProvider
+	sourceGCPProjectProvider    provider.SourceGCPProjectProvider
+	notificationChannelProvider provider.NotificationChannelProvider
 }
 
-func NewTaskRunHandler(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) *TaskRunHandler {
-	return &TaskRunHandler{tokenSourceProvider, credentialsProvider}
+func NewTaskRunHandler(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider, notificationChannelProvider provider.NotificationChannelProvider) *TaskRunHandler {
+	return &TaskRunHandler{tokenSourceProvider, credentialsProvider, sourceGCPProjectProvider, notificationChannelProvider}
 }
 
 func (g *TaskRunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -34,14 +45,43 @@ func (g *TaskRunHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if task, exist := mux.Vars(r)["task"]; exist {
-		go tasks.RunTask(task, g.tokenSourceProvider, g.credentialsProvider)
-		w.WriteHeader(http.StatusCreated)
+	task, exist := mux.Vars(r)["task"]
+	if !exist {
+		msg := "Bad request missing parameter: task"
+		prepareResponse(w, msg, msg, http.StatusBadRequest)
+		return
+	}
+
+	accepted, err := tasks.StartTask(task, g.tokenSourceProvider, g.credentialsProvider, g.sourceGCPProjectProvider, g.notificationChannelProvider)
+	if errors.Is(err, tasks.ErrUnknownTask) {
+		msg := fmt.Sprintf("Unknown task: %s", task)
+		prepareResponse(w, msg, msg, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		logMsg := fmt.Sprintf("Error starting task %s. Err: %s", task, err)
+		prepareResponse(w, logMsg, "Could not handle request", http.StatusInternalServerError)
+		return
+	}
+
+	// a skipped run is no error, otherwise cron would retry it while the task is still running
+	response := requestobjects.TaskTriggerResponse{Task: task, Status: taskAccepted}
+	statusCode := http.StatusCreated
+	if !accepted {
+		response.Status = taskSkipped
+		statusCode = http.StatusOK
+	}
+	responseBody, err := json.Marshal(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
-	msg := "Bad request missing parameter: task"
-	prepareResponse(w, msg, msg, http.StatusBadRequest)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(responseBody); err != nil {
+		glog.Warningf("Error writing response: %s", err)
+	}
 }
 
 func validateRequest(r *http.Request) error {
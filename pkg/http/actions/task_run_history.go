@@ -0,0 +1,71 @@
+package actions
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/ottogroup/penelope/pkg/builder"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"go.opencensus.io/trace"
+)
+
+type TaskRunListingHandler struct {
+	processorBuilder *builder.ProcessorBuilder
+}
+
+func NewTaskRunListingHandler(processorBuilder *builder.ProcessorBuilder) *TaskRunListingHandler {
+	return &TaskRunListingHandler{processorBuilder: processorBuilder}
+}
+
+// ServeHTTP will handle listing the runs of background tasks
+func (tl *TaskRunListingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "TaskRunListingHandler.ServeHTTP")
+	defer span.End()
+
+	request := requestobjects.TaskRunListingRequest{}
+	q := r.URL.Query()
+	request.Task = q.Get("task")
+	if q.Get("size") != "" {
+		i, err := strconv.Atoi(q.Get("size"))
+		if err != nil {
+			BadRequestResponse(w, r)
+			return
+		}
+		request.Page.Size = i
+	}
+	if q.Get("page") != "" {
+		i, err := strconv.Atoi(q.Get("page"))
+		if err != nil {
+			BadRequestResponse(w, r)
+			return
+		}
+		request.Page.Number = i
+	}
+
+	handleRequestByProcessor(ctx, w, r, request, http.StatusOK, tl.processorBuilder.ProcessorForTaskRunListing)
+}
+
+type TaskRunGettingHandler struct {
+	processorBuilder *builder.ProcessorBuilder
+}
+
+func NewTaskRunGettingHandler(processorBuilder *builder.ProcessorBuilder) *TaskRunGettingHandler {
+	return &TaskRunGettingHandler{processorBuilder: processorBuilder}
+}
+
+// ServeHTTP will handle getting a run of a background task
+func (tg *TaskRunGettingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.StartSpan(r.Context(), "TaskRunGettingHandler.ServeHTTP")
+	defer span.End()
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok {
+		msg := "Bad request missing parameter: id"
+		prepareResponse(w, msg, msg, http.StatusBadRequest)
+		return
+	}
+
+	request := requestobjects.TaskRunGettingRequest{ID: id}
+	handleRequestByProcessor(ctx, w, r, request, http.StatusOK, tg.processorBuilder.ProcessorForTaskRunGetting)
+}
@@ -17,7 +17,8 @@ func CheckRequestIsAllowed(principal *model.Principal, requestType requestobject
 	switch requestType {
 	case requestobjects.Updating:
 		isAllowed = matchRole(rbacRole, model.Owner)
-	case requestobjects.Creating, requestobjects.Cleanup:
+	case requestobjects.Creating, requestobjects.Cleanup, requestobjects.RestoreExecuting, requestobjects.Running,
+		requestobjects.RetentionLocking:
 		isAllowed = matchRole(rbacRole, model.Owner)
 	case requestobjects.Getting, requestobjects.Listing, requestobjects.Restoring, requestobjects.Calculating,
 		requestobjects.DatasetListing, requestobjects.BucketListing, requestobjects.SourceProjectGet:
@@ -13,6 +13,7 @@ import (
 	"github.com/ottogroup/penelope/pkg/http/actions"
 	"github.com/ottogroup/penelope/pkg/http/auth"
 	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/provider"
 	"github.com/ottogroup/penelope/pkg/secret"
 )
 
@@ -22,10 +23,13 @@ type API struct {
 }
 
 type NewAPIArgs struct {
-	ProcessorBuilder    *builder.ProcessorBuilder
-	AuthMiddleware      *auth.AuthenticationMiddleware
-	TokenSourceProvider impersonate.TargetPrincipalForProjectProvider
-	CredentialsProvider secret.SecretProvider
+	ProcessorBuilder         *builder.ProcessorBuilder
+	AuthMiddleware           *auth.AuthenticationMiddleware
+	TokenSourceProvider      impersonate.TargetPrincipalForProjectProvider
+	CredentialsProvider      secret.SecretProvider
+	SourceGCPProjectProvider provider.SourceGCPProjectProvider
+	// NotificationChannelProvider is optional, without it only the default notification channels and data owners are notified
+	NotificationChannelProvider provider.NotificationChannelProvider
 }
 
 func NewAPI(args NewAPIArgs) *API {
@@ -34,18 +38,19 @@ func NewAPI(args NewAPIArgs) *API {
 }
 
 // NewRestAPI return instance of API
-func NewRestAPI(processorBuilder *builder.ProcessorBuilder, authMiddleware *auth.AuthenticationMiddleware, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) *API {
+func NewRestAPI(processorBuilder *builder.ProcessorBuilder, authMiddleware *auth.AuthenticationMiddleware, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider) *API {
 	return NewAPI(NewAPIArgs{
-		processorBuilder,
-		authMiddleware,
-		tokenSourceProvider,
-		credentialsProvider,
+		ProcessorBuilder:         processorBuilder,
+		AuthMiddleware:           authMiddleware,
+		TokenSourceProvider:      tokenSourceProvider,
+		CredentialsProvider:      credentialsProvider,
+		SourceGCPProjectProvider: sourceGCPProjectProvider,
 	})
 }
 
 func createRouter(args NewAPIArgs) *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
-	for _, endpoint := range createEndpoints(args.ProcessorBuilder, args.TokenSourceProvider, args.CredentialsProvider) {
+	for _, endpoint := range createEndpoints(args.ProcessorBuilder, args.TokenSourceProvider, args.CredentialsProvider, args.SourceGCPProjectProvider, args.NotificationChannelProvider) {
 		if endpoint.handler == nil {
 			msg := fmt.Sprintf("no handler defined for enpoint: %s", endpoint.pathWithoutTrailingSlash())
 			panic(msg)
@@ -65,7 +70,7 @@ func createRouter(args NewAPIArgs) *mux.Router {
 	return router
 }
 
-func createEndpoints(processorBuilder *builder.ProcessorBuilder, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) []*Endpoint {
+func createEndpoints(processorBuilder *builder.ProcessorBuilder, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider, notificationChannelProvider provider.NotificationChannelProvider) []*Endpoint {
 	return []*Endpoint{
 		newAPIEndpoint(
 			backupPath,
@@ -91,12 +96,36 @@ func createEndpoints(processorBuilder *builder.ProcessorBuilder, tokenSourceProv
 			actions.NewAddBackupHandler(processorBuilder).ServeHTTP,
 			[]string{http.MethodPost},
 		),
+		newAPIEndpoint(
+			fmt.Sprintf("%s/violations", backupPath),
+			true,
+			actions.NewRPOViolationsHandler(processorBuilder).ServeHTTP,
+			[]string{http.MethodGet},
+		),
 		newAPIEndpoint(
 			fmt.Sprintf("%s/{backup_id}", backupPath),
 			true,
 			actions.NewGettingBackupHandler(processorBuilder).ServeHTTP,
 			[]string{http.MethodGet},
 		),
+		newAPIEndpoint(
+			fmt.Sprintf("%s/{backup_id}/run", backupPath),
+			true,
+			actions.NewRunBackupHandler(processorBuilder).ServeHTTP,
+			[]string{http.MethodPost},
+		),
+		newAPIEndpoint(
+			fmt.Sprintf("%s/{backup_id}/retention_lock", backupPath),
+			true,
+			actions.NewRetentionLockHandler(processorBuilder).ServeHTTP,
+			[]string{http.MethodPost},
+		),
+		newAPIEndpoint(
+			fmt.Sprintf("%s/{backup_id}/jobs/{job_id}/events", backupPath),
+			true,
+			actions.NewJobEventsHandler(processorBuilder).ServeHTTP,
+			[]string{http.MethodGet},
+		),
 		newAPIEndpoint(
 			fmt.Sprintf("%s/{backup_id}/clean_up", trashcansPath),
 			true,
@@ -109,10 +138,34 @@ func createEndpoints(processorBuilder *builder.ProcessorBuilder, tokenSourceProv
 			actions.NewListingBackupHandler(processorBuilder).ServeHTTP,
 			[]string{http.MethodGet},
 		),
+		newAPIEndpoint(
+			fmt.Sprintf("%s/runs", tasksPath),
+			true,
+			actions.NewTaskRunListingHandler(processorBuilder).ServeHTTP,
+			[]string{http.MethodGet},
+		),
+		newAPIEndpoint(
+			fmt.Sprintf("%s/runs/{id}", tasksPath),
+			true,
+			actions.NewTaskRunGettingHandler(processorBuilder).ServeHTTP,
+			[]string{http.MethodGet},
+		),
 		newAPIEndpoint(
 			fmt.Sprintf("%s/{task}", tasksPath),
 			false,
-			actions.NewTaskRunHandler(tokenSourceProvider, credentialsProvider).ServeHTTP,
+			actions.NewTaskRunHandler(tokenSourceProvider, credentialsProvider, sourceGCPProjectProvider, notificationChannelProvider).ServeHTTP,
+			[]string{http.MethodGet},
+		),
+		newAPIEndpoint(
+			fmt.Sprintf("%s/sinks", compliancePath),
+			true,
+			actions.NewSinkComplianceHandler(processorBuilder).ServeHTTP,
+			[]string{http.MethodGet},
+		),
+		newAPIEndpoint(
+			fmt.Sprintf("%s/availability_classes", compliancePath),
+			true,
+			actions.NewAvailabilityClassViolationsHandler(processorBuilder).ServeHTTP,
 			[]string{http.MethodGet},
 		),
 		newAPIEndpoint(
@@ -133,6 +186,18 @@ func createEndpoints(processorBuilder *builder.ProcessorBuilder, tokenSourceProv
 			actions.NewRestoringBackupHandler(processorBuilder).ServeHTTP,
 			[]string{http.MethodGet},
 		),
+		newAPIEndpoint(
+			fmt.Sprintf("%s/{backup_id}", restorePath),
+			true,
+			actions.NewRestoreExecutingHandler(processorBuilder).ServeHTTP,
+			[]string{http.MethodPost},
+		),
+		newAPIEndpoint(
+			fmt.Sprintf("%s/{backup_id}/jobs", restorePath),
+			true,
+			actions.NewRestoreStatusHandler(processorBuilder).ServeHTTP,
+			[]string{http.MethodGet},
+		),
 		newAPIEndpoint(
 			fmt.Sprintf("%s/{project_id}", datasetsPath),
 			true,
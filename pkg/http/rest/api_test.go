@@ -42,10 +42,20 @@ func (m *mockSourceGCPProjectProvider) GetSourceGCPProject(_ context.Context, _
 
 func createBuilder(backupProvider provider.SinkGCPProjectProvider, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider) *builder.ProcessorBuilder {
 	return builder.NewProcessorBuilder(
-		processor.NewCreatingProcessorFactory(backupProvider, tokenSourceProvider, credentialProvider, sourceGCPProjectProvider),
+		processor.NewCreatingProcessorFactory(backupProvider, tokenSourceProvider, credentialProvider, sourceGCPProjectProvider, nil, nil),
 		processor.NewGettingProcessorFactory(tokenSourceProvider, credentialProvider, sourceGCPProjectProvider),
 		processor.NewListingProcessorFactory(tokenSourceProvider, credentialProvider, sourceGCPProjectProvider),
-		processor.NewUpdatingProcessorFactory(tokenSourceProvider, credentialProvider),
+		processor.NewUpdatingProcessorFactory(tokenSourceProvider, credentialProvider, sourceGCPProjectProvider, nil),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
 		nil,
 		nil,
 		nil,
@@ -78,7 +88,17 @@ func restAPIFactoryWithStubFactory(tokenSourceProvider impersonate.TargetPrincip
 			&StubFactory[requestobjects.EmptyRequest, requestobjects.RegionsListResponse]{DefaultValue: requestobjects.RegionsListResponse{}},
 			&StubFactory[requestobjects.EmptyRequest, requestobjects.StorageClassListResponse]{DefaultValue: requestobjects.StorageClassListResponse{}},
 			&StubFactory[requestobjects.SourceProjectGetRequest, requestobjects.SourceProjectGetResponse]{DefaultValue: requestobjects.SourceProjectGetResponse{}}, nil,
-		), authenticationMiddleware, tokenSourceProvider, credentialProvider)
+			&StubFactory[requestobjects.RestoreExecutionRequest, requestobjects.RestoreJobsResponse]{DefaultValue: requestobjects.RestoreJobsResponse{}},
+			&StubFactory[requestobjects.RestoreStatusRequest, requestobjects.RestoreJobsResponse]{DefaultValue: requestobjects.RestoreJobsResponse{}},
+			&StubFactory[requestobjects.JobEventsRequest, requestobjects.JobEventsResponse]{DefaultValue: requestobjects.JobEventsResponse{}},
+			&StubFactory[requestobjects.RunRequest, requestobjects.RunResponse]{DefaultValue: requestobjects.RunResponse{}},
+			&StubFactory[requestobjects.TaskRunListingRequest, requestobjects.TaskRunListingResponse]{DefaultValue: requestobjects.TaskRunListingResponse{}},
+			&StubFactory[requestobjects.TaskRunGettingRequest, requestobjects.TaskRunResponse]{DefaultValue: requestobjects.TaskRunResponse{}},
+			&StubFactory[requestobjects.EmptyRequest, requestobjects.RPOViolationsResponse]{DefaultValue: requestobjects.RPOViolationsResponse{}},
+			&StubFactory[requestobjects.ProjectSinkComplianceRequest, requestobjects.ProjectSinkComplianceResponse]{DefaultValue: requestobjects.ProjectSinkComplianceResponse{}},
+			&StubFactory[requestobjects.RetentionLockRequest, requestobjects.RetentionLockResponse]{DefaultValue: requestobjects.RetentionLockResponse{}},
+			&StubFactory[requestobjects.EmptyRequest, requestobjects.AvailabilityClassViolationsResponse]{DefaultValue: requestobjects.AvailabilityClassViolationsResponse{}},
+		), authenticationMiddleware, tokenSourceProvider, credentialProvider, nil)
 	return httptest.NewServer(authenticationMiddleware.AddAuthentication(app.ServeHTTP))
 }
 
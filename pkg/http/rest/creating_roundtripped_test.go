@@ -25,7 +25,7 @@ func restAPIFactoryWithRealFactory(t *testing.T, principalRoleBindings []model.P
 		t.Error("expected", "instance of AuthenticationMiddleware can be created", "got", fmt.Sprintf("error: %s", err))
 		os.Exit(1)
 	}
-	app := NewRestAPI(createBuilder(backupProvider, tokenSourceProvider, secret.NewEnvSecretProvider(), sourceGCPProjectProvider), authenticationMiddleware, nil, secret.NewEnvSecretProvider())
+	app := NewRestAPI(createBuilder(backupProvider, tokenSourceProvider, secret.NewEnvSecretProvider(), sourceGCPProjectProvider), authenticationMiddleware, nil, secret.NewEnvSecretProvider(), sourceGCPProjectProvider)
 	return httptest.NewServer(authenticationMiddleware.AddAuthentication(app.ServeHTTP))
 }
 
@@ -155,7 +155,7 @@ func deletingBackup() *repository.Backup {
 			StorageClass:  "NEARLINE",
 		},
 		BackupOptions: repository.BackupOptions{
-			BigQueryOptions: repository.BigQueryOptions{"demo_delete_me_backup_target", []string{"gcp_billing_budget_amount_plan"}, []string{}},
+			BigQueryOptions: repository.BigQueryOptions{Dataset: "demo_delete_me_backup_target", Table: []string{"gcp_billing_budget_amount_plan"}, ExcludedTables: []string{}},
 		},
 		EntityAudit: repository.EntityAudit{
 			CreatedTimestamp: time.Now(),
@@ -16,6 +16,7 @@ const tasksPath = "tasks"
 const userPath = "users"
 const configPath = "config"
 const sourceProjectPath = "sourceProject"
+const compliancePath = "compliance"
 
 // Endpoint for a HTTP requests
 type Endpoint struct {
@@ -65,6 +65,8 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	storageService.DB().Model(&repository.RestoreDrill{}).Where("true").Delete()
+	storageService.DB().Model(&repository.RestoreJob{}).Where("true").Delete()
 	storageService.DB().Model(&repository.Job{}).Where("true").Delete()
 	storageService.DB().Model(&repository.SourceMetadata{}).Where("true").Delete()
 	storageService.DB().Model(&repository.SourceMetadataJob{}).Where("true").Delete()
@@ -0,0 +1,212 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"go.opencensus.io/trace"
+)
+
+const (
+	defaultThrottle     = 24 * time.Hour
+	defaultSMTPPort     = 587
+	notifierHTTPTimeout = 10 * time.Second
+)
+
+// Dispatcher routes events to the channels of their project, events with the same key are sent at most once per throttle
+type Dispatcher struct {
+	notifiers                map[provider.NotificationChannelType]Notifier
+	defaultChannels          []provider.NotificationChannel
+	channelProvider          provider.NotificationChannelProvider
+	sourceGCPProjectProvider provider.SourceGCPProjectProvider
+	notificationRepository   repository.NotificationRepository
+	throttle                 time.Duration
+	emailDomain              string
+}
+
+// NewDispatcher create a new Dispatcher configured by the environment, both providers are optional
+func NewDispatcher(ctxIn context.Context, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider, channelProvider provider.NotificationChannelProvider) (*Dispatcher, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewDispatcher")
+	defer span.End()
+
+	throttle := defaultThrottle
+	if config.NotificationThrottleMinutes.Exist() {
+		minutes, err := strconv.Atoi(config.NotificationThrottleMinutes.MustGet())
+		if err != nil || minutes < 0 {
+			return nil, fmt.Errorf("can not parse throttle from environment variable %s", config.NotificationThrottleMinutes)
+		}
+		throttle = time.Duration(minutes) * time.Minute
+	}
+
+	client := &http.Client{Timeout: notifierHTTPTimeout}
+	notifiers := map[provider.NotificationChannelType]Notifier{
+		provider.WebhookChannel: NewWebhookNotifier(client),
+		provider.SlackChannel:   NewSlackNotifier(client),
+	}
+	if config.NotificationSMTPHost.Exist() {
+		emailNotifier, err := newEmailNotifierFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		notifiers[provider.EmailChannel] = emailNotifier
+	}
+
+	var defaultChannels []provider.NotificationChannel
+	if config.NotificationWebhookURL.Exist() {
+		defaultChannels = append(defaultChannels, provider.NotificationChannel{Type: provider.WebhookChannel, Target: config.NotificationWebhookURL.MustGet()})
+	}
+	if config.NotificationSlackWebhookURL.Exist() {
+		defaultChannels = append(defaultChannels, provider.NotificationChannel{Type: provider.SlackChannel, Target: config.NotificationSlackWebhookURL.MustGet()})
+	}
+
+	notificationRepository, err := repository.NewNotificationRepository(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dispatcher{
+		notifiers:                notifiers,
+		defaultChannels:          defaultChannels,
+		channelProvider:          channelProvider,
+		sourceGCPProjectProvider: sourceGCPProjectProvider,
+		notificationRepository:   notificationRepository,
+		throttle:                 throttle,
+		emailDomain:              config.NotificationEmailDomain.GetOrDefault(""),
+	}, nil
+}
+
+func newEmailNotifierFromEnv() (Notifier, error) {
+	host := config.NotificationSMTPHost.MustGet()
+	port := defaultSMTPPort
+	if config.NotificationSMTPPort.Exist() {
+		var err error
+		port, err = strconv.Atoi(config.NotificationSMTPPort.MustGet())
+		if err != nil {
+			return nil, fmt.Errorf("can not parse port from environment variable %s", config.NotificationSMTPPort)
+		}
+	}
+	if !config.NotificationEmailFrom.Exist() {
+		return nil, fmt.Errorf("environment variable %s is required for notification emails", config.NotificationEmailFrom)
+	}
+
+	var auth smtp.Auth
+	if config.NotificationSMTPUser.Exist() {
+		auth = smtp.PlainAuth("", config.NotificationSMTPUser.MustGet(), config.NotificationSMTPPassword.GetOrDefault(""), host)
+	}
+	return NewEmailNotifier(host, port, config.NotificationEmailFrom.MustGet(), auth), nil
+}
+
+// Notify sends the event to every channel of its project, a failed delivery is logged and does not fail the caller
+func (d *Dispatcher) Notify(ctxIn context.Context, event Event) {
+	ctx, span := trace.StartSpan(ctxIn, "(*Dispatcher).Notify")
+	defer span.End()
+
+	channels := d.channelsFor(ctx, event.Project)
+	if len(channels) == 0 {
+		glog.Infof("No notification channel for project %s, dropping notification %s", event.Project, event.Key)
+		return
+	}
+
+	reserved, err := d.notificationRepository.TryReserveNotification(ctx, &repository.Notification{Key: event.Key, Kind: string(event.Kind), Project: event.Project}, d.throttle)
+	if err != nil {
+		glog.Warningf("could not deduplicate notification %s: %s", event.Key, err)
+		return
+	}
+	if !reserved {
+		glog.Infof("Notification %s was already sent within %s, skipping it", event.Key, d.throttle)
+		return
+	}
+
+	delivered := false
+	for _, channel := range channels {
+		notifier, exists := d.notifiers[channel.Type]
+		if !exists {
+			glog.Warningf("no notifier for channel type %q of project %s", channel.Type, event.Project)
+			continue
+		}
+		if err := notifier.Notify(ctx, channel.Target, event); err != nil {
+			glog.Warningf("could not deliver notification %s to %s channel: %s", event.Key, channel.Type, err)
+			continue
+		}
+		delivered = true
+	}
+
+	// a notification nobody received is sent again on the next occurrence
+	if !delivered {
+		if err := d.notificationRepository.ReleaseNotification(ctx, event.Key); err != nil {
+			glog.Warningf("could not release notification %s: %s", event.Key, err)
+		}
+	}
+}
+
+// channelsFor returns the default channels and the channels configured for the project,
+// the data owner of the project is emailed if the project has no channels configured
+func (d *Dispatcher) channelsFor(ctxIn context.Context, project string) []provider.NotificationChannel {
+	ctx, span := trace.StartSpan(ctxIn, "(*Dispatcher).channelsFor")
+	defer span.End()
+
+	channels := slices.Clone(d.defaultChannels)
+	if project == "" {
+		return channels
+	}
+
+	if d.channelProvider != nil {
+		projectChannels, err := d.channelProvider.GetNotificationChannels(ctx, project)
+		if err != nil {
+			glog.Warningf("could not get notification channels of project %s: %s", project, err)
+		}
+		if len(projectChannels) > 0 {
+			return appendChannels(channels, projectChannels...)
+		}
+	}
+
+	if _, exists := d.notifiers[provider.EmailChannel]; exists {
+		if dataOwner := d.dataOwnerEmail(ctx, project); dataOwner != "" {
+			channels = appendChannels(channels, provider.NotificationChannel{Type: provider.EmailChannel, Target: dataOwner})
+		}
+	}
+	return channels
+}
+
+// dataOwnerEmail returns the email address of the data owner of the project, a data owner without domain gets the
+// configured email domain
+func (d *Dispatcher) dataOwnerEmail(ctx context.Context, project string) string {
+	if d.sourceGCPProjectProvider == nil {
+		return ""
+	}
+
+	sourceGCPProject, err := d.sourceGCPProjectProvider.GetSourceGCPProject(ctx, project)
+	if err != nil {
+		glog.Warningf("could not get data owner of project %s: %s", project, err)
+		return ""
+	}
+
+	dataOwner := strings.TrimSpace(sourceGCPProject.DataOwner)
+	if dataOwner == "" || strings.Contains(dataOwner, "@") {
+		return dataOwner
+	}
+	if d.emailDomain == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s@%s", dataOwner, strings.TrimPrefix(d.emailDomain, "@"))
+}
+
+func appendChannels(channels []provider.NotificationChannel, additional ...provider.NotificationChannel) []provider.NotificationChannel {
+	for _, channel := range additional {
+		if !slices.Contains(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
@@ -0,0 +1,144 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeNotifier struct {
+	fail    bool
+	targets []string
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, target string, _ Event) error {
+	if f.fail {
+		return errors.New("channel is down")
+	}
+	f.targets = append(f.targets, target)
+	return nil
+}
+
+type fakeNotificationRepository struct {
+	mu   sync.Mutex
+	sent map[string]bool
+}
+
+func (f *fakeNotificationRepository) TryReserveNotification(_ context.Context, notification *repository.Notification, _ time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sent[notification.Key] {
+		return false, nil
+	}
+	f.sent[notification.Key] = true
+	return true, nil
+}
+
+func (f *fakeNotificationRepository) ReleaseNotification(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.sent, key)
+	return nil
+}
+
+type fakeChannelProvider map[string][]provider.NotificationChannel
+
+func (f fakeChannelProvider) GetNotificationChannels(_ context.Context, gcpProjectID string) ([]provider.NotificationChannel, error) {
+	return f[gcpProjectID], nil
+}
+
+type fakeSourceGCPProjectProvider map[string]string
+
+func (f fakeSourceGCPProjectProvider) GetSourceGCPProject(_ context.Context, gcpProjectID string) (provider.SourceGCPProject, error) {
+	dataOwner, exists := f[gcpProjectID]
+	if !exists {
+		return provider.SourceGCPProject{}, errors.New("unknown project")
+	}
+	return provider.SourceGCPProject{DataOwner: dataOwner}, nil
+}
+
+func newTestDispatcher(webhook, slack, email *fakeNotifier) *Dispatcher {
+	return &Dispatcher{
+		notifiers: map[provider.NotificationChannelType]Notifier{
+			provider.WebhookChannel: webhook,
+			provider.SlackChannel:   slack,
+			provider.EmailChannel:   email,
+		},
+		defaultChannels: []provider.NotificationChannel{{Type: provider.WebhookChannel, Target: "https://ops.example.com/hook"}},
+		channelProvider: fakeChannelProvider{
+			"project-with-channels": {
+				{Type: provider.SlackChannel, Target: "https://hooks.slack.com/services/team"},
+				{Type: provider.EmailChannel, Target: "team@example.com"},
+			},
+		},
+		sourceGCPProjectProvider: fakeSourceGCPProjectProvider{
+			"project-with-channels": "jane.doe",
+			"project-with-owner":    "john.doe",
+			"project-with-email":    "john.doe@other.example.com",
+		},
+		notificationRepository: &fakeNotificationRepository{sent: map[string]bool{}},
+		throttle:               time.Hour,
+		emailDomain:            "example.com",
+	}
+}
+
+func TestDispatcher_RoutesToProjectChannels(t *testing.T) {
+	webhook, slack, email := &fakeNotifier{}, &fakeNotifier{}, &fakeNotifier{}
+	dispatcher := newTestDispatcher(webhook, slack, email)
+
+	dispatcher.Notify(context.Background(), NewEvent(JobFailed, "job-1", "project-with-channels", "backup-1", "", ""))
+
+	assert.Equal(t, []string{"https://ops.example.com/hook"}, webhook.targets)
+	assert.Equal(t, []string{"https://hooks.slack.com/services/team"}, slack.targets)
+	assert.Equal(t, []string{"team@example.com"}, email.targets, "configured channels replace the data owner")
+}
+
+func TestDispatcher_RoutesToDataOwner(t *testing.T) {
+	webhook, slack, email := &fakeNotifier{}, &fakeNotifier{}, &fakeNotifier{}
+	dispatcher := newTestDispatcher(webhook, slack, email)
+
+	dispatcher.Notify(context.Background(), NewEvent(JobFailed, "job-1", "project-with-owner", "backup-1", "", ""))
+	dispatcher.Notify(context.Background(), NewEvent(JobFailed, "job-2", "project-with-email", "backup-2", "", ""))
+	dispatcher.Notify(context.Background(), NewEvent(JobFailed, "job-3", "unknown-project", "backup-3", "", ""))
+
+	assert.Equal(t, []string{"john.doe@example.com", "john.doe@other.example.com"}, email.targets)
+	assert.Len(t, webhook.targets, 3)
+	assert.Empty(t, slack.targets)
+}
+
+func TestDispatcher_DeduplicatesEvents(t *testing.T) {
+	webhook, slack, email := &fakeNotifier{}, &fakeNotifier{}, &fakeNotifier{}
+	dispatcher := newTestDispatcher(webhook, slack, email)
+
+	dispatcher.Notify(context.Background(), NewEvent(JobStuck, "job-1", "project-with-owner", "backup-1", "", ""))
+	dispatcher.Notify(context.Background(), NewEvent(JobStuck, "job-1", "project-with-owner", "backup-1", "", ""))
+
+	assert.Len(t, webhook.targets, 1)
+	assert.Len(t, email.targets, 1)
+}
+
+func TestDispatcher_ResendsUndeliveredEvents(t *testing.T) {
+	webhook, slack, email := &fakeNotifier{fail: true}, &fakeNotifier{}, &fakeNotifier{fail: true}
+	dispatcher := newTestDispatcher(webhook, slack, email)
+
+	dispatcher.Notify(context.Background(), NewEvent(RPOViolated, "backup-1", "project-with-owner", "backup-1", "", ""))
+	webhook.fail = false
+	dispatcher.Notify(context.Background(), NewEvent(RPOViolated, "backup-1", "project-with-owner", "backup-1", "", ""))
+
+	assert.Equal(t, []string{"https://ops.example.com/hook"}, webhook.targets)
+}
+
+func TestDispatcher_WithoutChannels(t *testing.T) {
+	notificationRepository := &fakeNotificationRepository{sent: map[string]bool{}}
+	dispatcher := &Dispatcher{notifiers: map[provider.NotificationChannelType]Notifier{}, notificationRepository: notificationRepository}
+
+	dispatcher.Notify(context.Background(), NewEvent(JobFailed, "job-1", "project-with-owner", "backup-1", "", ""))
+
+	assert.Empty(t, notificationRepository.sent, "events without channels are not recorded")
+}
@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opencensus.io/trace"
+)
+
+// emailNotifier sends the event as plain text email through an SMTP server
+type emailNotifier struct {
+	address string
+	from    string
+	auth    smtp.Auth
+}
+
+// NewEmailNotifier create a new Notifier for emails, auth is optional
+func NewEmailNotifier(host string, port int, from string, auth smtp.Auth) Notifier {
+	return &emailNotifier{
+		address: net.JoinHostPort(host, strconv.Itoa(port)),
+		from:    from,
+		auth:    auth,
+	}
+}
+
+func (e *emailNotifier) Notify(ctxIn context.Context, target string, event Event) error {
+	_, span := trace.StartSpan(ctxIn, "(*emailNotifier).Notify")
+	defer span.End()
+
+	err := smtp.SendMail(e.address, e.auth, e.from, []string{target}, e.message(target, event))
+	if err != nil {
+		return fmt.Errorf("could not send notification email to %s: %s", target, err)
+	}
+	return nil
+}
+
+func (e *emailNotifier) message(to string, event Event) []byte {
+	headers := []string{
+		"From: " + e.from,
+		"To: " + to,
+		"Subject: " + headerValue(event.Subject),
+		"Date: " + event.Timestamp.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	body := strings.ReplaceAll(event.Message, "\n", "\r\n")
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + body + "\r\n")
+}
+
+// headerValue keeps a value on one line, so it can not add headers
+func headerValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
@@ -0,0 +1,113 @@
+package notification
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailNotifier_SendsEmail(t *testing.T) {
+	server := newFakeSMTPServer(t)
+
+	event := NewEvent(RPOViolated, "backup-1", "local-account", "backup-1", "Recovery point objective\r\nBcc: someone@example.com", "backup-1 violates its RPO\nsource: dataset.table")
+	err := NewEmailNotifier(server.host, server.port, "penelope@example.com", nil).Notify(context.Background(), "john.doe@example.com", event)
+	require.NoError(t, err)
+
+	mail := <-server.mails
+	assert.Equal(t, "penelope@example.com", mail.from)
+	assert.Equal(t, []string{"john.doe@example.com"}, mail.recipients)
+	assert.Contains(t, mail.data, "Subject: Recovery point objective Bcc: someone@example.com\r\n")
+	assert.Contains(t, mail.data, "\r\n\r\nbackup-1 violates its RPO\r\nsource: dataset.table")
+}
+
+type fakeMail struct {
+	from       string
+	recipients []string
+	data       string
+}
+
+// fakeSMTPServer accepts the mails of one SMTP session without authentication
+type fakeSMTPServer struct {
+	host  string
+	port  int
+	mails chan fakeMail
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	host, rawPort, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(rawPort)
+	require.NoError(t, err)
+
+	server := &fakeSMTPServer{host: host, port: port, mails: make(chan fakeMail, 1)}
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		server.serve(textproto.NewConn(conn))
+	}()
+	return server
+}
+
+func (s *fakeSMTPServer) serve(conn *textproto.Conn) {
+	mail := fakeMail{}
+	_ = conn.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			_ = conn.PrintfLine("250 localhost")
+		case "MAIL":
+			mail.from = strings.Trim(strings.TrimPrefix(line[len("MAIL FROM:"):], " "), "<>")
+			_ = conn.PrintfLine("250 OK")
+		case "RCPT":
+			mail.recipients = append(mail.recipients, strings.Trim(strings.TrimPrefix(line[len("RCPT TO:"):], " "), "<>"))
+			_ = conn.PrintfLine("250 OK")
+		case "DATA":
+			_ = conn.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(conn.R)
+			if err != nil {
+				return
+			}
+			mail.data = data
+			s.mails <- mail
+			_ = conn.PrintfLine("250 OK")
+		case "QUIT":
+			_ = conn.PrintfLine("221 Bye")
+			return
+		default:
+			_ = conn.PrintfLine("250 OK")
+		}
+	}
+}
+
+func readData(reader *bufio.Reader) (string, error) {
+	var data strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		if line == ".\r\n" {
+			return data.String(), nil
+		}
+		data.WriteString(line)
+	}
+}
@@ -0,0 +1,51 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type EventKind string
+
+const (
+	// JobFailed is sent for a backup job which failed and is not retried anymore
+	JobFailed EventKind = "job_failed"
+	// JobStuck is sent for a backup job which did not finish in time
+	JobStuck EventKind = "job_stuck"
+	// RPOViolated is sent for a backup with sources older than its recovery point objective
+	RPOViolated EventKind = "rpo_violated"
+	// RestoreDrillFailed is sent for a restore drill which failed or exceeded the recovery time objective
+	RestoreDrillFailed EventKind = "restore_drill_failed"
+	// ComplianceFailed is sent for a backup or sink project which is not compliant anymore
+	ComplianceFailed EventKind = "compliance_failed"
+)
+
+// Event is a failure the owners of a project are notified about, events with the same key are deduplicated
+type Event struct {
+	Kind      EventKind `json:"kind"`
+	Key       string    `json:"key"`
+	Project   string    `json:"project"`
+	BackupID  string    `json:"backup_id,omitempty"`
+	Subject   string    `json:"subject"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewEvent creates an event, its key is made of the kind and the id of the failed item
+func NewEvent(kind EventKind, id, project, backupID, subject, message string) Event {
+	return Event{
+		Kind:      kind,
+		Key:       fmt.Sprintf("%s/%s", kind, id),
+		Project:   project,
+		BackupID:  backupID,
+		Subject:   subject,
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+}
+
+// Notifier delivers an event to the target of a channel, like a webhook URL or an email address
+type Notifier interface {
+	Notify(ctxIn context.Context, target string, event Event) error
+}
@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"go.opencensus.io/trace"
+)
+
+// webhookNotifier posts the event as JSON to the target URL
+type webhookNotifier struct {
+	client *http.Client
+}
+
+// NewWebhookNotifier create a new Notifier for webhooks
+func NewWebhookNotifier(client *http.Client) Notifier {
+	return &webhookNotifier{client: client}
+}
+
+func (w *webhookNotifier) Notify(ctxIn context.Context, target string, event Event) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*webhookNotifier).Notify")
+	defer span.End()
+
+	return postJSON(ctx, w.client, target, event)
+}
+
+// slackNotifier posts the event as message to a Slack compatible incoming webhook
+type slackNotifier struct {
+	client *http.Client
+}
+
+// NewSlackNotifier create a new Notifier for Slack compatible incoming webhooks
+func NewSlackNotifier(client *http.Client) Notifier {
+	return &slackNotifier{client: client}
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+func (s *slackNotifier) Notify(ctxIn context.Context, target string, event Event) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*slackNotifier).Notify")
+	defer span.End()
+
+	return postJSON(ctx, s.client, target, slackMessage{Text: fmt.Sprintf("*%s*\n%s", event.Subject, event.Message)})
+}
+
+// postJSON sends the payload to the target, errors never contain the target because a webhook URL is a secret itself
+func postJSON(ctx context.Context, client *http.Client, target string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("could not marshal notification: %s", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not create notification request: %s", redactURLError(err))
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("could not send notification to %s: %s", request.URL.Host, redactURLError(err))
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("notification was rejected with status %s", response.Status)
+	}
+	return nil
+}
+
+// redactURLError drops the URL of an error returned by the http client
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
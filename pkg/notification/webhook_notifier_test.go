@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_PostsEvent(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	event := NewEvent(JobFailed, "job-1", "local-account", "backup-1", "Backup job failed", "job job-1 failed")
+	err := NewWebhookNotifier(server.Client()).Notify(context.Background(), server.URL, event)
+	require.NoError(t, err)
+
+	assert.Equal(t, "job_failed/job-1", received.Key)
+	assert.Equal(t, JobFailed, received.Kind)
+	assert.Equal(t, "local-account", received.Project)
+	assert.Equal(t, "backup-1", received.BackupID)
+	assert.Equal(t, "job job-1 failed", received.Message)
+}
+
+func TestSlackNotifier_PostsMessage(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received = string(body)
+	}))
+	defer server.Close()
+
+	event := NewEvent(JobStuck, "job-1", "local-account", "backup-1", "Backup job is stuck", "job job-1 is stuck")
+	err := NewSlackNotifier(server.Client()).Notify(context.Background(), server.URL, event)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"text": "*Backup job is stuck*\njob job-1 is stuck"}`, received)
+}
+
+func TestWebhookNotifier_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.Client()).Notify(context.Background(), server.URL, NewEvent(JobFailed, "job-1", "local-account", "", "", ""))
+	assert.Error(t, err)
+}
+
+func TestSlackNotifier_ErrorDoesNotContainURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	target := server.URL + "/services/T000/B000/XXXX?token=secret"
+	server.Close()
+
+	err := NewSlackNotifier(server.Client()).Notify(context.Background(), target, NewEvent(JobFailed, "job-1", "local-account", "", "", ""))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "/services/T000/B000/XXXX")
+	assert.NotContains(t, err.Error(), "secret")
+
+	err = NewSlackNotifier(server.Client()).Notify(context.Background(), "https://hooks.slack.com/services/T000/B000/XXXX\x7f", NewEvent(JobFailed, "job-1", "local-account", "", "", ""))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "/services/T000/B000/XXXX")
+}
@@ -0,0 +1,130 @@
+package policy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+)
+
+// Violation of a policy rule by a backup
+type Violation struct {
+	Rule        string
+	Description string
+	Severity    provider.PolicySeverity
+}
+
+func (v Violation) String() string {
+	if v.Description == "" {
+		return fmt.Sprintf("%s (%s)", v.Rule, v.Severity)
+	}
+	return fmt.Sprintf("%s (%s): %s", v.Rule, v.Severity, v.Description)
+}
+
+// Blocking returns the violations of rules with severity block
+func Blocking(violations []Violation) []Violation {
+	var blocking []Violation
+	for _, violation := range violations {
+		if violation.Severity == provider.PolicyBlock {
+			blocking = append(blocking, violation)
+		}
+	}
+	return blocking
+}
+
+var (
+	env      *cel.Env
+	envErr   error
+	envOnce  sync.Once
+	programs sync.Map
+)
+
+func celEnv() (*cel.Env, error) {
+	envOnce.Do(func() {
+		env, envErr = cel.NewEnv(
+			cel.Variable("backup", cel.MapType(cel.StringType, cel.DynType)),
+			cel.Variable("availability_class", cel.StringType),
+		)
+	})
+	return env, envErr
+}
+
+// compile returns the program of a rule expression, programs are cached by their expression
+func compile(expression string) (cel.Program, error) {
+	if program, ok := programs.Load(expression); ok {
+		return program.(cel.Program), nil
+	}
+
+	e, err := celEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := e.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression returns %s instead of bool", ast.OutputType())
+	}
+	program, err := e.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+	programs.Store(expression, program)
+	return program, nil
+}
+
+// Evaluate checks the backup of a source project with the given availability class against the rules and returns
+// the violated rules, an expression evaluating to true means the backup complies with the rule
+func Evaluate(rules []provider.PolicyRule, backup *repository.Backup, availabilityClass provider.AvailabilityClass) ([]Violation, error) {
+	activation := map[string]interface{}{
+		"backup":             backupVariables(backup),
+		"availability_class": string(availabilityClass),
+	}
+
+	var violations []Violation
+	for _, rule := range rules {
+		program, err := compile(rule.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("policy rule %s can not be compiled: %s", rule.Name, err)
+		}
+		out, _, err := program.Eval(activation)
+		if err != nil {
+			return nil, fmt.Errorf("policy rule %s can not be evaluated: %s", rule.Name, err)
+		}
+		passed, ok := out.Value().(bool)
+		if !ok {
+			return nil, fmt.Errorf("policy rule %s returns %v instead of bool", rule.Name, out.Value())
+		}
+		if !passed {
+			violations = append(violations, Violation{Rule: rule.Name, Description: rule.Description, Severity: rule.Severity})
+		}
+	}
+	return violations, nil
+}
+
+// backupVariables are the fields of a backup available to rule expressions, every key is set so rules do not need to
+// check for presence
+func backupVariables(backup *repository.Backup) map[string]interface{} {
+	return map[string]interface{}{
+		"type":                        backup.Type.String(),
+		"strategy":                    backup.Strategy.String(),
+		"project":                     backup.SourceProject,
+		"target_project":              backup.TargetProject,
+		"region":                      backup.Region,
+		"dual_region":                 backup.DualRegion,
+		"storage_class":               backup.StorageClass,
+		"archive_ttm":                 int64(backup.ArchiveTTM),
+		"kms_key_name":                backup.KMSKeyName,
+		"immutable":                   backup.Immutable,
+		"recovery_point_objective":    int64(backup.RecoveryPointObjective),
+		"recovery_time_objective":     int64(backup.RecoveryTimeObjective),
+		"snapshot_lifetime_in_days":   int64(backup.SnapshotOptions.LifetimeInDays),
+		"snapshot_frequency_in_hours": int64(backup.FrequencyInHours),
+		"mirror_lifetime_in_days":     int64(backup.MirrorOptions.LifetimeInDays),
+		"max_retry_attempts":          int64(backup.MaxRetryAttempts),
+		"cron":                        backup.Cron,
+	}
+}
@@ -0,0 +1,58 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBackup() *repository.Backup {
+	return &repository.Backup{
+		ID:                     "policy",
+		Type:                   repository.CloudStorage,
+		Strategy:               repository.Snapshot,
+		SourceProject:          "local-account",
+		RecoveryPointObjective: 24,
+		SinkOptions:            repository.SinkOptions{TargetProject: "local-account-backup", Region: "europe-west1", StorageClass: "NEARLINE"},
+		SnapshotOptions:        repository.SnapshotOptions{LifetimeInDays: 30, FrequencyInHours: 24},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rules := []provider.PolicyRule{
+		{Name: "europe-only", Expression: `backup.region.startsWith("europe-")`, Severity: provider.PolicyBlock},
+		{Name: "dual-region-for-a4", Description: "A4 projects need a dual-region sink", Expression: `availability_class != "A4" || backup.dual_region != ""`, Severity: provider.PolicyWarn},
+		{Name: "lifetime", Expression: `backup.strategy != "Snapshot" || backup.snapshot_lifetime_in_days >= 14`, Severity: provider.PolicyBlock},
+	}
+
+	violations, err := Evaluate(rules, newBackup(), provider.A2Aimed)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = Evaluate(rules, newBackup(), provider.A4Resilient)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, Violation{Rule: "dual-region-for-a4", Description: "A4 projects need a dual-region sink", Severity: provider.PolicyWarn}, violations[0])
+	assert.Empty(t, Blocking(violations))
+
+	backup := newBackup()
+	backup.Region = "us-east1"
+	backup.SnapshotOptions.LifetimeInDays = 7
+	violations, err = Evaluate(rules, backup, provider.A1Irrelevant)
+	require.NoError(t, err)
+	assert.Len(t, Blocking(violations), 2)
+}
+
+func TestEvaluate_InvalidExpression(t *testing.T) {
+	_, err := Evaluate([]provider.PolicyRule{{Name: "syntax", Expression: `backup.region ==`, Severity: provider.PolicyBlock}}, newBackup(), provider.A1Irrelevant)
+	assert.Error(t, err)
+
+	_, err = Evaluate([]provider.PolicyRule{{Name: "not-bool", Expression: `backup.region`, Severity: provider.PolicyBlock}}, newBackup(), provider.A1Irrelevant)
+	assert.Error(t, err, "an expression must return bool")
+
+	_, err = Evaluate([]provider.PolicyRule{{Name: "unknown", Expression: `backup.unknown == ""`, Severity: provider.PolicyBlock}}, newBackup(), provider.A1Irrelevant)
+	assert.Error(t, err, "unknown fields can not be evaluated")
+}
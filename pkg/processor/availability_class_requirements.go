@@ -0,0 +1,102 @@
+package processor
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ottogroup/penelope/pkg/policy"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+)
+
+// availabilityClassRequirement the configuration a backup of a source project with the availability class needs,
+// zero values have no requirement
+type availabilityClassRequirement struct {
+	// MaxRecoveryPointObjective in hours
+	MaxRecoveryPointObjective int
+	// MaxRecoveryTimeObjective in minutes
+	MaxRecoveryTimeObjective int
+	Strategies               []repository.Strategy
+	DualRegion               bool
+	// MinRegionDistance in km between the region and the dual-region of the sink
+	MinRegionDistance float64
+	// MinLifetimeInDays of snapshots or of changed and deleted objects of a mirror, a lifetime of 0 keeps them forever
+	MinLifetimeInDays uint
+	Severity          provider.PolicySeverity
+}
+
+// availabilityClassRequirements requirement matrix per availability class, the A1 class has no requirements
+var availabilityClassRequirements = map[provider.AvailabilityClass]availabilityClassRequirement{
+	provider.A2Aimed: {
+		MaxRecoveryPointObjective: 72,
+		MaxRecoveryTimeObjective:  7 * 24 * 60,
+		MinLifetimeInDays:         7,
+		Severity:                  provider.PolicyWarn,
+	},
+	provider.A3Guaranteed: {
+		MaxRecoveryPointObjective: 24,
+		MaxRecoveryTimeObjective:  24 * 60,
+		Strategies:                []repository.Strategy{repository.Snapshot, repository.Mirror},
+		MinLifetimeInDays:         30,
+		Severity:                  provider.PolicyBlock,
+	},
+	provider.A4Resilient: {
+		MaxRecoveryPointObjective: 12,
+		MaxRecoveryTimeObjective:  8 * 60,
+		Strategies:                []repository.Strategy{repository.Snapshot, repository.Mirror},
+		DualRegion:                true,
+		MinRegionDistance:         200,
+		MinLifetimeInDays:         30,
+		Severity:                  provider.PolicyBlock,
+	},
+}
+
+// checkAvailabilityClass returns the requirements of the availability class the backup does not satisfy
+func checkAvailabilityClass(backup *repository.Backup, availabilityClass provider.AvailabilityClass) []policy.Violation {
+	requirement, ok := availabilityClassRequirements[availabilityClass]
+	if !ok {
+		return nil
+	}
+
+	var violations []policy.Violation
+	violate := func(rule, description string, args ...interface{}) {
+		violations = append(violations, policy.Violation{
+			Rule:        rule,
+			Description: fmt.Sprintf("%s requires %s", availabilityClass, fmt.Sprintf(description, args...)),
+			Severity:    requirement.Severity,
+		})
+	}
+
+	if requirement.MaxRecoveryPointObjective > 0 && (backup.RecoveryPointObjective <= 0 || backup.RecoveryPointObjective > requirement.MaxRecoveryPointObjective) {
+		violate("availability-class-rpo", "a recovery point objective of at most %d hours, backup has %d", requirement.MaxRecoveryPointObjective, backup.RecoveryPointObjective)
+	}
+	if requirement.MaxRecoveryTimeObjective > 0 && (backup.RecoveryTimeObjective <= 0 || backup.RecoveryTimeObjective > requirement.MaxRecoveryTimeObjective) {
+		violate("availability-class-rto", "a recovery time objective of at most %d minutes, backup has %d", requirement.MaxRecoveryTimeObjective, backup.RecoveryTimeObjective)
+	}
+	if len(requirement.Strategies) > 0 && !slices.Contains(requirement.Strategies, backup.Strategy) {
+		violate("availability-class-strategy", "one of the strategies %v, backup has %s", requirement.Strategies, backup.Strategy)
+	}
+
+	regionConfig := getRegionConfiguration(backup.Region)
+	if requirement.DualRegion && backup.DualRegion == "" && !regionConfig.MultiRegion {
+		violate("availability-class-dual-region", "a dual-region or multi-region sink, backup is stored in %s only", backup.Region)
+	}
+	if requirement.MinRegionDistance > 0 && backup.DualRegion != "" && !regionConfig.MultiRegion {
+		dualRegionConfig := getRegionConfiguration(backup.DualRegion)
+		if regionConfig.Region == "" || dualRegionConfig.Region == "" {
+			violate("availability-class-region-distance", "the regions of the sink to be %.0fkm apart, distance of %s and %s is unknown", requirement.MinRegionDistance, backup.Region, backup.DualRegion)
+		} else if distance := regionConfig.Location.Distance(dualRegionConfig.Location); distance < requirement.MinRegionDistance {
+			violate("availability-class-region-distance", "the regions of the sink to be %.0fkm apart, %s and %s are %.2fkm apart", requirement.MinRegionDistance, backup.Region, backup.DualRegion, distance)
+		}
+	}
+
+	lifetimeInDays := backup.SnapshotOptions.LifetimeInDays
+	if backup.Strategy == repository.Mirror {
+		lifetimeInDays = backup.MirrorOptions.LifetimeInDays
+	}
+	if requirement.MinLifetimeInDays > 0 && lifetimeInDays > 0 && lifetimeInDays < requirement.MinLifetimeInDays {
+		violate("availability-class-lifetime", "a lifetime of at least %d days, backup has %d", requirement.MinLifetimeInDays, lifetimeInDays)
+	}
+
+	return violations
+}
@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func violatedRules(backup *repository.Backup, availabilityClass provider.AvailabilityClass) []string {
+	var rules []string
+	for _, violation := range checkAvailabilityClass(backup, availabilityClass) {
+		rules = append(rules, violation.Rule)
+	}
+	return rules
+}
+
+func TestCheckAvailabilityClass(t *testing.T) {
+	backup := newCloudStorageSnapshotBackup("class", "bucket")
+	backup.RecoveryPointObjective = 12
+	backup.RecoveryTimeObjective = 240
+	backup.Region = "europe-west4"
+	backup.DualRegion = "europe-west8"
+	backup.SnapshotOptions.LifetimeInDays = 30
+
+	for _, class := range provider.A0Invalid.ValidValues() {
+		assert.Empty(t, checkAvailabilityClass(backup, class), "backup satisfies %s", class)
+	}
+
+	regional := *backup
+	regional.DualRegion = ""
+	regional.RecoveryPointObjective = 24
+	assert.Empty(t, checkAvailabilityClass(&regional, provider.A3Guaranteed))
+	assert.Equal(t, []string{"availability-class-rpo", "availability-class-dual-region"}, violatedRules(&regional, provider.A4Resilient))
+
+	multiRegion := regional
+	multiRegion.Region = "eu"
+	assert.Equal(t, []string{"availability-class-rpo"}, violatedRules(&multiRegion, provider.A4Resilient))
+
+	tableSnapshot := *backup
+	tableSnapshot.Strategy = repository.TableSnapshot
+	tableSnapshot.SnapshotOptions.LifetimeInDays = 7
+	assert.Equal(t, []string{"availability-class-strategy", "availability-class-lifetime"}, violatedRules(&tableSnapshot, provider.A3Guaranteed))
+
+	shortLived := *backup
+	shortLived.SnapshotOptions.LifetimeInDays = 3
+	violations := checkAvailabilityClass(&shortLived, provider.A2Aimed)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, provider.PolicyWarn, violations[0].Severity)
+
+	unlimited := *backup
+	unlimited.SnapshotOptions.LifetimeInDays = 0
+	assert.Empty(t, checkAvailabilityClass(&unlimited, provider.A4Resilient), "a lifetime of 0 keeps snapshots forever")
+}
+
+func TestCheckAvailabilityClass_RegionDistance(t *testing.T) {
+	backup := newCloudStorageSnapshotBackup("class", "bucket")
+	backup.RecoveryPointObjective = 12
+	backup.RecoveryTimeObjective = 240
+	backup.Region = "europe-west4"
+	backup.DualRegion = "europe-west4"
+
+	violations := checkAvailabilityClass(backup, provider.A4Resilient)
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "availability-class-region-distance", violations[0].Rule)
+	assert.Equal(t, provider.PolicyBlock, violations[0].Severity)
+
+	backup.DualRegion = "unknown-region1"
+	assert.Equal(t, []string{"availability-class-region-distance"}, violatedRules(backup, provider.A4Resilient))
+}
@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"context"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/http/auth"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type AvailabilityClassViolationsProcessorFactory interface {
+	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.EmptyRequest, requestobjects.AvailabilityClassViolationsResponse], error)
+}
+
+// availabilityClassViolationsProcessorFactory create Operations for listing backups not satisfying the availability class of their project
+type availabilityClassViolationsProcessorFactory struct {
+	credentialsProvider      secret.SecretProvider
+	sourceGCPProjectProvider provider.SourceGCPProjectProvider
+}
+
+func NewAvailabilityClassViolationsProcessorFactory(credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider) AvailabilityClassViolationsProcessorFactory {
+	return &availabilityClassViolationsProcessorFactory{credentialsProvider, sourceGCPProjectProvider}
+}
+
+// CreateProcessor return Operations for listing backups not satisfying the availability class of their project
+func (c availabilityClassViolationsProcessorFactory) CreateProcessor(ctxIn context.Context) (Operation[requestobjects.EmptyRequest, requestobjects.AvailabilityClassViolationsResponse], error) {
+	ctx, span := trace.StartSpan(ctxIn, "newAvailabilityClassViolationsProcessor")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &availabilityClassViolationsProcessor{}, err
+	}
+
+	return &availabilityClassViolationsProcessor{BackupRepository: backupRepository, sourceGCPProjectProvider: c.sourceGCPProjectProvider}, nil
+}
+
+type availabilityClassViolationsProcessor struct {
+	BackupRepository         repository.BackupRepository
+	sourceGCPProjectProvider provider.SourceGCPProjectProvider
+}
+
+// Process list the backups not satisfying the current availability class of their project, e.g. after the class was
+// raised, only backups which are not deleted and of projects the user may list are shown
+func (l availabilityClassViolationsProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.EmptyRequest]) (requestobjects.AvailabilityClassViolationsResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(availabilityClassViolationsProcessor).Process")
+	defer span.End()
+
+	backups, err := l.BackupRepository.GetBackups(ctx, repository.BackupFilter{})
+	if err != nil {
+		return requestobjects.AvailabilityClassViolationsResponse{}, errors.Wrap(err, "backup repository GetBackups failed")
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if backups[i].SourceProject != backups[j].SourceProject {
+			return backups[i].SourceProject < backups[j].SourceProject
+		}
+		return backups[i].ID < backups[j].ID
+	})
+
+	response := requestobjects.AvailabilityClassViolationsResponse{Violations: []requestobjects.AvailabilityClassViolationResponse{}}
+	classByProject := map[string]provider.AvailabilityClass{}
+	for _, backup := range backups {
+		if backup.Status == repository.BackupDeleted || backup.Status == repository.ToDelete {
+			continue
+		}
+		if !auth.CheckRequestIsAllowed(args.Principal, requestobjects.Listing, backup.SourceProject) {
+			continue
+		}
+
+		availabilityClass, checked := classByProject[backup.SourceProject]
+		if !checked {
+			sourceGCPProject, err := l.sourceGCPProjectProvider.GetSourceGCPProject(ctx, backup.SourceProject)
+			if err != nil {
+				return requestobjects.AvailabilityClassViolationsResponse{}, errors.Wrapf(err, "get source project failed %s", backup.SourceProject)
+			}
+			availabilityClass = sourceGCPProject.AvailabilityClass
+			classByProject[backup.SourceProject] = availabilityClass
+		}
+
+		violations := checkAvailabilityClass(backup, availabilityClass)
+		if len(violations) == 0 {
+			continue
+		}
+		response.Violations = append(response.Violations, requestobjects.AvailabilityClassViolationResponse{
+			BackupID:          backup.ID,
+			Description:       backup.Description,
+			Project:           backup.SourceProject,
+			Type:              backup.Type.String(),
+			Status:            backup.Status.String(),
+			AvailabilityClass: availabilityClass,
+			Requirements:      mapPolicyViolations(violations),
+		})
+	}
+
+	return response, nil
+}
@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/http/auth/model"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/repository/memory"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type availabilityClassSourceGCPProjectProvider map[string]provider.AvailabilityClass
+
+func (p availabilityClassSourceGCPProjectProvider) GetSourceGCPProject(_ context.Context, gcpProjectID string) (provider.SourceGCPProject, error) {
+	return provider.SourceGCPProject{AvailabilityClass: p[gcpProjectID]}, nil
+}
+
+func TestAvailabilityClassViolationsProcessor(t *testing.T) {
+	backupRepository := &memory.BackupRepository{}
+	for _, backup := range []*repository.Backup{
+		{ID: "raised", SourceProject: "source-a", Status: repository.Finished, Strategy: repository.Snapshot, RecoveryPointObjective: 48, RecoveryTimeObjective: 60},
+		{ID: "deleted", SourceProject: "source-a", Status: repository.BackupDeleted, Strategy: repository.Snapshot, RecoveryPointObjective: 48, RecoveryTimeObjective: 60},
+		{ID: "satisfied", SourceProject: "source-a", Status: repository.Finished, Strategy: repository.Snapshot, RecoveryPointObjective: 24, RecoveryTimeObjective: 60},
+		{ID: "not-allowed", SourceProject: "source-b", Status: repository.Finished, Strategy: repository.Snapshot, RecoveryPointObjective: 48, RecoveryTimeObjective: 60},
+	} {
+		_, err := backupRepository.AddBackup(context.Background(), backup)
+		require.NoError(t, err)
+	}
+	sourceGCPProjectProvider := availabilityClassSourceGCPProjectProvider{"source-a": provider.A3Guaranteed, "source-b": provider.A3Guaranteed}
+	principal := &model.Principal{
+		User:         model.User{Email: "owner@example.com"},
+		RoleBindings: []model.ProjectRoleBinding{{Role: model.Viewer, Project: "source-a"}},
+	}
+
+	response, err := availabilityClassViolationsProcessor{BackupRepository: backupRepository, sourceGCPProjectProvider: sourceGCPProjectProvider}.Process(context.Background(), &Argument[requestobjects.EmptyRequest]{Principal: principal})
+	require.NoError(t, err)
+
+	require.Len(t, response.Violations, 1)
+	assert.Equal(t, "raised", response.Violations[0].BackupID)
+	assert.Equal(t, provider.A3Guaranteed, response.Violations[0].AvailabilityClass)
+	require.Len(t, response.Violations[0].Requirements, 1)
+	assert.Equal(t, "availability-class-rpo", response.Violations[0].Requirements[0].Rule)
+	assert.Equal(t, "block", response.Violations[0].Requirements[0].Severity)
+}
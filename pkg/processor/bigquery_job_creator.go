@@ -2,14 +2,18 @@ package processor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"go.opencensus.io/trace"
 	"google.golang.org/api/googleapi"
 )
@@ -23,13 +27,14 @@ type BigQueryJobCreator struct {
 	SourceMetadataRepository    repository.SourceMetadataRepository
 	SourceMetadataJobRepository repository.SourceMetadataJobRepository
 	BigQuery                    bigquery.Client
+	CloudStorage                gcs.CloudStorageClient
 }
 
 var BackupSourceNotFoundErr = errors.New("error: backup source not found")
 
 // NewBigQueryJobCreator return instance of BigQueryJobCreator
 func NewBigQueryJobCreator(ctxIn context.Context, backupRepository repository.BackupRepository, jobRepository repository.JobRepository, bigQueryClient bigquery.Client,
-	sourceMetadataRepository repository.SourceMetadataRepository, sourceMetadataJobRepository repository.SourceMetadataJobRepository) *BigQueryJobCreator {
+	sourceMetadataRepository repository.SourceMetadataRepository, sourceMetadataJobRepository repository.SourceMetadataJobRepository, gcsClient gcs.CloudStorageClient) *BigQueryJobCreator {
 	_, span := trace.StartSpan(ctxIn, "NewBigQueryJobCreator")
 	defer span.End()
 
@@ -39,40 +44,56 @@ func NewBigQueryJobCreator(ctxIn context.Context, backupRepository repository.Ba
 		SourceMetadataRepository:    sourceMetadataRepository,
 		SourceMetadataJobRepository: sourceMetadataJobRepository,
 		BigQuery:                    bigQueryClient,
+		CloudStorage:                gcsClient,
 	}
 }
 
 // PrepareJobs new BigQuery extract job
 func (b *BigQueryJobCreator) PrepareJobs(ctxIn context.Context, backup *repository.Backup) error {
-	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).PrepareJobs")
+	_, err := b.PrepareJobsForTables(ctxIn, backup, nil)
+	return err
+}
+
+// PrepareJobsForTables prepares jobs right away and returns the new jobs
+// without tables all tables of the backup are prepared like a scheduled run, a subset of tables only gets snapshot jobs and leaves the schedule of the backup unchanged
+func (b *BigQueryJobCreator) PrepareJobsForTables(ctxIn context.Context, backup *repository.Backup, tables []string) ([]*repository.Job, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).PrepareJobsForTables")
 	defer span.End()
 
 	datasetExists, err := b.BigQuery.DoesDatasetExists(ctx, backup.SourceProject, backup.Dataset)
 	var googleAPIErr *googleapi.Error
 	if errors.As(err, &googleAPIErr) && googleAPIErr.Code != 404 {
-		return fmt.Errorf("error: could not check if dataset exists: %s", err)
+		return nil, fmt.Errorf("error: could not check if dataset exists: %s", err)
 	} else if !datasetExists {
-		return BackupSourceNotFoundErr
+		return nil, BackupSourceNotFoundErr
+	}
+
+	if len(tables) > 0 {
+		return b.prepareTableSubsetJobs(ctx, backup, tables)
 	}
 
 	if repository.Mirror == backup.Strategy {
 		return b.prepareMirrorJobs(ctx, backup)
 	} else if repository.Snapshot == backup.Strategy {
 		return b.prepareSnapshotJobs(ctx, backup)
+	} else if repository.TableSnapshot == backup.Strategy {
+		return b.prepareTableSnapshotJobs(ctx, backup)
 	} else {
-		return fmt.Errorf("unkown strategy %s", backup.Strategy)
+		return nil, fmt.Errorf("unkown strategy %s", backup.Strategy)
 	}
 }
 
-func (b *BigQueryJobCreator) prepareSnapshotJobs(ctxIn context.Context, backup *repository.Backup) error {
+func (b *BigQueryJobCreator) prepareSnapshotJobs(ctxIn context.Context, backup *repository.Backup) ([]*repository.Job, error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).prepareSnapshotJobs")
 	defer span.End()
 
 	tables, err := b.flattenTables(ctx, backup)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var preparedJobs []*repository.Job
+	manifestID := sync.OnceValue(func() string { return b.writeManifest(ctx, backup) })
 	for i := 0; i < len(tables); i += batchSize {
 		end := i + batchSize
 		if end > len(tables) {
@@ -91,10 +112,14 @@ func (b *BigQueryJobCreator) prepareSnapshotJobs(ctxIn context.Context, backup *
 				glog.Errorf("error checking existing snapshot jobs for backup with id %s and table %s: %s", backup.ID, table.Name, err)
 				continue
 			}
-			jobs = append(jobs, newJob(backup.ID, table.Name))
+			jobs = append(jobs, newJobForTable(backup.ID, table))
 		}
 		if len(jobs) > 0 {
+			setManifestID(jobs, manifestID())
 			err = b.JobRepository.AddJobs(ctx, jobs)
+			if err == nil {
+				preparedJobs = append(preparedJobs, jobs...)
+			}
 		}
 	}
 
@@ -102,16 +127,112 @@ func (b *BigQueryJobCreator) prepareSnapshotJobs(ctxIn context.Context, backup *
 		err = b.BackupRepository.UpdateLastScheduledTime(ctx, backup.ID, time.Now(), repository.Prepared)
 	}
 
-	return err
+	return preparedJobs, err
 }
 
-func (b *BigQueryJobCreator) prepareMirrorJobs(ctxIn context.Context, backup *repository.Backup) error {
+// prepareTableSnapshotJobs create a job per table, a table snapshot always contains all partitions of its base table
+func (b *BigQueryJobCreator) prepareTableSnapshotJobs(ctxIn context.Context, backup *repository.Backup) ([]*repository.Job, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).prepareTableSnapshotJobs")
+	defer span.End()
+
+	tables, err := b.listBaseTables(ctx, backup)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := b.newJobsForSources(ctx, backup, tables)
+	err = b.addJobs(ctx, jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, b.BackupRepository.UpdateLastScheduledTime(ctx, backup.ID, time.Now(), repository.Prepared)
+}
+
+// prepareTableSubsetJobs create snapshot jobs for the given tables only, a table snapshot backup gets a job per base table
+func (b *BigQueryJobCreator) prepareTableSubsetJobs(ctxIn context.Context, backup *repository.Backup, tables []string) ([]*repository.Job, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).prepareTableSubsetJobs")
+	defer span.End()
+
+	if repository.Mirror == backup.Strategy {
+		return nil, fmt.Errorf("a subset of tables can not be prepared for strategy %s", backup.Strategy)
+	}
+
+	var sources []string
+	rowCounts := map[string]int64{}
+	for _, table := range tables {
+		if repository.TableSnapshot == backup.Strategy {
+			baseTable, _, _ := strings.Cut(table, "$")
+			if !slices.Contains(sources, baseTable) {
+				sources = append(sources, baseTable)
+			}
+			continue
+		}
+		resultingTables, err := b.listBigQueryTable(ctx, backup, table)
+		if err != nil {
+			return nil, fmt.Errorf("error listing table %s: %w", table, err)
+		}
+		for _, resultingTable := range resultingTables {
+			sources = append(sources, resultingTable.Name)
+			rowCounts[resultingTable.Name] = resultingTable.NumRows
+		}
+	}
+
+	jobs := b.newJobsForSources(ctx, backup, sources)
+	for _, job := range jobs {
+		if rowCount, exists := rowCounts[job.Source]; exists {
+			job.SourceRowCount = &rowCount
+		}
+	}
+	if len(jobs) > 0 && repository.TableSnapshot != backup.Strategy {
+		setManifestID(jobs, b.writeManifest(ctx, backup))
+	}
+
+	err := b.addJobs(ctx, jobs)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// newJobsForSources create a job for every source which has no job waiting to be scheduled yet
+func (b *BigQueryJobCreator) newJobsForSources(ctxIn context.Context, backup *repository.Backup, sources []string) []*repository.Job {
+	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).newJobsForSources")
+	defer span.End()
+
+	var jobs []*repository.Job
+	for _, source := range sources {
+		rs, err := b.JobRepository.GetByBackupIdAndSourceAndStatus(ctx, backup.ID, source, repository.NotScheduled, repository.FinishedQuotaError)
+		if err == nil && len(rs) > 0 {
+			glog.Infof("job for backup with id %s and table %s already exists", backup.ID, source)
+			continue
+		} else if err != nil {
+			glog.Errorf("error checking existing jobs for backup with id %s and table %s: %s", backup.ID, source, err)
+			continue
+		}
+		jobs = append(jobs, newJob(backup.ID, source))
+	}
+	return jobs
+}
+
+func (b *BigQueryJobCreator) addJobs(ctxIn context.Context, jobs []*repository.Job) error {
+	for i := 0; i < len(jobs); i += batchSize {
+		end := min(i+batchSize, len(jobs))
+		err := b.JobRepository.AddJobs(ctxIn, jobs[i:end])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *BigQueryJobCreator) prepareMirrorJobs(ctxIn context.Context, backup *repository.Backup) ([]*repository.Job, error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).prepareMirrorJobs")
 	defer span.End()
 
 	tables, err := b.flattenTables(ctx, backup)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Process batch
@@ -132,18 +253,18 @@ func (b *BigQueryJobCreator) prepareMirrorJobs(ctxIn context.Context, backup *re
 
 	currentMetadatas, err := b.SourceMetadataRepository.GetLastByBackupID(ctx, backup.ID)
 	if err != nil {
-		return fmt.Errorf("error getting last job for backup with id %s: %s", backup.ID, err)
+		return nil, fmt.Errorf("error getting last job for backup with id %s: %s", backup.ID, err)
 	}
 	newJobDescriptors, err := b.collateState(ctx, backup.ID, tables)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var jobs []*repository.Job
 	for _, descriptor := range newJobDescriptors {
 		for _, notScheduledTable := range newTables {
 			if descriptor.table == notScheduledTable.Name {
-				jobs = append(jobs, newJob(backup.ID, descriptor.table))
+				jobs = append(jobs, newJobForTable(backup.ID, notScheduledTable))
 				break
 			}
 		}
@@ -151,14 +272,15 @@ func (b *BigQueryJobCreator) prepareMirrorJobs(ctxIn context.Context, backup *re
 
 	err = b.handleNewRevisionForTableWhenPreviousJobIsNotScheduled(ctxIn, backup, currentMetadatas, pendingJobForTables, newJobDescriptors, &jobs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(jobs) > 0 {
+		setManifestID(jobs, b.writeManifest(ctx, backup))
 		err = b.JobRepository.AddJobs(ctx, jobs)
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, descriptor := range newJobDescriptors {
@@ -166,7 +288,7 @@ func (b *BigQueryJobCreator) prepareMirrorJobs(ctxIn context.Context, backup *re
 			if descriptor.matchJob(job) {
 				err = b.SourceMetadataJobRepository.Add(ctx, descriptor.sourceMetadataID, job.ID)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				break
 			}
@@ -174,7 +296,7 @@ func (b *BigQueryJobCreator) prepareMirrorJobs(ctxIn context.Context, backup *re
 	}
 
 	err = b.BackupRepository.UpdateLastScheduledTime(ctx, backup.ID, time.Now(), repository.Prepared)
-	return err
+	return jobs, err
 }
 func (b *BigQueryJobCreator) handleNewRevisionForTableWhenPreviousJobIsNotScheduled(
 	ctxIn context.Context,
@@ -198,7 +320,7 @@ func (b *BigQueryJobCreator) handleNewRevisionForTableWhenPreviousJobIsNotSchedu
 					currentMetadata.Source == pendingJobForTable.Name &&
 					pendingJobForTable.Name == newJobDescriptor.table &&
 					pendingJobForTable.Checksum != currentMetadata.SourceChecksum {
-					*jobs = append(*jobs, newJob(backup.ID, newJobDescriptor.table))
+					*jobs = append(*jobs, newJobForTable(backup.ID, pendingJobForTable))
 					err := b.SourceMetadataRepository.MarkDeleted(ctxIn, currentMetadata.ID)
 					if err != nil {
 						return fmt.Errorf("error marking job %d as deleted: %s", currentMetadata.ID, err)
@@ -211,6 +333,38 @@ func (b *BigQueryJobCreator) handleNewRevisionForTableWhenPreviousJobIsNotSchedu
 	return nil
 }
 
+// writeManifest store the metadata of the source dataset next to the extracted data and return the id of the manifest
+// the rows are more important than the metadata, so a failure is only logged and the jobs are prepared without manifest
+func (b *BigQueryJobCreator) writeManifest(ctxIn context.Context, backup *repository.Backup) string {
+	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).writeManifest")
+	defer span.End()
+
+	manifest, err := b.BigQuery.GetDatasetManifest(ctx, backup.SourceProject, backup.Dataset)
+	if err != nil {
+		glog.Warningf("could not capture metadata manifest of dataset %s for backup with id %s: %s", backup.Dataset, backup.ID, err)
+		return ""
+	}
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		glog.Warningf("could not serialize metadata manifest of dataset %s for backup with id %s: %s", backup.Dataset, backup.ID, err)
+		return ""
+	}
+
+	manifestID := generateNewID()
+	err = b.CloudStorage.CreateObject(ctx, backup.Sink, repository.BuildBigQueryManifestPath(backup.Dataset, manifestID), string(content))
+	if err != nil {
+		glog.Warningf("could not write metadata manifest of dataset %s for backup with id %s: %s", backup.Dataset, backup.ID, err)
+		return ""
+	}
+	return manifestID
+}
+
+func setManifestID(jobs []*repository.Job, manifestID string) {
+	for _, job := range jobs {
+		job.ManifestID = manifestID
+	}
+}
+
 func (b *BigQueryJobCreator) flattenTables(ctxIn context.Context, backup *repository.Backup) (flattenedTables []*bigquery.Table, err error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).prepareMirrorJobs")
 	defer span.End()
@@ -254,6 +408,34 @@ func (b *BigQueryJobCreator) flattenTables(ctxIn context.Context, backup *reposi
 	return flattenedTables, err
 }
 
+// listBaseTables return the tables of a backup without partition decorators
+func (b *BigQueryJobCreator) listBaseTables(ctxIn context.Context, backup *repository.Backup) (tables []string, err error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).listBaseTables")
+	defer span.End()
+
+	if 0 < len(backup.BigQueryOptions.Table) {
+		for _, t := range backup.BigQueryOptions.Table {
+			baseTable, _, _ := strings.Cut(t, "$")
+			if !slices.Contains(tables, baseTable) {
+				tables = append(tables, baseTable)
+			}
+		}
+		return tables, nil
+	}
+
+	tablesInDataset, err := b.BigQuery.GetTablesInDataset(ctx, backup.SourceProject, backup.Dataset)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tablesInDataset {
+		if len(backup.ExcludedTables) > 0 && containsTableWithName(t.Name, backup.ExcludedTables) {
+			continue
+		}
+		tables = append(tables, t.Name)
+	}
+	return tables, nil
+}
+
 func (b *BigQueryJobCreator) listBigQueryTable(ctxIn context.Context, backup *repository.Backup, table string) (tables []*bigquery.Table, err error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*BigQueryJobCreator).listBigQueryTable")
 	defer span.End()
@@ -393,6 +575,14 @@ func newJob(backupID string, table string) *repository.Job {
 	}
 }
 
+// newJobForTable create a job which records the row count of the table or partition when it was prepared
+func newJobForTable(backupID string, table *bigquery.Table) *repository.Job {
+	job := newJob(backupID, table.Name)
+	rowCount := table.NumRows
+	job.SourceRowCount = &rowCount
+	return job
+}
+
 func (b *BigQueryJobCreator) getMetadataForTable(tableName string, sourceMetadata []*repository.SourceMetadata) *repository.SourceMetadata {
 	for _, meta := range sourceMetadata {
 		if meta.Source == tableName {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/ottogroup/penelope/pkg/config"
 	"github.com/ottogroup/penelope/pkg/http/auth"
@@ -13,6 +15,8 @@ import (
 	"github.com/ottogroup/penelope/pkg/requestobjects"
 	"github.com/ottogroup/penelope/pkg/service/bigquery"
 	"github.com/ottogroup/penelope/pkg/service/billing"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
@@ -94,6 +98,30 @@ func (c *calculatingProcessor) Process(ctxIn context.Context, args *Argument[req
 		}
 		result = calculateResponse
 	}
+	if repository.Firestore.EqualTo(request.Type) {
+		firestoreCalculator, err := c.newFirestoreCalculator(ctx, targetProject)
+		if err != nil {
+			return requestobjects.CalculatedResponse{}, errors.Wrap(err, "newFirestoreCalculator failed")
+		}
+		defer firestoreCalculator.adminClient.Close(ctx)
+		calculateResponse, err := firestoreCalculator.calculateCost(ctx, request)
+		if err != nil {
+			return requestobjects.CalculatedResponse{}, errors.Wrap(err, "firestoreCalculator.calculateCost failed")
+		}
+		result = calculateResponse
+	}
+	if repository.CloudSQL.EqualTo(request.Type) {
+		cloudSQLCalculator, err := c.newCloudSQLCalculator(ctx, targetProject)
+		if err != nil {
+			return requestobjects.CalculatedResponse{}, errors.Wrap(err, "newCloudSQLCalculator failed")
+		}
+		defer cloudSQLCalculator.adminClient.Close(ctx)
+		calculateResponse, err := cloudSQLCalculator.calculateCost(ctx, request)
+		if err != nil {
+			return requestobjects.CalculatedResponse{}, errors.Wrap(err, "cloudSQLCalculator.calculateCost failed")
+		}
+		result = calculateResponse
+	}
 	return result, nil
 }
 
@@ -111,6 +139,16 @@ type bigQueryCalculator struct {
 	bigQueryClient bigquery.Client
 }
 
+type firestoreCalculator struct {
+	baseCalculator
+	adminClient firestore.AdminClient
+}
+
+type cloudSQLCalculator struct {
+	baseCalculator
+	adminClient cloudsql.AdminClient
+}
+
 func (c *calculatingProcessor) newCloudStorageCalculator(ctxIn context.Context, targetProjectID string) (*cloudStorageCalculator, error) {
 	ctx, span := trace.StartSpan(ctxIn, "newCloudStorageCalculator")
 	defer span.End()
@@ -143,6 +181,73 @@ func (c *calculatingProcessor) newBigQueryCalculator(ctxIn context.Context, sour
 	return &BigQueryCalculator, nil
 }
 
+func (c *calculatingProcessor) newFirestoreCalculator(ctxIn context.Context, targetProjectID string) (*firestoreCalculator, error) {
+	ctx, span := trace.StartSpan(ctxIn, "newFirestoreCalculator")
+	defer span.End()
+
+	adminClient, err := firestore.NewAdminClient(ctx, c.tokenSourceProvider, targetProjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewAdminClient failed")
+	}
+	billingClient, err := billing.NewCloudBillingClient(ctx)
+	if err != nil {
+		adminClient.Close(ctx)
+		return nil, errors.Wrap(err, "NewCloudBillingClient failed")
+	}
+	FirestoreCalculator := firestoreCalculator{baseCalculator: baseCalculator{billingClient: billingClient}, adminClient: adminClient}
+	return &FirestoreCalculator, nil
+}
+
+// calculateCost estimates the size of an export with the stored data and index bytes, an export does not contain indexes so this is an upper bound
+func (c *firestoreCalculator) calculateCost(ctxIn context.Context, request *requestobjects.CalculateRequest) (requestobjects.CalculatedResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*firestoreCalculator).calculateCost")
+	defer span.End()
+
+	database := request.FirestoreOptions.Database
+	if database == "" {
+		database = repository.FirestoreDefaultDatabase
+	}
+
+	response := requestobjects.CalculatedResponse{}
+	storageSize, err := c.adminClient.DatabaseUsageInBytes(ctx, request.Project, database)
+	if err != nil {
+		return requestobjects.CalculatedResponse{}, errors.Wrap(err, "DatabaseUsageInBytes failed")
+	}
+	response.Costs, err = c.calculateCosts(request, storageSize)
+	return response, err
+}
+
+func (c *calculatingProcessor) newCloudSQLCalculator(ctxIn context.Context, targetProjectID string) (*cloudSQLCalculator, error) {
+	ctx, span := trace.StartSpan(ctxIn, "newCloudSQLCalculator")
+	defer span.End()
+
+	adminClient, err := cloudsql.NewAdminClient(ctx, c.tokenSourceProvider, targetProjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewAdminClient failed")
+	}
+	billingClient, err := billing.NewCloudBillingClient(ctx)
+	if err != nil {
+		adminClient.Close(ctx)
+		return nil, errors.Wrap(err, "NewCloudBillingClient failed")
+	}
+	CloudSQLCalculator := cloudSQLCalculator{baseCalculator: baseCalculator{billingClient: billingClient}, adminClient: adminClient}
+	return &CloudSQLCalculator, nil
+}
+
+// calculateCost estimates the size of the exports with the used disk bytes of the instance, exports are compressed so this is an upper bound
+func (c *cloudSQLCalculator) calculateCost(ctxIn context.Context, request *requestobjects.CalculateRequest) (requestobjects.CalculatedResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*cloudSQLCalculator).calculateCost")
+	defer span.End()
+
+	response := requestobjects.CalculatedResponse{}
+	storageSize, err := c.adminClient.InstanceUsageInBytes(ctx, request.Project, request.CloudSQLOptions.Instance)
+	if err != nil {
+		return requestobjects.CalculatedResponse{}, errors.Wrap(err, "InstanceUsageInBytes failed")
+	}
+	response.Costs, err = c.calculateCosts(request, storageSize)
+	return response, err
+}
+
 func (c *cloudStorageCalculator) calculateCost(ctxIn context.Context, request *requestobjects.CalculateRequest) (requestobjects.CalculatedResponse, error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*cloudStorageCalculator).calculateCost")
 	defer span.End()
@@ -165,10 +270,53 @@ func (c *bigQueryCalculator) calculateCost(ctxIn context.Context, request *reque
 	if err != nil {
 		return requestobjects.CalculatedResponse{}, errors.Wrap(err, "getTotalStorageSize failed")
 	}
-	response.Costs, err = c.calculateCosts(request, storageSize)
+	if repository.TableSnapshot.EqualTo(request.Strategy) {
+		response.Costs, err = c.calculateCosts(tableSnapshotCostRequest(request), storageSize*tableSnapshotChangeRatio)
+		return response, err
+	}
+	response.Costs, err = c.calculateCosts(request, storageSize*exportSizeRatio(request.BigQueryOptions))
 	return response, err
 }
 
+// tableSnapshotChangeRatio estimates the share of a table changed during the lifetime of a table snapshot
+// a table snapshot is only billed for the bytes which are no longer part of its base table
+const tableSnapshotChangeRatio = 0.1
+
+// tableSnapshotCostRequest price table snapshots as regional storage without replication
+// table snapshots are kept in the location of the source dataset and BigQuery active logical storage is priced close to regional storage
+func tableSnapshotCostRequest(request *requestobjects.CalculateRequest) *requestobjects.CalculateRequest {
+	snapshotRequest := *request
+	snapshotRequest.TargetOptions.StorageClass = "REGIONAL"
+	snapshotRequest.TargetOptions.DualRegion = ""
+	return &snapshotRequest
+}
+
+// exportSizeRatio estimates the size of the extracted files relative to the logical table size, uncompressed Avro is the reference
+func exportSizeRatio(options requestobjects.BigQueryOptions) float64 {
+	bigQueryOptions := repository.BigQueryOptions{
+		ExportFormat:      repository.ExportFormat(strings.ToUpper(options.ExportFormat)),
+		ExportCompression: repository.ExportCompression(strings.ToUpper(options.ExportCompression)),
+	}
+	format, compression := bigQueryOptions.GetExportFormat(), bigQueryOptions.GetExportCompression()
+
+	ratio := 1.0
+	switch format {
+	case repository.ParquetExport:
+		ratio = 0.8
+	case repository.JSONExport:
+		ratio = 2.0
+	}
+	switch compression {
+	case repository.SnappyCompression:
+		ratio *= 0.6
+	case repository.DeflateCompression, repository.GzipCompression:
+		ratio *= 0.4
+	case repository.ZstdCompression:
+		ratio *= 0.35
+	}
+	return ratio
+}
+
 func (c *bigQueryCalculator) getTotalStorageSize(ctxIn context.Context, request *requestobjects.CalculateRequest) (totalSize float64, err error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*bigQueryCalculator).getTotalStorageSize")
 	defer span.End()
@@ -255,16 +403,19 @@ func (c *baseCalculator) calculateCosts(request *requestobjects.CalculateRequest
 		writeCostsPerGB, err = c.billingClient.PricePerGB("CB83-3C2D-160D") // cost for write with replication per GB
 	}
 
-	if repository.Snapshot.EqualTo(request.Strategy) && request.SnapshotOptions.LifetimeInDays != 0 {
+	if isSnapshotStrategy(request.Strategy) && request.SnapshotOptions.LifetimeInDays != 0 {
 		periods = append(periods, int64(request.SnapshotOptions.LifetimeInDays))
 	} else {
 		periods = append(periods, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}...)
 	}
 	frequencyPerMonth = 1.0
 	averageGregorianDaysInMonth := 365.2425 / 12
-	if repository.Snapshot.EqualTo(request.Strategy) && 0 < request.SnapshotOptions.FrequencyInHours {
+	if isSnapshotStrategy(request.Strategy) && 0 < request.SnapshotOptions.FrequencyInHours {
 		frequencyPerMonth = 24 * averageGregorianDaysInMonth / float64(request.SnapshotOptions.FrequencyInHours)
 	}
+	if isSnapshotStrategy(request.Strategy) && request.ScheduleOptions != nil && request.ScheduleOptions.Cron != "" {
+		frequencyPerMonth = cronActivationsPerMonth(request.ScheduleOptions, averageGregorianDaysInMonth)
+	}
 
 	var costFraction float64
 	for _, period := range periods {
@@ -289,3 +440,22 @@ func (c *baseCalculator) calculateCosts(request *requestobjects.CalculateRequest
 	}
 	return costs, err
 }
+
+// cronActivationsPerMonth counts the activations of the cron expression within an average month starting now
+func cronActivationsPerMonth(options *requestobjects.ScheduleOptions, averageDaysInMonth float64) float64 {
+	backupSchedule, err := mapScheduleOptions(options).Parse()
+	if err != nil || !backupSchedule.HasCron() {
+		return 1.0
+	}
+	start := time.Now()
+	end := start.Add(time.Duration(averageDaysInMonth * 24 * float64(time.Hour)))
+	activations := 0.0
+	for next, ok := backupSchedule.Next(start); ok && next.Before(end); next, ok = backupSchedule.Next(next) {
+		activations++
+	}
+	return max(activations, 1.0)
+}
+
+func isSnapshotStrategy(strategy string) bool {
+	return repository.Snapshot.EqualTo(strategy) || repository.TableSnapshot.EqualTo(strategy)
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/requestobjects"
 	bq "github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"google.golang.org/api/cloudbilling/v1"
 )
 
@@ -74,6 +76,137 @@ func TestCalculatingProcessor_Process_expectOne(t *testing.T) {
 	}
 }
 
+func TestCalculatingProcessor_Process_ParquetWithSnappy(t *testing.T) {
+	// Given
+	calculateRequest := requestobjects.CalculateRequest{}
+	calculateRequest.Project = "local-account"
+	calculateRequest.TargetOptions = requestobjects.TargetOptions{Region: "europe-west1", StorageClass: "REGIONAL"}
+	calculateRequest.Type = repository.BigQuery.String()
+	calculateRequest.Strategy = repository.Snapshot.String()
+	calculateRequest.BigQueryOptions = requestobjects.BigQueryOptions{Dataset: "Billing", Table: []string{"gcp_billing_export"}, ExportFormat: "parquet", ExportCompression: "snappy"}
+	calculateRequest.SnapshotOptions = requestobjects.SnapshotOptions{LifetimeInDays: 20}
+
+	calculatorContext := givenATestBigQueryCalculatorContext()
+	var oneHundredGigiByteInGB float64 = 100
+	calculatorContext.BigQuery.fGetTable = &bq.Table{Name: "gcp_billing_export", SizeInBytes: oneHundredGigiByteInGB * oneGigiByteInBytes}
+	var pricePerGgiByteInNanos int64 = 17618000
+	calculatorContext.addPriceForStorage(pricePerGgiByteInNanos, 0, calculateRequest.TargetOptions.StorageClass, calculateRequest.TargetOptions.Region)
+	calculator := bigQueryCalculator{bigQueryClient: &calculatorContext.BigQuery, baseCalculator: baseCalculator{billingClient: &calculatorContext.Billing}}
+	// When
+	calculateResponse, err := calculator.calculateCost(context.Background(), &calculateRequest)
+	// Then
+	if err != nil {
+		t.Errorf("calculateCost failed. Err %+v", err)
+	}
+	if len(calculateResponse.Costs) != 1 {
+		t.Errorf("CalculateResponse expected one cost")
+		return
+	}
+	expectedCost := float64(pricePerGgiByteInNanos) * 0.000000001 * float64(calculateRequest.SnapshotOptions.LifetimeInDays) * oneHundredGigiByteInGB * 0.8 * 0.6
+	cost := calculateResponse.Costs[0]
+	if !floatEquals(expectedCost, cost.Cost) {
+		t.Errorf("CalculateResponse expected price to be %f was %f", expectedCost, cost.Cost)
+	}
+}
+
+func TestCalculatingProcessor_Process_TableSnapshot(t *testing.T) {
+	// Given
+	calculateRequest := requestobjects.CalculateRequest{}
+	calculateRequest.Project = "local-account"
+	calculateRequest.TargetOptions = requestobjects.TargetOptions{Region: "europe-west1", StorageClass: "COLDLINE"}
+	calculateRequest.Type = repository.BigQuery.String()
+	calculateRequest.Strategy = repository.TableSnapshot.String()
+	calculateRequest.BigQueryOptions = requestobjects.BigQueryOptions{Dataset: "Billing", Table: []string{"gcp_billing_export"}}
+	calculateRequest.SnapshotOptions = requestobjects.SnapshotOptions{LifetimeInDays: 20}
+
+	calculatorContext := givenATestBigQueryCalculatorContext()
+	var oneHundredGigiByteInGB float64 = 100
+	calculatorContext.BigQuery.fGetTable = &bq.Table{Name: "gcp_billing_export", SizeInBytes: oneHundredGigiByteInGB * oneGigiByteInBytes}
+	var pricePerGgiByteInNanos int64 = 17618000
+	calculatorContext.addPriceForStorage(pricePerGgiByteInNanos, 0, "REGIONAL", calculateRequest.TargetOptions.Region)
+	calculator := bigQueryCalculator{bigQueryClient: &calculatorContext.BigQuery, baseCalculator: baseCalculator{billingClient: &calculatorContext.Billing}}
+	// When
+	calculateResponse, err := calculator.calculateCost(context.Background(), &calculateRequest)
+	// Then
+	if err != nil {
+		t.Errorf("calculateCost failed. Err %+v", err)
+	}
+	if len(calculateResponse.Costs) != 1 {
+		t.Errorf("CalculateResponse expected one cost")
+		return
+	}
+	expectedCost := float64(pricePerGgiByteInNanos) * 0.000000001 * float64(calculateRequest.SnapshotOptions.LifetimeInDays) * oneHundredGigiByteInGB * tableSnapshotChangeRatio
+	cost := calculateResponse.Costs[0]
+	if !floatEquals(expectedCost, cost.Cost) {
+		t.Errorf("CalculateResponse expected price to be %f was %f", expectedCost, cost.Cost)
+	}
+}
+
+func TestCalculatingProcessor_Process_Firestore(t *testing.T) {
+	// Given
+	calculateRequest := requestobjects.CalculateRequest{}
+	calculateRequest.Project = "local-account"
+	calculateRequest.TargetOptions = requestobjects.TargetOptions{Region: "europe-west1", StorageClass: "REGIONAL"}
+	calculateRequest.Type = repository.Firestore.String()
+	calculateRequest.Strategy = repository.Snapshot.String()
+	calculateRequest.SnapshotOptions = requestobjects.SnapshotOptions{LifetimeInDays: 20}
+
+	calculatorContext := givenATestBigQueryCalculatorContext()
+	var tenGigiByteInGB float64 = 10
+	adminClient := &firestore.MockAdminClient{Databases: map[string]int64{"projects/local-account/databases/(default)": int64(tenGigiByteInGB * oneGigiByteInBytes)}}
+	var pricePerGgiByteInNanos int64 = 17618000
+	calculatorContext.addPriceForStorage(pricePerGgiByteInNanos, 0, calculateRequest.TargetOptions.StorageClass, calculateRequest.TargetOptions.Region)
+	calculator := firestoreCalculator{adminClient: adminClient, baseCalculator: baseCalculator{billingClient: &calculatorContext.Billing}}
+	// When
+	calculateResponse, err := calculator.calculateCost(context.Background(), &calculateRequest)
+	// Then
+	if err != nil {
+		t.Errorf("calculateCost failed. Err %+v", err)
+	}
+	if len(calculateResponse.Costs) != 1 {
+		t.Errorf("CalculateResponse expected one cost")
+		return
+	}
+	expectedCost := float64(pricePerGgiByteInNanos) * 0.000000001 * float64(calculateRequest.SnapshotOptions.LifetimeInDays) * tenGigiByteInGB
+	cost := calculateResponse.Costs[0]
+	if !floatEquals(expectedCost, cost.Cost) {
+		t.Errorf("CalculateResponse expected price to be %f was %f", expectedCost, cost.Cost)
+	}
+}
+
+func TestCalculatingProcessor_Process_CloudSQL(t *testing.T) {
+	// Given
+	calculateRequest := requestobjects.CalculateRequest{}
+	calculateRequest.Project = "local-account"
+	calculateRequest.TargetOptions = requestobjects.TargetOptions{Region: "europe-west1", StorageClass: "REGIONAL"}
+	calculateRequest.Type = repository.CloudSQL.String()
+	calculateRequest.Strategy = repository.Snapshot.String()
+	calculateRequest.SnapshotOptions = requestobjects.SnapshotOptions{LifetimeInDays: 20}
+	calculateRequest.CloudSQLOptions = requestobjects.CloudSQLOptions{Instance: "orders-db"}
+
+	calculatorContext := givenATestBigQueryCalculatorContext()
+	var tenGigiByteInGB float64 = 10
+	adminClient := &cloudsql.MockAdminClient{Instances: map[string]*cloudsql.MockInstance{"local-account:orders-db": {SizeInBytes: int64(tenGigiByteInGB * oneGigiByteInBytes)}}}
+	var pricePerGgiByteInNanos int64 = 17618000
+	calculatorContext.addPriceForStorage(pricePerGgiByteInNanos, 0, calculateRequest.TargetOptions.StorageClass, calculateRequest.TargetOptions.Region)
+	calculator := cloudSQLCalculator{adminClient: adminClient, baseCalculator: baseCalculator{billingClient: &calculatorContext.Billing}}
+	// When
+	calculateResponse, err := calculator.calculateCost(context.Background(), &calculateRequest)
+	// Then
+	if err != nil {
+		t.Errorf("calculateCost failed. Err %+v", err)
+	}
+	if len(calculateResponse.Costs) != 1 {
+		t.Errorf("CalculateResponse expected one cost")
+		return
+	}
+	expectedCost := float64(pricePerGgiByteInNanos) * 0.000000001 * float64(calculateRequest.SnapshotOptions.LifetimeInDays) * tenGigiByteInGB
+	cost := calculateResponse.Costs[0]
+	if !floatEquals(expectedCost, cost.Cost) {
+		t.Errorf("CalculateResponse expected price to be %f was %f", expectedCost, cost.Cost)
+	}
+}
+
 type testBillingClient struct {
 	SKU map[string]*cloudbilling.Sku
 	Err error
@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"go.opencensus.io/trace"
+)
+
+// CloudSQLJobCreator will create exports of the databases of a Cloud SQL instance
+type CloudSQLJobCreator struct {
+	backupRepository repository.BackupRepository
+	jobRepository    repository.JobRepository
+	adminClient      cloudsql.AdminClient
+}
+
+// NewCloudSQLJobCreator return instance of CloudSQLJobCreator
+func NewCloudSQLJobCreator(ctxIn context.Context, backupRepository repository.BackupRepository, jobRepository repository.JobRepository, adminClient cloudsql.AdminClient) *CloudSQLJobCreator {
+	_, span := trace.StartSpan(ctxIn, "NewCloudSQLJobCreator")
+	defer span.End()
+
+	return &CloudSQLJobCreator{
+		backupRepository: backupRepository,
+		jobRepository:    jobRepository,
+		adminClient:      adminClient,
+	}
+}
+
+// PrepareJobs for CloudSQL backup, every job exports one database of the instance
+func (c *CloudSQLJobCreator) PrepareJobs(ctxIn context.Context, backup *repository.Backup) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*CloudSQLJobCreator).PrepareJobs")
+	defer span.End()
+
+	if repository.Snapshot != backup.Strategy {
+		return fmt.Errorf("unsupported strategy %s", backup.Strategy)
+	}
+
+	exists, err := c.adminClient.DoesInstanceExist(ctx, backup.SourceProject, backup.CloudSQLOptions.Instance)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return BackupSourceNotFoundErr
+	}
+
+	databases := backup.CloudSQLOptions.Databases
+	if len(databases) == 0 {
+		databases, err = c.adminClient.ListDatabases(ctx, backup.SourceProject, backup.CloudSQLOptions.Instance)
+		if err != nil {
+			return err
+		}
+	}
+
+	var jobs []*repository.Job
+	for _, database := range databases {
+		jobs = append(jobs, &repository.Job{
+			ID:       generateNewID(),
+			BackupID: backup.ID,
+			Status:   repository.NotScheduled,
+			Source:   database,
+			Type:     repository.CloudSQL,
+		})
+	}
+
+	if len(jobs) > 0 {
+		err = c.jobRepository.AddJobs(ctx, jobs)
+	}
+	if err == nil {
+		err = c.backupRepository.UpdateLastScheduledTime(ctx, backup.ID, time.Now(), repository.Prepared)
+	}
+
+	return err
+}
@@ -33,21 +33,52 @@ func NewCloudStorageJobCreator(ctxIn context.Context, backupRepository repositor
 
 // PrepareJobs for GCS backup
 func (b *CloudStorageJobCreator) PrepareJobs(ctxIn context.Context, backup *repository.Backup) error {
-	ctx, span := trace.StartSpan(ctxIn, "(*CloudStorageJobCreator).PrepareJobs")
+	_, err := b.PrepareJobsForPrefixes(ctxIn, backup, nil)
+	return err
+}
+
+// PrepareJobsForPrefixes prepares a transfer job right away and returns it
+// without prefixes the job copies the backup like a scheduled run, with prefixes the job only copies them and leaves the schedule of the backup unchanged
+func (b *CloudStorageJobCreator) PrepareJobsForPrefixes(ctxIn context.Context, backup *repository.Backup, prefixes []string) ([]*repository.Job, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*CloudStorageJobCreator).PrepareJobsForPrefixes")
 	defer span.End()
 
 	var bucketExists, _ = b.gcsClient.DoesBucketExist(ctx, backup.SourceProject, backup.Bucket)
 	if !bucketExists {
-		return BucketNotFound
+		return nil, BucketNotFound
+	}
+
+	if repository.Mirror != backup.Strategy && repository.Snapshot != backup.Strategy {
+		return nil, fmt.Errorf("unsupported strategy %s", backup.Strategy)
+	}
+	if len(prefixes) > 0 {
+		return b.preparePrefixJobs(ctx, backup, prefixes)
+	}
+	return b.prepareSnapshotJobs(ctx, backup)
+}
+
+// preparePrefixJobs create a new transfer job which only copies the given prefixes, it never reuses a previous transfer job
+func (b *CloudStorageJobCreator) preparePrefixJobs(ctxIn context.Context, backup *repository.Backup, prefixes []string) ([]*repository.Job, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*CloudStorageJobCreator).preparePrefixJobs")
+	defer span.End()
+
+	job := &repository.Job{
+		ID:              generateNewID(),
+		BackupID:        backup.ID,
+		Status:          repository.NotScheduled,
+		Source:          backup.CloudStorageOptions.Bucket,
+		Type:            repository.CloudStorage,
+		IncludePrefixes: prefixes,
 	}
 
-	if repository.Mirror == backup.Strategy || repository.Snapshot == backup.Strategy {
-		return b.prepareSnapshotJobs(ctx, backup)
+	err := b.jobRepository.AddJob(ctx, job)
+	if err != nil {
+		return nil, err
 	}
-	return fmt.Errorf("unsupported strategy %s", backup.Strategy)
+	return []*repository.Job{job}, nil
 }
 
-func (b *CloudStorageJobCreator) prepareSnapshotJobs(ctxIn context.Context, backup *repository.Backup) error {
+func (b *CloudStorageJobCreator) prepareSnapshotJobs(ctxIn context.Context, backup *repository.Backup) ([]*repository.Job, error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*CloudStorageJobCreator).prepareSnapshotJobs")
 	defer span.End()
 
@@ -67,9 +98,9 @@ func (b *CloudStorageJobCreator) prepareSnapshotJobs(ctxIn context.Context, back
 	}
 
 	err := b.jobRepository.AddJob(ctx, job)
-	if err == nil {
-		err = b.backupRepository.UpdateLastScheduledTime(ctx, backup.ID, time.Now(), repository.Prepared)
+	if err != nil {
+		return nil, err
 	}
 
-	return err
+	return []*repository.Job{job}, b.backupRepository.UpdateLastScheduledTime(ctx, backup.ID, time.Now(), repository.Prepared)
 }
@@ -3,6 +3,7 @@ package processor
 import (
 	iam "cloud.google.com/go/iam/apiv2"
 	"cloud.google.com/go/iam/apiv2/iampb"
+	"cloud.google.com/go/storage"
 	"context"
 	"errors"
 	"fmt"
@@ -14,6 +15,8 @@ import (
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/requestobjects"
 	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"go.opencensus.io/trace"
 	gimpersonate "google.golang.org/api/impersonate"
@@ -32,12 +35,14 @@ type ComplianceProcessorFactory interface {
 type complianceProcessorFactory struct {
 	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
 	backupProvider      provider.SinkGCPProjectProvider
+	sinkKMSKeyProvider  provider.SinkKMSKeyProvider
 }
 
-func NewComplianceProcessorFactory(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, backupProvider provider.SinkGCPProjectProvider) ComplianceProcessorFactory {
+func NewComplianceProcessorFactory(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, backupProvider provider.SinkGCPProjectProvider, sinkKMSKeyProvider provider.SinkKMSKeyProvider) ComplianceProcessorFactory {
 	return &complianceProcessorFactory{
 		tokenSourceProvider: tokenSourceProvider,
 		backupProvider:      backupProvider,
+		sinkKMSKeyProvider:  sinkKMSKeyProvider,
 	}
 }
 
@@ -52,7 +57,11 @@ func (c complianceProcessorFactory) CreateProcessor(ctxIn context.Context) (Oper
 				tokenSourceProvider: c.tokenSourceProvider,
 				backupProvider:      c.backupProvider,
 			},
-			&backupEncryptionCheck{},
+			&backupEncryptionCheck{
+				tokenSourceProvider: c.tokenSourceProvider,
+				backupProvider:      c.backupProvider,
+				sinkKMSKeyProvider:  c.sinkKMSKeyProvider,
+			},
 			&backupProjectCheck{
 				backupProvider: c.backupProvider,
 			},
@@ -72,6 +81,31 @@ type ComplianceCheck interface {
 	Check(ctx context.Context, request requestobjects.ComplianceRequest) (requestobjects.ComplianceCheck, error)
 }
 
+// SinkProjectCheck checks a sink project independent of the backups written into it
+type SinkProjectCheck interface {
+	CheckSinkProject(ctx context.Context, targetProject string) (requestobjects.ComplianceCheck, error)
+}
+
+// NewSinkProjectChecks returns the checks which make sure a sink project keeps its backups immutable
+func NewSinkProjectChecks(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider) []SinkProjectCheck {
+	return []SinkProjectCheck{
+		&backupOnlySinkProjectCheck{tokenSourceProvider: tokenSourceProvider},
+		&backupWithSingleWriterCheck{tokenSourceProvider: tokenSourceProvider},
+	}
+}
+
+// SinkBucketCheck checks the sink bucket of a single backup
+type SinkBucketCheck interface {
+	CheckSinkBucket(ctx context.Context, backup *repository.Backup) (requestobjects.ComplianceCheck, error)
+}
+
+// NewSinkBucketChecks returns the checks which make sure the sink bucket of a backup keeps its objects immutable
+func NewSinkBucketChecks(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider) []SinkBucketCheck {
+	return []SinkBucketCheck{
+		&backupRetentionCheck{tokenSourceProvider: tokenSourceProvider},
+	}
+}
+
 type complianceProcessor struct {
 	checks []ComplianceCheck
 }
@@ -132,6 +166,33 @@ func (c *backupLocationCheck) Check(ctx context.Context, request requestobjects.
 			return requestobjects.ComplianceCheck{}, err
 		}
 		sourceRegion = details.Location
+	} else if repository.Firestore.EqualTo(request.Type) {
+		adminClient, err := firestore.NewAdminClient(ctx, c.tokenSourceProvider, targetProject)
+		if err != nil {
+			return requestobjects.ComplianceCheck{}, err
+		}
+		defer adminClient.Close(ctx)
+
+		database := request.FirestoreOptions.Database
+		if database == "" {
+			database = repository.FirestoreDefaultDatabase
+		}
+		sourceRegion, err = adminClient.GetDatabaseLocation(ctx, request.Project, database)
+		if err != nil {
+			return requestobjects.ComplianceCheck{}, err
+		}
+	} else if repository.CloudSQL.EqualTo(request.Type) {
+		adminClient, err := cloudsql.NewAdminClient(ctx, c.tokenSourceProvider, targetProject)
+		if err != nil {
+			return requestobjects.ComplianceCheck{}, err
+		}
+		defer adminClient.Close(ctx)
+
+		instance, err := adminClient.GetInstance(ctx, request.Project, request.CloudSQLOptions.Instance)
+		if err != nil {
+			return requestobjects.ComplianceCheck{}, err
+		}
+		sourceRegion = instance.Region
 	} else {
 		return requestobjects.ComplianceCheck{}, fmt.Errorf("unknown request type `%s` for check backup location", request.Type)
 	}
@@ -178,14 +239,133 @@ func (c *backupProjectCheck) Check(ctx context.Context, request requestobjects.C
 }
 
 type backupEncryptionCheck struct {
+	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
+	backupProvider      provider.SinkGCPProjectProvider
+	sinkKMSKeyProvider  provider.SinkKMSKeyProvider
 }
 
+// Check passes if the backup is encrypted with a customer-managed key or if the source is not, a source encrypted with a
+// customer-managed key must not be copied into a backup with Google-managed encryption
 func (c *backupEncryptionCheck) Check(ctx context.Context, request requestobjects.ComplianceRequest) (requestobjects.ComplianceCheck, error) {
-	return requestobjects.ComplianceCheck{
-		Field:       "request.Project",
-		Passed:      true, // Google Cloud Storage Buckets are encrypted by default
+	targetProject, err := c.backupProvider.GetSinkGCPProjectID(ctx, request.Project)
+	if err != nil {
+		return requestobjects.ComplianceCheck{}, err
+	}
+
+	kmsKeyName, err := resolveKMSKeyName(ctx, c.sinkKMSKeyProvider, request.TargetOptions.KMSKeyName, targetProject)
+	if err != nil {
+		return requestobjects.ComplianceCheck{}, err
+	}
+
+	sourceKMSKeyName, err := c.sourceKMSKeyName(ctx, request, targetProject)
+	if err != nil {
+		return requestobjects.ComplianceCheck{}, err
+	}
+
+	return evaluateEncryption(kmsKeyName, sourceKMSKeyName), nil
+}
+
+// sourceKMSKeyName returns the default KMS key of the source bucket or dataset, empty for Google-managed encryption
+func (c *backupEncryptionCheck) sourceKMSKeyName(ctx context.Context, request requestobjects.ComplianceRequest, targetProject string) (string, error) {
+	if repository.CloudStorage.EqualTo(request.Type) {
+		storageClient, err := gcs.NewCloudStorageClient(ctx, c.tokenSourceProvider, targetProject)
+		if err != nil {
+			return "", err
+		}
+		defer storageClient.Close(ctx)
+
+		details, err := storageClient.GetBucketDetails(ctx, request.GCSOptions.Bucket)
+		if err != nil {
+			return "", err
+		}
+		if details.Encryption == nil {
+			return "", nil
+		}
+		return details.Encryption.DefaultKMSKeyName, nil
+	}
+	if repository.BigQuery.EqualTo(request.Type) {
+		bigQueryClient, err := bigquery.NewBigQueryClient(ctx, c.tokenSourceProvider, request.Project, targetProject)
+		if err != nil {
+			return "", err
+		}
+		details, err := bigQueryClient.GetDatasetDetails(ctx, request.Project, request.BigQueryOptions.Dataset)
+		if err != nil {
+			return "", err
+		}
+		if details.DefaultEncryptionConfig == nil {
+			return "", nil
+		}
+		return details.DefaultEncryptionConfig.KMSKeyName, nil
+	}
+	return "", nil
+}
+
+func evaluateEncryption(kmsKeyName, sourceKMSKeyName string) requestobjects.ComplianceCheck {
+	result := requestobjects.ComplianceCheck{
+		Field:       "target.kms_key_name",
+		Passed:      true,
 		Description: "Backup should be encrypted",
-	}, nil
+	}
+	switch {
+	case kmsKeyName != "":
+		result.Details = fmt.Sprintf("Backup is encrypted with customer-managed key %s", kmsKeyName)
+	case sourceKMSKeyName != "":
+		result.Passed = false
+		result.Details = fmt.Sprintf("Source is encrypted with customer-managed key %s but backup would be encrypted with a Google-managed key", sourceKMSKeyName)
+	default:
+		result.Details = "Backup is encrypted with a Google-managed key"
+	}
+	return result
+}
+
+type backupRetentionCheck struct {
+	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
+}
+
+// CheckSinkBucket passes if the sink bucket of an immutable backup has a locked retention policy of the backup lifetime,
+// backups which are not immutable are not checked
+func (c *backupRetentionCheck) CheckSinkBucket(ctx context.Context, backup *repository.Backup) (requestobjects.ComplianceCheck, error) {
+	if backup.RetentionInDays() == 0 {
+		return requestobjects.ComplianceCheck{
+			Field:       "target.immutable",
+			Passed:      true,
+			Description: "Backup is not immutable",
+		}, nil
+	}
+
+	storageClient, err := gcs.NewCloudStorageClient(ctx, c.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return requestobjects.ComplianceCheck{}, err
+	}
+	defer storageClient.Close(ctx)
+
+	details, err := storageClient.GetBucketDetails(ctx, backup.Sink)
+	if err != nil {
+		return requestobjects.ComplianceCheck{}, err
+	}
+
+	return evaluateRetention(backup, details.RetentionPolicy), nil
+}
+
+func evaluateRetention(backup *repository.Backup, policy *storage.RetentionPolicy) requestobjects.ComplianceCheck {
+	retentionInDays := backup.RetentionInDays()
+	result := requestobjects.ComplianceCheck{
+		Field:       "target.immutable",
+		Passed:      false,
+		Description: "Immutable backup should have a locked retention policy of its lifetime",
+	}
+	switch {
+	case policy == nil:
+		result.Details = fmt.Sprintf("Sink bucket %s has no retention policy", backup.Sink)
+	case policy.RetentionPeriod < gcs.RetentionPeriod(retentionInDays):
+		result.Details = fmt.Sprintf("Sink bucket %s retains objects for %.0f days instead of %d days", backup.Sink, policy.RetentionPeriod.Hours()/24, retentionInDays)
+	case !policy.IsLocked:
+		result.Details = fmt.Sprintf("Retention policy of sink bucket %s is not locked", backup.Sink)
+	default:
+		result.Passed = true
+		result.Details = fmt.Sprintf("Sink bucket %s retains objects for %d days with a locked retention policy", backup.Sink, retentionInDays)
+	}
+	return result
 }
 
 var allowedServices = []string{
@@ -205,6 +385,10 @@ func (c *backupOnlySinkProjectCheck) Check(ctx context.Context, request requesto
 		return requestobjects.ComplianceCheck{}, err
 	}
 
+	return c.CheckSinkProject(ctx, targetProject)
+}
+
+func (c *backupOnlySinkProjectCheck) CheckSinkProject(ctx context.Context, targetProject string) (requestobjects.ComplianceCheck, error) {
 	targetPrincipal, delegates, err := c.tokenSourceProvider.GetTargetPrincipalForProject(ctx, targetProject)
 	if err != nil {
 		return requestobjects.ComplianceCheck{}, fmt.Errorf("could not get target principal for project %s: %s", targetProject, err)
@@ -257,6 +441,7 @@ func (c *backupOnlySinkProjectCheck) Check(ctx context.Context, request requesto
 			Field:       "request.Target",
 			Passed:      false,
 			Description: "Backup project should have only allowed services enabled",
+			Details:     fmt.Sprintf("Not allowed services are enabled: %s", strings.Join(invalidServices, ", ")),
 		}, nil
 	}
 
@@ -287,6 +472,10 @@ func (c *backupWithSingleWriterCheck) Check(ctx context.Context, request request
 		return requestobjects.ComplianceCheck{}, err
 	}
 
+	return c.CheckSinkProject(ctx, targetProject)
+}
+
+func (c *backupWithSingleWriterCheck) CheckSinkProject(ctx context.Context, targetProject string) (requestobjects.ComplianceCheck, error) {
 	compliant := false
 
 	targetPrincipal, delegates, err := c.tokenSourceProvider.GetTargetPrincipalForProject(ctx, targetProject)
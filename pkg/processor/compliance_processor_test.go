@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKMSKeyName = "projects/local-kms/locations/europe-west3/keyRings/backups/cryptoKeys/local-account"
+
+type fakeSinkKMSKeyProvider map[string]string
+
+func (f fakeSinkKMSKeyProvider) GetSinkKMSKeyName(_ context.Context, sinkGCPProjectID string) (string, error) {
+	if sinkGCPProjectID == "broken-backup" {
+		return "", errors.New("provider file not found")
+	}
+	return f[sinkGCPProjectID], nil
+}
+
+func TestResolveKMSKeyName(t *testing.T) {
+	kmsKeyProvider := fakeSinkKMSKeyProvider{"local-account-backup": testKMSKeyName}
+
+	kmsKeyName, err := resolveKMSKeyName(context.Background(), kmsKeyProvider, "", "local-account-backup")
+	require.NoError(t, err)
+	assert.Equal(t, testKMSKeyName, kmsKeyName, "the key of the sink project is used")
+
+	requested := "projects/local-kms/locations/europe-west3/keyRings/backups/cryptoKeys/regulated"
+	kmsKeyName, err = resolveKMSKeyName(context.Background(), kmsKeyProvider, requested, "local-account-backup")
+	require.NoError(t, err)
+	assert.Equal(t, requested, kmsKeyName, "the key of the request overrides the key of the sink project")
+
+	_, err = resolveKMSKeyName(context.Background(), kmsKeyProvider, "regulated", "local-account-backup")
+	assert.Error(t, err, "a key has to be given by its resource name")
+
+	_, err = resolveKMSKeyName(context.Background(), kmsKeyProvider, "", "broken-backup")
+	assert.Error(t, err)
+
+	kmsKeyName, err = resolveKMSKeyName(context.Background(), nil, "", "local-account-backup")
+	require.NoError(t, err)
+	assert.Empty(t, kmsKeyName)
+}
+
+func TestEvaluateEncryption(t *testing.T) {
+	check := evaluateEncryption(testKMSKeyName, "")
+	assert.True(t, check.Passed)
+	assert.Contains(t, check.Details, testKMSKeyName)
+
+	check = evaluateEncryption("", testKMSKeyName)
+	assert.False(t, check.Passed, "a source with customer-managed key requires a backup with customer-managed key")
+
+	check = evaluateEncryption("", "")
+	assert.True(t, check.Passed)
+	assert.Equal(t, "Backup is encrypted with a Google-managed key", check.Details)
+}
+
+func TestEvaluateRetention(t *testing.T) {
+	backup := newImmutableBackup("immutable", 30)
+
+	result := evaluateRetention(backup, nil)
+	assert.False(t, result.Passed)
+	assert.Contains(t, result.Details, "no retention policy")
+
+	result = evaluateRetention(backup, &storage.RetentionPolicy{RetentionPeriod: gcs.RetentionPeriod(7), IsLocked: true})
+	assert.False(t, result.Passed)
+	assert.Contains(t, result.Details, "7 days instead of 30 days")
+
+	result = evaluateRetention(backup, &storage.RetentionPolicy{RetentionPeriod: gcs.RetentionPeriod(30)})
+	assert.False(t, result.Passed)
+	assert.Contains(t, result.Details, "not locked")
+
+	result = evaluateRetention(backup, &storage.RetentionPolicy{RetentionPeriod: gcs.RetentionPeriod(30), IsLocked: true})
+	assert.True(t, result.Passed)
+
+	result = evaluateRetention(backup, &storage.RetentionPolicy{RetentionPeriod: gcs.RetentionPeriod(60), IsLocked: true})
+	assert.True(t, result.Passed, "a longer retention keeps the backup immutable")
+}
@@ -3,6 +3,7 @@ package processor
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,6 +19,8 @@ import (
 	"github.com/ottogroup/penelope/pkg/requestobjects"
 	"github.com/ottogroup/penelope/pkg/secret"
 	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
@@ -25,6 +28,9 @@ import (
 
 const sinkSTSAccountScheme = "project-%s@storage-transfer-service.iam.gserviceaccount.com"
 
+// sourceFirestoreAccountScheme is the service agent running managed exports of a source project
+const sourceFirestoreAccountScheme = "service-%s@gcp-sa-firestore.iam.gserviceaccount.com"
+
 type CreatingProcessorFactory interface {
 	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.CreateRequest, requestobjects.BackupResponse], error)
 }
@@ -35,14 +41,21 @@ type creatingProcessorFactory struct {
 	tokenSourceProvider      impersonate.TargetPrincipalForProjectProvider
 	credentialsProvider      secret.SecretProvider
 	sourceGCPProjectProvider provider.SourceGCPProjectProvider
+	sinkKMSKeyProvider       provider.SinkKMSKeyProvider
+	policyRuleProvider       provider.PolicyRuleProvider
 }
 
-func NewCreatingProcessorFactory(backupProvider provider.SinkGCPProjectProvider, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider) CreatingProcessorFactory {
+// NewCreatingProcessorFactory create a new factory, sinkKMSKeyProvider is optional and without it only backups with a
+// KMS key in the request are encrypted with a customer-managed key, policyRuleProvider is optional and without it no
+// policy rules are enforced
+func NewCreatingProcessorFactory(backupProvider provider.SinkGCPProjectProvider, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider, sinkKMSKeyProvider provider.SinkKMSKeyProvider, policyRuleProvider provider.PolicyRuleProvider) CreatingProcessorFactory {
 	return &creatingProcessorFactory{
 		backupProvider:           backupProvider,
 		tokenSourceProvider:      tokenSourceProvider,
 		credentialsProvider:      credentialsProvider,
 		sourceGCPProjectProvider: sourceGCPProjectProvider,
+		sinkKMSKeyProvider:       sinkKMSKeyProvider,
+		policyRuleProvider:       policyRuleProvider,
 	}
 }
 
@@ -68,6 +81,8 @@ func (c *creatingProcessorFactory) CreateProcessor(ctxIn context.Context) (Opera
 		backupProvider:           c.backupProvider,
 		tokenSourceProvider:      c.tokenSourceProvider,
 		sourceGCPProjectProvider: c.sourceGCPProjectProvider,
+		sinkKMSKeyProvider:       c.sinkKMSKeyProvider,
+		policyRuleProvider:       c.policyRuleProvider,
 	}, nil
 }
 
@@ -77,6 +92,8 @@ type creatingProcessor struct {
 	backupProvider           provider.SinkGCPProjectProvider
 	sourceGCPProjectProvider provider.SourceGCPProjectProvider
 	tokenSourceProvider      impersonate.TargetPrincipalForProjectProvider
+	sinkKMSKeyProvider       provider.SinkKMSKeyProvider
+	policyRuleProvider       provider.PolicyRuleProvider
 }
 
 func (b *creatingProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.CreateRequest]) (requestobjects.BackupResponse, error) {
@@ -89,10 +106,19 @@ func (b *creatingProcessor) Process(ctxIn context.Context, args *Argument[reques
 		return requestobjects.BackupResponse{}, fmt.Errorf("%s is not allowed for user %q on project %q", requestobjects.Creating.String(), args.Principal.User.Email, request.Project)
 	}
 
+	sourceGCPProject, err := b.sourceGCPProjectProvider.GetSourceGCPProject(ctx, request.Project)
+	if err != nil {
+		return requestobjects.BackupResponse{}, err
+	}
+
 	backup, err := b.prepareBackupFromRequest(ctx, request)
 	if err != nil {
 		return requestobjects.BackupResponse{}, err
 	}
+	policyWarnings, err := checkBackupPolicies(ctx, b.policyRuleProvider, backup, nil, sourceGCPProject.AvailabilityClass)
+	if err != nil {
+		return requestobjects.BackupResponse{}, err
+	}
 	var impl creatingProcessorImpl
 	if repository.BigQuery.EqualTo(request.Type) {
 		impl, err = b.createBigQueryImpl(ctx, request)
@@ -106,17 +132,27 @@ func (b *creatingProcessor) Process(ctxIn context.Context, args *Argument[reques
 			return requestobjects.BackupResponse{}, err
 		}
 	}
+	if repository.Firestore.EqualTo(request.Type) {
+		impl, err = b.createFirestoreImpl(ctx, request)
+		if err != nil {
+			return requestobjects.BackupResponse{}, err
+		}
+	}
+	if repository.CloudSQL.EqualTo(request.Type) {
+		impl, err = b.createCloudSQLImpl(ctx, request)
+		if err != nil {
+			return requestobjects.BackupResponse{}, err
+		}
+	}
 	defer impl.close(ctx)
 
 	processedBackup, err := impl.process(ctx, backup)
 	if err != nil {
 		return requestobjects.BackupResponse{}, err
 	}
-	sourceGCPProject, err := b.sourceGCPProjectProvider.GetSourceGCPProject(ctx, request.Project)
-	if err != nil {
-		return requestobjects.BackupResponse{}, err
-	}
-	return mapBackupToResponse(processedBackup, nil, sourceGCPProject), nil
+	response := mapBackupToResponse(processedBackup, nil, sourceGCPProject)
+	response.PolicyWarnings = policyWarnings
+	return response, nil
 }
 
 func (b *creatingProcessor) prepareBackupFromRequest(ctxIn context.Context, request requestobjects.CreateRequest) (*repository.Backup, error) {
@@ -145,6 +181,19 @@ func (b *creatingProcessor) prepareBackupFromRequest(ctxIn context.Context, requ
 		}
 	}
 
+	if repository.TableSnapshot.EqualTo(strategy) && !repository.BigQuery.EqualTo(request.Type) {
+		return nil, fmt.Errorf("%s strategy is only supported for bigquery backups", repository.TableSnapshot)
+	}
+	if repository.TableSnapshot.EqualTo(strategy) && request.SnapshotOptions.LifetimeInDays == 0 {
+		return nil, fmt.Errorf("%s strategy requires a lifetime in days to expire the table snapshots", repository.TableSnapshot)
+	}
+	if err := ValidateScheduleOptions(strategy, request.SnapshotOptions.FrequencyInHours, request.ScheduleOptions); err != nil {
+		return nil, err
+	}
+	if err := ValidateImmutability(request); err != nil {
+		return nil, err
+	}
+
 	region := request.TargetOptions.Region
 	for _, r := range Regions {
 		if strings.EqualFold(r.String(), region) {
@@ -167,16 +216,46 @@ func (b *creatingProcessor) prepareBackupFromRequest(ctxIn context.Context, requ
 		return nil, err
 	}
 
-	if !(repository.BigQuery.EqualTo(request.Type) || repository.CloudStorage.EqualTo(request.Type)) {
+	if !(repository.BigQuery.EqualTo(request.Type) || repository.CloudStorage.EqualTo(request.Type) || repository.Firestore.EqualTo(request.Type) || repository.CloudSQL.EqualTo(request.Type)) {
 		return nil, fmt.Errorf("can not process request for type %s", request.Type)
 	}
+	kmsKeyName, err := resolveKMSKeyName(ctx, b.sinkKMSKeyProvider, request.TargetOptions.KMSKeyName, targetProject)
+	if err != nil {
+		return nil, err
+	}
 	var suffix string
 	if repository.CloudStorage.EqualTo(request.Type) {
 		suffix = "gcs"
 	}
 	if repository.BigQuery.EqualTo(request.Type) {
 		suffix = "bq"
+	}
+	if repository.Firestore.EqualTo(request.Type) {
+		suffix = "fs"
+	}
+	if repository.CloudSQL.EqualTo(request.Type) {
+		suffix = "sql"
 	} // little smell
+	database := request.FirestoreOptions.Database
+	if database == "" {
+		database = repository.FirestoreDefaultDatabase
+	}
+	fileType := repository.CloudSQLFileType(request.CloudSQLOptions.FileType)
+	if fileType == "" {
+		fileType = repository.CloudSQLExportSQL
+	}
+	var exportFormat repository.ExportFormat
+	var exportCompression repository.ExportCompression
+	if repository.BigQuery.EqualTo(request.Type) {
+		bigQueryOptions := repository.BigQueryOptions{
+			ExportFormat:      repository.ExportFormat(strings.ToUpper(request.BigQueryOptions.ExportFormat)),
+			ExportCompression: repository.ExportCompression(strings.ToUpper(request.BigQueryOptions.ExportCompression)),
+		}
+		exportFormat, exportCompression = bigQueryOptions.GetExportFormat(), bigQueryOptions.GetExportCompression()
+		if !repository.IsSupportedExportFormat(exportFormat, exportCompression) {
+			return nil, fmt.Errorf("export format %s does not support compression %s", exportFormat, exportCompression)
+		}
+	}
 	sinkName := fmt.Sprintf("bkp_%s_%s", suffix, id)
 	backup := repository.Backup{
 		ID:            id,
@@ -192,36 +271,71 @@ func (b *creatingProcessor) prepareBackupFromRequest(ctxIn context.Context, requ
 			Sink:          sinkName,
 			StorageClass:  storageClass,
 			ArchiveTTM:    request.TargetOptions.ArchiveTTM,
+			KMSKeyName:    kmsKeyName,
+			Immutable:     request.TargetOptions.Immutable,
 		},
 		SnapshotOptions: repository.SnapshotOptions{
 			LifetimeInDays:   request.SnapshotOptions.LifetimeInDays,
 			FrequencyInHours: request.SnapshotOptions.FrequencyInHours,
 		},
+		ScheduleOptions: mapScheduleOptions(request.ScheduleOptions),
 		MirrorOptions: repository.MirrorOptions{
 			LifetimeInDays: request.MirrorOptions.LifetimeInDays,
 		},
 		BackupOptions: repository.BackupOptions{
 			BigQueryOptions: repository.BigQueryOptions{
-				Dataset:        request.BigQueryOptions.Dataset,
-				Table:          request.BigQueryOptions.Table,
-				ExcludedTables: request.BigQueryOptions.ExcludedTables,
+				Dataset:           request.BigQueryOptions.Dataset,
+				Table:             request.BigQueryOptions.Table,
+				ExcludedTables:    request.BigQueryOptions.ExcludedTables,
+				ExportFormat:      exportFormat,
+				ExportCompression: exportCompression,
 			},
 			CloudStorageOptions: repository.CloudStorageOptions{
 				Bucket:      request.GCSOptions.Bucket,
 				ExcludePath: normalizePath(request.GCSOptions.ExcludePath),
 				IncludePath: normalizePath(request.GCSOptions.IncludePath),
 			},
+			FirestoreOptions: repository.FirestoreOptions{
+				Database:      database,
+				CollectionIDs: request.FirestoreOptions.CollectionIDs,
+			},
+			CloudSQLOptions: repository.CloudSQLOptions{
+				Instance:  request.CloudSQLOptions.Instance,
+				Databases: request.CloudSQLOptions.Databases,
+				FileType:  fileType,
+				CSVQuery:  request.CloudSQLOptions.CSVQuery,
+			},
 		},
 		EntityAudit: repository.EntityAudit{
 			CreatedTimestamp: time.Now(),
 		},
 		RecoveryPointObjective: request.RecoveryPointObjective,
 		RecoveryTimeObjective:  request.RecoveryTimeObjective,
+		MaxRetryAttempts:       request.MaxRetryAttempts,
 	}
 
 	return &backup, nil
 }
 
+// resolveKMSKeyName returns the KMS key of the request or else the KMS key of the sink project,
+// an empty key means Google-managed encryption
+func resolveKMSKeyName(ctxIn context.Context, sinkKMSKeyProvider provider.SinkKMSKeyProvider, requestedKMSKeyName, targetProject string) (string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "resolveKMSKeyName")
+	defer span.End()
+
+	if requestedKMSKeyName != "" {
+		return requestedKMSKeyName, provider.ValidateKMSKeyName(requestedKMSKeyName)
+	}
+	if sinkKMSKeyProvider == nil {
+		return "", nil
+	}
+	kmsKeyName, err := sinkKMSKeyProvider.GetSinkKMSKeyName(ctx, targetProject)
+	if err != nil {
+		return "", fmt.Errorf("could not get KMS key of sink project %s: %s", targetProject, err)
+	}
+	return kmsKeyName, nil
+}
+
 func normalizePath(pathList []string) []string {
 	var updatedPathList []string
 
@@ -288,6 +402,62 @@ func (b *creatingProcessor) createCloudStorageImpl(ctxIn context.Context, reques
 	return cloudStorageProcessor, nil
 }
 
+func (b *creatingProcessor) createFirestoreImpl(ctxIn context.Context, request requestobjects.CreateRequest) (creatingProcessorImpl, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*creatingProcessor).createFirestoreImpl")
+	defer span.End()
+
+	targetProject, err := b.backupProvider.GetSinkGCPProjectID(ctx, request.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	adminClient, err := firestore.NewAdminClient(ctx, b.tokenSourceProvider, targetProject)
+	if err != nil {
+		return nil, err
+	}
+
+	gcsClient, err := gcs.NewCloudStorageClient(ctx, b.tokenSourceProvider, targetProject)
+	if err != nil {
+		adminClient.Close(ctx)
+		return nil, err
+	}
+
+	firestoreProcessor := &firestoreProcessorImpl{
+		BackupRepository: b.BackupRepository,
+		Firestore:        adminClient,
+		CloudStorage:     gcsClient,
+	}
+	return firestoreProcessor, nil
+}
+
+func (b *creatingProcessor) createCloudSQLImpl(ctxIn context.Context, request requestobjects.CreateRequest) (creatingProcessorImpl, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*creatingProcessor).createCloudSQLImpl")
+	defer span.End()
+
+	targetProject, err := b.backupProvider.GetSinkGCPProjectID(ctx, request.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	adminClient, err := cloudsql.NewAdminClient(ctx, b.tokenSourceProvider, targetProject)
+	if err != nil {
+		return nil, err
+	}
+
+	gcsClient, err := gcs.NewCloudStorageClient(ctx, b.tokenSourceProvider, targetProject)
+	if err != nil {
+		adminClient.Close(ctx)
+		return nil, err
+	}
+
+	cloudSQLProcessor := &cloudSQLProcessorImpl{
+		BackupRepository: b.BackupRepository,
+		CloudSQL:         adminClient,
+		CloudStorage:     gcsClient,
+	}
+	return cloudSQLProcessor, nil
+}
+
 type creatingProcessorImpl interface {
 	process(ctxIn context.Context, backup *repository.Backup) (*repository.Backup, error)
 	close(context.Context)
@@ -305,6 +475,18 @@ type cloudStorageProcessorImpl struct {
 	CloudStorage     gcs.CloudStorageClient
 }
 
+type firestoreProcessorImpl struct {
+	BackupRepository repository.BackupRepository
+	Firestore        firestore.AdminClient
+	CloudStorage     gcs.CloudStorageClient
+}
+
+type cloudSQLProcessorImpl struct {
+	BackupRepository repository.BackupRepository
+	CloudSQL         cloudsql.AdminClient
+	CloudStorage     gcs.CloudStorageClient
+}
+
 func (b *bigQueryProcessorImpl) close(ctxIn context.Context) {
 	ctx, span := trace.StartSpan(ctxIn, "(*bigQueryProcessorImpl).close")
 	defer span.End()
@@ -329,7 +511,11 @@ func (b *bigQueryProcessorImpl) process(ctxIn context.Context, backup *repositor
 		return nil, err
 	}
 
-	err = prepareSink(ctx, b.CloudStorage, backup)
+	if backup.Strategy == repository.TableSnapshot {
+		err = prepareSnapshotDataset(ctx, b.BigQuery, backup)
+	} else {
+		err = prepareSink(ctx, b.CloudStorage, backup)
+	}
 	return backup, err
 }
 
@@ -407,6 +593,141 @@ func (c *cloudStorageProcessorImpl) validateSource(ctxIn context.Context, backup
 	return nil
 }
 
+func (f *firestoreProcessorImpl) close(ctxIn context.Context) {
+	ctx, span := trace.StartSpan(ctxIn, "(*firestoreProcessorImpl).close")
+	defer span.End()
+
+	f.Firestore.Close(ctx)
+	f.CloudStorage.Close(ctx)
+}
+
+func (f *firestoreProcessorImpl) process(ctxIn context.Context, backup *repository.Backup) (*repository.Backup, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*firestoreProcessorImpl).process")
+	defer span.End()
+
+	err := f.validateSource(ctx, backup)
+	if err != nil {
+		return nil, err
+	}
+	backup, err = f.BackupRepository.AddBackup(ctx, backup)
+	if err != nil {
+		return nil, err
+	}
+
+	err = prepareSink(ctx, f.CloudStorage, backup)
+	return backup, err
+}
+
+func (f *firestoreProcessorImpl) validateSource(ctxIn context.Context, backup *repository.Backup) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*firestoreProcessorImpl).validateSource")
+	defer span.End()
+
+	if backup.Strategy != repository.Snapshot {
+		return fmt.Errorf("firestore backups only support the %s strategy", repository.Snapshot)
+	}
+
+	exists, err := f.Firestore.DoesDatabaseExist(ctx, backup.SourceProject, backup.FirestoreOptions.Database)
+	if err != nil {
+		return errors.Wrap(err, "operation DoesDatabaseExist failed")
+	}
+
+	if !exists {
+		glog.Errorf("firestore database %s not found in project %s", backup.FirestoreOptions.Database, backup.SourceProject)
+		return fmt.Errorf("firestore database %s not found in project %s", backup.FirestoreOptions.Database, backup.SourceProject)
+	}
+
+	return nil
+}
+
+func (c *cloudSQLProcessorImpl) close(ctxIn context.Context) {
+	ctx, span := trace.StartSpan(ctxIn, "(*cloudSQLProcessorImpl).close")
+	defer span.End()
+
+	c.CloudSQL.Close(ctx)
+	c.CloudStorage.Close(ctx)
+}
+
+func (c *cloudSQLProcessorImpl) process(ctxIn context.Context, backup *repository.Backup) (*repository.Backup, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*cloudSQLProcessorImpl).process")
+	defer span.End()
+
+	err := c.validateSource(ctx, backup)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := c.CloudSQL.GetInstance(ctx, backup.SourceProject, backup.CloudSQLOptions.Instance)
+	if err != nil {
+		return nil, errors.Wrap(err, "operation GetInstance failed")
+	}
+	backup, err = c.BackupRepository.AddBackup(ctx, backup)
+	if err != nil {
+		return nil, err
+	}
+
+	err = prepareSink(ctx, c.CloudStorage, backup)
+	if err != nil {
+		return backup, err
+	}
+	return backup, grantCloudSQLExportAccess(ctx, c.CloudStorage, backup, instance)
+}
+
+func (c *cloudSQLProcessorImpl) validateSource(ctxIn context.Context, backup *repository.Backup) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*cloudSQLProcessorImpl).validateSource")
+	defer span.End()
+
+	if backup.Strategy != repository.Snapshot {
+		return fmt.Errorf("cloudsql backups only support the %s strategy", repository.Snapshot)
+	}
+	if backup.CloudSQLOptions.FileType == repository.CloudSQLExportCSV && backup.CloudSQLOptions.CSVQuery == "" {
+		return fmt.Errorf("cloudsql backups with file type %s need a query", repository.CloudSQLExportCSV)
+	}
+
+	exists, err := c.CloudSQL.DoesInstanceExist(ctx, backup.SourceProject, backup.CloudSQLOptions.Instance)
+	if err != nil {
+		return errors.Wrap(err, "operation DoesInstanceExist failed")
+	}
+
+	if !exists {
+		glog.Errorf("cloudsql instance %s not found in project %s", backup.CloudSQLOptions.Instance, backup.SourceProject)
+		return fmt.Errorf("cloudsql instance %s not found in project %s", backup.CloudSQLOptions.Instance, backup.SourceProject)
+	}
+
+	if len(backup.CloudSQLOptions.Databases) == 0 {
+		return nil
+	}
+	databases, err := c.CloudSQL.ListDatabases(ctx, backup.SourceProject, backup.CloudSQLOptions.Instance)
+	if err != nil {
+		return errors.Wrap(err, "operation ListDatabases failed")
+	}
+	for _, database := range backup.CloudSQLOptions.Databases {
+		if !slices.Contains(databases, database) {
+			return fmt.Errorf("database %s not found in cloudsql instance %s", database, backup.CloudSQLOptions.Instance)
+		}
+	}
+
+	return nil
+}
+
+// prepareSnapshotDataset create the dataset holding the table snapshots of a backup
+// table snapshots have to be in the location of their base tables, so the location of the source dataset is used instead of the region of the request
+func prepareSnapshotDataset(ctxIn context.Context, bigQueryClient bigquery.Client, backup *repository.Backup) error {
+	ctx, span := trace.StartSpan(ctxIn, "prepareSnapshotDataset")
+	defer span.End()
+
+	sourceDataset, err := bigQueryClient.GetDatasetDetails(ctx, backup.SourceProject, backup.Dataset)
+	if err != nil {
+		return errors.Wrap(err, "operation GetDatasetDetails failed")
+	}
+
+	labels := gcs.NewLabels(util.PascalCaseToSnakeCase(backup.Type.String()), backup.ID, backup.SourceProject)
+	_, err = bigQueryClient.CreateDataset(ctx, backup.TargetProject, repository.BuildTableSnapshotDataset(backup.Sink), sourceDataset.Location, tableSnapshotExpiration(backup.SnapshotOptions.LifetimeInDays), labels.Labels())
+	return err
+}
+
+func tableSnapshotExpiration(lifetimeInDays uint) time.Duration {
+	return time.Duration(lifetimeInDays) * 24 * time.Hour
+}
+
 func prepareSink(ctxIn context.Context, cloudStorageClient gcs.CloudStorageClient, backup *repository.Backup) error {
 	ctx, span := trace.StartSpan(ctxIn, "prepareSink")
 	defer span.End()
@@ -426,14 +747,16 @@ func prepareSink(ctxIn context.Context, cloudStorageClient gcs.CloudStorageClien
 		}
 
 		err = cloudStorageClient.CreateBucket(ctx, gcs.CloudStorageBucket{
-			backup.TargetProject,
-			backup.Sink,
-			backup.Region,
-			backup.DualRegion,
-			backup.StorageClass,
-			lifetimeInDays,
-			backup.ArchiveTTM,
-			gcs.NewLabels(util.PascalCaseToSnakeCase(backup.Type.String()), backup.ID, backup.SourceProject),
+			Project:         backup.TargetProject,
+			Bucket:          backup.Sink,
+			Location:        backup.Region,
+			DualLocation:    backup.DualRegion,
+			StorageClass:    backup.StorageClass,
+			LifetimeInDays:  lifetimeInDays,
+			ArchiveTTM:      backup.ArchiveTTM,
+			Labels:          gcs.NewLabels(util.PascalCaseToSnakeCase(backup.Type.String()), backup.ID, backup.SourceProject),
+			KMSKeyName:      backup.KMSKeyName,
+			RetentionInDays: backup.RetentionInDays(),
 		})
 		if err != nil {
 			return err
@@ -442,6 +765,9 @@ func prepareSink(ctxIn context.Context, cloudStorageClient gcs.CloudStorageClien
 		if err != nil {
 			return err
 		}
+		if backup.Type == repository.Firestore {
+			return grantFirestoreExportAccess(ctx, cloudStorageClient, backup)
+		}
 		if backup.Type != repository.CloudStorage {
 			return nil
 		}
@@ -465,6 +791,42 @@ func prepareSink(ctxIn context.Context, cloudStorageClient gcs.CloudStorageClien
 	return nil
 }
 
+// grantFirestoreExportAccess allow the Firestore service agent of the source project to write managed exports into the sink
+// based on https://cloud.google.com/firestore/docs/manage-data/export-import#permissions
+func grantFirestoreExportAccess(ctxIn context.Context, cloudStorageClient gcs.CloudStorageClient, backup *repository.Backup) error {
+	ctx, span := trace.StartSpan(ctxIn, "grantFirestoreExportAccess")
+	defer span.End()
+
+	project, err := cloudStorageClient.GetProject(ctx, backup.SourceProject)
+	if err != nil {
+		return err
+	}
+	projectNumber := strings.ReplaceAll(project.Name, "projects/", "")
+	return addBucketIAMBinding(ctx, cloudStorageClient, backup.Sink, "serviceAccount:"+fmt.Sprintf(sourceFirestoreAccountScheme, projectNumber), "roles/storage.objectAdmin")
+}
+
+// addBucketIAMBinding adds a binding to the IAM policy of a bucket and keeps its existing bindings
+func addBucketIAMBinding(ctxIn context.Context, cloudStorageClient gcs.CloudStorageClient, bucket, member string, role iam.RoleName) error {
+	ctx, span := trace.StartSpan(ctxIn, "addBucketIAMBinding")
+	defer span.End()
+
+	bucketPolicy, err := cloudStorageClient.GetBucketIAMPolicy(ctx, bucket)
+	if err != nil {
+		return errors.Wrapf(err, "could not get IAM policy of bucket %s", bucket)
+	}
+	bucketPolicy.Add(member, role)
+	return cloudStorageClient.SetBucketIAMPolicy(ctx, bucket, bucketPolicy)
+}
+
+// grantCloudSQLExportAccess allow the service account of the source instance to write exports into the sink
+// based on https://cloud.google.com/sql/docs/mysql/import-export/import-export-sql#required_roles_and_permissions_for_exporting_to
+func grantCloudSQLExportAccess(ctxIn context.Context, cloudStorageClient gcs.CloudStorageClient, backup *repository.Backup, instance *cloudsql.Instance) error {
+	ctx, span := trace.StartSpan(ctxIn, "grantCloudSQLExportAccess")
+	defer span.End()
+
+	return addBucketIAMBinding(ctx, cloudStorageClient, backup.Sink, "serviceAccount:"+instance.ServiceAccountEmailAddress, "roles/storage.objectAdmin")
+}
+
 func validateIntersection(ctxIn context.Context, backup *repository.Backup) error {
 	if backup.Type == repository.BigQuery && hasIntersection(backup.Table, backup.ExcludedTables) {
 		return fmt.Errorf("bigquery tables have intersections: %v, %v", backup.Table, backup.ExcludedTables)
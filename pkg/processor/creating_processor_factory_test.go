@@ -5,8 +5,13 @@ import (
 	"testing"
 	"time"
 
+	"cloud.google.com/go/iam"
+	"cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
 	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreatingProcessor_validateIntersection_BigQuery(t *testing.T) {
@@ -80,3 +85,55 @@ func TestCreatingProcessor_validateIntersection_CloudStorage(t *testing.T) {
 	err = validateIntersection(ctx, backup)
 	assert.NotNil(t, err, "expected error")
 }
+
+// iamGcsClient fakes the bucket IAM policy and project lookup of a CloudStorageClient, other methods are not implemented
+type iamGcsClient struct {
+	gcs.CloudStorageClient
+	policy *iam.Policy
+}
+
+func (c *iamGcsClient) GetProject(_ context.Context, projectID string) (*resourcemanagerpb.Project, error) {
+	return &resourcemanagerpb.Project{Name: "projects/123456", ProjectId: projectID}, nil
+}
+
+func (c *iamGcsClient) GetBucketIAMPolicy(context.Context, string) (*iam.Policy, error) {
+	return c.policy, nil
+}
+
+func (c *iamGcsClient) SetBucketIAMPolicy(_ context.Context, _ string, policy *iam.Policy) error {
+	c.policy = policy
+	return nil
+}
+
+func newIAMGcsClient() *iamGcsClient {
+	policy := &iam.Policy{}
+	policy.Add("projectOwner:local-account-backup", "roles/storage.legacyBucketOwner")
+	policy.Add("serviceAccount:backup@local-account-backup.iam.gserviceaccount.com", "roles/storage.admin")
+	return &iamGcsClient{policy: policy}
+}
+
+func assertExistingBindingsKept(t *testing.T, policy *iam.Policy) {
+	assert.True(t, policy.HasRole("projectOwner:local-account-backup", "roles/storage.legacyBucketOwner"))
+	assert.True(t, policy.HasRole("serviceAccount:backup@local-account-backup.iam.gserviceaccount.com", "roles/storage.admin"))
+}
+
+func TestGrantFirestoreExportAccess_KeepsExistingBindings(t *testing.T) {
+	client := newIAMGcsClient()
+	backup := &repository.Backup{ID: "fs", Type: repository.Firestore, SourceProject: "local-account", SinkOptions: repository.SinkOptions{Sink: "bkp_fs_fs"}}
+
+	require.NoError(t, grantFirestoreExportAccess(context.Background(), client, backup))
+
+	assert.True(t, client.policy.HasRole("serviceAccount:service-123456@gcp-sa-firestore.iam.gserviceaccount.com", "roles/storage.objectAdmin"))
+	assertExistingBindingsKept(t, client.policy)
+}
+
+func TestGrantCloudSQLExportAccess_KeepsExistingBindings(t *testing.T) {
+	client := newIAMGcsClient()
+	backup := &repository.Backup{ID: "sql", Type: repository.CloudSQL, SourceProject: "local-account", SinkOptions: repository.SinkOptions{Sink: "bkp_sql_sql"}}
+	instance := &cloudsql.Instance{ServiceAccountEmailAddress: "p123456-abcdef@gcp-sa-cloud-sql.iam.gserviceaccount.com"}
+
+	require.NoError(t, grantCloudSQLExportAccess(context.Background(), client, backup, instance))
+
+	assert.True(t, client.policy.HasRole("serviceAccount:p123456-abcdef@gcp-sa-cloud-sql.iam.gserviceaccount.com", "roles/storage.objectAdmin"))
+	assertExistingBindingsKept(t, client.policy)
+}
@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
+	"go.opencensus.io/trace"
+)
+
+// FirestoreJobCreator will create managed exports of a Firestore database
+type FirestoreJobCreator struct {
+	backupRepository repository.BackupRepository
+	jobRepository    repository.JobRepository
+	adminClient      firestore.AdminClient
+}
+
+// NewFirestoreJobCreator return instance of FirestoreJobCreator
+func NewFirestoreJobCreator(ctxIn context.Context, backupRepository repository.BackupRepository, jobRepository repository.JobRepository, adminClient firestore.AdminClient) *FirestoreJobCreator {
+	_, span := trace.StartSpan(ctxIn, "NewFirestoreJobCreator")
+	defer span.End()
+
+	return &FirestoreJobCreator{
+		backupRepository: backupRepository,
+		jobRepository:    jobRepository,
+		adminClient:      adminClient,
+	}
+}
+
+// PrepareJobs for Firestore backup, every job exports the whole database or the configured collections
+func (f *FirestoreJobCreator) PrepareJobs(ctxIn context.Context, backup *repository.Backup) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*FirestoreJobCreator).PrepareJobs")
+	defer span.End()
+
+	if repository.Snapshot != backup.Strategy {
+		return fmt.Errorf("unsupported strategy %s", backup.Strategy)
+	}
+
+	exists, err := f.adminClient.DoesDatabaseExist(ctx, backup.SourceProject, backup.FirestoreOptions.Database)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return BackupSourceNotFoundErr
+	}
+
+	job := &repository.Job{
+		ID:       generateNewID(),
+		BackupID: backup.ID,
+		Status:   repository.NotScheduled,
+		Source:   backup.FirestoreOptions.Database,
+		Type:     repository.Firestore,
+	}
+
+	err = f.jobRepository.AddJob(ctx, job)
+	if err == nil {
+		err = f.backupRepository.UpdateLastScheduledTime(ctx, backup.ID, time.Now(), repository.Prepared)
+	}
+
+	return err
+}
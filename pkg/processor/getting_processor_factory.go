@@ -48,13 +48,30 @@ func (c gettingProcessorFactory) CreateProcessor(ctxIn context.Context) (Operati
 		return &gettingProcessor{}, err
 	}
 
-	return &gettingProcessor{BackupRepository: backupRepository, JobRepository: jobRepository, sourceGCPProjectProvider: c.sourceGCPProjectProvider}, nil
+	restoreDrillRepository, err := repository.NewRestoreDrillRepository(ctx, c.credentialProvider)
+	if err != nil {
+		glog.Error(err)
+		return &gettingProcessor{}, err
+	}
+
+	sourceFreshnessRepository, err := repository.NewSourceFreshnessRepository(ctx, c.credentialProvider)
+	if err != nil {
+		glog.Error(err)
+		return &gettingProcessor{}, err
+	}
+
+	return &gettingProcessor{BackupRepository: backupRepository, JobRepository: jobRepository, RestoreDrillRepository: restoreDrillRepository, SourceFreshnessRepository: sourceFreshnessRepository, sourceGCPProjectProvider: c.sourceGCPProjectProvider}, nil
 }
 
+// restoreDrillsInResponse number of latest restore drills shown for a backup
+const restoreDrillsInResponse = 10
+
 type gettingProcessor struct {
-	BackupRepository         repository.BackupRepository
-	JobRepository            repository.JobRepository
-	sourceGCPProjectProvider provider.SourceGCPProjectProvider
+	BackupRepository          repository.BackupRepository
+	JobRepository             repository.JobRepository
+	RestoreDrillRepository    repository.RestoreDrillRepository
+	SourceFreshnessRepository repository.SourceFreshnessRepository
+	sourceGCPProjectProvider  provider.SourceGCPProjectProvider
 }
 
 // Process request
@@ -120,8 +137,23 @@ func (l gettingProcessor) Process(ctxIn context.Context, args *Argument[requesto
 		return requestobjects.BackupResponse{}, errors.Wrapf(err, "sourceGCPProjectProvider GetSourceGCPProject failed  %s", backup.SourceProject)
 	}
 
+	restoreDrills, err := l.RestoreDrillRepository.GetLatestForBackupID(ctx, backup.ID, restoreDrillsInResponse)
+	if err != nil {
+		return requestobjects.BackupResponse{}, errors.Wrapf(err, "restore drill repository GetLatestForBackupID failed  %s", request.BackupID)
+	}
+
 	res := mapBackupToResponse(backup, jobs, sourceProject)
 	res.JobsTotal = uint64(jobCount)
 	res.RecoverableJobsTotal = uint64(recoverableJobCount)
+	mapRestoreDrillsToResponse(&res, restoreDrills)
+
+	// the freshness of a backup without RPO is outdated, it is not checked anymore
+	if backup.RecoveryPointObjective > 0 {
+		freshness, err := l.SourceFreshnessRepository.GetForBackupID(ctx, backup.ID)
+		if err != nil {
+			return requestobjects.BackupResponse{}, errors.Wrapf(err, "source freshness repository GetForBackupID failed  %s", request.BackupID)
+		}
+		mapSourceFreshnessToResponse(&res, freshness)
+	}
 	return res, err
 }
@@ -17,5 +17,48 @@ func Test_MakeResponseForArchiveTTM(t *testing.T) {
 	assert.Equal(t, backupResponse.TargetOptions.ArchiveTTM, backup.ArchiveTTM)
 	body, err := json.Marshal(&backupResponse)
 	assert.Nil(t, err, "expected no error")
-	assert.Equal(t, string(`{"id":"","recovery_point_objective":0,"recovery_time_objective":0,"target":{"archive_ttm":123},"snapshot_options":{},"mirror_options":{},"bigquery_options":{},"gcs_options":{},"description":"","status":"","sink":"","sink_project":"","data_owner":"","data_availability_class":""}`), string(body))
+	assert.Equal(t, string(`{"id":"","recovery_point_objective":0,"recovery_time_objective":0,"target":{"archive_ttm":123},"snapshot_options":{},"mirror_options":{},"bigquery_options":{},"gcs_options":{},"firestore_options":{},"cloudsql_options":{},"description":"","status":"","sink":"","sink_project":"","data_owner":"","data_availability_class":""}`), string(body))
+}
+
+func Test_MakeResponseForRestoreDrills(t *testing.T) {
+	expectedCount := int64(10)
+	restoredCount := int64(9)
+	drills := []*repository.RestoreDrill{
+		{ID: "drill-3", Status: repository.DrillRunning},
+		{ID: "drill-2", Status: repository.DrillFailed, ExpectedCount: &expectedCount, RestoredCount: &restoredCount, ErrorMessage: "restored 9 but expected 10"},
+		{ID: "drill-1", Status: repository.DrillSucceeded},
+	}
+
+	backupResponse := mapBackupToResponse(&repository.Backup{}, []*repository.Job{}, provider.SourceGCPProject{})
+	mapRestoreDrillsToResponse(&backupResponse, drills)
+	assert.True(t, backupResponse.RestoreDrillFlagged)
+	assert.Len(t, backupResponse.RestoreDrills, 3)
+	assert.Equal(t, "drill-2", backupResponse.RestoreDrills[1].ID)
+	assert.Equal(t, &restoredCount, backupResponse.RestoreDrills[1].RestoredCount)
+
+	backupResponse = mapBackupToResponse(&repository.Backup{}, []*repository.Job{}, provider.SourceGCPProject{})
+	mapRestoreDrillsToResponse(&backupResponse, drills[2:])
+	assert.False(t, backupResponse.RestoreDrillFlagged)
+
+	backupResponse = mapBackupToResponse(&repository.Backup{}, []*repository.Job{}, provider.SourceGCPProject{})
+	mapRestoreDrillsToResponse(&backupResponse, []*repository.RestoreDrill{{ID: "drill-4", Status: repository.DrillSucceeded, RTOBreached: true}})
+	assert.True(t, backupResponse.RestoreDrillFlagged)
+}
+
+func Test_MakeResponseForSourceFreshness(t *testing.T) {
+	freshness := []*repository.SourceFreshness{
+		{BackupID: "backup", Source: "table_a", RPOViolated: false},
+		{BackupID: "backup", Source: "table_b", RPOViolated: true},
+	}
+
+	backupResponse := mapBackupToResponse(&repository.Backup{}, []*repository.Job{}, provider.SourceGCPProject{})
+	mapSourceFreshnessToResponse(&backupResponse, freshness)
+	assert.True(t, backupResponse.RPOViolated)
+	assert.Len(t, backupResponse.SourceFreshness, 2)
+	assert.Equal(t, "table_b", backupResponse.SourceFreshness[1].Source)
+	assert.Empty(t, backupResponse.SourceFreshness[1].LastRecoveryPoint)
+
+	backupResponse = mapBackupToResponse(&repository.Backup{}, []*repository.Job{}, provider.SourceGCPProject{})
+	mapSourceFreshnessToResponse(&backupResponse, freshness[:1])
+	assert.False(t, backupResponse.RPOViolated)
 }
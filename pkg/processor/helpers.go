@@ -2,6 +2,7 @@ package processor
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ottogroup/penelope/pkg/provider"
@@ -27,6 +28,81 @@ func formatTime(t time.Time) string {
 	return t.Format(time.RFC3339)
 }
 
+// ValidateScheduleOptions checks that cron expression, timezone and windows are parsable
+// a cron expression is only allowed for snapshot strategies and replaces the frequency in hours
+func ValidateScheduleOptions(strategy string, frequencyInHours uint, options *requestobjects.ScheduleOptions) error {
+	if options == nil {
+		return nil
+	}
+	if options.Cron != "" && !repository.Snapshot.EqualTo(strategy) && !repository.TableSnapshot.EqualTo(strategy) {
+		return fmt.Errorf("cron schedule is only supported for %s and %s strategies", repository.Snapshot, repository.TableSnapshot)
+	}
+	if options.Cron != "" && frequencyInHours > 0 {
+		return fmt.Errorf("cron schedule can not be combined with a snapshot frequency in hours")
+	}
+	_, err := mapScheduleOptions(options).Parse()
+	return err
+}
+
+// ValidateImmutability checks that an immutable backup writes into a sink bucket and has a lifetime, the lifetime becomes
+// the retention period of the bucket. A Cloud Storage mirror replaces objects and moves them into the trashcan, which a
+// retention policy prevents, so it can not be immutable
+func ValidateImmutability(request requestobjects.CreateRequest) error {
+	if !request.TargetOptions.Immutable {
+		return nil
+	}
+	if !repository.Mirror.EqualTo(request.Strategy) && !repository.Snapshot.EqualTo(request.Strategy) {
+		return fmt.Errorf("immutability is only supported for %s and %s strategies", repository.Snapshot, repository.Mirror)
+	}
+	if repository.CloudStorage.EqualTo(request.Type) && repository.Mirror.EqualTo(request.Strategy) {
+		return fmt.Errorf("immutability is not supported for %s backups with %s strategy", repository.CloudStorage, repository.Mirror)
+	}
+	if repository.Mirror.EqualTo(request.Strategy) && request.MirrorOptions.LifetimeInDays == 0 {
+		return fmt.Errorf("immutable backups require a mirror lifetime in days as retention period")
+	}
+	if repository.Snapshot.EqualTo(request.Strategy) && request.SnapshotOptions.LifetimeInDays == 0 {
+		return fmt.Errorf("immutable backups require a snapshot lifetime in days as retention period")
+	}
+	return nil
+}
+
+func mapScheduleOptions(options *requestobjects.ScheduleOptions) repository.ScheduleOptions {
+	if options == nil {
+		return repository.ScheduleOptions{}
+	}
+	return repository.ScheduleOptions{
+		Cron:     strings.TrimSpace(options.Cron),
+		Timezone: options.Timezone,
+		Windows:  options.Windows,
+	}
+}
+
+func mapScheduleOptionsToResponse(options repository.ScheduleOptions) *requestobjects.ScheduleOptions {
+	if options.IsEmpty() {
+		return nil
+	}
+	return &requestobjects.ScheduleOptions{
+		Cron:     options.Cron,
+		Timezone: options.Timezone,
+		Windows:  options.Windows,
+	}
+}
+
+func mapJobErrorsToResponse(jobErrors []repository.JobError) []requestobjects.JobErrorResponse {
+	var response []requestobjects.JobErrorResponse
+	for _, jobError := range jobErrors {
+		response = append(response, requestobjects.JobErrorResponse{
+			Attempt:   jobError.Attempt,
+			Message:   jobError.Message,
+			Code:      jobError.Code,
+			Reason:    jobError.Reason,
+			Retryable: jobError.Retryable,
+			Timestamp: formatTime(jobError.Timestamp),
+		})
+	}
+	return response
+}
+
 func mapBackupToResponse(backup *repository.Backup, jobs []*repository.Job, sourceGCPProject provider.SourceGCPProject) requestobjects.BackupResponse {
 	var jobResponse []requestobjects.JobResponse
 	for _, job := range jobs {
@@ -37,17 +113,34 @@ func mapBackupToResponse(backup *repository.Backup, jobs []*repository.Job, sour
 		if backup.Type == repository.CloudStorage {
 			foreignJobID = string(job.ForeignJobID.CloudStorageID)
 		}
+		if backup.Type == repository.Firestore {
+			foreignJobID = string(job.ForeignJobID.FirestoreID)
+		}
+		if backup.Type == repository.CloudSQL {
+			foreignJobID = string(job.ForeignJobID.CloudSQLID)
+		}
 		jobResponse = append(jobResponse, requestobjects.JobResponse{
 			ID:               job.ID,
 			BackupID:         job.BackupID,
 			ForeignJobID:     foreignJobID,
 			Status:           job.Status.String(),
 			Source:           job.Source,
+			Attempts:         job.Attempts,
+			NextAttempt:      formatTime(job.NextAttemptTime),
+			LastErrorMessage: job.LastErrorMessage,
+			LastErrorCode:    job.LastErrorCode,
+			LastErrorReason:  job.LastErrorReason,
+			LastErrorTime:    formatTime(job.LastErrorTime),
+			ErrorHistory:     mapJobErrorsToResponse(job.ErrorHistory),
 			CreatedTimestamp: formatTime(job.CreatedTimestamp),
 			UpdatedTimestamp: formatTime(job.UpdatedTimestamp),
 			DeletedTimestamp: formatTime(job.DeletedTimestamp),
 		})
 	}
+	var exportFormat, exportCompression string
+	if backup.Type == repository.BigQuery {
+		exportFormat, exportCompression = backup.BigQueryOptions.GetExportFormat().String(), backup.BigQueryOptions.GetExportCompression().String()
+	}
 	status := backup.Status
 	if repository.Prepared == backup.Status {
 		status = "Running" //rewording prepared status for frontend
@@ -73,51 +166,182 @@ func mapBackupToResponse(backup *repository.Backup, jobs []*repository.Job, sour
 			Project:                backup.SourceProject,
 			RecoveryPointObjective: backup.RecoveryPointObjective,
 			RecoveryTimeObjective:  backup.RecoveryTimeObjective,
+			MaxRetryAttempts:       backup.MaxRetryAttempts,
 			TargetOptions: requestobjects.TargetOptions{
-				StorageClass: backup.StorageClass,
-				Region:       backup.Region,
-				ArchiveTTM:   backup.SinkOptions.ArchiveTTM,
+				StorageClass:    backup.StorageClass,
+				Region:          backup.Region,
+				ArchiveTTM:      backup.SinkOptions.ArchiveTTM,
+				KMSKeyName:      backup.SinkOptions.KMSKeyName,
+				Immutable:       backup.SinkOptions.Immutable,
+				RetentionLocked: backup.SinkOptions.RetentionLocked,
 			},
 			SnapshotOptions: requestobjects.SnapshotOptions{
 				FrequencyInHours: backup.FrequencyInHours,
 				LifetimeInDays:   backup.SnapshotOptions.LifetimeInDays,
 				LastScheduled:    formatTime(backup.LastScheduledTime),
 			},
+			ScheduleOptions: mapScheduleOptionsToResponse(backup.ScheduleOptions),
 			MirrorOptions: requestobjects.MirrorOptions{
 				LifetimeInDays: backup.MirrorOptions.LifetimeInDays,
 			},
 			BigQueryOptions: requestobjects.BigQueryOptions{
-				Dataset:        backup.Dataset,
-				Table:          backup.Table,
-				ExcludedTables: backup.ExcludedTables,
+				Dataset:           backup.Dataset,
+				Table:             backup.Table,
+				ExcludedTables:    backup.ExcludedTables,
+				ExportFormat:      exportFormat,
+				ExportCompression: exportCompression,
 			},
 			GCSOptions: requestobjects.GCSOptions{
 				Bucket:      backup.Bucket,
 				ExcludePath: backup.ExcludePath,
 				IncludePath: backup.IncludePath,
 			},
+			FirestoreOptions: requestobjects.FirestoreOptions{
+				Database:      backup.FirestoreOptions.Database,
+				CollectionIDs: backup.CollectionIDs,
+			},
+			CloudSQLOptions: requestobjects.CloudSQLOptions{
+				Instance:  backup.Instance,
+				Databases: backup.Databases,
+				FileType:  backup.FileType.String(),
+				CSVQuery:  backup.CSVQuery,
+			},
 		},
 		Jobs: jobResponse,
 	}
 }
 
-func mapToRestoreResponse(backup *repository.Backup, jobs []*repository.Job) (restoreResponse requestobjects.RestoreResponse) {
+func mapToRestoreResponse(backup *repository.Backup, jobs []*repository.Job, request requestobjects.RestoreRequest) (restoreResponse requestobjects.RestoreResponse) {
 	restoreResponse.BackupID = backup.ID
+	if backup.Type == repository.CloudStorage {
+		targetBucket := backup.CloudStorageOptions.Bucket
+		if request.TargetBucket != "" {
+			targetBucket = request.TargetBucket
+		}
+		var overwriteFlag string
+		switch request.WriteMode {
+		case requestobjects.RestoreWriteTruncate:
+			overwriteFlag = " --overwrite-when=always"
+		case requestobjects.RestoreWriteAppend:
+			overwriteFlag = " --overwrite-when=never"
+		}
+		// the sink bucket always holds the latest state, one transfer restores all of it
+		restoreResponse.RestoreActions = append(restoreResponse.RestoreActions, requestobjects.RestoreAction{
+			Type: "gcs",
+			Action: fmt.Sprintf(`gcloud transfer jobs create "gs://%s" "gs://%s" --project "%s" --exclude-prefixes="%s/"%s`,
+				backup.Sink,
+				targetBucket,
+				backup.TargetProject,
+				backup.GetTrashcanPath(),
+				overwriteFlag,
+			),
+		})
+		return restoreResponse
+	}
+	if backup.Type == repository.Firestore {
+		targetProject := backup.SourceProject
+		if request.TargetProject != "" {
+			targetProject = request.TargetProject
+		}
+		var collectionsFlag string
+		if len(backup.CollectionIDs) > 0 {
+			collectionsFlag = fmt.Sprintf(` --collection-ids="%s"`, strings.Join(backup.CollectionIDs, ","))
+		}
+		// an import overwrites documents with the same id and keeps all others, so write modes do not apply
+		for _, job := range jobs {
+			restoreResponse.RestoreActions = append(restoreResponse.RestoreActions, requestobjects.RestoreAction{
+				Type: "firestore",
+				Action: fmt.Sprintf(`gcloud firestore import "%s" --project "%s" --database "%s"%s`,
+					repository.BuildFullFirestoreExportPath(backup.Sink, backup.FirestoreOptions.Database, job.ID),
+					targetProject,
+					backup.FirestoreOptions.Database,
+					collectionsFlag,
+				),
+			})
+		}
+		return restoreResponse
+	}
+	if backup.Type == repository.CloudSQL {
+		targetProject := backup.SourceProject
+		if request.TargetProject != "" {
+			targetProject = request.TargetProject
+		}
+		// every job exported one database, a CSV export holds a query result so the target table has to be named by the user
+		for _, job := range jobs {
+			var tableFlag string
+			if backup.FileType == repository.CloudSQLExportCSV {
+				tableFlag = ` --table "TABLE_NAME"`
+			}
+			restoreResponse.RestoreActions = append(restoreResponse.RestoreActions, requestobjects.RestoreAction{
+				Type: "cloudsql",
+				Action: fmt.Sprintf(`gcloud sql import %s "%s" "%s" --project "%s" --database "%s"%s`,
+					strings.ToLower(backup.FileType.String()),
+					backup.Instance,
+					repository.BuildFullCloudSQLExportPath(backup.Sink, backup.Instance, job.Source, job.ID, backup.FileType),
+					targetProject,
+					job.Source,
+					tableFlag,
+				),
+			})
+		}
+		return restoreResponse
+	}
+	targetProject, targetDataset := backup.SourceProject, backup.BigQueryOptions.Dataset
+	if request.TargetProject != "" {
+		targetProject = request.TargetProject
+	}
+	if request.TargetDataset != "" {
+		targetDataset = request.TargetDataset
+	}
+	var writeFlag string
+	switch request.WriteMode {
+	case requestobjects.RestoreWriteTruncate:
+		writeFlag = " --replace"
+	case requestobjects.RestoreWriteAppend:
+		writeFlag = " --noreplace"
+	}
+	var autodetectFlag string
+	if backup.BigQueryOptions.GetExportFormat() == repository.JSONExport {
+		autodetectFlag = " --autodetect"
+	}
+	mapping := tableMapping{prefix: request.TablePrefix, suffix: request.TableSuffix}
+	if backup.Strategy == repository.TableSnapshot {
+		// a table snapshot can only be restored into an empty or a replaced table
+		clobberFlag := " --no_clobber"
+		if request.WriteMode == requestobjects.RestoreWriteTruncate {
+			clobberFlag = " --force"
+		}
+		for _, job := range jobs {
+			restoreResponse.RestoreActions = append(restoreResponse.RestoreActions, requestobjects.RestoreAction{
+				Type: "bq",
+				Action: fmt.Sprintf(`bq cp --restore%s "%s:%s.%s" "%s:%s.%s"`,
+					clobberFlag,
+					backup.TargetProject,
+					repository.BuildTableSnapshotDataset(backup.Sink),
+					repository.BuildTableSnapshotName(job.Source, job.ID),
+					targetProject,
+					targetDataset,
+					mapping.targetTable(job.Source),
+				),
+			})
+		}
+		return restoreResponse
+	}
 	for _, job := range jobs {
 		var action string
 		var backupType string
 		if backup.Type == repository.BigQuery {
 			backupType = "bq"
-			action += fmt.Sprintf(`bq --location=EU load --project_id "%s" --source_format=AVRO "%s.%s" "%s"`,
-				backup.SourceProject,
-				backup.BigQueryOptions.Dataset,
-				job.Source,
-				repository.BuildFullObjectStoragePath(backup.Sink, backup.BigQueryOptions.Dataset, job.Source, job.ID),
+			action += fmt.Sprintf(`bq load --project_id "%s" --source_format=%s%s%s "%s.%s" "%s"`,
+				targetProject,
+				backup.BigQueryOptions.GetExportFormat(),
+				autodetectFlag,
+				writeFlag,
+				targetDataset,
+				mapping.targetTable(job.Source),
+				repository.BuildFullObjectStoragePath(backup.Sink, backup.BigQueryOptions.Dataset, job.Source, job.ID, backup.BigQueryOptions.FileExtension()),
 			)
 		}
-		if backup.Type == repository.CloudStorage {
-			backupType = "gcs"
-		}
 		restoreResponse.RestoreActions = append(restoreResponse.RestoreActions, requestobjects.RestoreAction{
 			Type:   backupType,
 			Action: action,
@@ -125,3 +349,117 @@ func mapToRestoreResponse(backup *repository.Backup, jobs []*repository.Job) (re
 	}
 	return restoreResponse
 }
+
+func mapJobEventsToResponse(backupID, jobID string, events []*repository.JobEvent) requestobjects.JobEventsResponse {
+	response := requestobjects.JobEventsResponse{
+		BackupID: backupID,
+		JobID:    jobID,
+		Events:   []requestobjects.JobEventResponse{},
+	}
+	for _, event := range events {
+		response.Events = append(response.Events, requestobjects.JobEventResponse{
+			Status:      event.Status.String(),
+			Attempt:     event.Attempt,
+			Message:     event.Message,
+			ErrorCode:   event.ErrorCode,
+			ErrorReason: event.ErrorReason,
+			Timestamp:   formatTime(event.CreatedTimestamp),
+		})
+	}
+	return response
+}
+
+func mapTaskRunToResponse(run *repository.TaskRun) requestobjects.TaskRunResponse {
+	response := requestobjects.TaskRunResponse{
+		ID:             run.ID,
+		Task:           run.Task,
+		Status:         run.Status.String(),
+		StartTimestamp: formatTime(run.StartTime),
+		EndTimestamp:   formatTime(run.EndTime),
+		ProcessedCount: run.ProcessedCount,
+		FailedCount:    run.FailedCount,
+		Errors:         []string{},
+	}
+	if !run.EndTime.IsZero() {
+		response.DurationSeconds = int(run.EndTime.Sub(run.StartTime).Seconds())
+	}
+	if run.Errors != nil {
+		response.Errors = run.Errors
+	}
+	return response
+}
+
+func mapRestoreJobsToResponse(backupID, restoreID string, jobs []*repository.RestoreJob) requestobjects.RestoreJobsResponse {
+	response := requestobjects.RestoreJobsResponse{
+		BackupID:   backupID,
+		RestoreID:  restoreID,
+		Statistics: make(map[string]uint64),
+		Jobs:       []requestobjects.RestoreJobResponse{},
+	}
+	for _, job := range jobs {
+		foreignJobID := job.BigQueryID.String()
+		target := fmt.Sprintf("%s.%s.%s", job.TargetProject, job.TargetDataset, job.TargetTable)
+		if job.Type == repository.CloudStorage {
+			foreignJobID = job.CloudStorageID.String()
+			target = fmt.Sprintf("gs://%s", job.TargetBucket)
+		}
+		response.Statistics[job.Status.String()]++
+		response.Jobs = append(response.Jobs, requestobjects.RestoreJobResponse{
+			ID:               job.ID,
+			RestoreID:        job.RestoreID,
+			BackupJobID:      job.BackupJobID,
+			ForeignJobID:     foreignJobID,
+			Status:           job.Status.String(),
+			Source:           job.Source,
+			Target:           target,
+			ErrorMessage:     job.ErrorMessage,
+			CreatedTimestamp: formatTime(job.CreatedTimestamp),
+			UpdatedTimestamp: formatTime(job.UpdatedTimestamp),
+		})
+	}
+	return response
+}
+
+// mapSourceFreshnessToResponse add the freshness of the backup sources, the backup violates the RPO if one source does
+func mapSourceFreshnessToResponse(response *requestobjects.BackupResponse, freshness []*repository.SourceFreshness) {
+	for _, source := range freshness {
+		response.RPOViolated = response.RPOViolated || source.RPOViolated
+		response.SourceFreshness = append(response.SourceFreshness, mapSourceFreshness(source))
+	}
+}
+
+func mapSourceFreshness(source *repository.SourceFreshness) requestobjects.SourceFreshnessResponse {
+	return requestobjects.SourceFreshnessResponse{
+		Source:            source.Source,
+		LastRecoveryPoint: formatTime(source.LastRecoveryPoint),
+		RPOViolated:       source.RPOViolated,
+		CheckedTimestamp:  formatTime(source.CheckedTimestamp),
+	}
+}
+
+// mapRestoreDrillsToResponse add the drills of a backup, the backup is flagged if its latest finished drill failed or exceeded the RTO
+func mapRestoreDrillsToResponse(response *requestobjects.BackupResponse, drills []*repository.RestoreDrill) {
+	flagChecked := false
+	for _, drill := range drills {
+		if !flagChecked && drill.Status != repository.DrillRunning {
+			response.RestoreDrillFlagged = drill.IsFlagged()
+			flagChecked = true
+		}
+		response.RestoreDrills = append(response.RestoreDrills, requestobjects.RestoreDrillResponse{
+			ID:                    drill.ID,
+			RestoreJobID:          drill.RestoreJobID,
+			Status:                drill.Status.String(),
+			AvailabilityClass:     drill.AvailabilityClass,
+			Source:                drill.Source,
+			Target:                drill.Target,
+			ExpectedCount:         drill.ExpectedCount,
+			RestoredCount:         drill.RestoredCount,
+			ElapsedSeconds:        drill.ElapsedSeconds,
+			RecoveryTimeObjective: drill.RecoveryTimeObjective,
+			RTOBreached:           drill.RTOBreached,
+			ErrorMessage:          drill.ErrorMessage,
+			CreatedTimestamp:      formatTime(drill.CreatedTimestamp),
+			UpdatedTimestamp:      formatTime(drill.UpdatedTimestamp),
+		})
+	}
+}
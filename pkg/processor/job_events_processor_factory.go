@@ -0,0 +1,99 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/http/auth"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type JobEventsProcessorFactory interface {
+	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.JobEventsRequest, requestobjects.JobEventsResponse], error)
+}
+
+// jobEventsProcessorFactory create Operations for the event history of backup jobs
+type jobEventsProcessorFactory struct {
+	credentialsProvider secret.SecretProvider
+}
+
+func NewJobEventsProcessorFactory(credentialsProvider secret.SecretProvider) JobEventsProcessorFactory {
+	return &jobEventsProcessorFactory{credentialsProvider}
+}
+
+// CreateProcessor return Operations for the event history of backup jobs
+func (c jobEventsProcessorFactory) CreateProcessor(ctxIn context.Context) (Operation[requestobjects.JobEventsRequest, requestobjects.JobEventsResponse], error) {
+	ctx, span := trace.StartSpan(ctxIn, "newJobEventsProcessor")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &jobEventsProcessor{}, err
+	}
+	jobRepository, err := repository.NewJobRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &jobEventsProcessor{}, err
+	}
+	jobEventRepository, err := repository.NewJobEventRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &jobEventsProcessor{}, err
+	}
+
+	return &jobEventsProcessor{BackupRepository: backupRepository, JobRepository: jobRepository, JobEventRepository: jobEventRepository}, nil
+}
+
+type jobEventsProcessor struct {
+	BackupRepository   repository.BackupRepository
+	JobRepository      repository.JobRepository
+	JobEventRepository repository.JobEventRepository
+}
+
+func (l jobEventsProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.JobEventsRequest]) (requestobjects.JobEventsResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(jobEventsProcessor).Process")
+	defer span.End()
+
+	var request = args.Request
+
+	backup, err := l.BackupRepository.GetBackup(ctx, request.BackupID)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return requestobjects.JobEventsResponse{}, requestobjects.ApiError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("no backup with id %q found", request.BackupID),
+			}
+		}
+		return requestobjects.JobEventsResponse{}, errors.Wrapf(err, "get backup failed %s", request.BackupID)
+	}
+
+	if !auth.CheckRequestIsAllowed(args.Principal, requestobjects.Getting, backup.SourceProject) {
+		return requestobjects.JobEventsResponse{}, fmt.Errorf("%s is not allowed for user %q on project %q", requestobjects.Getting.String(), args.Principal.User.Email, backup.SourceProject)
+	}
+
+	job, err := l.JobRepository.GetJob(ctx, request.JobID)
+	if err != nil {
+		return requestobjects.JobEventsResponse{}, errors.Wrapf(err, "get job failed %s", request.JobID)
+	}
+	if job == nil || job.BackupID != backup.ID {
+		return requestobjects.JobEventsResponse{}, requestobjects.ApiError{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("no job with id %q found for backup %q", request.JobID, backup.ID),
+		}
+	}
+
+	events, err := l.JobEventRepository.GetForJobID(ctx, job.ID)
+	if err != nil {
+		return requestobjects.JobEventsResponse{}, errors.Wrapf(err, "job event repository GetForJobID failed %s", job.ID)
+	}
+
+	return mapJobEventsToResponse(backup.ID, job.ID, events), nil
+}
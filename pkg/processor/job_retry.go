@@ -0,0 +1,130 @@
+package processor
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/ottogroup/penelope/pkg/service/util"
+)
+
+const (
+	defaultJobRetryMaxAttempts = 5
+	defaultJobRetryBaseDelay   = 10 * time.Minute
+	maxJobRetryDelay           = 24 * time.Hour
+	// maxJobErrorHistory limits how many failed attempts are kept per job
+	maxJobErrorHistory = 20
+)
+
+// IsRetryableJobError classifies the error of a job into retryable and permanent, unknown errors are permanent
+func IsRetryableJobError(backupType repository.BackupType, err error) bool {
+	switch backupType {
+	case repository.BigQuery:
+		return bigquery.IsRetryableError(err)
+	case repository.CloudStorage:
+		return gcs.IsRetryableError(err)
+	case repository.Firestore:
+		return firestore.IsRetryableError(err)
+	case repository.CloudSQL:
+		return cloudsql.IsRetryableError(err)
+	default:
+		return util.IsRetryableAPIError(err)
+	}
+}
+
+// JobErrorDetails extracts the code and reason reported by the Google API for the error of a job
+func JobErrorDetails(backupType repository.BackupType, err error) (code string, reason string) {
+	switch backupType {
+	case repository.BigQuery:
+		return bigquery.ErrorDetails(err)
+	case repository.CloudStorage:
+		return gcs.ErrorDetails(err)
+	case repository.Firestore:
+		return firestore.ErrorDetails(err)
+	case repository.CloudSQL:
+		return cloudsql.ErrorDetails(err)
+	default:
+		return util.APIErrorDetails(err)
+	}
+}
+
+// NewJobError describes the error of a failed attempt, the attempt number is set by NextJobRetry
+func NewJobError(backupType repository.BackupType, err error, now time.Time) repository.JobError {
+	message := "unknown error"
+	if err != nil {
+		message = err.Error()
+	}
+	code, reason := JobErrorDetails(backupType, err)
+
+	return repository.JobError{
+		Message:   message,
+		Code:      code,
+		Reason:    reason,
+		Retryable: IsRetryableJobError(backupType, err),
+		Timestamp: now,
+	}
+}
+
+// NextJobRetry adds a failed attempt to the retry bookkeeping of a job
+// a retryable error plans the next attempt with exponential backoff until maxAttempts are reached
+func NextJobRetry(retry repository.JobRetry, jobErr repository.JobError, maxAttempts int) repository.JobRetry {
+	retry.Attempts++
+	jobErr.Attempt = retry.Attempts
+
+	retry.LastErrorMessage = jobErr.Message
+	retry.LastErrorCode = jobErr.Code
+	retry.LastErrorReason = jobErr.Reason
+	retry.LastErrorTime = jobErr.Timestamp
+	retry.NextAttemptTime = time.Time{}
+	retry.ErrorHistory = append(retry.ErrorHistory, jobErr)
+	if len(retry.ErrorHistory) > maxJobErrorHistory {
+		retry.ErrorHistory = retry.ErrorHistory[len(retry.ErrorHistory)-maxJobErrorHistory:]
+	}
+
+	if jobErr.Retryable && retry.Attempts < maxAttempts {
+		retry.NextAttemptTime = jobErr.Timestamp.Add(jobRetryDelay(retry.Attempts))
+	}
+
+	return retry
+}
+
+// jobRetryDelay doubles the base delay with every failed attempt
+func jobRetryDelay(attempts int) time.Duration {
+	delay := jobRetryBaseDelay()
+	for i := 1; i < attempts && delay < maxJobRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxJobRetryDelay)
+}
+
+// JobRetryMaxAttempts returns the max attempts of the backup or the configured default
+func JobRetryMaxAttempts(backup *repository.Backup) int {
+	if backup != nil && backup.MaxRetryAttempts > 0 {
+		return backup.MaxRetryAttempts
+	}
+	if config.JobRetryMaxAttempts.Exist() {
+		attempts, err := strconv.Atoi(config.JobRetryMaxAttempts.MustGet())
+		if err == nil && attempts >= 0 {
+			return attempts
+		}
+		glog.Warningf("can not parse max attempts from environment variable %s", config.JobRetryMaxAttempts)
+	}
+	return defaultJobRetryMaxAttempts
+}
+
+func jobRetryBaseDelay() time.Duration {
+	if config.JobRetryBaseDelayMinutes.Exist() {
+		minutes, err := strconv.Atoi(config.JobRetryBaseDelayMinutes.MustGet())
+		if err == nil && minutes > 0 {
+			return time.Duration(minutes) * time.Minute
+		}
+		glog.Warningf("can not parse base delay from environment variable %s", config.JobRetryBaseDelayMinutes)
+	}
+	return defaultJobRetryBaseDelay
+}
@@ -0,0 +1,96 @@
+package processor
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	bq "cloud.google.com/go/bigquery"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsRetryableJobError(t *testing.T) {
+	assert.True(t, IsRetryableJobError(repository.BigQuery, &bq.Error{Reason: "backendError"}))
+	assert.False(t, IsRetryableJobError(repository.BigQuery, &bq.Error{Reason: "invalid"}))
+	assert.True(t, IsRetryableJobError(repository.BigQuery, &googleapi.Error{Code: http.StatusServiceUnavailable}))
+	assert.False(t, IsRetryableJobError(repository.BigQuery, &googleapi.Error{Code: http.StatusForbidden}))
+	assert.True(t, IsRetryableJobError(repository.CloudStorage, &gcs.TransferOperationError{Code: 14, Message: "unavailable"}))
+	assert.False(t, IsRetryableJobError(repository.CloudStorage, &gcs.TransferOperationError{Code: 7, Message: "permission denied"}))
+	assert.True(t, IsRetryableJobError(repository.Firestore, &googleapi.Error{Code: http.StatusTooManyRequests}))
+	assert.True(t, IsRetryableJobError(repository.Firestore, &firestore.ExportOperationError{Code: 14, Message: "unavailable"}))
+	assert.False(t, IsRetryableJobError(repository.Firestore, &firestore.ExportOperationError{Code: 7, Message: "permission denied"}))
+	assert.True(t, IsRetryableJobError(repository.CloudSQL, &cloudsql.ExportOperationError{Code: "INTERNAL_ERROR", Message: "internal error"}))
+	assert.False(t, IsRetryableJobError(repository.CloudSQL, errors.New("export failed")))
+	assert.False(t, IsRetryableJobError(repository.BigQuery, nil))
+}
+
+func TestNextJobRetry_ExponentialBackoff(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	retry := repository.JobRetry{}
+
+	retry = NextJobRetry(retry, repository.JobError{Message: "first", Retryable: true, Timestamp: now}, 3)
+	assert.Equal(t, 1, retry.Attempts)
+	assert.Equal(t, now.Add(defaultJobRetryBaseDelay), retry.NextAttemptTime)
+	assert.Equal(t, "first", retry.LastErrorMessage)
+	assert.Equal(t, now, retry.LastErrorTime)
+
+	retry = NextJobRetry(retry, repository.JobError{Message: "second", Retryable: true, Timestamp: now}, 3)
+	assert.Equal(t, 2, retry.Attempts)
+	assert.Equal(t, now.Add(2*defaultJobRetryBaseDelay), retry.NextAttemptTime)
+
+	retry = NextJobRetry(retry, repository.JobError{Message: "third", Retryable: true, Timestamp: now}, 3)
+	assert.Equal(t, 3, retry.Attempts)
+	assert.True(t, retry.NextAttemptTime.IsZero(), "max attempts reached")
+	assert.Len(t, retry.ErrorHistory, 3)
+	assert.Equal(t, repository.JobError{Attempt: 3, Message: "third", Retryable: true, Timestamp: now}, retry.ErrorHistory[2])
+}
+
+func TestNextJobRetry_PermanentError(t *testing.T) {
+	now := time.Now()
+	jobErr := NewJobError(repository.BigQuery, &bq.Error{Reason: "accessDenied", Message: "access denied"}, now)
+	retry := NextJobRetry(repository.JobRetry{}, jobErr, 5)
+	assert.Equal(t, 1, retry.Attempts)
+	assert.True(t, retry.NextAttemptTime.IsZero())
+	assert.False(t, retry.ErrorHistory[0].Retryable)
+	assert.Equal(t, "accessDenied", retry.LastErrorReason)
+}
+
+func TestNewJobError_Details(t *testing.T) {
+	now := time.Now()
+
+	jobErr := NewJobError(repository.BigQuery, &bq.Error{Reason: "backendError", Message: "backend error"}, now)
+	assert.Equal(t, "", jobErr.Code)
+	assert.Equal(t, "backendError", jobErr.Reason)
+	assert.True(t, jobErr.Retryable)
+
+	apiErr := &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "accessDenied"}}}
+	jobErr = NewJobError(repository.Firestore, apiErr, now)
+	assert.Equal(t, "403", jobErr.Code)
+	assert.Equal(t, "accessDenied", jobErr.Reason)
+	assert.False(t, jobErr.Retryable)
+
+	jobErr = NewJobError(repository.CloudStorage, &gcs.TransferOperationError{Code: 7, Message: "permission denied"}, now)
+	assert.Equal(t, "PermissionDenied", jobErr.Code)
+	assert.Equal(t, "transfer operation finished in failed state: permission denied", jobErr.Message)
+
+	jobErr = NewJobError(repository.CloudSQL, nil, now)
+	assert.Equal(t, "unknown error", jobErr.Message)
+	assert.Empty(t, jobErr.Code)
+}
+
+func TestJobRetryDelay_Capped(t *testing.T) {
+	assert.Equal(t, defaultJobRetryBaseDelay, jobRetryDelay(1))
+	assert.Equal(t, 4*defaultJobRetryBaseDelay, jobRetryDelay(3))
+	assert.Equal(t, maxJobRetryDelay, jobRetryDelay(30))
+}
+
+func TestJobRetryMaxAttempts(t *testing.T) {
+	assert.Equal(t, defaultJobRetryMaxAttempts, JobRetryMaxAttempts(&repository.Backup{}))
+	assert.Equal(t, 2, JobRetryMaxAttempts(&repository.Backup{MaxRetryAttempts: 2}))
+}
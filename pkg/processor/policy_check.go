@@ -0,0 +1,113 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ottogroup/penelope/pkg/policy"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"go.opencensus.io/trace"
+)
+
+// checkBackupPolicies checks the backup against the requirements of the availability class and the policy rules, it
+// returns an ApiError if a requirement or rule with severity block is violated and the other violations otherwise.
+// For an update the previous backup is given, violations it already had only warn so the backup can still be changed.
+// policyRuleProvider is optional.
+func checkBackupPolicies(ctxIn context.Context, policyRuleProvider provider.PolicyRuleProvider, backup, previous *repository.Backup, availabilityClass provider.AvailabilityClass) ([]requestobjects.PolicyViolationResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "checkBackupPolicies")
+	defer span.End()
+
+	violations, err := evaluateBackupPolicies(ctx, policyRuleProvider, backup, availabilityClass)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		previousViolations, err := evaluateBackupPolicies(ctx, policyRuleProvider, previous, availabilityClass)
+		if err != nil {
+			return nil, err
+		}
+		for i, violation := range violations {
+			if slices.ContainsFunc(previousViolations, func(v policy.Violation) bool { return v.Rule == violation.Rule }) {
+				violations[i].Severity = provider.PolicyWarn
+			}
+		}
+	}
+
+	if blocking := policy.Blocking(violations); len(blocking) > 0 {
+		var reasons []string
+		for _, violation := range blocking {
+			reasons = append(reasons, violation.String())
+		}
+		return nil, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("backup violates policy rules: %s", strings.Join(reasons, "; ")),
+		}
+	}
+
+	return mapPolicyViolations(violations), nil
+}
+
+// evaluateBackupPolicies returns the requirements of the availability class and the policy rules the backup violates
+func evaluateBackupPolicies(ctxIn context.Context, policyRuleProvider provider.PolicyRuleProvider, backup *repository.Backup, availabilityClass provider.AvailabilityClass) ([]policy.Violation, error) {
+	ctx, span := trace.StartSpan(ctxIn, "evaluateBackupPolicies")
+	defer span.End()
+
+	violations := checkAvailabilityClass(backup, availabilityClass)
+	if policyRuleProvider == nil {
+		return violations, nil
+	}
+
+	rules, err := policyRuleProvider.GetPolicyRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get policy rules: %s", err)
+	}
+	ruleViolations, err := policy.Evaluate(rules, backup, availabilityClass)
+	if err != nil {
+		return nil, err
+	}
+	return append(violations, ruleViolations...), nil
+}
+
+func mapPolicyViolations(violations []policy.Violation) []requestobjects.PolicyViolationResponse {
+	var responses []requestobjects.PolicyViolationResponse
+	for _, violation := range violations {
+		responses = append(responses, requestobjects.PolicyViolationResponse{
+			Rule:        violation.Rule,
+			Description: violation.Description,
+			Severity:    string(violation.Severity),
+		})
+	}
+	return responses
+}
+
+// applyUpdateFields returns a copy of the backup with the non-zero fields of an update applied like UpdateBackup does
+func applyUpdateFields(backup *repository.Backup, fields repository.UpdateFields) *repository.Backup {
+	updated := *backup
+	if fields.SnapshotTTL > 0 {
+		updated.SnapshotOptions.LifetimeInDays = fields.SnapshotTTL
+	}
+	if fields.MirrorTTL > 0 {
+		updated.MirrorOptions.LifetimeInDays = fields.MirrorTTL
+	}
+	if fields.ArchiveTTM > 0 {
+		updated.ArchiveTTM = fields.ArchiveTTM
+	}
+	if fields.RecoveryPointObjective > 0 {
+		updated.RecoveryPointObjective = fields.RecoveryPointObjective
+	}
+	if fields.RecoveryTimeObjective > 0 {
+		updated.RecoveryTimeObjective = fields.RecoveryTimeObjective
+	}
+	if fields.MaxRetryAttempts > 0 {
+		updated.MaxRetryAttempts = fields.MaxRetryAttempts
+	}
+	if fields.Schedule != nil {
+		updated.ScheduleOptions = *fields.Schedule
+	}
+	return &updated
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticPolicyRuleProvider []provider.PolicyRule
+
+func (p staticPolicyRuleProvider) GetPolicyRules(context.Context) ([]provider.PolicyRule, error) {
+	return p, nil
+}
+
+func TestCheckBackupPolicies(t *testing.T) {
+	rules := staticPolicyRuleProvider{
+		{Name: "lifetime", Description: "Snapshots are kept at least 14 days", Expression: `backup.snapshot_lifetime_in_days >= 14`, Severity: provider.PolicyBlock},
+		{Name: "rpo", Expression: `availability_class == "A1" || backup.recovery_point_objective <= 24`, Severity: provider.PolicyWarn},
+	}
+	backup := newCloudStorageSnapshotBackup("policy", "bucket")
+	backup.SnapshotOptions.LifetimeInDays = 30
+	backup.RecoveryPointObjective = 48
+	backup.RecoveryTimeObjective = 60
+
+	warnings, err := checkBackupPolicies(context.Background(), rules, backup, nil, provider.A1Irrelevant)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	warnings, err = checkBackupPolicies(context.Background(), rules, backup, nil, provider.A2Aimed)
+	require.NoError(t, err)
+	assert.Equal(t, []requestobjects.PolicyViolationResponse{{Rule: "rpo", Severity: "warn"}}, warnings)
+
+	shortLived := applyUpdateFields(backup, repository.UpdateFields{SnapshotTTL: 7})
+	_, err = checkBackupPolicies(context.Background(), rules, shortLived, backup, provider.A2Aimed)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(requestobjects.ApiError).Code)
+	assert.Contains(t, err.Error(), "lifetime")
+	assert.Equal(t, uint(30), backup.SnapshotOptions.LifetimeInDays, "the update is applied to a copy")
+
+	paused := applyUpdateFields(shortLived, repository.UpdateFields{Status: repository.Paused})
+	warnings, err = checkBackupPolicies(context.Background(), rules, paused, shortLived, provider.A2Aimed)
+	require.NoError(t, err, "a violation the backup already had does not block an update")
+	assert.Equal(t, []requestobjects.PolicyViolationResponse{{Rule: "lifetime", Description: "Snapshots are kept at least 14 days", Severity: "warn"}, {Rule: "rpo", Severity: "warn"}}, warnings)
+}
+
+func TestCheckBackupPolicies_AvailabilityClass(t *testing.T) {
+	backup := newCloudStorageSnapshotBackup("class", "bucket")
+	backup.SnapshotOptions.LifetimeInDays = 30
+	backup.RecoveryPointObjective = 48
+	backup.RecoveryTimeObjective = 60
+
+	_, err := checkBackupPolicies(context.Background(), nil, backup, nil, provider.A3Guaranteed)
+	require.Error(t, err, "policy rules are optional but availability classes are always checked")
+	assert.Contains(t, err.Error(), "availability-class-rpo")
+
+	warnings, err := checkBackupPolicies(context.Background(), nil, backup, nil, provider.A1Irrelevant)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
@@ -0,0 +1,527 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	bq "cloud.google.com/go/bigquery"
+	"cloud.google.com/go/storage"
+	"github.com/go-pg/pg/v10"
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/http/auth"
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type RestoreExecutingProcessorFactory interface {
+	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.RestoreExecutionRequest, requestobjects.RestoreJobsResponse], error)
+}
+
+// restoreExecutingProcessorFactory create Operations for executing a restore
+type restoreExecutingProcessorFactory struct {
+	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
+	credentialsProvider secret.SecretProvider
+}
+
+func NewRestoreExecutingProcessorFactory(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) RestoreExecutingProcessorFactory {
+	return &restoreExecutingProcessorFactory{tokenSourceProvider, credentialsProvider}
+}
+
+// CreateProcessor return Operations for executing a restore
+func (c restoreExecutingProcessorFactory) CreateProcessor(ctxIn context.Context) (Operation[requestobjects.RestoreExecutionRequest, requestobjects.RestoreJobsResponse], error) {
+	ctx, span := trace.StartSpan(ctxIn, "newRestoreExecutingProcessor")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &restoreExecutingProcessor{}, err
+	}
+	jobRepository, err := repository.NewJobRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &restoreExecutingProcessor{}, err
+	}
+	restoreJobRepository, err := repository.NewRestoreJobRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &restoreExecutingProcessor{}, err
+	}
+
+	return &restoreExecutingProcessor{
+		BackupRepository:     backupRepository,
+		JobRepository:        jobRepository,
+		RestoreJobRepository: restoreJobRepository,
+		tokenSourceProvider:  c.tokenSourceProvider,
+	}, nil
+}
+
+type restoreExecutingProcessor struct {
+	BackupRepository     repository.BackupRepository
+	JobRepository        repository.JobRepository
+	RestoreJobRepository repository.RestoreJobRepository
+	tokenSourceProvider  impersonate.TargetPrincipalForProjectProvider
+}
+
+func (l restoreExecutingProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.RestoreExecutionRequest]) (requestobjects.RestoreJobsResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(restoreExecutingProcessor).Process")
+	defer span.End()
+
+	var request = args.Request
+
+	backup, err := l.BackupRepository.GetBackup(ctx, request.BackupID)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("no backup with id %q found", request.BackupID),
+			}
+		}
+		return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "get backup failed %s", request.BackupID)
+	}
+
+	if !auth.CheckRequestIsAllowed(args.Principal, requestobjects.RestoreExecuting, backup.SourceProject) {
+		return requestobjects.RestoreJobsResponse{}, fmt.Errorf("%s is not allowed for user %q on project %q", requestobjects.RestoreExecuting.String(), args.Principal.User.Email, backup.SourceProject)
+	}
+
+	if request.TargetProject == "" {
+		request.TargetProject = backup.SourceProject
+	}
+	if !auth.CheckRequestIsAllowed(args.Principal, requestobjects.RestoreExecuting, request.TargetProject) {
+		return requestobjects.RestoreJobsResponse{}, fmt.Errorf("%s is not allowed for user %q on project %q", requestobjects.RestoreExecuting.String(), args.Principal.User.Email, request.TargetProject)
+	}
+
+	if request.WriteMode == "" {
+		request.WriteMode = requestobjects.RestoreWriteEmpty
+	}
+	if !slices.Contains(request.WriteMode.ValidValues(), request.WriteMode) {
+		return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("write mode %q is not one of %v", request.WriteMode, request.WriteMode.ValidValues()),
+		}
+	}
+
+	switch backup.Type {
+	case repository.BigQuery:
+		return l.restoreBigQuery(ctx, backup, request)
+	case repository.CloudStorage:
+		return l.restoreCloudStorage(ctx, backup, request)
+	}
+
+	return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+		Code:    http.StatusBadRequest,
+		Message: fmt.Sprintf("restore execution is not supported for backup type %s", backup.Type),
+	}
+}
+
+func (l restoreExecutingProcessor) restoreBigQuery(ctxIn context.Context, backup *repository.Backup, request requestobjects.RestoreExecutionRequest) (requestobjects.RestoreJobsResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(restoreExecutingProcessor).restoreBigQuery")
+	defer span.End()
+
+	if request.TargetDataset == "" {
+		request.TargetDataset = backup.BigQueryOptions.Dataset
+	}
+
+	backupJobs, err := getBackupRestoreJobs(ctx, l.JobRepository, backup, request.JobIDForTimestamp, request.Timestamp)
+	if err != nil {
+		return requestobjects.RestoreJobsResponse{}, err
+	}
+	backupJobs = filterJobsForRestore(backupJobs, request.Tables)
+	if len(backupJobs) == 0 {
+		return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("no backup data to restore found for backup %q", backup.ID),
+		}
+	}
+	if request.TargetTable != "" && len(baseTableNames(backupJobs)) > 1 {
+		return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: "target table can only be set when restoring a single table",
+		}
+	}
+	if backup.Strategy == repository.TableSnapshot && request.WriteMode == requestobjects.RestoreWriteAppend {
+		return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("table snapshots can only be restored with write mode %s or %s", requestobjects.RestoreWriteEmpty, requestobjects.RestoreWriteTruncate),
+		}
+	}
+	mapping := tableMapping{table: request.TargetTable, prefix: request.TablePrefix, suffix: request.TableSuffix}
+
+	loadJobHandler, err := bigquery.NewLoadJobHandler(ctx, l.tokenSourceProvider, request.TargetProject, backup.TargetProject)
+	if err != nil {
+		return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "could not create load job handler for backup %s", backup.ID)
+	}
+	defer loadJobHandler.Close(ctx)
+
+	exists, err := loadJobHandler.DoesDatasetExists(ctx, request.TargetProject, request.TargetDataset)
+	if err != nil || !exists {
+		return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("target dataset %s.%s does not exist or is not accessible", request.TargetProject, request.TargetDataset),
+		}
+	}
+
+	if request.WriteMode == requestobjects.RestoreWriteEmpty {
+		existingTables, err := loadJobHandler.GetTableNames(ctx, request.TargetProject, request.TargetDataset)
+		if err != nil {
+			return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "could not list tables of target dataset %s.%s", request.TargetProject, request.TargetDataset)
+		}
+		if conflicts := conflictingTables(backupJobs, mapping, existingTables); len(conflicts) > 0 {
+			return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+				Code:    http.StatusConflict,
+				Message: fmt.Sprintf("target dataset %s.%s already contains tables %v, choose write mode %s or %s to restore into them", request.TargetProject, request.TargetDataset, conflicts, requestobjects.RestoreWriteAppend, requestobjects.RestoreWriteTruncate),
+			}
+		}
+	}
+
+	err = l.recreateBigQueryObjects(ctx, backup, request, backupJobs, mapping, loadJobHandler)
+	if err != nil {
+		return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "could not recreate tables of backup %s", backup.ID)
+	}
+
+	restoreID := generateNewID()
+	var restoreJobs []*repository.RestoreJob
+	for _, backupJob := range backupJobs {
+		restoreJobs = append(restoreJobs, &repository.RestoreJob{
+			ID:            generateNewID(),
+			RestoreID:     restoreID,
+			BackupID:      backup.ID,
+			BackupJobID:   backupJob.ID,
+			Type:          backup.Type,
+			Status:        repository.RestoreNotScheduled,
+			Source:        backupJob.Source,
+			TargetProject: request.TargetProject,
+			TargetDataset: request.TargetDataset,
+			TargetTable:   mapping.targetTable(backupJob.Source),
+		})
+	}
+
+	err = l.RestoreJobRepository.AddRestoreJobs(ctx, restoreJobs)
+	if err != nil {
+		return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "could not add restore jobs for backup %s", backup.ID)
+	}
+
+	for _, restoreJob := range restoreJobs {
+		loadJobID, err := startBigQueryRestoreJob(ctx, loadJobHandler, backup, restoreJob, bq.TableWriteDisposition(request.WriteMode))
+		patch := repository.RestoreJobPatch{ID: restoreJob.ID, Status: repository.RestoreScheduled}
+		if err != nil {
+			glog.Warningf("could not start restore job %s: %s", restoreJob, err)
+			patch.Status = repository.RestoreError
+			patch.ErrorMessage = err.Error()
+		}
+		patch.BigQueryID = loadJobID
+
+		if err := l.RestoreJobRepository.PatchRestoreJobStatus(ctx, patch); err != nil {
+			return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "could not update restore job %s", restoreJob.ID)
+		}
+		restoreJob.Status = patch.Status
+		restoreJob.ErrorMessage = patch.ErrorMessage
+		restoreJob.BigQueryID = patch.BigQueryID
+	}
+
+	return mapRestoreJobsToResponse(backup.ID, restoreID, restoreJobs), nil
+}
+
+// startBigQueryRestoreJob load the extracted files of a backup job into the target table or restore its table snapshot
+func startBigQueryRestoreJob(ctxIn context.Context, loadJobHandler *bigquery.LoadJobHandler, backup *repository.Backup, restoreJob *repository.RestoreJob, writeDisposition bq.TableWriteDisposition) (repository.LoadJobID, error) {
+	ctx, span := trace.StartSpan(ctxIn, "startBigQueryRestoreJob")
+	defer span.End()
+
+	if backup.Strategy == repository.TableSnapshot {
+		snapshotTable := repository.BuildTableSnapshotName(restoreJob.Source, restoreJob.BackupJobID)
+		return loadJobHandler.CreateRestoreSnapshotJob(ctx, backup.TargetProject, repository.BuildTableSnapshotDataset(backup.Sink), snapshotTable, restoreJob.TargetProject, restoreJob.TargetDataset, restoreJob.TargetTable, writeDisposition)
+	}
+
+	sourceURI := repository.BuildFullObjectStoragePath(backup.Sink, backup.BigQueryOptions.Dataset, restoreJob.Source, restoreJob.BackupJobID, backup.BigQueryOptions.FileExtension())
+	return loadJobHandler.CreateLoadJob(ctx, restoreJob.TargetProject, restoreJob.TargetDataset, restoreJob.TargetTable, sourceURI, backup.BigQueryOptions.GetExportFormat(), writeDisposition)
+}
+
+// recreateBigQueryObjects create tables from the metadata manifest before data is loaded into them
+// views, routines and access entries are only recreated when the whole dataset is restored
+func (l restoreExecutingProcessor) recreateBigQueryObjects(ctxIn context.Context, backup *repository.Backup, request requestobjects.RestoreExecutionRequest, backupJobs []*repository.Job, mapping tableMapping, loadJobHandler *bigquery.LoadJobHandler) error {
+	ctx, span := trace.StartSpan(ctxIn, "(restoreExecutingProcessor).recreateBigQueryObjects")
+	defer span.End()
+
+	manifestID := latestManifestID(backupJobs)
+	if manifestID == "" {
+		// jobs prepared before manifests were written
+		return nil
+	}
+
+	gcsClient, err := gcs.NewCloudStorageClient(ctx, l.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return errors.Wrapf(err, "could not create cloud storage client for backup %s", backup.ID)
+	}
+	defer gcsClient.Close(ctx)
+
+	content, err := gcsClient.ReadObject(ctx, backup.Sink, repository.BuildBigQueryManifestPath(backup.BigQueryOptions.Dataset, manifestID))
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		// the manifest got deleted, the tables are created by the load jobs and views are not restored
+		glog.Warningf("metadata manifest %s of backup %s does not exist, restoring without it", manifestID, backup.ID)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not read metadata manifest %s", manifestID)
+	}
+	var manifest bigquery.DatasetManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return errors.Wrapf(err, "could not parse metadata manifest %s", manifestID)
+	}
+
+	var tables []string
+	for table := range baseTableNames(backupJobs) {
+		tables = append(tables, table)
+	}
+	slices.Sort(tables)
+	withDatasetObjects := len(request.Tables) == 0 && request.TargetTable == ""
+	return loadJobHandler.RecreateObjects(ctx, request.TargetProject, request.TargetDataset, &manifest, tables, mapping.targetBaseTable, withDatasetObjects)
+}
+
+// latestManifestID return the manifest of the most recently prepared job, it describes the state closest to the restored data
+func latestManifestID(jobs []*repository.Job) string {
+	var latest *repository.Job
+	for _, job := range jobs {
+		if job.ManifestID != "" && (latest == nil || job.CreatedTimestamp.After(latest.CreatedTimestamp)) {
+			latest = job
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.ManifestID
+}
+
+func (l restoreExecutingProcessor) restoreCloudStorage(ctxIn context.Context, backup *repository.Backup, request requestobjects.RestoreExecutionRequest) (requestobjects.RestoreJobsResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(restoreExecutingProcessor).restoreCloudStorage")
+	defer span.End()
+
+	if request.TargetBucket == "" {
+		request.TargetBucket = backup.CloudStorageOptions.Bucket
+	}
+	if request.JobIDForTimestamp != "" || !request.Timestamp.IsZero() {
+		return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: "cloud storage backups can only be restored to their latest state",
+		}
+	}
+	if request.TargetBucket == backup.Sink {
+		return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: "target bucket can not be the backup sink",
+		}
+	}
+	if request.WriteMode == requestobjects.RestoreWriteTruncate && request.IncludeTrashcan {
+		// the transfer of the bucket would delete the objects restored from the trashcan
+		return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("the trashcan can not be restored with write mode %s", requestobjects.RestoreWriteTruncate),
+		}
+	}
+
+	gcsClient, err := gcs.NewCloudStorageClient(ctx, l.tokenSourceProvider, request.TargetProject)
+	if err != nil {
+		return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "could not create cloud storage client for backup %s", backup.ID)
+	}
+	defer gcsClient.Close(ctx)
+
+	exists, err := gcsClient.DoesBucketExist(ctx, request.TargetProject, request.TargetBucket)
+	if err != nil || !exists {
+		return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("target bucket %s in project %s does not exist or is not accessible", request.TargetBucket, request.TargetProject),
+		}
+	}
+
+	transferJobHandler, err := gcs.NewTransferJobHandler(ctx, l.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "could not create transfer job handler for backup %s", backup.ID)
+	}
+	defer transferJobHandler.Close(ctx)
+
+	transfers := restoreTransfers(backup, request)
+
+	options := restoreTransferOptions(request.WriteMode)
+	if request.WriteMode == requestobjects.RestoreWriteEmpty {
+		conflict, err := hasConflictingObjects(ctx, gcsClient, request.TargetBucket, transfers[0].includePrefixes)
+		if err != nil {
+			return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "could not list objects of target bucket %s", request.TargetBucket)
+		}
+		if conflict {
+			return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+				Code:    http.StatusConflict,
+				Message: fmt.Sprintf("target bucket %s already contains objects, choose write mode %s or %s to restore into it", request.TargetBucket, requestobjects.RestoreWriteAppend, requestobjects.RestoreWriteTruncate),
+			}
+		}
+	}
+	restoreID := generateNewID()
+	var restoreJobs []*repository.RestoreJob
+	for _, transfer := range transfers {
+		restoreJobs = append(restoreJobs, &repository.RestoreJob{
+			ID:            generateNewID(),
+			RestoreID:     restoreID,
+			BackupID:      backup.ID,
+			Type:          backup.Type,
+			Status:        repository.RestoreNotScheduled,
+			Source:        fmt.Sprintf("gs://%s/%s", backup.Sink, transfer.path),
+			TargetProject: request.TargetProject,
+			TargetBucket:  request.TargetBucket,
+		})
+	}
+
+	err = l.RestoreJobRepository.AddRestoreJobs(ctx, restoreJobs)
+	if err != nil {
+		return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "could not add restore jobs for backup %s", backup.ID)
+	}
+
+	for i, transfer := range transfers {
+		restoreJob := restoreJobs[i]
+		transferJobID, err := transferJobHandler.CreateRestoreTransferJob(ctx, backup.TargetProject, backup.Sink, transfer.path, restoreJob.TargetProject, restoreJob.TargetBucket, "", transfer.includePrefixes, transfer.excludePrefixes, options)
+		patch := repository.RestoreJobPatch{ID: restoreJob.ID, Status: repository.RestoreScheduled}
+		if err != nil {
+			glog.Warningf("could not start restore job %s: %s", restoreJob, err)
+			patch.Status = repository.RestoreError
+			patch.ErrorMessage = err.Error()
+		}
+		patch.CloudStorageID = repository.TransferJobID(transferJobID)
+
+		if err := l.RestoreJobRepository.PatchRestoreJobStatus(ctx, patch); err != nil {
+			return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "could not update restore job %s", restoreJob.ID)
+		}
+		restoreJob.Status = patch.Status
+		restoreJob.ErrorMessage = patch.ErrorMessage
+		restoreJob.CloudStorageID = patch.CloudStorageID
+	}
+
+	return mapRestoreJobsToResponse(backup.ID, restoreID, restoreJobs), nil
+}
+
+// restoreTransfer a single transfer out of the sink bucket, prefixes are relative to path
+type restoreTransfer struct {
+	path            string
+	includePrefixes []string
+	excludePrefixes []string
+}
+
+// restoreTransfers split a Cloud Storage restore into a transfer of the sink bucket and optionally of its trashcan
+// exclude prefixes must be nested in an include prefix, so internal objects are only excluded explicitly when nothing is included
+func restoreTransfers(backup *repository.Backup, request requestobjects.RestoreExecutionRequest) []restoreTransfer {
+	trashcanPath := backup.GetTrashcanPath() + "/"
+
+	bucket := restoreTransfer{includePrefixes: request.IncludePrefixes, excludePrefixes: request.ExcludePrefixes}
+	if len(request.IncludePrefixes) == 0 {
+		bucket.excludePrefixes = append([]string{trashcanPath}, request.ExcludePrefixes...)
+	}
+	transfers := []restoreTransfer{bucket}
+
+	if request.IncludeTrashcan {
+		trashcan := restoreTransfer{path: trashcanPath, includePrefixes: request.IncludePrefixes, excludePrefixes: request.ExcludePrefixes}
+		if len(request.IncludePrefixes) == 0 {
+			trashcan.excludePrefixes = append([]string{repository.TrashcanMarkerObject}, request.ExcludePrefixes...)
+		}
+		transfers = append(transfers, trashcan)
+	}
+
+	return transfers
+}
+
+// restoreTransferOptions map the write mode of a restore to the transfer options, WRITE_EMPTY is checked before the
+// transfer starts and only replaces objects which changed in between
+func restoreTransferOptions(writeMode requestobjects.RestoreWriteMode) gcs.RestoreTransferOptions {
+	switch writeMode {
+	case requestobjects.RestoreWriteAppend:
+		return gcs.RestoreTransferOptions{Overwrite: gcs.OverwriteNever}
+	case requestobjects.RestoreWriteTruncate:
+		return gcs.RestoreTransferOptions{Overwrite: gcs.OverwriteAlways, DeleteUniqueInTarget: true}
+	}
+	return gcs.RestoreTransferOptions{Overwrite: gcs.OverwriteDifferent}
+}
+
+// filterJobsForRestore keeps only jobs for the given tables, a table matches all of its partitions
+func filterJobsForRestore(jobs []*repository.Job, tables []string) []*repository.Job {
+	if len(tables) == 0 {
+		return jobs
+	}
+
+	var filtered []*repository.Job
+	for _, job := range jobs {
+		for _, table := range tables {
+			if job.Source == table || baseTableName(job.Source) == table {
+				filtered = append(filtered, job)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// tableMapping rename restored tables, table replaces the source table name before prefix and suffix are added
+type tableMapping struct {
+	table  string
+	prefix string
+	suffix string
+}
+
+// targetTable rename a source table while keeping its partition decorator
+func (m tableMapping) targetTable(source string) string {
+	return m.targetBaseTable(source) + strings.TrimPrefix(source, baseTableName(source))
+}
+
+func (m tableMapping) targetBaseTable(source string) string {
+	table := baseTableName(source)
+	if m.table != "" {
+		table = m.table
+	}
+	return m.prefix + table + m.suffix
+}
+
+// conflictingTables list target tables of a restore which already exist
+func conflictingTables(jobs []*repository.Job, mapping tableMapping, existingTables []string) []string {
+	var conflicts []string
+	for table := range baseTableNames(jobs) {
+		target := mapping.targetBaseTable(table)
+		if slices.Contains(existingTables, target) && !slices.Contains(conflicts, target) {
+			conflicts = append(conflicts, target)
+		}
+	}
+	slices.Sort(conflicts)
+	return conflicts
+}
+
+// hasConflictingObjects check if the target bucket already holds objects which a restore of the prefixes would write
+func hasConflictingObjects(ctxIn context.Context, gcsClient gcs.CloudStorageClient, bucket string, includePrefixes []string) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "hasConflictingObjects")
+	defer span.End()
+
+	if len(includePrefixes) == 0 {
+		includePrefixes = []string{""}
+	}
+	for _, prefix := range includePrefixes {
+		exists, err := gcsClient.HasObjectWithPrefix(ctx, bucket, prefix)
+		if err != nil || exists {
+			return exists, err
+		}
+	}
+	return false, nil
+}
+
+func baseTableName(source string) string {
+	return strings.SplitN(source, "$", 2)[0]
+}
+
+func baseTableNames(jobs []*repository.Job) map[string]bool {
+	tables := make(map[string]bool)
+	for _, job := range jobs {
+		tables[baseTableName(job.Source)] = true
+	}
+	return tables
+}
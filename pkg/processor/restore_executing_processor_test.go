@@ -0,0 +1,219 @@
+package processor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreExecutingProcessor_filterJobsForRestore(t *testing.T) {
+	jobs := []*repository.Job{
+		{ID: "1", Source: "table_a"},
+		{ID: "2", Source: "table_b$20240101"},
+		{ID: "3", Source: "table_b$20240102"},
+		{ID: "4", Source: "table_c"},
+	}
+
+	assert.Len(t, filterJobsForRestore(jobs, nil), 4)
+	assert.Len(t, filterJobsForRestore(jobs, []string{"table_b"}), 2)
+	assert.Len(t, filterJobsForRestore(jobs, []string{"table_b$20240102", "table_c"}), 2)
+	assert.Empty(t, filterJobsForRestore(jobs, []string{"unknown"}))
+}
+
+func TestRestoreExecutingProcessor_latestManifestID(t *testing.T) {
+	now := time.Now()
+	jobs := []*repository.Job{
+		{ID: "1", ManifestID: "manifest-1", EntityAudit: repository.EntityAudit{CreatedTimestamp: now.Add(-48 * time.Hour)}},
+		{ID: "2", ManifestID: "manifest-2", EntityAudit: repository.EntityAudit{CreatedTimestamp: now.Add(-24 * time.Hour)}},
+		{ID: "3", EntityAudit: repository.EntityAudit{CreatedTimestamp: now}},
+	}
+
+	assert.Equal(t, "manifest-2", latestManifestID(jobs))
+	assert.Empty(t, latestManifestID(jobs[2:]))
+}
+
+func TestRestoreExecutingProcessor_tableMapping(t *testing.T) {
+	assert.Equal(t, "table_a", tableMapping{}.targetTable("table_a"))
+	assert.Equal(t, "restored", tableMapping{table: "restored"}.targetTable("table_a"))
+	assert.Equal(t, "restored$20240101", tableMapping{table: "restored"}.targetTable("table_b$20240101"))
+	assert.Equal(t, "tmp_table_b_copy$20240101", tableMapping{prefix: "tmp_", suffix: "_copy"}.targetTable("table_b$20240101"))
+	assert.Equal(t, "tmp_restored", tableMapping{table: "restored", prefix: "tmp_"}.targetTable("table_a"))
+}
+
+func TestRestoreExecutingProcessor_conflictingTables(t *testing.T) {
+	jobs := []*repository.Job{
+		{ID: "1", Source: "table_a"},
+		{ID: "2", Source: "table_b$20240101"},
+		{ID: "3", Source: "table_b$20240102"},
+	}
+
+	assert.Equal(t, []string{"table_a", "table_b"}, conflictingTables(jobs, tableMapping{}, []string{"table_b", "table_a", "table_c"}))
+	assert.Empty(t, conflictingTables(jobs, tableMapping{prefix: "tmp_"}, []string{"table_a", "table_b"}))
+	assert.Equal(t, []string{"tmp_table_b"}, conflictingTables(jobs, tableMapping{prefix: "tmp_"}, []string{"tmp_table_b"}))
+}
+
+func TestRestoreExecutingProcessor_mapRestoreJobsToResponse(t *testing.T) {
+	response := mapRestoreJobsToResponse("backup-1", "restore-1", []*repository.RestoreJob{
+		{ID: "1", Status: repository.RestoreScheduled, TargetProject: "p", TargetDataset: "d", TargetTable: "t"},
+		{ID: "2", Status: repository.RestoreScheduled},
+		{ID: "3", Status: repository.RestoreError},
+	})
+
+	assert.Equal(t, uint64(2), response.Statistics[repository.RestoreScheduled.String()])
+	assert.Equal(t, uint64(1), response.Statistics[repository.RestoreError.String()])
+	assert.Equal(t, "p.d.t", response.Jobs[0].Target)
+}
+
+func TestRestoreExecutingProcessor_restoreTransfers(t *testing.T) {
+	backup := &repository.Backup{ID: "backup-1"}
+
+	transfers := restoreTransfers(backup, requestobjects.RestoreExecutionRequest{ExcludePrefixes: []string{"tmp/"}})
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, "", transfers[0].path)
+	assert.Equal(t, []string{".trashcan_backup-1/", "tmp/"}, transfers[0].excludePrefixes)
+
+	transfers = restoreTransfers(backup, requestobjects.RestoreExecutionRequest{IncludeTrashcan: true})
+	assert.Len(t, transfers, 2)
+	assert.Equal(t, ".trashcan_backup-1/", transfers[1].path)
+	assert.Equal(t, []string{repository.TrashcanMarkerObject}, transfers[1].excludePrefixes)
+
+	transfers = restoreTransfers(backup, requestobjects.RestoreExecutionRequest{IncludeTrashcan: true, IncludePrefixes: []string{"data/"}, ExcludePrefixes: []string{"data/tmp/"}})
+	assert.Equal(t, []string{"data/"}, transfers[0].includePrefixes)
+	assert.Equal(t, []string{"data/tmp/"}, transfers[0].excludePrefixes)
+	assert.Equal(t, []string{"data/tmp/"}, transfers[1].excludePrefixes)
+}
+
+func TestRestoreExecutingProcessor_restoreTransferOptions(t *testing.T) {
+	assert.Equal(t, gcs.RestoreTransferOptions{Overwrite: gcs.OverwriteDifferent}, restoreTransferOptions(requestobjects.RestoreWriteEmpty))
+	assert.Equal(t, gcs.RestoreTransferOptions{Overwrite: gcs.OverwriteNever}, restoreTransferOptions(requestobjects.RestoreWriteAppend))
+	assert.Equal(t, gcs.RestoreTransferOptions{Overwrite: gcs.OverwriteAlways, DeleteUniqueInTarget: true}, restoreTransferOptions(requestobjects.RestoreWriteTruncate))
+}
+
+func TestRestoreExecutingProcessor_truncateWithTrashcan(t *testing.T) {
+	backup := &repository.Backup{ID: "backup-1", Type: repository.CloudStorage, SinkOptions: repository.SinkOptions{Sink: "sink-bucket"}}
+	request := requestobjects.RestoreExecutionRequest{TargetProject: "project", TargetBucket: "bucket", WriteMode: requestobjects.RestoreWriteTruncate, IncludeTrashcan: true}
+
+	_, err := restoreExecutingProcessor{}.restoreCloudStorage(context.Background(), backup, request)
+
+	var apiErr requestobjects.ApiError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+}
+
+func TestRestoreExecutingProcessor_mapRestoreJobsToResponseCloudStorage(t *testing.T) {
+	response := mapRestoreJobsToResponse("backup-1", "restore-1", []*repository.RestoreJob{
+		{ID: "1", Type: repository.CloudStorage, Status: repository.RestoreScheduled, TargetBucket: "bucket",
+			RestoreForeignJobID: repository.RestoreForeignJobID{CloudStorageID: "transferJobs/1"}},
+	})
+
+	assert.Equal(t, "gs://bucket", response.Jobs[0].Target)
+	assert.Equal(t, "transferJobs/1", response.Jobs[0].ForeignJobID)
+}
+
+func TestRestoreExecutingProcessor_getBackupRestoreJobsPointInTimeValidation(t *testing.T) {
+	ctx := context.Background()
+	timestamp := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := getBackupRestoreJobs(ctx, nil, &repository.Backup{ID: "backup-1", Strategy: repository.Mirror}, "job-1", timestamp)
+	assert.Equal(t, http.StatusBadRequest, err.(requestobjects.ApiError).Code)
+
+	_, err = getBackupRestoreJobs(ctx, nil, &repository.Backup{ID: "backup-1", Strategy: repository.Snapshot}, "", timestamp)
+	assert.Equal(t, http.StatusBadRequest, err.(requestobjects.ApiError).Code)
+}
+
+func TestRestoringProcessor_mapToRestoreResponseWithTarget(t *testing.T) {
+	backup := &repository.Backup{
+		ID:            "backup-1",
+		Type:          repository.BigQuery,
+		SourceProject: "source-project",
+		SinkOptions:   repository.SinkOptions{Sink: "sink-bucket"},
+		BackupOptions: repository.BackupOptions{BigQueryOptions: repository.BigQueryOptions{Dataset: "dataset"}},
+	}
+	jobs := []*repository.Job{{ID: "job-1", Source: "table_a$20240101"}}
+
+	response := mapToRestoreResponse(backup, jobs, requestobjects.RestoreRequest{
+		TargetProject: "sandbox",
+		TargetDataset: "inspect",
+		TablePrefix:   "tmp_",
+		WriteMode:     requestobjects.RestoreWriteTruncate,
+	})
+
+	assert.Len(t, response.RestoreActions, 1)
+	assert.Contains(t, response.RestoreActions[0].Action, `bq load --project_id "sandbox" --source_format=AVRO --replace "inspect.tmp_table_a$20240101"`)
+}
+
+func TestRestoringProcessor_mapToRestoreResponseForJSONExport(t *testing.T) {
+	backup := &repository.Backup{
+		ID:            "backup-1",
+		Type:          repository.BigQuery,
+		SourceProject: "source-project",
+		SinkOptions:   repository.SinkOptions{Sink: "sink-bucket"},
+		BackupOptions: repository.BackupOptions{BigQueryOptions: repository.BigQueryOptions{Dataset: "dataset", ExportFormat: repository.JSONExport, ExportCompression: repository.GzipCompression}},
+	}
+	jobs := []*repository.Job{{ID: "job-1", Source: "table_a"}}
+
+	response := mapToRestoreResponse(backup, jobs, requestobjects.RestoreRequest{})
+
+	assert.Len(t, response.RestoreActions, 1)
+	assert.Equal(t, `bq load --project_id "source-project" --source_format=NEWLINE_DELIMITED_JSON --autodetect "dataset.table_a" "gs://sink-bucket/dataset/dataset/table/table_a/job-1-*.json.gz"`, response.RestoreActions[0].Action)
+}
+
+func TestRestoringProcessor_mapToRestoreResponseForTableSnapshot(t *testing.T) {
+	backup := &repository.Backup{
+		ID:            "backup-1",
+		Type:          repository.BigQuery,
+		Strategy:      repository.TableSnapshot,
+		SourceProject: "source-project",
+		SinkOptions:   repository.SinkOptions{TargetProject: "sink-project", Sink: "bkp_bq_1a2b-3c4d"},
+		BackupOptions: repository.BackupOptions{BigQueryOptions: repository.BigQueryOptions{Dataset: "dataset"}},
+	}
+	jobs := []*repository.Job{{ID: "job-1", Source: "table_a"}}
+
+	response := mapToRestoreResponse(backup, jobs, requestobjects.RestoreRequest{TablePrefix: "restored_", WriteMode: requestobjects.RestoreWriteTruncate})
+
+	assert.Len(t, response.RestoreActions, 1)
+	assert.Equal(t, "bq", response.RestoreActions[0].Type)
+	assert.Equal(t, `bq cp --restore --force "sink-project:bkp_bq_1a2b_3c4d.table_a_job1" "source-project:dataset.restored_table_a"`, response.RestoreActions[0].Action)
+}
+
+func TestRestoringProcessor_mapToRestoreResponseForFirestore(t *testing.T) {
+	backup := &repository.Backup{
+		ID:            "backup-1",
+		Type:          repository.Firestore,
+		SourceProject: "source-project",
+		SinkOptions:   repository.SinkOptions{Sink: "sink-bucket"},
+		BackupOptions: repository.BackupOptions{FirestoreOptions: repository.FirestoreOptions{Database: "(default)", CollectionIDs: []string{"users", "orders"}}},
+	}
+	jobs := []*repository.Job{{ID: "job-1", Source: "(default)"}}
+
+	response := mapToRestoreResponse(backup, jobs, requestobjects.RestoreRequest{TargetProject: "sandbox"})
+
+	assert.Len(t, response.RestoreActions, 1)
+	assert.Equal(t, "firestore", response.RestoreActions[0].Type)
+	assert.Equal(t, `gcloud firestore import "gs://sink-bucket/firestore/(default)/job-1" --project "sandbox" --database "(default)" --collection-ids="users,orders"`, response.RestoreActions[0].Action)
+}
+
+func TestRestoringProcessor_mapToRestoreResponseForCloudSQL(t *testing.T) {
+	backup := &repository.Backup{
+		ID:            "backup-1",
+		Type:          repository.CloudSQL,
+		SourceProject: "source-project",
+		SinkOptions:   repository.SinkOptions{Sink: "sink-bucket"},
+		BackupOptions: repository.BackupOptions{CloudSQLOptions: repository.CloudSQLOptions{Instance: "orders-db", FileType: repository.CloudSQLExportSQL}},
+	}
+	jobs := []*repository.Job{{ID: "job-1", Source: "orders"}, {ID: "job-2", Source: "customers"}}
+
+	response := mapToRestoreResponse(backup, jobs, requestobjects.RestoreRequest{})
+
+	assert.Len(t, response.RestoreActions, 2)
+	assert.Equal(t, "cloudsql", response.RestoreActions[0].Type)
+	assert.Equal(t, `gcloud sql import sql "orders-db" "gs://sink-bucket/cloudsql/orders-db/orders/job-1.sql.gz" --project "source-project" --database "orders"`, response.RestoreActions[0].Action)
+	assert.Equal(t, `gcloud sql import sql "orders-db" "gs://sink-bucket/cloudsql/orders-db/customers/job-2.sql.gz" --project "source-project" --database "customers"`, response.RestoreActions[1].Action)
+}
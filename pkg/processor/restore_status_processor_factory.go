@@ -0,0 +1,82 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/http/auth"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type RestoreStatusProcessorFactory interface {
+	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.RestoreStatusRequest, requestobjects.RestoreJobsResponse], error)
+}
+
+// restoreStatusProcessorFactory create Operations for restore progress
+type restoreStatusProcessorFactory struct {
+	credentialsProvider secret.SecretProvider
+}
+
+func NewRestoreStatusProcessorFactory(credentialsProvider secret.SecretProvider) RestoreStatusProcessorFactory {
+	return &restoreStatusProcessorFactory{credentialsProvider}
+}
+
+// CreateProcessor return Operations for restore progress
+func (c restoreStatusProcessorFactory) CreateProcessor(ctxIn context.Context) (Operation[requestobjects.RestoreStatusRequest, requestobjects.RestoreJobsResponse], error) {
+	ctx, span := trace.StartSpan(ctxIn, "newRestoreStatusProcessor")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &restoreStatusProcessor{}, err
+	}
+	restoreJobRepository, err := repository.NewRestoreJobRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &restoreStatusProcessor{}, err
+	}
+
+	return &restoreStatusProcessor{BackupRepository: backupRepository, RestoreJobRepository: restoreJobRepository}, nil
+}
+
+type restoreStatusProcessor struct {
+	BackupRepository     repository.BackupRepository
+	RestoreJobRepository repository.RestoreJobRepository
+}
+
+func (l restoreStatusProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.RestoreStatusRequest]) (requestobjects.RestoreJobsResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(restoreStatusProcessor).Process")
+	defer span.End()
+
+	var request = args.Request
+
+	backup, err := l.BackupRepository.GetBackup(ctx, request.BackupID)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return requestobjects.RestoreJobsResponse{}, requestobjects.ApiError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("no backup with id %q found", request.BackupID),
+			}
+		}
+		return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "get backup failed %s", request.BackupID)
+	}
+
+	if !auth.CheckRequestIsAllowed(args.Principal, requestobjects.Restoring, backup.SourceProject) {
+		return requestobjects.RestoreJobsResponse{}, fmt.Errorf("%s is not allowed for user %q on project %q", requestobjects.Restoring.String(), args.Principal.User.Email, backup.SourceProject)
+	}
+
+	restoreJobs, err := l.RestoreJobRepository.GetForBackupID(ctx, backup.ID, request.RestoreID)
+	if err != nil {
+		return requestobjects.RestoreJobsResponse{}, errors.Wrapf(err, "restore job repository GetForBackupID failed %s", backup.ID)
+	}
+
+	return mapRestoreJobsToResponse(backup.ID, request.RestoreID, restoreJobs), nil
+}
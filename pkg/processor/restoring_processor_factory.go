@@ -3,6 +3,9 @@ package processor
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"slices"
+	"time"
 
 	"github.com/go-pg/pg/v10"
 	"github.com/golang/glog"
@@ -70,14 +73,57 @@ func (l restoringProcessor) Process(ctxIn context.Context, args *Argument[reques
 		}
 		return requestobjects.RestoreResponse{}, errors.Wrapf(err, "get backup failed %s", request.BackupID)
 	}
-	jobs, err := l.JobRepository.GetBackupRestoreJobs(ctx, backup.ID, request.JobIDForTimestamp)
+	jobs, err := getBackupRestoreJobs(ctx, l.JobRepository, backup, request.JobIDForTimestamp, request.Timestamp)
 	if err != nil {
-		return requestobjects.RestoreResponse{}, errors.Wrapf(err, "job repository GetBackupJobs failed  %s", request.JobIDForTimestamp)
+		return requestobjects.RestoreResponse{}, err
 	}
 
 	if !auth.CheckRequestIsAllowed(args.Principal, requestobjects.Restoring, backup.SourceProject) {
 		return requestobjects.RestoreResponse{}, fmt.Errorf("%s is not allowed for user %q on project %q", requestobjects.Restoring.String(), args.Principal.User.Email, backup.TargetProject)
 	}
+	if request.TargetProject != "" && !auth.CheckRequestIsAllowed(args.Principal, requestobjects.Restoring, request.TargetProject) {
+		return requestobjects.RestoreResponse{}, fmt.Errorf("%s is not allowed for user %q on project %q", requestobjects.Restoring.String(), args.Principal.User.Email, request.TargetProject)
+	}
+	if request.WriteMode != "" && !slices.Contains(request.WriteMode.ValidValues(), request.WriteMode) {
+		return requestobjects.RestoreResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("write mode %q is not one of %v", request.WriteMode, request.WriteMode.ValidValues()),
+		}
+	}
+
+	return mapToRestoreResponse(backup, jobs, request), err
+}
 
-	return mapToRestoreResponse(backup, jobs), err
+// getBackupRestoreJobs select the jobs holding the backup state at the time of a job or at a point in time
+// a point in time can only be resolved for mirror backups
+func getBackupRestoreJobs(ctxIn context.Context, jobRepository repository.JobRepository, backup *repository.Backup, jobID string, timestamp time.Time) ([]*repository.Job, error) {
+	ctx, span := trace.StartSpan(ctxIn, "getBackupRestoreJobs")
+	defer span.End()
+
+	if timestamp.IsZero() {
+		jobs, err := jobRepository.GetBackupRestoreJobs(ctx, backup.ID, jobID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "job repository GetBackupRestoreJobs failed %s", jobID)
+		}
+		return jobs, nil
+	}
+
+	if jobID != "" {
+		return nil, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: "job id and timestamp can not be used together",
+		}
+	}
+	if backup.Strategy != repository.Mirror {
+		return nil, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("point in time restore is only supported for %s backups", repository.Mirror),
+		}
+	}
+
+	jobs, err := jobRepository.GetBackupRestoreJobsAt(ctx, backup.ID, timestamp)
+	if err != nil {
+		return nil, errors.Wrapf(err, "job repository GetBackupRestoreJobsAt failed %s", timestamp.Format(time.RFC3339))
+	}
+	return jobs, nil
 }
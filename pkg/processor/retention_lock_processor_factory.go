@@ -0,0 +1,149 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/http/auth"
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type RetentionLockProcessorFactory interface {
+	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.RetentionLockRequest, requestobjects.RetentionLockResponse], error)
+}
+
+// retentionLockProcessorFactory create Operations for locking the retention policy of an immutable backup
+type retentionLockProcessorFactory struct {
+	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
+	credentialsProvider secret.SecretProvider
+}
+
+func NewRetentionLockProcessorFactory(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) RetentionLockProcessorFactory {
+	return &retentionLockProcessorFactory{tokenSourceProvider, credentialsProvider}
+}
+
+// CreateProcessor return Operations for locking the retention policy of an immutable backup
+func (c retentionLockProcessorFactory) CreateProcessor(ctxIn context.Context) (Operation[requestobjects.RetentionLockRequest, requestobjects.RetentionLockResponse], error) {
+	ctx, span := trace.StartSpan(ctxIn, "newRetentionLockProcessor")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &retentionLockProcessor{}, err
+	}
+
+	return &retentionLockProcessor{
+		BackupRepository:    backupRepository,
+		tokenSourceProvider: c.tokenSourceProvider,
+	}, nil
+}
+
+type retentionLockProcessor struct {
+	BackupRepository    repository.BackupRepository
+	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
+}
+
+func (l retentionLockProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.RetentionLockRequest]) (requestobjects.RetentionLockResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(retentionLockProcessor).Process")
+	defer span.End()
+
+	var request = args.Request
+
+	backup, err := l.BackupRepository.GetBackup(ctx, request.BackupID)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return requestobjects.RetentionLockResponse{}, requestobjects.ApiError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("no backup with id %q found", request.BackupID),
+			}
+		}
+		return requestobjects.RetentionLockResponse{}, errors.Wrapf(err, "get backup failed %s", request.BackupID)
+	}
+
+	if !auth.CheckRequestIsAllowed(args.Principal, requestobjects.RetentionLocking, backup.SourceProject) {
+		return requestobjects.RetentionLockResponse{}, fmt.Errorf("%s is not allowed for user %q on project %q", requestobjects.RetentionLocking.String(), args.Principal.User.Email, backup.SourceProject)
+	}
+
+	if err := validateRetentionLockRequest(backup, request); err != nil {
+		return requestobjects.RetentionLockResponse{}, err
+	}
+
+	if !backup.RetentionLocked {
+		gcsClient, err := gcs.NewCloudStorageClient(ctx, l.tokenSourceProvider, backup.TargetProject)
+		if err != nil {
+			return requestobjects.RetentionLockResponse{}, errors.Wrap(err, "NewCloudStorageClient failed")
+		}
+		defer gcsClient.Close(ctx)
+
+		if err := lockSinkBucketRetention(ctx, gcsClient, backup); err != nil {
+			return requestobjects.RetentionLockResponse{}, err
+		}
+		if err := l.BackupRepository.MarkRetentionLocked(ctx, backup.ID); err != nil {
+			return requestobjects.RetentionLockResponse{}, errors.Wrapf(err, "retention policy of sink %s is locked but backup %s could not be marked", backup.Sink, backup.ID)
+		}
+		glog.Infof("User %s locked the retention policy of sink %s for backup %s", args.Principal.User.Email, backup.Sink, backup.ID)
+	}
+
+	return requestobjects.RetentionLockResponse{
+		BackupID:        backup.ID,
+		Sink:            backup.Sink,
+		RetentionInDays: backup.RetentionInDays(),
+		RetentionLocked: true,
+	}, nil
+}
+
+// validateRetentionLockRequest checks that the backup is immutable and still in use and that the request repeats the
+// backup id, locking can not be undone and keeps the sink bucket until all objects reached the retention period
+func validateRetentionLockRequest(backup *repository.Backup, request requestobjects.RetentionLockRequest) error {
+	if !backup.Immutable {
+		return requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("backup %s is not immutable, only the retention policy of immutable backups can be locked", backup.ID),
+		}
+	}
+	if backup.Status != repository.NotStarted && backup.Status != repository.Prepared && backup.Status != repository.Finished && backup.Status != repository.Paused {
+		return requestobjects.ApiError{
+			Code:    http.StatusConflict,
+			Message: fmt.Sprintf("retention policy of backup %s with status %s can not be locked", backup.ID, backup.Status),
+		}
+	}
+	if request.Confirm != backup.ID {
+		return requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("locking the retention policy of backup %s is irreversible, confirm it by setting confirm to the backup id", backup.ID),
+		}
+	}
+	return nil
+}
+
+// lockSinkBucketRetention sets the retention policy of the backup lifetime if the sink bucket misses it and locks it
+func lockSinkBucketRetention(ctxIn context.Context, gcsClient gcs.CloudStorageClient, backup *repository.Backup) error {
+	ctx, span := trace.StartSpan(ctxIn, "lockSinkBucketRetention")
+	defer span.End()
+
+	details, err := gcsClient.GetBucketDetails(ctx, backup.Sink)
+	if err != nil {
+		return errors.Wrapf(err, "could not get details of sink %s", backup.Sink)
+	}
+
+	policy := details.RetentionPolicy
+	if policy != nil && policy.IsLocked {
+		return nil
+	}
+	if policy == nil || policy.RetentionPeriod != gcs.RetentionPeriod(backup.RetentionInDays()) {
+		if err := gcsClient.SetBucketRetentionPolicy(ctx, backup.Sink, backup.RetentionInDays()); err != nil {
+			return err
+		}
+	}
+	return gcsClient.LockBucketRetentionPolicy(ctx, backup.Sink)
+}
@@ -0,0 +1,104 @@
+package processor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// retentionGcsClient fakes the bucket retention policy of a CloudStorageClient, other methods are not implemented
+type retentionGcsClient struct {
+	gcs.CloudStorageClient
+	policy             *storage.RetentionPolicy
+	setRetentionInDays []uint
+	lockCalls          int
+}
+
+func (c *retentionGcsClient) GetBucketDetails(_ context.Context, _ string) (*storage.BucketAttrs, error) {
+	return &storage.BucketAttrs{RetentionPolicy: c.policy}, nil
+}
+
+func (c *retentionGcsClient) SetBucketRetentionPolicy(_ context.Context, _ string, retentionInDays uint) error {
+	c.setRetentionInDays = append(c.setRetentionInDays, retentionInDays)
+	return nil
+}
+
+func (c *retentionGcsClient) LockBucketRetentionPolicy(_ context.Context, _ string) error {
+	c.lockCalls++
+	return nil
+}
+
+func newImmutableBackup(backupID string, lifetimeInDays uint) *repository.Backup {
+	backup := newCloudStorageSnapshotBackup(backupID, "bucket")
+	backup.Status = repository.Finished
+	backup.Sink = "bkp_gcs_" + backupID
+	backup.Immutable = true
+	backup.SnapshotOptions.LifetimeInDays = lifetimeInDays
+	return backup
+}
+
+func TestValidateRetentionLockRequest(t *testing.T) {
+	backup := newImmutableBackup("lock", 30)
+
+	assert.NoError(t, validateRetentionLockRequest(backup, requestobjects.RetentionLockRequest{BackupID: "lock", Confirm: "lock"}))
+
+	err := validateRetentionLockRequest(backup, requestobjects.RetentionLockRequest{BackupID: "lock"})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(requestobjects.ApiError).Code, "locking must be confirmed")
+
+	mutable := newCloudStorageSnapshotBackup("mutable", "bucket")
+	mutable.Status = repository.Finished
+	err = validateRetentionLockRequest(mutable, requestobjects.RetentionLockRequest{BackupID: "mutable", Confirm: "mutable"})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.(requestobjects.ApiError).Code)
+
+	deleted := newImmutableBackup("deleted", 30)
+	deleted.Status = repository.BackupDeleted
+	err = validateRetentionLockRequest(deleted, requestobjects.RetentionLockRequest{BackupID: "deleted", Confirm: "deleted"})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusConflict, err.(requestobjects.ApiError).Code)
+}
+
+func TestLockSinkBucketRetention(t *testing.T) {
+	backup := newImmutableBackup("lock", 30)
+
+	withoutPolicy := &retentionGcsClient{}
+	require.NoError(t, lockSinkBucketRetention(context.Background(), withoutPolicy, backup))
+	assert.Equal(t, []uint{30}, withoutPolicy.setRetentionInDays, "a missing policy is set before locking")
+	assert.Equal(t, 1, withoutPolicy.lockCalls)
+
+	withPolicy := &retentionGcsClient{policy: &storage.RetentionPolicy{RetentionPeriod: gcs.RetentionPeriod(30)}}
+	require.NoError(t, lockSinkBucketRetention(context.Background(), withPolicy, backup))
+	assert.Empty(t, withPolicy.setRetentionInDays)
+	assert.Equal(t, 1, withPolicy.lockCalls)
+
+	locked := &retentionGcsClient{policy: &storage.RetentionPolicy{RetentionPeriod: gcs.RetentionPeriod(30), IsLocked: true}}
+	require.NoError(t, lockSinkBucketRetention(context.Background(), locked, backup))
+	assert.Zero(t, locked.lockCalls, "a locked policy is not locked again")
+}
+
+func TestValidateImmutability(t *testing.T) {
+	request := requestobjects.CreateRequest{Strategy: repository.Snapshot.String(), TargetOptions: requestobjects.TargetOptions{Immutable: true}}
+	assert.Error(t, ValidateImmutability(request), "a snapshot lifetime is required")
+
+	request.SnapshotOptions.LifetimeInDays = 30
+	assert.NoError(t, ValidateImmutability(request))
+
+	mirror := requestobjects.CreateRequest{Strategy: repository.Mirror.String(), TargetOptions: requestobjects.TargetOptions{Immutable: true}, MirrorOptions: requestobjects.MirrorOptions{LifetimeInDays: 7}}
+	assert.NoError(t, ValidateImmutability(mirror))
+
+	mirror.Type = repository.CloudStorage.String()
+	assert.Error(t, ValidateImmutability(mirror), "a Cloud Storage mirror replaces and trashes objects under retention")
+
+	tableSnapshot := requestobjects.CreateRequest{Strategy: repository.TableSnapshot.String(), TargetOptions: requestobjects.TargetOptions{Immutable: true}, SnapshotOptions: requestobjects.SnapshotOptions{LifetimeInDays: 30}}
+	assert.Error(t, ValidateImmutability(tableSnapshot), "table snapshots have no sink bucket")
+
+	assert.NoError(t, ValidateImmutability(requestobjects.CreateRequest{Strategy: repository.Snapshot.String()}))
+}
@@ -0,0 +1,92 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/http/auth"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type RPOViolationsProcessorFactory interface {
+	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.EmptyRequest, requestobjects.RPOViolationsResponse], error)
+}
+
+// rpoViolationsProcessorFactory create Operations for listing backups violating their recovery point objective
+type rpoViolationsProcessorFactory struct {
+	credentialsProvider secret.SecretProvider
+}
+
+func NewRPOViolationsProcessorFactory(credentialsProvider secret.SecretProvider) RPOViolationsProcessorFactory {
+	return &rpoViolationsProcessorFactory{credentialsProvider}
+}
+
+// CreateProcessor return Operations for listing backups violating their recovery point objective
+func (c rpoViolationsProcessorFactory) CreateProcessor(ctxIn context.Context) (Operation[requestobjects.EmptyRequest, requestobjects.RPOViolationsResponse], error) {
+	ctx, span := trace.StartSpan(ctxIn, "newRPOViolationsProcessor")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &rpoViolationsProcessor{}, err
+	}
+	sourceFreshnessRepository, err := repository.NewSourceFreshnessRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &rpoViolationsProcessor{}, err
+	}
+
+	return &rpoViolationsProcessor{BackupRepository: backupRepository, SourceFreshnessRepository: sourceFreshnessRepository}, nil
+}
+
+type rpoViolationsProcessor struct {
+	BackupRepository          repository.BackupRepository
+	SourceFreshnessRepository repository.SourceFreshnessRepository
+}
+
+// Process list the backups with sources violating their RPO, only backups of projects the user may list are shown
+func (l rpoViolationsProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.EmptyRequest]) (requestobjects.RPOViolationsResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(rpoViolationsProcessor).Process")
+	defer span.End()
+
+	violations, err := l.SourceFreshnessRepository.GetViolations(ctx)
+	if err != nil {
+		return requestobjects.RPOViolationsResponse{}, errors.Wrap(err, "source freshness repository GetViolations failed")
+	}
+
+	response := requestobjects.RPOViolationsResponse{Violations: []requestobjects.RPOViolationResponse{}}
+	allowedByBackup := map[string]bool{}
+	for _, violation := range violations {
+		allowed, checked := allowedByBackup[violation.BackupID]
+		if !checked {
+			backup, err := l.BackupRepository.GetBackup(ctx, violation.BackupID)
+			if err != nil {
+				return requestobjects.RPOViolationsResponse{}, errors.Wrapf(err, "get backup failed %s", violation.BackupID)
+			}
+			allowed = auth.CheckRequestIsAllowed(args.Principal, requestobjects.Listing, backup.SourceProject)
+			allowedByBackup[violation.BackupID] = allowed
+			if allowed {
+				response.Violations = append(response.Violations, requestobjects.RPOViolationResponse{
+					BackupID:               backup.ID,
+					Description:            backup.Description,
+					Project:                backup.SourceProject,
+					Type:                   backup.Type.String(),
+					RecoveryPointObjective: backup.RecoveryPointObjective,
+				})
+			}
+		}
+		if !allowed {
+			continue
+		}
+		// violations are ordered by backup, so the sources belong to the last backup
+		last := &response.Violations[len(response.Violations)-1]
+		last.Sources = append(last.Sources, mapSourceFreshness(violation))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,223 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/http/auth"
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type RunProcessorFactory interface {
+	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.RunRequest, requestobjects.RunResponse], error)
+}
+
+// runProcessorFactory create Operations for running a backup on demand
+type runProcessorFactory struct {
+	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
+	credentialsProvider secret.SecretProvider
+}
+
+func NewRunProcessorFactory(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) RunProcessorFactory {
+	return &runProcessorFactory{tokenSourceProvider, credentialsProvider}
+}
+
+// CreateProcessor return Operations for running a backup on demand
+func (c runProcessorFactory) CreateProcessor(ctxIn context.Context) (Operation[requestobjects.RunRequest, requestobjects.RunResponse], error) {
+	ctx, span := trace.StartSpan(ctxIn, "newRunProcessor")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &runProcessor{}, err
+	}
+	jobRepository, err := repository.NewJobRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &runProcessor{}, err
+	}
+	sourceMetadataRepository, err := repository.NewSourceMetadataRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &runProcessor{}, err
+	}
+	sourceMetadataJobRepository, err := repository.NewSourceMetadataJobRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &runProcessor{}, err
+	}
+
+	return &runProcessor{
+		BackupRepository:            backupRepository,
+		JobRepository:               jobRepository,
+		SourceMetadataRepository:    sourceMetadataRepository,
+		SourceMetadataJobRepository: sourceMetadataJobRepository,
+		tokenSourceProvider:         c.tokenSourceProvider,
+	}, nil
+}
+
+type runProcessor struct {
+	BackupRepository            repository.BackupRepository
+	JobRepository               repository.JobRepository
+	SourceMetadataRepository    repository.SourceMetadataRepository
+	SourceMetadataJobRepository repository.SourceMetadataJobRepository
+	tokenSourceProvider         impersonate.TargetPrincipalForProjectProvider
+}
+
+func (l runProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.RunRequest]) (requestobjects.RunResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(runProcessor).Process")
+	defer span.End()
+
+	var request = args.Request
+
+	backup, err := l.BackupRepository.GetBackup(ctx, request.BackupID)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			return requestobjects.RunResponse{}, requestobjects.ApiError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("no backup with id %q found", request.BackupID),
+			}
+		}
+		return requestobjects.RunResponse{}, errors.Wrapf(err, "get backup failed %s", request.BackupID)
+	}
+
+	if !auth.CheckRequestIsAllowed(args.Principal, requestobjects.Running, backup.SourceProject) {
+		return requestobjects.RunResponse{}, fmt.Errorf("%s is not allowed for user %q on project %q", requestobjects.Running.String(), args.Principal.User.Email, backup.SourceProject)
+	}
+
+	if backup.Status != repository.NotStarted && backup.Status != repository.Prepared && backup.Status != repository.Finished {
+		return requestobjects.RunResponse{}, requestobjects.ApiError{
+			Code:    http.StatusConflict,
+			Message: fmt.Sprintf("backup %s with status %s can not be run", backup.ID, backup.Status),
+		}
+	}
+
+	if err := validateRunRequest(backup, request); err != nil {
+		return requestobjects.RunResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+
+	var jobs []*repository.Job
+	switch backup.Type {
+	case repository.BigQuery:
+		jobs, err = l.runBigQueryBackup(ctx, backup, request.Tables)
+	case repository.CloudStorage:
+		jobs, err = l.runCloudStorageBackup(ctx, backup, request.Prefixes)
+	}
+	if errors.Is(err, BackupSourceNotFoundErr) || errors.Is(err, BucketNotFound) {
+		return requestobjects.RunResponse{}, requestobjects.ApiError{
+			Code:    http.StatusConflict,
+			Message: fmt.Sprintf("source of backup %s does not exist", backup.ID),
+		}
+	}
+	if err != nil {
+		return requestobjects.RunResponse{}, errors.Wrapf(err, "could not prepare jobs for backup %s", backup.ID)
+	}
+
+	glog.Infof("User %s ran backup %s on demand, %d jobs were prepared", args.Principal.User.Email, backup.ID, len(jobs))
+	response := requestobjects.RunResponse{BackupID: backup.ID, JobIDs: []string{}}
+	for _, job := range jobs {
+		response.JobIDs = append(response.JobIDs, job.ID)
+	}
+	return response, nil
+}
+
+func (l runProcessor) runBigQueryBackup(ctxIn context.Context, backup *repository.Backup, tables []string) ([]*repository.Job, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(runProcessor).runBigQueryBackup")
+	defer span.End()
+
+	bigQueryClient, err := bigquery.NewBigQueryClient(ctx, l.tokenSourceProvider, backup.SourceProject, backup.TargetProject)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewBigQueryClient failed")
+	}
+	defer bigQueryClient.Close(ctx)
+
+	gcsClient, err := gcs.NewCloudStorageClient(ctx, l.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewCloudStorageClient failed")
+	}
+	defer gcsClient.Close(ctx)
+
+	jobCreator := NewBigQueryJobCreator(ctx, l.BackupRepository, l.JobRepository, bigQueryClient, l.SourceMetadataRepository, l.SourceMetadataJobRepository, gcsClient)
+	return jobCreator.PrepareJobsForTables(ctx, backup, tables)
+}
+
+func (l runProcessor) runCloudStorageBackup(ctxIn context.Context, backup *repository.Backup, prefixes []string) ([]*repository.Job, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(runProcessor).runCloudStorageBackup")
+	defer span.End()
+
+	gcsClient, err := gcs.NewCloudStorageClient(ctx, l.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return nil, errors.Wrap(err, "NewCloudStorageClient failed")
+	}
+	defer gcsClient.Close(ctx)
+
+	return NewCloudStorageJobCreator(ctx, l.BackupRepository, l.JobRepository, gcsClient).PrepareJobsForPrefixes(ctx, backup, prefixes)
+}
+
+// validateRunRequest checks that the tables or prefixes of an on-demand run are part of the backup
+func validateRunRequest(backup *repository.Backup, request requestobjects.RunRequest) error {
+	if backup.Type != repository.BigQuery && backup.Type != repository.CloudStorage {
+		return fmt.Errorf("on-demand runs are only supported for %s and %s backups", repository.BigQuery, repository.CloudStorage)
+	}
+	if len(request.Tables) > 0 && backup.Type != repository.BigQuery {
+		return fmt.Errorf("tables can only be given for %s backups", repository.BigQuery)
+	}
+	if len(request.Prefixes) > 0 && backup.Type != repository.CloudStorage {
+		return fmt.Errorf("prefixes can only be given for %s backups", repository.CloudStorage)
+	}
+
+	if len(request.Tables) > 0 && backup.Strategy == repository.Mirror {
+		return fmt.Errorf("tables can not be given for %s backups, a mirror always runs for all tables", repository.Mirror)
+	}
+	for _, table := range request.Tables {
+		baseTable, _, _ := strings.Cut(table, "$")
+		if baseTable == "" {
+			return fmt.Errorf("table must not be empty")
+		}
+		if containsTableWithName(baseTable, backup.ExcludedTables) {
+			return fmt.Errorf("table %s is excluded from the backup", baseTable)
+		}
+		if len(backup.BigQueryOptions.Table) > 0 && !containsBaseTable(backup.BigQueryOptions.Table, table, baseTable) {
+			return fmt.Errorf("table %s is not part of the backup", table)
+		}
+	}
+
+	for _, prefix := range request.Prefixes {
+		if prefix == "" {
+			return fmt.Errorf("prefix must not be empty")
+		}
+		if len(backup.IncludePath) > 0 && !slices.ContainsFunc(backup.IncludePath, func(includePath string) bool { return strings.HasPrefix(prefix, includePath) }) {
+			return fmt.Errorf("prefix %s is not included in the backup", prefix)
+		}
+		if slices.ContainsFunc(backup.ExcludePath, func(excludePath string) bool { return strings.HasPrefix(prefix, excludePath) }) {
+			return fmt.Errorf("prefix %s is excluded from the backup", prefix)
+		}
+	}
+	return nil
+}
+
+// containsBaseTable checks if the backup tables contain the table itself or, for a partition, its base table
+func containsBaseTable(backupTables []string, table, baseTable string) bool {
+	for _, backupTable := range backupTables {
+		if backupTable == table || backupTable == baseTable {
+			return true
+		}
+	}
+	return false
+}
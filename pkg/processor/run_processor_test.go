@@ -0,0 +1,39 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRunRequest_BigQuery(t *testing.T) {
+	backup := newBigQuerySnapshotBackup("run", "dataset", []string{"orders", "events$20240101"})
+	backup.ExcludedTables = []string{"customers"}
+
+	assert.NoError(t, validateRunRequest(backup, requestobjects.RunRequest{}))
+	assert.NoError(t, validateRunRequest(backup, requestobjects.RunRequest{Tables: []string{"orders", "events$20240101"}}))
+	assert.NoError(t, validateRunRequest(backup, requestobjects.RunRequest{Tables: []string{"orders$20240102"}}))
+	assert.Error(t, validateRunRequest(backup, requestobjects.RunRequest{Tables: []string{"payments"}}))
+	assert.Error(t, validateRunRequest(backup, requestobjects.RunRequest{Tables: []string{"customers"}}))
+	assert.Error(t, validateRunRequest(backup, requestobjects.RunRequest{Prefixes: []string{"a/"}}))
+
+	mirror := newBigQueryMirrorBackup("run", "dataset", nil)
+	assert.NoError(t, validateRunRequest(mirror, requestobjects.RunRequest{}))
+	assert.Error(t, validateRunRequest(mirror, requestobjects.RunRequest{Tables: []string{"orders"}}))
+}
+
+func TestValidateRunRequest_CloudStorage(t *testing.T) {
+	backup := newCloudStorageSnapshotBackup("run", "bucket")
+	backup.IncludePath = []string{"exports/"}
+	backup.ExcludePath = []string{"exports/tmp/"}
+
+	assert.NoError(t, validateRunRequest(backup, requestobjects.RunRequest{Prefixes: []string{"exports/2024/"}}))
+	assert.Error(t, validateRunRequest(backup, requestobjects.RunRequest{Prefixes: []string{"logs/"}}))
+	assert.Error(t, validateRunRequest(backup, requestobjects.RunRequest{Prefixes: []string{"exports/tmp/a"}}))
+	assert.Error(t, validateRunRequest(backup, requestobjects.RunRequest{Prefixes: []string{""}}))
+	assert.Error(t, validateRunRequest(backup, requestobjects.RunRequest{Tables: []string{"orders"}}))
+
+	firestoreBackup := newFirestoreSnapshotBackup("run", "(default)")
+	assert.Error(t, validateRunRequest(firestoreBackup, requestobjects.RunRequest{}))
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
 	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"go.opencensus.io/trace"
 )
@@ -25,13 +27,22 @@ const (
 	// cloudStorageBatchLimit defines how many jobs are processed in one batch
 	// quota rate limits for Transfer Service is 600 per minute but having in mind request latency we set limit to 100
 	cloudStorageBatchLimit = 100
+	// firestoreBatchLimit defines how many jobs are processed in one batch
+	// every job is a long-running export operation, the admin API only accepts a few export requests per minute
+	firestoreBatchLimit = 20
+	// cloudSQLBatchLimit defines how many jobs are processed in one batch
+	// an instance runs only one export at a time, jobs of a busy instance stay unscheduled until the next run
+	cloudSQLBatchLimit = 20
 )
 
 // ScheduleProcessor defines operation for scheduling
 type ScheduleProcessor interface {
-	CreateBigQueryJobCreator(ctxIn context.Context, client bigquery.Client) *BigQueryJobCreator
+	CreateBigQueryJobCreator(ctxIn context.Context, client bigquery.Client, gcsClient gcs.CloudStorageClient) *BigQueryJobCreator
 	CreateCloudStorageJobCreator(ctxIn context.Context, gcsClient gcs.CloudStorageClient) *CloudStorageJobCreator
-	GetNextBackupJobs(context.Context, repository.BackupType) ([]*repository.Job, error)
+	CreateFirestoreJobCreator(ctxIn context.Context, adminClient firestore.AdminClient) *FirestoreJobCreator
+	CreateCloudSQLJobCreator(ctxIn context.Context, adminClient cloudsql.AdminClient) *CloudSQLJobCreator
+	GetNextBackupJobs(context.Context, repository.BackupType, repository.NextJobsFilter) ([]*repository.Job, error)
+	GetNextBackupJobSourceProjects(context.Context, repository.BackupType) ([]string, error)
 	GetScheduledBackupJobs(context.Context, repository.BackupType) ([]*repository.Job, error)
 	GetExpired(context.Context, repository.BackupType) ([]*repository.Backup, error)
 	GetExpiredBigQueryMirrorRevisions(ctxIn context.Context, maxRevisionLifetimeInWeeks int) ([]*repository.MirrorRevision, error)
@@ -39,6 +50,11 @@ type ScheduleProcessor interface {
 	GetByStatusAndAfter(context.Context, []repository.JobStatus, int) ([]*repository.Job, error)
 	GetJobsForBackupID(ctxIn context.Context, backupID string, jobPage repository.Page) ([]*repository.Job, error)
 	UpdateJob(ctxIn context.Context, backupType repository.BackupType, jobID string, status repository.JobStatus, externalID string) error
+	MarkJobFailed(ctxIn context.Context, job *repository.Job, status repository.JobStatus, jobErr error) error
+	GetJobsToRetry(ctxIn context.Context, before time.Time) ([]*repository.Job, error)
+	RequeueJob(ctxIn context.Context, jobID string) error
+	GetInFlightJobCounts(ctxIn context.Context) ([]repository.InFlightJobCount, error)
+	AddJobEvent(ctxIn context.Context, job *repository.Job, status repository.JobStatus, jobErr error) error
 	UpdateBackupStatus(ctxIn context.Context, id string, status repository.BackupStatus) error
 	UpdateLastCleanupTime(ctxIn context.Context, backupID string, lastCleanupTime time.Time) error
 	MarkBackupDeleted(ctxIn context.Context, id string) error
@@ -58,6 +74,7 @@ type defaultScheduleProcessor struct {
 	sourceMetadataRepository    repository.SourceMetadataRepository
 	sourceMetadataJobRepository repository.SourceMetadataJobRepository
 	sourceTrashcanRepository    repository.SourceTrashcanRepository
+	jobEventRepository          repository.JobEventRepository
 }
 
 // NewScheduleProcessor create new instance of ScheduleProcessor
@@ -90,20 +107,26 @@ func NewScheduleProcessor(ctxIn context.Context, credentialsProvider secret.Secr
 		return nil, err
 	}
 
+	jobEventRepository, err := repository.NewJobEventRepository(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
 	return &defaultScheduleProcessor{
 		backupRepository:            backupRepository,
 		jobRepository:               jobRepository,
 		sourceMetadataRepository:    sourceMetadataRepository,
 		sourceMetadataJobRepository: sourceMetadataJobRepository,
 		sourceTrashcanRepository:    sourceTrashcanRepository,
+		jobEventRepository:          jobEventRepository,
 	}, nil
 }
 
-func (d *defaultScheduleProcessor) CreateBigQueryJobCreator(ctxIn context.Context, bigQueryClient bigquery.Client) *BigQueryJobCreator {
+func (d *defaultScheduleProcessor) CreateBigQueryJobCreator(ctxIn context.Context, bigQueryClient bigquery.Client, gcsClient gcs.CloudStorageClient) *BigQueryJobCreator {
 	ctx, span := trace.StartSpan(ctxIn, "(*defaultScheduleProcessor).CreateBigQueryJobCreator")
 	defer span.End()
 
-	return NewBigQueryJobCreator(ctx, d.backupRepository, d.jobRepository, bigQueryClient, d.sourceMetadataRepository, d.sourceMetadataJobRepository)
+	return NewBigQueryJobCreator(ctx, d.backupRepository, d.jobRepository, bigQueryClient, d.sourceMetadataRepository, d.sourceMetadataJobRepository, gcsClient)
 }
 
 func (d *defaultScheduleProcessor) CreateCloudStorageJobCreator(ctxIn context.Context, gcsClient gcs.CloudStorageClient) *CloudStorageJobCreator {
@@ -113,15 +136,39 @@ func (d *defaultScheduleProcessor) CreateCloudStorageJobCreator(ctxIn context.Co
 	return NewCloudStorageJobCreator(ctx, d.backupRepository, d.jobRepository, gcsClient)
 }
 
-func (d *defaultScheduleProcessor) GetNextBackupJobs(ctxIn context.Context, backupType repository.BackupType) ([]*repository.Job, error) {
+func (d *defaultScheduleProcessor) CreateFirestoreJobCreator(ctxIn context.Context, adminClient firestore.AdminClient) *FirestoreJobCreator {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultScheduleProcessor).CreateFirestoreJobCreator")
+	defer span.End()
+
+	return NewFirestoreJobCreator(ctx, d.backupRepository, d.jobRepository, adminClient)
+}
+
+func (d *defaultScheduleProcessor) CreateCloudSQLJobCreator(ctxIn context.Context, adminClient cloudsql.AdminClient) *CloudSQLJobCreator {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultScheduleProcessor).CreateCloudSQLJobCreator")
+	defer span.End()
+
+	return NewCloudSQLJobCreator(ctx, d.backupRepository, d.jobRepository, adminClient)
+}
+
+// GetNextBackupJobs returns the next batch of NotScheduled jobs of the type in the order they should be scheduled
+func (d *defaultScheduleProcessor) GetNextBackupJobs(ctxIn context.Context, backupType repository.BackupType, filter repository.NextJobsFilter) ([]*repository.Job, error) {
 	if backupType == repository.CloudStorage {
-		return d.jobRepository.ListByTypeAndStatusWithLimit(ctxIn, backupType, repository.NotScheduled, cloudStorageBatchLimit)
+		return d.jobRepository.ListNextJobs(ctxIn, backupType, filter, cloudStorageBatchLimit)
 	} else if backupType == repository.BigQuery {
-		return d.jobRepository.ListByTypeAndStatusWithLimit(ctxIn, backupType, repository.NotScheduled, bigQueryBatchLimit)
+		return d.jobRepository.ListNextJobs(ctxIn, backupType, filter, bigQueryBatchLimit)
+	} else if backupType == repository.Firestore {
+		return d.jobRepository.ListNextJobs(ctxIn, backupType, filter, firestoreBatchLimit)
+	} else if backupType == repository.CloudSQL {
+		return d.jobRepository.ListNextJobs(ctxIn, backupType, filter, cloudSQLBatchLimit)
 	}
 	return nil, fmt.Errorf("unknown backup type %v", backupType.String())
 }
 
+// GetNextBackupJobSourceProjects returns the source projects of the backups with NotScheduled jobs of the type
+func (d *defaultScheduleProcessor) GetNextBackupJobSourceProjects(ctxIn context.Context, backupType repository.BackupType) ([]string, error) {
+	return d.jobRepository.GetSourceProjectsOfJobs(ctxIn, backupType, repository.NotScheduled)
+}
+
 func (d *defaultScheduleProcessor) GetScheduledBackupJobs(ctxIn context.Context, backupType repository.BackupType) ([]*repository.Job, error) {
 	return d.jobRepository.GetByJobTypeAndStatus(ctxIn, backupType, repository.Scheduled, repository.Pending)
 }
@@ -134,6 +181,10 @@ func (d *defaultScheduleProcessor) UpdateJob(ctxIn context.Context, backupType r
 		patch.ForeignJobID.BigQueryID = repository.ExtractJobID(externalID)
 	case repository.CloudStorage.String():
 		patch.ForeignJobID.CloudStorageID = repository.TransferJobID(externalID)
+	case repository.Firestore.String():
+		patch.ForeignJobID.FirestoreID = repository.ExportOperationID(externalID)
+	case repository.CloudSQL.String():
+		patch.ForeignJobID.CloudSQLID = repository.ExportOperationID(externalID)
 	default:
 		return fmt.Errorf("unknown job type %v", backupType.String())
 	}
@@ -141,6 +192,48 @@ func (d *defaultScheduleProcessor) UpdateJob(ctxIn context.Context, backupType r
 	return d.jobRepository.PatchJobStatus(ctxIn, patch)
 }
 
+// MarkJobFailed set the failed status of a job and plans its next attempt if the error is retryable
+func (d *defaultScheduleProcessor) MarkJobFailed(ctxIn context.Context, job *repository.Job, status repository.JobStatus, jobErr error) error {
+	backup, err := d.backupRepository.GetBackup(ctxIn, job.BackupID)
+	if err != nil {
+		return fmt.Errorf("could not get backup with id %s: %s", job.BackupID, err)
+	}
+
+	retry := NextJobRetry(job.JobRetry, NewJobError(job.Type, jobErr, time.Now()), JobRetryMaxAttempts(backup))
+	job.JobRetry = retry
+
+	return d.jobRepository.PatchJobRetry(ctxIn, job.ID, status, retry)
+}
+
+func (d *defaultScheduleProcessor) GetJobsToRetry(ctxIn context.Context, before time.Time) ([]*repository.Job, error) {
+	return d.jobRepository.GetJobsToRetry(ctxIn, before)
+}
+
+func (d *defaultScheduleProcessor) RequeueJob(ctxIn context.Context, jobID string) error {
+	return d.jobRepository.RequeueJob(ctxIn, jobID)
+}
+
+func (d *defaultScheduleProcessor) GetInFlightJobCounts(ctxIn context.Context) ([]repository.InFlightJobCount, error) {
+	return d.jobRepository.GetInFlightJobCounts(ctxIn)
+}
+
+// AddJobEvent records a status change of a job in its event history, jobErr is optional and describes why the status was reached
+func (d *defaultScheduleProcessor) AddJobEvent(ctxIn context.Context, job *repository.Job, status repository.JobStatus, jobErr error) error {
+	event := &repository.JobEvent{
+		JobID:            job.ID,
+		BackupID:         job.BackupID,
+		Status:           status,
+		Attempt:          job.Attempts,
+		CreatedTimestamp: time.Now(),
+	}
+	if jobErr != nil {
+		event.Message = jobErr.Error()
+		event.ErrorCode, event.ErrorReason = JobErrorDetails(job.Type, jobErr)
+	}
+
+	return d.jobEventRepository.AddJobEvent(ctxIn, event)
+}
+
 func (d *defaultScheduleProcessor) UpdateBackupStatus(ctxIn context.Context, id string, status repository.BackupStatus) error {
 	return d.backupRepository.MarkStatus(ctxIn, id, status)
 }
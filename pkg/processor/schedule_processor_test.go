@@ -2,9 +2,11 @@ package processor
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/iam"
 	"cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
@@ -14,6 +16,8 @@ import (
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/repository/memory"
 	bq "github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -275,6 +279,37 @@ func TestBigQueryJobCreator_PrepareJobs_Snapshot_partitionTables_withJobsThatAre
 	assert.Equal(t, 3, len(jobsForBackup))
 }
 
+func TestBigQueryJobCreator_PrepareJobs_Snapshot_partitionTables_recordRowCount(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	testContext := givenATestContext()
+	backup := newBigQuerySnapshotBackup("partitionTables_recordRowCount", "dataset", []string{})
+	_, err := testContext.BackupRepository.AddBackup(ctx, backup)
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+	testContext.BigQuery.fDoesDatasetExists = true
+	testContext.BigQuery.fDoesTableHasPartitions = true
+	testContext.BigQuery.fGetTablesInDataset = append(testContext.BigQuery.fGetTablesInDataset, &bq.Table{Name: "partition", Checksum: "000", NumRows: 30})
+	testContext.BigQuery.fGetTablePartitions = []*bq.Table{
+		{Name: "partition$20190101", Checksum: "111", NumRows: 10},
+		{Name: "partition$20190102", Checksum: "111", NumRows: 20},
+	}
+	bigQueryJobCreator := givenABigQueryJobCreatorWithTestContext(testContext)
+	// When
+	err = bigQueryJobCreator.PrepareJobs(ctx, backup)
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+
+	// Then
+	jobsForBackup, err := testContext.MemoryJobRepository.ListNotScheduledJobsForBackup(ctx, backup.ID)
+	require.NoErrorf(t, err, "should getting last jobs for backup %s", backup.ID)
+	require.Equal(t, 2, len(jobsForBackup))
+	rowCounts := map[string]int64{}
+	for _, job := range jobsForBackup {
+		require.NotNilf(t, job.SourceRowCount, "job for %s should record the row count of its partition", job.Source)
+		rowCounts[job.Source] = *job.SourceRowCount
+	}
+	assert.Equal(t, map[string]int64{"partition$20190101": 10, "partition$20190102": 20}, rowCounts)
+}
+
 func TestBigQueryJobCreator_PrepareJobs_partitionTables_Mirror_withJobsThatAreAlreadyScheduled_expectChanges(t *testing.T) {
 	// Given
 	ctx := context.Background()
@@ -428,6 +463,58 @@ func TestBigQueryJobCreator_PrepareJobs_Snapshot_nonPartitionTables_expectNewJob
 	assert.Equal(t, 1, len(jobsForBackup))
 }
 
+func TestBigQueryJobCreator_PrepareJobs_Snapshot_writesManifest(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	testContext := givenATestContext()
+	backup := newBigQuerySnapshotBackup("Snapshot_writesManifest", "dataset", []string{})
+	backup.Sink = "sink-bucket"
+	testContext.BackupRepository.AddBackup(ctx, backup)
+	testContext.BigQuery.fDoesDatasetExists = true
+	testContext.BigQuery.fGetTable = &bq.Table{Name: "orders", Checksum: "111"}
+	testContext.BigQuery.fGetTablesInDataset = []*bq.Table{{Name: "orders", Checksum: "111"}}
+	testContext.BigQuery.fGetDatasetManifest = &bq.DatasetManifest{Dataset: "dataset", Tables: []bq.TableManifest{
+		{Name: "orders", Type: "TABLE", Clustering: []string{"customer_id"}},
+		{Name: "open_orders", Type: "VIEW", ViewQuery: "SELECT * FROM dataset.orders WHERE open"},
+	}}
+	bigQueryJobCreator := givenABigQueryJobCreatorWithTestContext(testContext)
+	// When
+	err := bigQueryJobCreator.PrepareJobs(ctx, backup)
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+
+	// Then
+	jobsForBackup, err := testContext.MemoryJobRepository.ListNotScheduledJobsForBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobsForBackup))
+	require.NotEmpty(t, jobsForBackup[0].ManifestID)
+	content, exists := testContext.CloudStorageClient.fCreatedObjects["sink-bucket/"+repository.BuildBigQueryManifestPath("dataset", jobsForBackup[0].ManifestID)]
+	require.True(t, exists, "manifest should be written into the sink")
+	assert.Contains(t, content, `"view_query":"SELECT * FROM dataset.orders WHERE open"`)
+	assert.Contains(t, content, `"clustering":["customer_id"]`)
+}
+
+func TestBigQueryJobCreator_PrepareJobs_Snapshot_withoutManifest(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	testContext := givenATestContext()
+	backup := newBigQuerySnapshotBackup("Snapshot_withoutManifest", "dataset", []string{})
+	testContext.BackupRepository.AddBackup(ctx, backup)
+	testContext.BigQuery.fDoesDatasetExists = true
+	testContext.BigQuery.fGetTable = &bq.Table{Name: "orders", Checksum: "111"}
+	testContext.BigQuery.fGetTablesInDataset = []*bq.Table{{Name: "orders", Checksum: "111"}}
+	bigQueryJobCreator := givenABigQueryJobCreatorWithTestContext(testContext)
+	// When
+	err := bigQueryJobCreator.PrepareJobs(ctx, backup)
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+
+	// Then
+	jobsForBackup, err := testContext.MemoryJobRepository.ListNotScheduledJobsForBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobsForBackup))
+	assert.Empty(t, jobsForBackup[0].ManifestID)
+	assert.Empty(t, testContext.CloudStorageClient.fCreatedObjects)
+}
+
 func TestBigQueryJobCreator_PrepareJobs_Snapshot_partitionTables_expectNewJobs(t *testing.T) {
 	// Given
 	ctx := context.Background()
@@ -473,6 +560,111 @@ func TestBigQueryJobCreator_PrepareJobs_Snapshot_partitionTable_expectNewJobs(t
 	assert.Equal(t, 1, len(jobsForBackup))
 }
 
+func TestBigQueryJobCreator_PrepareJobs_TableSnapshot_partitionTables_expectJobPerTable(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	testContext := givenATestContext()
+	backup := newBigQuerySnapshotBackup("TableSnapshot_partitionTables_expectJobPerTable", "dataset", []string{"partition$20190101", "partition$20190102", "orders"})
+	backup.Strategy = repository.TableSnapshot
+	testContext.BackupRepository.AddBackup(ctx, backup)
+	testContext.BigQuery.fDoesDatasetExists = true
+	bigQueryJobCreator := givenABigQueryJobCreatorWithTestContext(testContext)
+	// When
+	err := bigQueryJobCreator.PrepareJobs(ctx, backup)
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+
+	// Then
+	jobsForBackup, err := testContext.MemoryJobRepository.ListNotScheduledJobsForBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	var sources []string
+	for _, job := range jobsForBackup {
+		sources = append(sources, job.Source)
+		assert.Empty(t, job.ManifestID)
+	}
+	assert.ElementsMatch(t, []string{"partition", "orders"}, sources)
+	assert.Empty(t, testContext.CloudStorageClient.fCreatedObjects)
+}
+
+func TestBigQueryJobCreator_PrepareJobsForTables_Snapshot_onlyGivenTables(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	testContext := givenATestContext()
+	backup := newBigQuerySnapshotBackup("PrepareJobsForTables_Snapshot_onlyGivenTables", "dataset", []string{})
+	testContext.BackupRepository.AddBackup(ctx, backup)
+	testContext.BigQuery.fDoesDatasetExists = true
+	testContext.BigQuery.fDoesTableHasPartitions = false
+	testContext.BigQuery.fGetTable = &bq.Table{Name: "orders", Checksum: "111"}
+	testContext.BigQuery.fGetTablesInDataset = []*bq.Table{{Name: "orders"}, {Name: "customers"}}
+	bigQueryJobCreator := givenABigQueryJobCreatorWithTestContext(testContext)
+	// When
+	jobs, err := bigQueryJobCreator.PrepareJobsForTables(ctx, backup, []string{"orders"})
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+
+	// Then
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "orders", jobs[0].Source)
+	jobsForBackup, err := testContext.MemoryJobRepository.ListNotScheduledJobsForBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(jobsForBackup))
+	storedBackup, err := testContext.BackupRepository.GetBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	assert.True(t, storedBackup.LastScheduledTime.IsZero(), "a run for a subset of tables must not change the schedule")
+}
+
+func TestBigQueryJobCreator_PrepareJobsForTables_Mirror_subsetNotSupported(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	testContext := givenATestContext()
+	backup := newBigQueryMirrorBackup("PrepareJobsForTables_Mirror_subsetNotSupported", "dataset", []string{})
+	testContext.BackupRepository.AddBackup(ctx, backup)
+	testContext.BigQuery.fDoesDatasetExists = true
+	bigQueryJobCreator := givenABigQueryJobCreatorWithTestContext(testContext)
+	// When
+	_, err := bigQueryJobCreator.PrepareJobsForTables(ctx, backup, []string{"orders"})
+	// Then
+	require.Error(t, err)
+}
+
+func TestBigQueryJobCreator_PrepareJobs_TableSnapshot_datasetWithExcludedTables(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	testContext := givenATestContext()
+	backup := newBigQuerySnapshotBackup("TableSnapshot_datasetWithExcludedTables", "dataset", []string{})
+	backup.Strategy = repository.TableSnapshot
+	backup.ExcludedTables = []string{"tmp"}
+	testContext.BackupRepository.AddBackup(ctx, backup)
+	testContext.BigQuery.fDoesDatasetExists = true
+	testContext.BigQuery.fGetTablesInDataset = []*bq.Table{{Name: "orders"}, {Name: "tmp"}}
+	bigQueryJobCreator := givenABigQueryJobCreatorWithTestContext(testContext)
+	// When
+	err := bigQueryJobCreator.PrepareJobs(ctx, backup)
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+
+	// Then
+	jobsForBackup, err := testContext.MemoryJobRepository.ListNotScheduledJobsForBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobsForBackup))
+	assert.Equal(t, "orders", jobsForBackup[0].Source)
+}
+
+func TestPrepareSnapshotDataset_inSourceLocationWithLifetime(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	client := &testBigQueryClient{fGetDatasetDetails: &bigquery.DatasetMetadata{Location: "europe-west3"}}
+	backup := newBigQuerySnapshotBackup("prepareSnapshotDataset", "dataset", []string{})
+	backup.Strategy = repository.TableSnapshot
+	backup.TargetProject = "sink-project"
+	backup.Sink = "bkp_bq_1a2b-3c4d"
+	backup.SnapshotOptions.LifetimeInDays = 7
+
+	// When
+	err := prepareSnapshotDataset(ctx, client, backup)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"europe-west3/sink-project.bkp_bq_1a2b_3c4d": 7 * 24 * time.Hour}, client.fCreatedDatasets)
+}
+
 func TestCloudStorageJobCreator_PrepareJobs_strategyNotExist(t *testing.T) {
 	// Given
 	ctx := context.Background()
@@ -506,8 +698,136 @@ func TestCloudStorageJobCreator_PrepareJobs_SimpleBucket(t *testing.T) {
 	assert.Equal(t, 1, len(jobsForBackup))
 }
 
+func TestCloudStorageJobCreator_PrepareJobsForPrefixes(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	backup := newCloudStorageSnapshotBackup("PrepareJobsForPrefixes", "test-bucket")
+	testContext := givenACloudStorageJobTesttestContext()
+	testContext.BackupRepository.AddBackup(ctx, backup)
+	cloudStorageJobCreator := givenACloudStorageJobCreatorWithTestContext(testContext)
+	// When
+	jobs, err := cloudStorageJobCreator.PrepareJobsForPrefixes(ctx, backup, []string{"exports/2024/"})
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+
+	// Then
+	require.Len(t, jobs, 1)
+	assert.Equal(t, []string{"exports/2024/"}, jobs[0].IncludePrefixes)
+	assert.Empty(t, jobs[0].CloudStorageID)
+	storedBackup, err := testContext.BackupRepository.GetBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	assert.True(t, storedBackup.LastScheduledTime.IsZero(), "a run for a subset of prefixes must not change the schedule")
+}
+
+func TestFirestoreJobCreator_PrepareJobs_Snapshot(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	backup := newFirestoreSnapshotBackup("firestoreSnapshot", repository.FirestoreDefaultDatabase)
+	backupRepository := &memory.BackupRepository{}
+	jobRepository := &memory.JobRepository{}
+	backupRepository.AddBackup(ctx, backup)
+	adminClient := &firestore.MockAdminClient{Databases: map[string]int64{"projects/source-project/databases/(default)": 1}}
+	firestoreJobCreator := NewFirestoreJobCreator(ctx, backupRepository, jobRepository, adminClient)
+	// When
+	err := firestoreJobCreator.PrepareJobs(ctx, backup)
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+
+	// Then
+	jobsForBackup, err := jobRepository.ListNotScheduledJobsForBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobsForBackup))
+	assert.Equal(t, repository.Firestore, jobsForBackup[0].Type)
+	assert.Equal(t, repository.FirestoreDefaultDatabase, jobsForBackup[0].Source)
+}
+
+func TestFirestoreJobCreator_PrepareJobs_databaseNotExist(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	backup := newFirestoreSnapshotBackup("databaseNotExist", "orders")
+	backupRepository := &memory.BackupRepository{}
+	jobRepository := &memory.JobRepository{}
+	backupRepository.AddBackup(ctx, backup)
+	firestoreJobCreator := NewFirestoreJobCreator(ctx, backupRepository, jobRepository, &firestore.MockAdminClient{})
+	// When
+	err := firestoreJobCreator.PrepareJobs(ctx, backup)
+	// Then
+	assert.ErrorIs(t, err, BackupSourceNotFoundErr)
+}
+
+func TestFirestoreJobCreator_PrepareJobs_Mirror(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	backup := newFirestoreSnapshotBackup("firestoreMirror", repository.FirestoreDefaultDatabase)
+	backup.Strategy = repository.Mirror
+	adminClient := &firestore.MockAdminClient{Databases: map[string]int64{"projects/source-project/databases/(default)": 1}}
+	firestoreJobCreator := NewFirestoreJobCreator(ctx, &memory.BackupRepository{}, &memory.JobRepository{}, adminClient)
+	// When
+	err := firestoreJobCreator.PrepareJobs(ctx, backup)
+	// Then
+	require.Error(t, err, "expected error for mirror strategy")
+}
+
+func TestCloudSQLJobCreator_PrepareJobs_AllDatabases(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	backup := newCloudSQLSnapshotBackup("cloudSQLAllDatabases", "orders-db")
+	backupRepository := &memory.BackupRepository{}
+	jobRepository := &memory.JobRepository{}
+	backupRepository.AddBackup(ctx, backup)
+	adminClient := &cloudsql.MockAdminClient{Instances: map[string]*cloudsql.MockInstance{"source-project:orders-db": {Databases: []string{"orders", "customers"}}}}
+	cloudSQLJobCreator := NewCloudSQLJobCreator(ctx, backupRepository, jobRepository, adminClient)
+	// When
+	err := cloudSQLJobCreator.PrepareJobs(ctx, backup)
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+
+	// Then
+	jobsForBackup, err := jobRepository.ListNotScheduledJobsForBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(jobsForBackup))
+	var sources []string
+	for _, job := range jobsForBackup {
+		assert.Equal(t, repository.CloudSQL, job.Type)
+		sources = append(sources, job.Source)
+	}
+	assert.ElementsMatch(t, []string{"orders", "customers"}, sources)
+}
+
+func TestCloudSQLJobCreator_PrepareJobs_SelectedDatabases(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	backup := newCloudSQLSnapshotBackup("cloudSQLSelectedDatabases", "orders-db")
+	backup.CloudSQLOptions.Databases = []string{"orders"}
+	backupRepository := &memory.BackupRepository{}
+	jobRepository := &memory.JobRepository{}
+	backupRepository.AddBackup(ctx, backup)
+	adminClient := &cloudsql.MockAdminClient{Instances: map[string]*cloudsql.MockInstance{"source-project:orders-db": {Databases: []string{"orders", "customers"}}}}
+	cloudSQLJobCreator := NewCloudSQLJobCreator(ctx, backupRepository, jobRepository, adminClient)
+	// When
+	err := cloudSQLJobCreator.PrepareJobs(ctx, backup)
+	require.NoErrorf(t, err, "should prepare jobs for backup %s", backup.ID)
+
+	// Then
+	jobsForBackup, err := jobRepository.ListNotScheduledJobsForBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobsForBackup))
+	assert.Equal(t, "orders", jobsForBackup[0].Source)
+}
+
+func TestCloudSQLJobCreator_PrepareJobs_instanceNotExist(t *testing.T) {
+	// Given
+	ctx := context.Background()
+	backup := newCloudSQLSnapshotBackup("instanceNotExist", "orders-db")
+	backupRepository := &memory.BackupRepository{}
+	jobRepository := &memory.JobRepository{}
+	backupRepository.AddBackup(ctx, backup)
+	cloudSQLJobCreator := NewCloudSQLJobCreator(ctx, backupRepository, jobRepository, &cloudsql.MockAdminClient{})
+	// When
+	err := cloudSQLJobCreator.PrepareJobs(ctx, backup)
+	// Then
+	assert.ErrorIs(t, err, BackupSourceNotFoundErr)
+}
+
 func givenABigQueryJobCreatorWithTestContext(ctx *testContextBigQueryJobCreator) *BigQueryJobCreator {
-	return NewBigQueryJobCreator(context.Background(), ctx.BackupRepository, ctx.JobRepository, &ctx.BigQuery, ctx.SourceMetadataRepository, ctx.SourceMetadataJobRepository)
+	return NewBigQueryJobCreator(context.Background(), ctx.BackupRepository, ctx.JobRepository, &ctx.BigQuery, ctx.SourceMetadataRepository, ctx.SourceMetadataJobRepository, ctx.CloudStorageClient)
 }
 
 func givenACloudStorageJobCreatorWithTestContext(ctx *testContextCloudStorageJobCreator) *CloudStorageJobCreator {
@@ -534,6 +854,7 @@ func givenATestContext() *testContextBigQueryJobCreator {
 		SourceMetadataRepository:    &sourceMetadataRepository,
 		SourceMetadataJobRepository: &sourceMetadataJobRepository,
 		BigQuery:                    bigQueryClient,
+		CloudStorageClient:          &stubGcsClient{},
 	}
 }
 
@@ -559,6 +880,7 @@ type testContextBigQueryJobCreator struct {
 	BigQuery                    testBigQueryClient
 	SourceMetadataRepository    repository.SourceMetadataRepository
 	SourceMetadataJobRepository repository.SourceMetadataJobRepository
+	CloudStorageClient          *stubGcsClient
 }
 
 type testContextCloudStorageJobCreator struct {
@@ -579,6 +901,9 @@ type testBigQueryClient struct {
 	fGetTablesInDataset     []*bq.Table
 	fGetTable               *bq.Table
 	fGetTableErr            error
+	fGetDatasetManifest     *bq.DatasetManifest
+	fGetDatasetDetails      *bigquery.DatasetMetadata
+	fCreatedDatasets        map[string]time.Duration
 }
 
 func (t *testBigQueryClient) DeleteExtractJob(ctxIn context.Context, extractJobID repository.ExtractJobID) error {
@@ -605,7 +930,10 @@ func (*testBigQueryClient) IsInitialized(context.Context) bool {
 	panic("implement me")
 }
 
-func (*testBigQueryClient) ExtractTableToGcsAsAvro(c context.Context, dataset, table, gcsURI string) *bigquery.Extractor {
+func (*testBigQueryClient) Close(context.Context) {
+}
+
+func (*testBigQueryClient) ExtractTableToGcs(c context.Context, dataset, table, gcsURI string, format repository.ExportFormat, compression repository.ExportCompression) *bigquery.Extractor {
 	panic("implement me")
 }
 
@@ -613,6 +941,14 @@ func (*testBigQueryClient) GetExtractJobStatus(c context.Context, extractJobID r
 	panic("implement me")
 }
 
+func (*testBigQueryClient) LoadTableFromGcs(c context.Context, project, dataset, table, sourceURI string, format repository.ExportFormat, writeDisposition bigquery.TableWriteDisposition) *bigquery.Loader {
+	panic("implement me")
+}
+
+func (*testBigQueryClient) GetLoadJobStatus(c context.Context, loadJobID repository.LoadJobID) (*bigquery.JobStatus, error) {
+	panic("implement me")
+}
+
 func (t *testBigQueryClient) DoesDatasetExists(c context.Context, project string, dataset string) (bool, error) {
 	return t.fDoesDatasetExists, nil
 }
@@ -626,21 +962,88 @@ func (t *testBigQueryClient) HasTablePartitions(c context.Context, project strin
 }
 
 func (t *testBigQueryClient) GetDatasetDetails(ctxIn context.Context, project string, dataset string) (*bigquery.DatasetMetadata, error) {
+	if t.fGetDatasetDetails == nil {
+		return nil, fmt.Errorf("no details for dataset %s.%s", project, dataset)
+	}
+	return t.fGetDatasetDetails, nil
+}
+
+func (t *testBigQueryClient) GetDatasetManifest(ctxIn context.Context, project string, dataset string) (*bq.DatasetManifest, error) {
+	if t.fGetDatasetManifest == nil {
+		return nil, fmt.Errorf("no manifest for dataset %s.%s", project, dataset)
+	}
+	return t.fGetDatasetManifest, nil
+}
+
+func (t *testBigQueryClient) CreateTableFromManifest(ctxIn context.Context, project, dataset, table string, manifest bq.TableManifest) (bool, error) {
+	panic("implement me")
+}
+
+func (t *testBigQueryClient) CreateRoutineFromManifest(ctxIn context.Context, project, dataset, routine string, manifest bq.RoutineManifest) (bool, error) {
+	panic("implement me")
+}
+
+func (t *testBigQueryClient) AddDatasetAccess(ctxIn context.Context, project, dataset string, entries []bq.AccessEntryManifest) error {
+	panic("implement me")
+}
+
+func (t *testBigQueryClient) SnapshotTable(ctxIn context.Context, dataset, table, snapshotDataset, snapshotTable, kmsKeyName string) *bigquery.Copier {
+	panic("implement me")
+}
+
+func (t *testBigQueryClient) RestoreTableSnapshot(ctxIn context.Context, snapshotProject, snapshotDataset, snapshotTable, project, dataset, table string, writeDisposition bigquery.TableWriteDisposition) *bigquery.Copier {
+	panic("implement me")
+}
+
+func (t *testBigQueryClient) CreateDataset(ctxIn context.Context, project, dataset, location string, defaultTableExpiration time.Duration, labels map[string]string) (bool, error) {
+	if t.fCreatedDatasets == nil {
+		t.fCreatedDatasets = make(map[string]time.Duration)
+	}
+	t.fCreatedDatasets[fmt.Sprintf("%s/%s.%s", location, project, dataset)] = defaultTableExpiration
+	return true, nil
+}
+
+func (t *testBigQueryClient) UpdateDefaultTableExpiration(ctxIn context.Context, project, dataset string, defaultTableExpiration time.Duration) error {
+	panic("implement me")
+}
+
+func (t *testBigQueryClient) DeleteDataset(ctxIn context.Context, project, dataset string) error {
+	panic("implement me")
+}
+
+func (t *testBigQueryClient) DeleteTable(ctxIn context.Context, project, dataset, table string) error {
 	panic("implement me")
 }
 
 type stubGcsClient struct {
 	fDeleteObjectsErr error
+	fCreatedObjects   map[string]string
 }
 
 func (g *stubGcsClient) DeleteObjectWithPrefix(ctxIn context.Context, bucket string, objectPrefixName string) error {
 	panic("implement me")
 }
 
+func (g *stubGcsClient) HasObjectWithPrefix(ctxIn context.Context, bucket string, objectPrefixName string) (bool, error) {
+	panic("implement me")
+}
+
+func (g *stubGcsClient) CountObjectsWithPrefix(ctxIn context.Context, bucket string, objectPrefixName string) (int64, error) {
+	panic("implement me")
+}
+
+func (g *stubGcsClient) ListPrefixes(ctxIn context.Context, bucket string, objectPrefixName string) ([]string, error) {
+	panic("implement me")
+}
+
 func (g *stubGcsClient) GetProject(ctxIn context.Context, projectID string) (*resourcemanagerpb.Project, error) {
 	panic("implement me")
 }
 
+func (g *stubGcsClient) GetBucketIAMPolicy(ctxIn context.Context, bucket string) (*iam.Policy, error) {
+	panic("implement me")
+}
+
 func (g *stubGcsClient) SetBucketIAMPolicy(ctxIn context.Context, bucket string, policy *iam.Policy) error {
 	panic("implement me")
 }
@@ -654,7 +1057,11 @@ func (g *stubGcsClient) MoveObject(c context.Context, bucketName, oldObjectName,
 }
 
 func (g *stubGcsClient) CreateObject(c context.Context, bucketName, objectName, content string) error {
-	panic("implement me")
+	if g.fCreatedObjects == nil {
+		g.fCreatedObjects = map[string]string{}
+	}
+	g.fCreatedObjects[bucketName+"/"+objectName] = content
+	return nil
 }
 
 func (g *stubGcsClient) DeleteObject(c context.Context, bucketName string, objectName string) error {
@@ -697,10 +1104,26 @@ func (*stubGcsClient) ReadObject(c context.Context, bucketName, objectName strin
 	panic("implement me")
 }
 
+func (*stubGcsClient) GetRetentionExpirationTime(ctxIn context.Context, bucket string) (time.Time, error) {
+	panic("implement me")
+}
+
 func (*stubGcsClient) GetBucketDetails(ctxIn context.Context, bucket string) (*storage.BucketAttrs, error) {
 	panic("implement me")
 }
 
+func (*stubGcsClient) SetBucketDefaultKMSKey(ctxIn context.Context, bucket string, kmsKeyName string) error {
+	panic("implement me")
+}
+
+func (*stubGcsClient) SetBucketRetentionPolicy(ctxIn context.Context, bucket string, retentionInDays uint) error {
+	panic("implement me")
+}
+
+func (*stubGcsClient) LockBucketRetentionPolicy(ctxIn context.Context, bucket string) error {
+	panic("implement me")
+}
+
 type testGcsClientFactory struct {
 	CloudStorageClient gcs.CloudStorageClient
 }
@@ -725,6 +1148,23 @@ func newBigQueryMirrorBackup(backupID string, dataset string, tables []string) *
 	}
 }
 
+func newFirestoreSnapshotBackup(backupID string, database string) *repository.Backup {
+	return &repository.Backup{ID: backupID, Strategy: repository.Snapshot, Type: repository.Firestore, SourceProject: "source-project",
+		BackupOptions: repository.BackupOptions{FirestoreOptions: repository.FirestoreOptions{
+			Database: database,
+		}},
+	}
+}
+
+func newCloudSQLSnapshotBackup(backupID string, instance string) *repository.Backup {
+	return &repository.Backup{ID: backupID, Strategy: repository.Snapshot, Type: repository.CloudSQL, SourceProject: "source-project",
+		BackupOptions: repository.BackupOptions{CloudSQLOptions: repository.CloudSQLOptions{
+			Instance: instance,
+			FileType: repository.CloudSQLExportSQL,
+		}},
+	}
+}
+
 func newCloudStorageSnapshotBackup(backupID string, bucket string) *repository.Backup {
 	return &repository.Backup{ID: backupID, Strategy: repository.Snapshot, Type: repository.CloudStorage,
 		BackupOptions: repository.BackupOptions{CloudStorageOptions: repository.CloudStorageOptions{
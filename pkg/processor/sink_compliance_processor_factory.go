@@ -0,0 +1,97 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/http/auth"
+	"github.com/ottogroup/penelope/pkg/http/auth/model"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type SinkComplianceProcessorFactory interface {
+	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.ProjectSinkComplianceRequest, requestobjects.ProjectSinkComplianceResponse], error)
+}
+
+// sinkComplianceProcessorFactory create Operations for listing the compliance checks of sink projects
+type sinkComplianceProcessorFactory struct {
+	credentialsProvider secret.SecretProvider
+}
+
+func NewSinkComplianceProcessorFactory(credentialsProvider secret.SecretProvider) SinkComplianceProcessorFactory {
+	return &sinkComplianceProcessorFactory{credentialsProvider}
+}
+
+// CreateProcessor return Operations for listing the compliance checks of sink projects
+func (c sinkComplianceProcessorFactory) CreateProcessor(ctxIn context.Context) (Operation[requestobjects.ProjectSinkComplianceRequest, requestobjects.ProjectSinkComplianceResponse], error) {
+	ctx, span := trace.StartSpan(ctxIn, "newSinkComplianceProcessor")
+	defer span.End()
+
+	sinkComplianceCheckRepository, err := repository.NewSinkComplianceCheckRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &sinkComplianceProcessor{}, err
+	}
+
+	return &sinkComplianceProcessor{SinkComplianceCheckRepository: sinkComplianceCheckRepository}, nil
+}
+
+type sinkComplianceProcessor struct {
+	SinkComplianceCheckRepository repository.SinkComplianceCheckRepository
+}
+
+// Process list the latest check of every sink project or the history of one sink project, only checks of sink projects
+// the user may list or with backups of projects the user may list are shown
+func (l sinkComplianceProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.ProjectSinkComplianceRequest]) (requestobjects.ProjectSinkComplianceResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(sinkComplianceProcessor).Process")
+	defer span.End()
+
+	var request = args.Request
+
+	var checks []*repository.SinkComplianceCheck
+	var err error
+	if request.Project == "" {
+		checks, err = l.SinkComplianceCheckRepository.GetLatestSinkComplianceChecks(ctx)
+	} else {
+		page := repository.Page{Size: request.Page.Size, Number: request.Page.Number}
+		if page.Size <= 0 {
+			page.Size = repository.AllJobs
+		}
+		checks, err = l.SinkComplianceCheckRepository.GetSinkComplianceChecks(ctx, request.Project, page)
+	}
+	if err != nil {
+		return requestobjects.ProjectSinkComplianceResponse{}, errors.Wrap(err, "sink compliance check repository failed")
+	}
+
+	response := requestobjects.ProjectSinkComplianceResponse{Checks: []requestobjects.ProjectSinkComplianceCheck{}}
+	for _, check := range checks {
+		if !isSinkComplianceCheckVisible(args.Principal, check) {
+			continue
+		}
+		response.Checks = append(response.Checks, requestobjects.ProjectSinkComplianceCheck{
+			Project:        check.ProjectSink,
+			SourceProjects: check.SourceProjects,
+			Compliant:      check.Compliant,
+			Reasons:        check.Reasons,
+			LastCheck:      check.LastCheck,
+		})
+	}
+
+	return response, nil
+}
+
+func isSinkComplianceCheckVisible(principal *model.Principal, check *repository.SinkComplianceCheck) bool {
+	if auth.CheckRequestIsAllowed(principal, requestobjects.Listing, check.ProjectSink) {
+		return true
+	}
+	for _, sourceProject := range check.SourceProjects {
+		if auth.CheckRequestIsAllowed(principal, requestobjects.Listing, sourceProject) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/http/auth/model"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSinkComplianceCheckRepository struct {
+	latest      []*repository.SinkComplianceCheck
+	history     []*repository.SinkComplianceCheck
+	projectSink string
+	page        repository.Page
+}
+
+func (f *fakeSinkComplianceCheckRepository) AddSinkComplianceCheck(context.Context, *repository.SinkComplianceCheck) error {
+	return nil
+}
+
+func (f *fakeSinkComplianceCheckRepository) GetLatestSinkComplianceChecks(context.Context) ([]*repository.SinkComplianceCheck, error) {
+	return f.latest, nil
+}
+
+func (f *fakeSinkComplianceCheckRepository) GetSinkComplianceChecks(_ context.Context, projectSink string, page repository.Page) ([]*repository.SinkComplianceCheck, error) {
+	f.projectSink = projectSink
+	f.page = page
+	return f.history, nil
+}
+
+func TestSinkComplianceProcessor_ShowsOnlyAllowedChecks(t *testing.T) {
+	checkRepository := &fakeSinkComplianceCheckRepository{latest: []*repository.SinkComplianceCheck{
+		{ProjectSink: "sink-a", SourceProjects: []string{"source-a"}, Compliant: true},
+		{ProjectSink: "sink-b", SourceProjects: []string{"source-b", "source-c"}, Compliant: false, Reasons: []string{"Backup project should have single writer"}},
+		{ProjectSink: "sink-c", SourceProjects: []string{"source-d"}, Compliant: true},
+	}}
+	principal := &model.Principal{
+		User: model.User{Email: "security@example.com"},
+		RoleBindings: []model.ProjectRoleBinding{
+			{Role: model.Viewer, Project: "sink-a"},
+			{Role: model.Owner, Project: "source-c"},
+		},
+	}
+
+	response, err := sinkComplianceProcessor{SinkComplianceCheckRepository: checkRepository}.Process(context.Background(), &Argument[requestobjects.ProjectSinkComplianceRequest]{Principal: principal})
+	require.NoError(t, err)
+
+	require.Len(t, response.Checks, 2)
+	assert.Equal(t, "sink-a", response.Checks[0].Project)
+	assert.Equal(t, "sink-b", response.Checks[1].Project, "a sink project with backups of an allowed source project is shown")
+	assert.False(t, response.Checks[1].Compliant)
+	assert.Equal(t, []string{"Backup project should have single writer"}, response.Checks[1].Reasons)
+}
+
+func TestSinkComplianceProcessor_History(t *testing.T) {
+	checkRepository := &fakeSinkComplianceCheckRepository{history: []*repository.SinkComplianceCheck{
+		{ProjectSink: "sink-a", SourceProjects: []string{"source-a"}, Compliant: false},
+		{ProjectSink: "sink-a", SourceProjects: []string{"source-a"}, Compliant: true},
+	}}
+	principal := &model.Principal{
+		User:         model.User{Email: "security@example.com"},
+		RoleBindings: []model.ProjectRoleBinding{{Role: model.Viewer, Project: "source-a"}},
+	}
+
+	request := requestobjects.ProjectSinkComplianceRequest{Project: "sink-a", Page: requestobjects.Page{Size: 10, Number: 1}}
+	response, err := sinkComplianceProcessor{SinkComplianceCheckRepository: checkRepository}.Process(context.Background(), &Argument[requestobjects.ProjectSinkComplianceRequest]{Request: request, Principal: principal})
+	require.NoError(t, err)
+
+	assert.Equal(t, "sink-a", checkRepository.projectSink)
+	assert.Equal(t, repository.Page{Size: 10, Number: 1}, checkRepository.page)
+	require.Len(t, response.Checks, 2)
+	assert.False(t, response.Checks[0].Compliant)
+}
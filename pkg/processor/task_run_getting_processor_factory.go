@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type TaskRunGettingProcessorFactory interface {
+	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.TaskRunGettingRequest, requestobjects.TaskRunResponse], error)
+}
+
+// taskRunGettingProcessorFactory create Operations for getting a run of a background task
+type taskRunGettingProcessorFactory struct {
+	credentialsProvider secret.SecretProvider
+}
+
+func NewTaskRunGettingProcessorFactory(credentialsProvider secret.SecretProvider) TaskRunGettingProcessorFactory {
+	return &taskRunGettingProcessorFactory{credentialsProvider}
+}
+
+// CreateProcessor return Operations for getting a run of a background task
+func (c taskRunGettingProcessorFactory) CreateProcessor(ctxIn context.Context) (Operation[requestobjects.TaskRunGettingRequest, requestobjects.TaskRunResponse], error) {
+	ctx, span := trace.StartSpan(ctxIn, "newTaskRunGettingProcessor")
+	defer span.End()
+
+	taskRunRepository, err := repository.NewTaskRunRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &taskRunGettingProcessor{}, err
+	}
+
+	return &taskRunGettingProcessor{TaskRunRepository: taskRunRepository, appProject: config.GCPProjectId.GetOrDefault("")}, nil
+}
+
+type taskRunGettingProcessor struct {
+	TaskRunRepository repository.TaskRunRepository
+	appProject        string
+}
+
+func (l taskRunGettingProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.TaskRunGettingRequest]) (requestobjects.TaskRunResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(taskRunGettingProcessor).Process")
+	defer span.End()
+
+	var request = args.Request
+
+	if err := checkTaskRunsAllowed(args.Principal, l.appProject); err != nil {
+		return requestobjects.TaskRunResponse{}, err
+	}
+
+	run, err := l.TaskRunRepository.GetTaskRun(ctx, request.ID)
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			return requestobjects.TaskRunResponse{}, requestobjects.ApiError{
+				Code:    http.StatusNotFound,
+				Message: fmt.Sprintf("no task run with id %q found", request.ID),
+			}
+		}
+		return requestobjects.TaskRunResponse{}, errors.Wrapf(err, "get task run failed %s", request.ID)
+	}
+
+	return mapTaskRunToResponse(run), nil
+}
@@ -0,0 +1,108 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/http/auth"
+	"github.com/ottogroup/penelope/pkg/http/auth/model"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+const defaultTaskRunPageSize = 100
+
+type TaskRunListingProcessorFactory interface {
+	CreateProcessor(ctxIn context.Context) (Operation[requestobjects.TaskRunListingRequest, requestobjects.TaskRunListingResponse], error)
+}
+
+// taskRunListingProcessorFactory create Operations for listing the runs of background tasks
+type taskRunListingProcessorFactory struct {
+	credentialsProvider secret.SecretProvider
+	tasks               []string
+}
+
+// NewTaskRunListingProcessorFactory create a new factory, tasks are the known tasks a listing can be filtered by
+func NewTaskRunListingProcessorFactory(credentialsProvider secret.SecretProvider, tasks []string) TaskRunListingProcessorFactory {
+	return &taskRunListingProcessorFactory{credentialsProvider, tasks}
+}
+
+// CreateProcessor return Operations for listing the runs of background tasks
+func (c taskRunListingProcessorFactory) CreateProcessor(ctxIn context.Context) (Operation[requestobjects.TaskRunListingRequest, requestobjects.TaskRunListingResponse], error) {
+	ctx, span := trace.StartSpan(ctxIn, "newTaskRunListingProcessor")
+	defer span.End()
+
+	taskRunRepository, err := repository.NewTaskRunRepository(ctx, c.credentialsProvider)
+	if err != nil {
+		glog.Error(err)
+		return &taskRunListingProcessor{}, err
+	}
+
+	return &taskRunListingProcessor{TaskRunRepository: taskRunRepository, tasks: c.tasks, appProject: config.GCPProjectId.GetOrDefault("")}, nil
+}
+
+type taskRunListingProcessor struct {
+	TaskRunRepository repository.TaskRunRepository
+	tasks             []string
+	appProject        string
+}
+
+func (l taskRunListingProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.TaskRunListingRequest]) (requestobjects.TaskRunListingResponse, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(taskRunListingProcessor).Process")
+	defer span.End()
+
+	var request = args.Request
+
+	if err := checkTaskRunsAllowed(args.Principal, l.appProject); err != nil {
+		return requestobjects.TaskRunListingResponse{}, err
+	}
+
+	if request.Task != "" && !slices.Contains(l.tasks, request.Task) {
+		return requestobjects.TaskRunListingResponse{}, requestobjects.ApiError{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("unknown task %q", request.Task),
+		}
+	}
+
+	page := repository.Page{Size: request.Page.Size, Number: request.Page.Number}
+	if page.Size <= 0 {
+		page.Size = defaultTaskRunPageSize
+	}
+	if page.Number < 0 {
+		page.Number = 0
+	}
+
+	runs, err := l.TaskRunRepository.ListTaskRuns(ctx, request.Task, page)
+	if err != nil {
+		return requestobjects.TaskRunListingResponse{}, errors.Wrap(err, "task run repository ListTaskRuns failed")
+	}
+
+	response := requestobjects.TaskRunListingResponse{TaskRuns: []requestobjects.TaskRunResponse{}}
+	for _, run := range runs {
+		response.TaskRuns = append(response.TaskRuns, mapTaskRunToResponse(run))
+	}
+	return response, nil
+}
+
+// checkTaskRunsAllowed task runs are not bound to a project and their errors name backups of any project, so only
+// principals with a role in the project Penelope runs in can see them
+func checkTaskRunsAllowed(principal *model.Principal, appProject string) error {
+	if !auth.CheckRequestIsAllowed(principal, requestobjects.Listing, appProject) {
+		email := ""
+		if principal != nil {
+			email = principal.User.Email
+		}
+		return requestobjects.ApiError{
+			Code:    http.StatusForbidden,
+			Message: fmt.Sprintf("%s of task runs is not allowed for user %q on project %q", requestobjects.Listing.String(), email, appProject),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/http/auth/model"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTaskRunRepository struct {
+	repository.TaskRunRepository
+	runs []*repository.TaskRun
+}
+
+func (f *fakeTaskRunRepository) GetTaskRun(_ context.Context, id string) (*repository.TaskRun, error) {
+	for _, run := range f.runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeTaskRunRepository) ListTaskRuns(context.Context, string, repository.Page) ([]*repository.TaskRun, error) {
+	return f.runs, nil
+}
+
+func TestTaskRunProcessors_RestrictedToAppProject(t *testing.T) {
+	taskRunRepository := &fakeTaskRunRepository{runs: []*repository.TaskRun{{ID: "run-1", Task: "prepare_backup_jobs"}}}
+	outsider := &model.Principal{
+		User:         model.User{Email: "outsider@example.com"},
+		RoleBindings: []model.ProjectRoleBinding{{Role: model.Owner, Project: "other-project"}},
+	}
+	member := &model.Principal{
+		User:         model.User{Email: "member@example.com"},
+		RoleBindings: []model.ProjectRoleBinding{{Role: model.Viewer, Project: "penelope-project"}},
+	}
+
+	listing := taskRunListingProcessor{TaskRunRepository: taskRunRepository, appProject: "penelope-project"}
+	getting := taskRunGettingProcessor{TaskRunRepository: taskRunRepository, appProject: "penelope-project"}
+
+	t.Run("principal without role on app project", func(t *testing.T) {
+		var apiErr requestobjects.ApiError
+
+		_, err := listing.Process(context.Background(), &Argument[requestobjects.TaskRunListingRequest]{Principal: outsider})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.Code)
+
+		_, err = getting.Process(context.Background(), &Argument[requestobjects.TaskRunGettingRequest]{Request: requestobjects.TaskRunGettingRequest{ID: "run-1"}, Principal: outsider})
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusForbidden, apiErr.Code)
+	})
+
+	t.Run("principal with role on app project", func(t *testing.T) {
+		listResponse, err := listing.Process(context.Background(), &Argument[requestobjects.TaskRunListingRequest]{Principal: member})
+		require.NoError(t, err)
+		require.Len(t, listResponse.TaskRuns, 1)
+
+		getResponse, err := getting.Process(context.Background(), &Argument[requestobjects.TaskRunGettingRequest]{Request: requestobjects.TaskRunGettingRequest{ID: "run-1"}, Principal: member})
+		require.NoError(t, err)
+		assert.Equal(t, "run-1", getResponse.ID)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/ottogroup/penelope/pkg/http/auth"
 	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/provider"
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/requestobjects"
 	"github.com/ottogroup/penelope/pkg/secret"
@@ -22,12 +23,15 @@ type UpdatingProcessorFactory interface {
 
 // UpdatingProcessorFactory factory for operation Updating
 type updatingProcessorFactory struct {
-	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
-	credentialsProvider secret.SecretProvider
+	tokenSourceProvider      impersonate.TargetPrincipalForProjectProvider
+	credentialsProvider      secret.SecretProvider
+	sourceGCPProjectProvider provider.SourceGCPProjectProvider
+	policyRuleProvider       provider.PolicyRuleProvider
 }
 
-func NewUpdatingProcessorFactory(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) UpdatingProcessorFactory {
-	return &updatingProcessorFactory{tokenSourceProvider, credentialsProvider}
+// NewUpdatingProcessorFactory create a new factory, policyRuleProvider is optional and without it no policy rules are enforced
+func NewUpdatingProcessorFactory(tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider, policyRuleProvider provider.PolicyRuleProvider) UpdatingProcessorFactory {
+	return &updatingProcessorFactory{tokenSourceProvider, credentialsProvider, sourceGCPProjectProvider, policyRuleProvider}
 }
 
 // CreateProcessor create instance of Operations
@@ -48,9 +52,11 @@ func (c updatingProcessorFactory) CreateProcessor(ctxIn context.Context) (Operat
 	}
 
 	return &updatingProcessor{
-		BackupRepository:    backupRepository,
-		JobRepository:       jobRepository,
-		tokenSourceProvider: c.tokenSourceProvider,
+		BackupRepository:         backupRepository,
+		JobRepository:            jobRepository,
+		tokenSourceProvider:      c.tokenSourceProvider,
+		sourceGCPProjectProvider: c.sourceGCPProjectProvider,
+		policyRuleProvider:       c.policyRuleProvider,
 	}, nil
 }
 
@@ -59,7 +65,9 @@ type updatingProcessor struct {
 	JobRepository    repository.JobRepository
 	Context          context.Context
 
-	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
+	tokenSourceProvider      impersonate.TargetPrincipalForProjectProvider
+	sourceGCPProjectProvider provider.SourceGCPProjectProvider
+	policyRuleProvider       provider.PolicyRuleProvider
 }
 
 func (c updatingProcessor) Process(ctxIn context.Context, args *Argument[requestobjects.UpdateRequest]) (requestobjects.UpdateResponse, error) {
@@ -113,6 +121,26 @@ func (c updatingProcessor) Process(ctxIn context.Context, args *Argument[request
 			return requestobjects.UpdateResponse{}, fmt.Errorf("bigQuery request has intersection in tables: %s, %s", request.Table, request.ExcludedTables)
 		}
 	}
+	var schedule *repository.ScheduleOptions
+	if request.Schedule != nil {
+		if err := ValidateScheduleOptions(backup.Strategy.String(), backup.FrequencyInHours, request.Schedule); err != nil {
+			return requestobjects.UpdateResponse{}, requestobjects.ApiError{
+				Code:    400,
+				Message: fmt.Sprintf("invalid schedule: %s", err),
+			}
+		}
+		scheduleOptions := mapScheduleOptions(request.Schedule)
+		schedule = &scheduleOptions
+	}
+
+	lifetimeInDays := requestedLifetimeInDays(backup.Strategy, request)
+	if backup.RetentionLocked && lifetimeInDays > 0 && lifetimeInDays < backup.RetentionInDays() {
+		return requestobjects.UpdateResponse{}, requestobjects.ApiError{
+			Code:    400,
+			Message: fmt.Sprintf("retention policy of backup %s is locked, its lifetime of %d days can only be increased", backup.ID, backup.RetentionInDays()),
+		}
+	}
+
 	fields := repository.UpdateFields{
 		BackupID:               request.BackupID,
 		Status:                 repository.BackupStatus(request.Status),
@@ -121,18 +149,46 @@ func (c updatingProcessor) Process(ctxIn context.Context, args *Argument[request
 		ExcludePath:            request.ExcludePath,
 		Table:                  request.Table,
 		ExcludedTables:         request.ExcludedTables,
+		CollectionIDs:          request.CollectionIDs,
+		Databases:              request.Databases,
 		MirrorTTL:              request.MirrorTTL,
 		SnapshotTTL:            request.SnapshotTTL,
 		ArchiveTTM:             request.ArchiveTTM,
 		RecoveryPointObjective: request.RecoveryPointObjective,
 		RecoveryTimeObjective:  request.RecoveryTimeObjective,
+		MaxRetryAttempts:       request.MaxRetryAttempts,
+		Schedule:               schedule,
+	}
+
+	sourceGCPProject, err := c.sourceGCPProjectProvider.GetSourceGCPProject(ctx, backup.SourceProject)
+	if err != nil {
+		return requestobjects.UpdateResponse{}, err
 	}
+	policyWarnings, err := checkBackupPolicies(ctx, c.policyRuleProvider, applyUpdateFields(backup, fields), backup, sourceGCPProject.AvailabilityClass)
+	if err != nil {
+		return requestobjects.UpdateResponse{}, err
+	}
+
 	err = c.BackupRepository.UpdateBackup(ctx, fields)
 
 	if err != nil {
 		return requestobjects.UpdateResponse{}, err
 	}
 
+	if backup.Strategy == repository.TableSnapshot {
+		err = c.updateSnapshotDataset(ctx, backup, request)
+		if err != nil {
+			return requestobjects.UpdateResponse{}, err
+		}
+		backup, err = c.BackupRepository.GetBackup(ctx, request.BackupID)
+		if err != nil {
+			return requestobjects.UpdateResponse{}, err
+		}
+		response := prepareUpdateResponse(backup)
+		response.PolicyWarnings = policyWarnings
+		return response, nil
+	}
+
 	client, err := gcs.NewCloudStorageClient(ctx, c.tokenSourceProvider, backup.TargetProject)
 	if err != nil {
 		return requestobjects.UpdateResponse{}, fmt.Errorf("updatingProcessor.Process NewCloudStorageClient failed: %v", err)
@@ -167,13 +223,64 @@ func (c updatingProcessor) Process(ctxIn context.Context, args *Argument[request
 	}
 
 	backup, err = c.BackupRepository.GetBackup(ctx, request.BackupID)
-	return prepareUpdateResponse(backup), err
+	if err != nil {
+		return requestobjects.UpdateResponse{}, err
+	}
+	if backup.Immutable && lifetimeInDays > 0 {
+		err = client.SetBucketRetentionPolicy(ctx, backup.Sink, backup.RetentionInDays())
+		if err != nil {
+			return requestobjects.UpdateResponse{}, fmt.Errorf("updatingProcessor.Process SetBucketRetentionPolicy failed: %v", err)
+		}
+	}
+	response := prepareUpdateResponse(backup)
+	response.PolicyWarnings = policyWarnings
+	return response, nil
+}
+
+// requestedLifetimeInDays returns the lifetime the request sets for the strategy of the backup, 0 if it is not changed
+func requestedLifetimeInDays(strategy repository.Strategy, request requestobjects.UpdateRequest) uint {
+	switch strategy {
+	case repository.Mirror:
+		return request.MirrorTTL
+	case repository.Snapshot:
+		return request.SnapshotTTL
+	}
+	return 0
+}
+
+// updateSnapshotDataset recreate a deleted snapshot dataset and apply a changed lifetime to the table snapshots created afterwards
+func (c updatingProcessor) updateSnapshotDataset(ctxIn context.Context, backup *repository.Backup, request requestobjects.UpdateRequest) error {
+	ctx, span := trace.StartSpan(ctxIn, "(updatingProcessor).updateSnapshotDataset")
+	defer span.End()
+
+	client, err := bigquery.NewBigQueryClient(ctx, c.tokenSourceProvider, backup.SourceProject, backup.TargetProject)
+	if err != nil {
+		return fmt.Errorf("failed to create BigQuery client: %s", err)
+	}
+
+	// if backup was deleted, create snapshot dataset again
+	if repository.BackupDeleted.EqualTo(backup.Status.String()) && repository.NotStarted.EqualTo(request.Status) {
+		glog.Infof("recreating snapshot dataset for backup: %v", backup)
+		err = prepareSnapshotDataset(ctx, client, backup)
+		if err != nil {
+			return fmt.Errorf("snapshot dataset couldn't be prepared: %v", backup)
+		}
+	}
+
+	if request.SnapshotTTL > 0 {
+		err = client.UpdateDefaultTableExpiration(ctx, backup.TargetProject, repository.BuildTableSnapshotDataset(backup.Sink), tableSnapshotExpiration(request.SnapshotTTL))
+		if err != nil {
+			return fmt.Errorf("updatingProcessor.Process UpdateDefaultTableExpiration for TableSnapshot failed: %v", err)
+		}
+	}
+	return nil
 }
 
 func prepareUpdateResponse(backup *repository.Backup) requestobjects.UpdateResponse {
 	updateResponse := requestobjects.UpdateResponse{}
 	updateResponse.Status = backup.Status.String()
 	updateResponse.BackupID = backup.ID
+	updateResponse.Schedule = mapScheduleOptionsToResponse(backup.ScheduleOptions)
 	updateResponse.CreatedTimestamp = formatTime(backup.CreatedTimestamp)
 	updateResponse.UpdatedTimestamp = formatTime(backup.UpdatedTimestamp)
 	updateResponse.DeletedTimestamp = formatTime(backup.DeletedTimestamp)
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"go.opencensus.io/trace"
+	"gopkg.in/yaml.v3"
+)
+
+type NotificationChannelType string
+
+const (
+	WebhookChannel NotificationChannelType = "webhook"
+	SlackChannel   NotificationChannelType = "slack"
+	EmailChannel   NotificationChannelType = "email"
+)
+
+// NotificationChannel receives the notifications of a project, the target is a webhook URL or an email address
+type NotificationChannel struct {
+	Type   NotificationChannelType `json:"type" yaml:"type"`
+	Target string                  `json:"target" yaml:"target"`
+}
+
+// NotificationChannelProvider returns the channels notified about failures of a source project,
+// no channels are returned if the project has none configured
+type NotificationChannelProvider interface {
+	GetNotificationChannels(ctxIn context.Context, gcpProjectID string) ([]NotificationChannel, error)
+}
+
+type defaultNotificationChannelProvider struct {
+	client          gcs.CloudStorageClient
+	lastFetch       time.Time
+	refreshDuration time.Duration
+	cache           []projectNotificationChannels
+}
+
+type projectNotificationChannels struct {
+	Project  string                `yaml:"project"`
+	Channels []NotificationChannel `yaml:"channels"`
+}
+
+func (d *defaultNotificationChannelProvider) GetNotificationChannels(ctxIn context.Context, gcpProjectID string) ([]NotificationChannel, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultNotificationChannelProvider).GetNotificationChannels")
+	defer span.End()
+
+	if time.Since(d.lastFetch) > d.refreshDuration {
+		bucketName := config.DefaultProviderBucketEnv.MustGet()
+		objectName := config.DefaultProviderNotificationChannelsPathEnv.MustGet()
+
+		object, err := d.client.ReadObject(ctx, bucketName, objectName)
+		if err != nil {
+			return nil, err
+		}
+
+		var cache []projectNotificationChannels
+		if err = yaml.Unmarshal(object, &cache); err != nil {
+			return nil, fmt.Errorf("can not parse yaml file %s", err)
+		}
+		d.cache = cache
+		d.lastFetch = time.Now()
+	}
+
+	for _, entry := range d.cache {
+		if entry.Project == gcpProjectID {
+			return entry.Channels, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func NewDefaultNotificationChannelProvider(ctxIn context.Context, gcsClient gcs.CloudStorageClient) (NotificationChannelProvider, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewDefaultNotificationChannelProvider")
+	defer span.End()
+
+	if gcsClient == nil || !gcsClient.IsInitialized(ctx) {
+		return &defaultNotificationChannelProvider{}, fmt.Errorf("can not create instance of defaultNotificationChannelProvider with unititialized GcsClient")
+	}
+
+	ttl, err := defaultProviderCacheTTL()
+	if err != nil {
+		return &defaultNotificationChannelProvider{}, fmt.Errorf("can not create instance of defaultNotificationChannelProvider %s", err)
+	}
+
+	return &defaultNotificationChannelProvider{client: gcsClient, lastFetch: time.Now().Add(ttl * -2), refreshDuration: ttl}, nil
+}
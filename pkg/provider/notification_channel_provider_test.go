@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultNotificationChannelProvider_Found(t *testing.T) {
+	_ = os.Setenv(config.DefaultProviderBucketEnv.String(), "local-xyz-dev.appspot.com")
+	_ = os.Setenv(config.DefaultProviderNotificationChannelsPathEnv.String(), "notification-channels.yaml")
+
+	content := `
+- project: local-account
+  channels:
+    - type: slack
+      target: https://hooks.slack.com/services/T000/B000/XXXX
+    - type: email
+      target: team@example.com
+`
+	channelProvider, err := NewDefaultNotificationChannelProvider(context.Background(), &gcs.MockGcsClient{
+		ClientInitialized: true,
+		ObjectContent:     []byte(content),
+	})
+	assert.NoError(t, err)
+
+	channels, err := channelProvider.GetNotificationChannels(context.Background(), "local-account")
+	assert.NoError(t, err)
+	assert.Equal(t, []NotificationChannel{
+		{Type: SlackChannel, Target: "https://hooks.slack.com/services/T000/B000/XXXX"},
+		{Type: EmailChannel, Target: "team@example.com"},
+	}, channels)
+}
+
+func TestDefaultNotificationChannelProvider_NotFound(t *testing.T) {
+	_ = os.Setenv(config.DefaultProviderBucketEnv.String(), "local-xyz-dev.appspot.com")
+	_ = os.Setenv(config.DefaultProviderNotificationChannelsPathEnv.String(), "notification-channels.yaml")
+
+	channelProvider, err := NewDefaultNotificationChannelProvider(context.Background(), &gcs.MockGcsClient{
+		ClientInitialized: true,
+		ObjectContent:     []byte(""),
+	})
+	assert.NoError(t, err)
+
+	channels, err := channelProvider.GetNotificationChannels(context.Background(), "local-account")
+	assert.NoError(t, err)
+	assert.Empty(t, channels)
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"go.opencensus.io/trace"
+	"gopkg.in/yaml.v3"
+)
+
+type PolicySeverity string
+
+const (
+	// PolicyWarn lets a backup violating the rule pass and reports the violation
+	PolicyWarn PolicySeverity = "warn"
+	// PolicyBlock rejects the creation or update of a backup violating the rule
+	PolicyBlock PolicySeverity = "block"
+)
+
+// PolicyRule is a compliance rule for backups, the expression is a CEL expression that evaluates to true if the
+// backup complies with the rule
+type PolicyRule struct {
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description"`
+	Expression  string         `yaml:"expression"`
+	Severity    PolicySeverity `yaml:"severity"`
+}
+
+// PolicyRuleProvider returns the compliance rules every backup is checked against
+type PolicyRuleProvider interface {
+	GetPolicyRules(ctxIn context.Context) ([]PolicyRule, error)
+}
+
+type defaultPolicyRuleProvider struct {
+	client          gcs.CloudStorageClient
+	lastFetch       time.Time
+	refreshDuration time.Duration
+	cache           []PolicyRule
+}
+
+func (d *defaultPolicyRuleProvider) GetPolicyRules(ctxIn context.Context) ([]PolicyRule, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultPolicyRuleProvider).GetPolicyRules")
+	defer span.End()
+
+	if time.Since(d.lastFetch) > d.refreshDuration {
+		bucketName := config.DefaultProviderBucketEnv.MustGet()
+		objectName := config.DefaultProviderPolicyRulesPathEnv.MustGet()
+
+		object, err := d.client.ReadObject(ctx, bucketName, objectName)
+		if err != nil {
+			return nil, err
+		}
+
+		var cache []PolicyRule
+		if err = yaml.Unmarshal(object, &cache); err != nil {
+			return nil, fmt.Errorf("can not parse yaml file %s", err)
+		}
+		for _, rule := range cache {
+			if err := validatePolicyRule(rule); err != nil {
+				return nil, err
+			}
+		}
+		d.cache = cache
+		d.lastFetch = time.Now()
+	}
+
+	return d.cache, nil
+}
+
+func validatePolicyRule(rule PolicyRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("policy rule without name")
+	}
+	if rule.Expression == "" {
+		return fmt.Errorf("policy rule %s has no expression", rule.Name)
+	}
+	if rule.Severity != PolicyWarn && rule.Severity != PolicyBlock {
+		return fmt.Errorf("policy rule %s has invalid severity %q, expected %s or %s", rule.Name, rule.Severity, PolicyWarn, PolicyBlock)
+	}
+	return nil
+}
+
+func NewDefaultPolicyRuleProvider(ctxIn context.Context, gcsClient gcs.CloudStorageClient) (PolicyRuleProvider, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewDefaultPolicyRuleProvider")
+	defer span.End()
+
+	if gcsClient == nil || !gcsClient.IsInitialized(ctx) {
+		return &defaultPolicyRuleProvider{}, fmt.Errorf("can not create instance of defaultPolicyRuleProvider with unititialized GcsClient")
+	}
+
+	ttl, err := defaultProviderCacheTTL()
+	if err != nil {
+		return &defaultPolicyRuleProvider{}, fmt.Errorf("can not create instance of defaultPolicyRuleProvider %s", err)
+	}
+
+	return &defaultPolicyRuleProvider{client: gcsClient, lastFetch: time.Now().Add(ttl * -2), refreshDuration: ttl}, nil
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultPolicyRuleProvider_Found(t *testing.T) {
+	_ = os.Setenv(config.DefaultProviderBucketEnv.String(), "local-xyz-dev.appspot.com")
+	_ = os.Setenv(config.DefaultProviderPolicyRulesPathEnv.String(), "policy-rules.yaml")
+
+	content := `
+- name: europe-only
+  description: Backups must be stored in Europe
+  expression: backup.region.startsWith("europe-")
+  severity: block
+- name: dual-region-for-a4
+  expression: availability_class != "A4" || backup.dual_region != ""
+  severity: warn
+`
+	policyRuleProvider, err := NewDefaultPolicyRuleProvider(context.Background(), &gcs.MockGcsClient{
+		ClientInitialized: true,
+		ObjectContent:     []byte(content),
+	})
+	require.NoError(t, err)
+
+	rules, err := policyRuleProvider.GetPolicyRules(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "europe-only", rules[0].Name)
+	assert.Equal(t, "Backups must be stored in Europe", rules[0].Description)
+	assert.Equal(t, PolicyBlock, rules[0].Severity)
+	assert.Equal(t, PolicyWarn, rules[1].Severity)
+}
+
+func TestDefaultPolicyRuleProvider_InvalidSeverity(t *testing.T) {
+	_ = os.Setenv(config.DefaultProviderBucketEnv.String(), "local-xyz-dev.appspot.com")
+	_ = os.Setenv(config.DefaultProviderPolicyRulesPathEnv.String(), "policy-rules.yaml")
+
+	content := `
+- name: europe-only
+  expression: backup.region.startsWith("europe-")
+  severity: fatal
+`
+	policyRuleProvider, err := NewDefaultPolicyRuleProvider(context.Background(), &gcs.MockGcsClient{
+		ClientInitialized: true,
+		ObjectContent:     []byte(content),
+	})
+	require.NoError(t, err)
+
+	_, err = policyRuleProvider.GetPolicyRules(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"go.opencensus.io/trace"
+	"gopkg.in/yaml.v3"
+)
+
+var kmsKeyNamePattern = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+
+// ValidateKMSKeyName checks that a KMS key is given by its resource name like
+// projects/<project>/locations/<location>/keyRings/<key ring>/cryptoKeys/<key>
+func ValidateKMSKeyName(kmsKeyName string) error {
+	if !kmsKeyNamePattern.MatchString(kmsKeyName) {
+		return fmt.Errorf("invalid KMS key %q, expected projects/<project>/locations/<location>/keyRings/<key ring>/cryptoKeys/<key>", kmsKeyName)
+	}
+	return nil
+}
+
+// SinkKMSKeyProvider returns the customer-managed encryption key of the backups in a sink project,
+// an empty key is returned if the sink project uses Google-managed encryption
+type SinkKMSKeyProvider interface {
+	GetSinkKMSKeyName(ctxIn context.Context, sinkGCPProjectID string) (string, error)
+}
+
+type defaultSinkKMSKeyProvider struct {
+	client          gcs.CloudStorageClient
+	lastFetch       time.Time
+	refreshDuration time.Duration
+	cache           []projectKMSKey
+}
+
+type projectKMSKey struct {
+	Project    string `yaml:"project"`
+	KMSKeyName string `yaml:"kms_key_name"`
+}
+
+func (d *defaultSinkKMSKeyProvider) GetSinkKMSKeyName(ctxIn context.Context, sinkGCPProjectID string) (string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultSinkKMSKeyProvider).GetSinkKMSKeyName")
+	defer span.End()
+
+	if time.Since(d.lastFetch) > d.refreshDuration {
+		bucketName := config.DefaultProviderBucketEnv.MustGet()
+		objectName := config.DefaultProviderSinkKMSKeyPathEnv.MustGet()
+
+		object, err := d.client.ReadObject(ctx, bucketName, objectName)
+		if err != nil {
+			return "", err
+		}
+
+		var cache []projectKMSKey
+		if err = yaml.Unmarshal(object, &cache); err != nil {
+			return "", fmt.Errorf("can not parse yaml file %s", err)
+		}
+		for _, entry := range cache {
+			if err := ValidateKMSKeyName(entry.KMSKeyName); err != nil {
+				return "", fmt.Errorf("KMS key of sink project %s: %s", entry.Project, err)
+			}
+		}
+		d.cache = cache
+		d.lastFetch = time.Now()
+	}
+
+	for _, entry := range d.cache {
+		if entry.Project == sinkGCPProjectID {
+			return entry.KMSKeyName, nil
+		}
+	}
+
+	return "", nil
+}
+
+func NewDefaultSinkKMSKeyProvider(ctxIn context.Context, gcsClient gcs.CloudStorageClient) (SinkKMSKeyProvider, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewDefaultSinkKMSKeyProvider")
+	defer span.End()
+
+	if gcsClient == nil || !gcsClient.IsInitialized(ctx) {
+		return &defaultSinkKMSKeyProvider{}, fmt.Errorf("can not create instance of defaultSinkKMSKeyProvider with unititialized GcsClient")
+	}
+
+	ttl, err := defaultProviderCacheTTL()
+	if err != nil {
+		return &defaultSinkKMSKeyProvider{}, fmt.Errorf("can not create instance of defaultSinkKMSKeyProvider %s", err)
+	}
+
+	return &defaultSinkKMSKeyProvider{client: gcsClient, lastFetch: time.Now().Add(ttl * -2), refreshDuration: ttl}, nil
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultSinkKMSKeyProvider_Found(t *testing.T) {
+	_ = os.Setenv(config.DefaultProviderBucketEnv.String(), "local-xyz-dev.appspot.com")
+	_ = os.Setenv(config.DefaultProviderSinkKMSKeyPathEnv.String(), "sink-kms-keys.yaml")
+
+	content := `
+- project: local-account-backup
+  kms_key_name: projects/local-kms/locations/europe-west3/keyRings/backups/cryptoKeys/local-account
+`
+	kmsKeyProvider, err := NewDefaultSinkKMSKeyProvider(context.Background(), &gcs.MockGcsClient{
+		ClientInitialized: true,
+		ObjectContent:     []byte(content),
+	})
+	assert.NoError(t, err)
+
+	kmsKeyName, err := kmsKeyProvider.GetSinkKMSKeyName(context.Background(), "local-account-backup")
+	assert.NoError(t, err)
+	assert.Equal(t, "projects/local-kms/locations/europe-west3/keyRings/backups/cryptoKeys/local-account", kmsKeyName)
+
+	kmsKeyName, err = kmsKeyProvider.GetSinkKMSKeyName(context.Background(), "other-backup")
+	assert.NoError(t, err)
+	assert.Empty(t, kmsKeyName, "a sink project without key uses Google-managed encryption")
+}
+
+func TestDefaultSinkKMSKeyProvider_InvalidKey(t *testing.T) {
+	_ = os.Setenv(config.DefaultProviderBucketEnv.String(), "local-xyz-dev.appspot.com")
+	_ = os.Setenv(config.DefaultProviderSinkKMSKeyPathEnv.String(), "sink-kms-keys.yaml")
+
+	content := `
+- project: local-account-backup
+  kms_key_name: local-account
+`
+	kmsKeyProvider, err := NewDefaultSinkKMSKeyProvider(context.Background(), &gcs.MockGcsClient{
+		ClientInitialized: true,
+		ObjectContent:     []byte(content),
+	})
+	assert.NoError(t, err)
+
+	_, err = kmsKeyProvider.GetSinkKMSKeyName(context.Background(), "local-account-backup")
+	assert.Error(t, err)
+}
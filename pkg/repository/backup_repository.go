@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/go-pg/pg/v10"
 	"github.com/go-pg/pg/v10/orm"
 	"github.com/ottogroup/penelope/pkg/secret"
 	"github.com/ottogroup/penelope/pkg/service"
@@ -26,11 +27,15 @@ type UpdateFields struct {
 	ExcludePath            []string
 	Table                  []string
 	ExcludedTables         []string
+	CollectionIDs          []string
+	Databases              []string
 	MirrorTTL              uint
 	SnapshotTTL            uint
 	ArchiveTTM             uint
 	RecoveryPointObjective int
 	RecoveryTimeObjective  int
+	MaxRetryAttempts       int
+	Schedule               *ScheduleOptions
 }
 
 // BackupRepository defines operations for a Backup
@@ -51,6 +56,7 @@ type BackupRepository interface {
 	GetScheduledBackups(context.Context, BackupType) ([]*Backup, error)
 	GetBackupsByCleanupTrashcanStatus(ctx context.Context, status TrashcanCleanupStatus) ([]*Backup, error)
 	MarkTrashcanCleanup(ctx context.Context, id string, trashcanCleanup TrashcanCleanup) error
+	MarkRetentionLocked(ctxIn context.Context, backupID string) error
 }
 
 // defaultBackupRepository implements BackupRepository
@@ -227,6 +233,31 @@ func (d *defaultBackupRepository) MarkStatus(ctxIn context.Context, id string, s
 	return nil
 }
 
+// MarkRetentionLocked marks the retention policy of the backup sink as locked
+func (d *defaultBackupRepository) MarkRetentionLocked(ctxIn context.Context, backupID string) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultBackupRepository).MarkRetentionLocked")
+	defer span.End()
+
+	backup := &Backup{
+		ID:          backupID,
+		SinkOptions: SinkOptions{RetentionLocked: true},
+		EntityAudit: EntityAudit{
+			UpdatedTimestamp: time.Now(),
+		},
+	}
+
+	_, err := d.storageService.DB().Model(backup).
+		Column("target_retention_locked", "audit_updated_timestamp").
+		WherePK().
+		Update()
+
+	if err != nil {
+		logQueryError("MarkRetentionLocked", err)
+		return fmt.Errorf("error during executing updating backup statemant: %s", err)
+	}
+	return nil
+}
+
 // UpdateBackup change backup status
 func (d *defaultBackupRepository) UpdateBackup(ctxIn context.Context, fields UpdateFields) error {
 	_, span := trace.StartSpan(ctxIn, "(*defaultBackupRepository).UpdateBackupStatus")
@@ -248,6 +279,12 @@ func (d *defaultBackupRepository) UpdateBackup(ctxIn context.Context, fields Upd
 				IncludePath: fields.IncludePath,
 				ExcludePath: fields.ExcludePath,
 			},
+			FirestoreOptions: FirestoreOptions{
+				CollectionIDs: fields.CollectionIDs,
+			},
+			CloudSQLOptions: CloudSQLOptions{
+				Databases: fields.Databases,
+			},
 		},
 		EntityAudit: EntityAudit{
 			UpdatedTimestamp: time.Now(),
@@ -260,6 +297,10 @@ func (d *defaultBackupRepository) UpdateBackup(ctxIn context.Context, fields Upd
 		},
 		RecoveryPointObjective: fields.RecoveryPointObjective,
 		RecoveryTimeObjective:  fields.RecoveryTimeObjective,
+		MaxRetryAttempts:       fields.MaxRetryAttempts,
+	}
+	if fields.Schedule != nil {
+		backup.ScheduleOptions = *fields.Schedule
 	}
 
 	if fields.Status.EqualTo(BackupDeleted.String()) {
@@ -271,6 +312,8 @@ func (d *defaultBackupRepository) UpdateBackup(ctxIn context.Context, fields Upd
 		"bigquery_excluded_tables",
 		"cloudstorage_include_path",
 		"cloudstorage_exclude_path",
+		"firestore_collection_ids",
+		"cloudsql_databases",
 		"audit_updated_timestamp",
 		"audit_deleted_timestamp",
 	}
@@ -281,6 +324,9 @@ func (d *defaultBackupRepository) UpdateBackup(ctxIn context.Context, fields Upd
 	if fields.RecoveryTimeObjective > 0 {
 		columns = append(columns, "recovery_time_objective")
 	}
+	if fields.MaxRetryAttempts > 0 {
+		columns = append(columns, "max_retry_attempts")
+	}
 	if fields.ArchiveTTM > 0 {
 		columns = append(columns, "archive_ttm")
 	}
@@ -290,6 +336,9 @@ func (d *defaultBackupRepository) UpdateBackup(ctxIn context.Context, fields Upd
 	if fields.SnapshotTTL > 0 {
 		columns = append(columns, "snapshot_lifetime_in_days")
 	}
+	if fields.Schedule != nil {
+		columns = append(columns, "schedule_cron", "schedule_timezone", "schedule_windows")
+	}
 	if fields.Status != "" {
 		columns = append(columns, "status")
 	}
@@ -410,6 +459,7 @@ func (d *defaultBackupRepository) GetExpired(ctxIn context.Context, backupType B
 			return sub.Where("status = ?", Finished).
 				Where("audit_deleted_timestamp IS NULL").
 				Where("snapshot_frequency_in_hours = 0").
+				Where("coalesce(schedule_cron, '') = ''").
 				Where("snapshot_lifetime_in_days > 0").
 				Where("audit_created_timestamp + INTERVAL ' 1 DAY ' * snapshot_lifetime_in_days < NOW()"), nil
 		}).Select()
@@ -491,6 +541,8 @@ func (d *defaultBackupRepository) getBigQueryMirrorPastRevisionsThatExpiredAfter
 		ColumnExpr("s.source").
 		ColumnExpr("b.target_project").
 		ColumnExpr("b.target_sink").
+		ColumnExpr("b.bigquery_export_format").
+		ColumnExpr("b.bigquery_export_compression").
 		Select(&revisions)
 
 	if err != nil {
@@ -534,6 +586,8 @@ func (d *defaultBackupRepository) getBigQueryMirrorRevisionsThatExpiredByTTL(ctx
 		ColumnExpr("s.source").
 		ColumnExpr("b.target_project").
 		ColumnExpr("b.target_sink").
+		ColumnExpr("b.bigquery_export_format").
+		ColumnExpr("b.bigquery_export_compression").
 		Select(&revisions)
 
 	if err != nil {
@@ -564,7 +618,7 @@ func (d *defaultBackupRepository) GetScheduledBackups(ctxIn context.Context, bac
 	return backups, nil
 }
 
-// GetBigQueryOneShotSnapshots return backups that are BigQuery with strategy Snapshot
+// GetBigQueryOneShotSnapshots return backups that are BigQuery with strategy Snapshot or TableSnapshot
 func (d *defaultBackupRepository) GetBigQueryOneShotSnapshots(ctxIn context.Context, status BackupStatus) ([]*Backup, error) {
 	_, span := trace.StartSpan(ctxIn, "(*defaultBackupRepository).GetBigQueryOneShotSnapshots")
 	defer span.End()
@@ -573,9 +627,10 @@ func (d *defaultBackupRepository) GetBigQueryOneShotSnapshots(ctxIn context.Cont
 
 	err := d.storageService.DB().Model(&backups).
 		Where("status = ?", status).
-		Where("strategy = ?", Snapshot).
+		Where("strategy in (?)", pg.In([]Strategy{Snapshot, TableSnapshot})).
 		Where("audit_deleted_timestamp IS NULL").
 		Where("snapshot_frequency_in_hours = 0").
+		Where("coalesce(schedule_cron, '') = ''").
 		Select()
 
 	if err != nil {
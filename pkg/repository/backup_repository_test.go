@@ -91,6 +91,29 @@ func TestDefaultBackupRepository_MarkDeleted(t *testing.T) {
 	assert.Equal(t, backup.Status, BackupDeleted)
 }
 
+func TestDefaultBackupRepository_MarkRetentionLocked(t *testing.T) {
+	ctx, storageService := prepareTest(t)
+
+	backupRepository := &defaultBackupRepository{storageService: storageService}
+
+	err := setBackups(backupRepository.storageService, []*Backup{
+		{ID: "backup-id-1232", Status: Finished, SinkOptions: SinkOptions{Immutable: true}},
+		{ID: "backup-id-2412", Status: Finished, SinkOptions: SinkOptions{Immutable: true}},
+	})
+	assert.NoError(t, err)
+
+	err = backupRepository.MarkRetentionLocked(ctx, "backup-id-1232")
+	assert.NoError(t, err)
+
+	backup, err := backupRepository.GetBackup(ctx, "backup-id-1232")
+	require.NoError(t, err)
+	assert.True(t, backup.RetentionLocked)
+	assert.True(t, backup.Immutable)
+
+	count, _ := storageService.DB().Model(&Backup{}).Where("target_retention_locked").Count()
+	assert.Equal(t, 1, count, "one row should be affected")
+}
+
 func TestDefaultBackupRepository_MarkDeleted_OnlyOneRowShouldBeAffected(t *testing.T) {
 	ctx, storageService := prepareTest(t)
 
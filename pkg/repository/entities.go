@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/ottogroup/penelope/pkg/schedule"
 )
 
 // EntityAudit defines changes that happened to a given entity
@@ -46,6 +48,8 @@ type Backup struct {
 	Type                   BackupType   `pg:"type"`
 	RecoveryPointObjective int          `pg:"recovery_point_objective"`
 	RecoveryTimeObjective  int          `pg:"recovery_time_objective"`
+	// MaxRetryAttempts of a failed job, the configured default is used if not set
+	MaxRetryAttempts int `pg:"max_retry_attempts"`
 
 	Strategy          Strategy
 	SourceProject     string    `pg:"project"`
@@ -54,12 +58,16 @@ type Backup struct {
 
 	SinkOptions
 	SnapshotOptions
+	ScheduleOptions
 	BackupOptions
 	EntityAudit
 	MirrorOptions
 	TrashcanCleanup
 }
 
+// TrashcanMarkerObject is placed in every trashcan to explain its content
+const TrashcanMarkerObject = "THIS_TRASHCAN_CONTAINS_DELETED_OBJECTS_FROM_SOURCE"
+
 // GetTrashcanPath give a patho to object moved into trashcan
 func (b Backup) GetTrashcanPath() string {
 	return fmt.Sprintf(".trashcan_%s", b.ID)
@@ -67,20 +75,41 @@ func (b Backup) GetTrashcanPath() string {
 
 // IsOneshot returns true if backup is oneshot snapshot
 func (b Backup) IsOneshot() bool {
-	return b.Strategy == Snapshot && b.SnapshotOptions.FrequencyInHours == 0
+	return b.Strategy.IsSnapshot() && b.SnapshotOptions.FrequencyInHours == 0 && !b.ScheduleOptions.HasCron()
+}
+
+// RetentionInDays returns the retention period of the sink bucket which is the lifetime of the backup, 0 if the backup is not immutable
+func (b Backup) RetentionInDays() uint {
+	if !b.Immutable {
+		return 0
+	}
+	switch b.Strategy {
+	case Mirror:
+		return b.MirrorOptions.LifetimeInDays
+	case Snapshot:
+		return b.SnapshotOptions.LifetimeInDays
+	}
+	return 0
 }
 
 func (b Backup) String() string {
 	snapshotOptionsString := ""
-	if Snapshot == b.Strategy {
+	if b.Strategy.IsSnapshot() {
 		snapshotOptionsString += fmt.Sprintf("snapshotOptions={lifetimeInDays=%d frequencyInHours=%d} ", b.SnapshotOptions.LifetimeInDays, b.FrequencyInHours)
 	}
+	if !b.ScheduleOptions.IsEmpty() {
+		snapshotOptionsString += fmt.Sprintf("scheduleOptions={cron=%q timezone=%s windows=%v} ", b.Cron, b.Timezone, b.Windows)
+	}
 
 	backupOptionsString := ""
 	if BigQuery == b.Type {
-		backupOptionsString += fmt.Sprintf("bigQueryOptions={dataset=%s tables=%v, excluded_tables=%v} ", b.Dataset, b.Table, b.ExcludedTables)
+		backupOptionsString += fmt.Sprintf("bigQueryOptions={dataset=%s tables=%v, excluded_tables=%v, exportFormat=%s, exportCompression=%s} ", b.Dataset, b.Table, b.ExcludedTables, b.GetExportFormat(), b.GetExportCompression())
 	} else if CloudStorage == b.Type {
 		backupOptionsString += fmt.Sprintf("cloudStorageOptions={bucket=%s includePath=%s excludePath=%s} ", b.Bucket, b.IncludePath, b.ExcludePath)
+	} else if Firestore == b.Type {
+		backupOptionsString += fmt.Sprintf("firestoreOptions={database=%s collectionIDs=%v} ", b.Database, b.CollectionIDs)
+	} else if CloudSQL == b.Type {
+		backupOptionsString += fmt.Sprintf("cloudSQLOptions={instance=%s databases=%v fileType=%s} ", b.Instance, b.Databases, b.FileType)
 	}
 
 	sinkOptions := fmt.Sprintf("targetProject=%s region=%s sink=%s storageClass=%s", b.TargetProject, b.Region, b.Sink, b.StorageClass)
@@ -91,20 +120,26 @@ func (b Backup) String() string {
 		b.CreatedTimestamp, b.UpdatedTimestamp, b.DeletedTimestamp, snapshotOptionsString, backupOptionsString)
 }
 
-// SinkOptions for a backup
+// SinkOptions for a backup, KMSKeyName is empty if the sink uses Google-managed encryption
+// an Immutable sink bucket has a retention policy of the backup lifetime, which can not be removed anymore once RetentionLocked
 type SinkOptions struct {
-	TargetProject string
-	Region        string `pg:"target_region"`
-	DualRegion    string `pg:"target_dual_region"`
-	Sink          string `pg:"target_sink"`
-	StorageClass  string `pg:"target_storage_class"`
-	ArchiveTTM    uint   `pg:"archive_ttm"`
+	TargetProject   string
+	Region          string `pg:"target_region"`
+	DualRegion      string `pg:"target_dual_region"`
+	Sink            string `pg:"target_sink"`
+	StorageClass    string `pg:"target_storage_class"`
+	ArchiveTTM      uint   `pg:"archive_ttm"`
+	KMSKeyName      string `pg:"target_kms_key_name"`
+	Immutable       bool   `pg:"target_immutable,use_zero"`
+	RetentionLocked bool   `pg:"target_retention_locked,use_zero"`
 }
 
 // BackupOptions backup options for specific technology
 type BackupOptions struct {
 	BigQueryOptions
 	CloudStorageOptions
+	FirestoreOptions
+	CloudSQLOptions
 }
 
 // SnapshotOptions strategy backup options
@@ -113,6 +148,28 @@ type SnapshotOptions struct {
 	FrequencyInHours uint `pg:"snapshot_frequency_in_hours,use_zero"`
 }
 
+// ScheduleOptions cron schedule and allowed execution windows of a backup
+type ScheduleOptions struct {
+	Cron     string   `pg:"schedule_cron"`
+	Timezone string   `pg:"schedule_timezone"`
+	Windows  []string `pg:"schedule_windows"`
+}
+
+// HasCron returns true if the backup is scheduled by a cron expression
+func (s ScheduleOptions) HasCron() bool {
+	return s.Cron != ""
+}
+
+// IsEmpty returns true if neither a cron expression nor execution windows are configured
+func (s ScheduleOptions) IsEmpty() bool {
+	return s.Cron == "" && len(s.Windows) == 0
+}
+
+// Parse validates the options and returns the evaluable schedule
+func (s ScheduleOptions) Parse() (*schedule.Schedule, error) {
+	return schedule.New(s.Cron, s.Timezone, s.Windows)
+}
+
 // TrashcanCleanup status of trashcan cleanup
 type TrashcanCleanup struct {
 	Status                TrashcanCleanupStatus `pg:"trashcan_cleanup_status"`
@@ -127,10 +184,34 @@ type MirrorOptions struct {
 }
 
 // BigQueryOptions for a BigQuery backup
+// ExportFormat and ExportCompression are empty for backups extracting uncompressed Avro
 type BigQueryOptions struct {
-	Dataset        string   `pg:"bigquery_dataset"`
-	Table          []string `pg:"bigquery_table"`
-	ExcludedTables []string `pg:"bigquery_excluded_tables"`
+	Dataset           string            `pg:"bigquery_dataset"`
+	Table             []string          `pg:"bigquery_table"`
+	ExcludedTables    []string          `pg:"bigquery_excluded_tables"`
+	ExportFormat      ExportFormat      `pg:"bigquery_export_format"`
+	ExportCompression ExportCompression `pg:"bigquery_export_compression"`
+}
+
+// GetExportFormat return the format of the extract jobs
+func (o BigQueryOptions) GetExportFormat() ExportFormat {
+	if o.ExportFormat == "" {
+		return AvroExport
+	}
+	return o.ExportFormat
+}
+
+// GetExportCompression return the compression of the extract jobs
+func (o BigQueryOptions) GetExportCompression() ExportCompression {
+	if o.ExportCompression == "" {
+		return NoCompression
+	}
+	return o.ExportCompression
+}
+
+// FileExtension of the files written by the extract jobs
+func (o BigQueryOptions) FileExtension() string {
+	return ExportFileExtension(o.GetExportFormat(), o.GetExportCompression())
 }
 
 // CloudStorageOptions for a GCS backup
@@ -140,6 +221,22 @@ type CloudStorageOptions struct {
 	ExcludePath []string `pg:"cloudstorage_exclude_path"`
 }
 
+// FirestoreOptions for a Firestore backup
+// An empty CollectionIDs exports all collections of the database
+type FirestoreOptions struct {
+	Database      string   `pg:"firestore_database"`
+	CollectionIDs []string `pg:"firestore_collection_ids"`
+}
+
+// CloudSQLOptions for a Cloud SQL backup
+// An empty Databases exports every database of the instance, CSVQuery is the select query of a CSV export
+type CloudSQLOptions struct {
+	Instance  string           `pg:"cloudsql_instance"`
+	Databases []string         `pg:"cloudsql_databases"`
+	FileType  CloudSQLFileType `pg:"cloudsql_file_type"`
+	CSVQuery  string           `pg:"cloudsql_csv_query"`
+}
+
 // ExtractJobID  for GCS technology
 type ExtractJobID string
 
@@ -182,10 +279,19 @@ func (j TransferJobID) String() string {
 	return string(j)
 }
 
+// ExportOperationID long-running operation of a Firestore managed export or a Cloud SQL instance export
+type ExportOperationID string
+
+func (j ExportOperationID) String() string {
+	return string(j)
+}
+
 // ForeignJobID job id for a specific technology
 type ForeignJobID struct {
-	BigQueryID     ExtractJobID  `pg:"bigquery_extract_job_id"`
-	CloudStorageID TransferJobID `pg:"cloudstorage_transfer_job_id"`
+	BigQueryID     ExtractJobID      `pg:"bigquery_extract_job_id"`
+	CloudStorageID TransferJobID     `pg:"cloudstorage_transfer_job_id"`
+	FirestoreID    ExportOperationID `pg:"firestore_export_operation_id"`
+	CloudSQLID     ExportOperationID `pg:"cloudsql_export_operation_id"`
 }
 
 // Job a backup unit of work
@@ -198,22 +304,223 @@ type Job struct {
 	Type     BackupType `pg:"type"`
 	Status   JobStatus  `pg:"status"`
 	Source   string     `pg:"source"`
+	// ManifestID of the metadata manifest written while the job was prepared, only set for BigQuery jobs
+	ManifestID string `pg:"manifest_id"`
+	// SourceRowCount of the table or partition when the job was prepared, only set for BigQuery jobs of listed tables
+	SourceRowCount *int64 `pg:"source_row_count"`
+	// IncludePrefixes narrow a Cloud Storage job of an on-demand run to some prefixes instead of the include path of the backup
+	IncludePrefixes []string `pg:"include_prefixes"`
+	JobRetry
 	ForeignJobID
 	EntityAudit
 }
 
+// JobRetry tracks failed attempts of a job, a job is only retried if NextAttemptTime is set
+type JobRetry struct {
+	Attempts         int        `pg:"attempts,use_zero"`
+	NextAttemptTime  time.Time  `pg:"next_attempt_timestamp"`
+	LastErrorMessage string     `pg:"last_error_message"`
+	LastErrorCode    string     `pg:"last_error_code"`
+	LastErrorReason  string     `pg:"last_error_reason"`
+	LastErrorTime    time.Time  `pg:"last_error_timestamp"`
+	ErrorHistory     []JobError `pg:"error_history"`
+}
+
+// JobError is the error of one failed attempt of a job
+// Code is the HTTP or RPC status and Reason the error reason reported by the Google API, both are optional
+type JobError struct {
+	Attempt   int       `json:"attempt"`
+	Message   string    `json:"message"`
+	Code      string    `json:"code,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Retryable bool      `json:"retryable"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// JobEvent is an entry in the history of a job, written whenever its status changes or an attempt fails
+type JobEvent struct {
+	//lint:ignore U1000 makes sure to have correct table name
+	tableName struct{} `pg:"job_events,alias:je"`
+
+	ID               int       `pg:"id,pk"`
+	JobID            string    `pg:"job_id"`
+	BackupID         string    `pg:"backup_id"`
+	Status           JobStatus `pg:"status"`
+	Attempt          int       `pg:"attempt,use_zero"`
+	Message          string    `pg:"message"`
+	ErrorCode        string    `pg:"error_code"`
+	ErrorReason      string    `pg:"error_reason"`
+	CreatedTimestamp time.Time `pg:"audit_created_timestamp"`
+}
+
 func (j Job) String() string {
 	foreignJobIDString := "ForeignJobID="
 	if j.ForeignJobID.BigQueryID != "" {
 		foreignJobIDString += fmt.Sprintf("BigQueryExtractJobID:%s", j.ForeignJobID.BigQueryID)
 	} else if j.ForeignJobID.CloudStorageID != "" {
 		foreignJobIDString += fmt.Sprintf("CloudStorageTransferJobID:%s", j.ForeignJobID.CloudStorageID)
+	} else if j.ForeignJobID.FirestoreID != "" {
+		foreignJobIDString += fmt.Sprintf("FirestoreExportOperationID:%s", j.ForeignJobID.FirestoreID)
+	} else if j.ForeignJobID.CloudSQLID != "" {
+		foreignJobIDString += fmt.Sprintf("CloudSQLExportOperationID:%s", j.ForeignJobID.CloudSQLID)
 	}
 
 	return fmt.Sprintf("backupID=%s jobID=%s type=%s status=%s source=%s createdTimestamp=%q updatedTimestamp=%q deletedTimestamp=%q %s",
 		j.BackupID, j.ID, j.Type, j.Status, j.Source, j.CreatedTimestamp, j.UpdatedTimestamp, j.DeletedTimestamp, foreignJobIDString)
 }
 
+// LoadJobID BigQuery load job used for restoring data
+type LoadJobID string
+
+func NewLoadJobIDWithLocation(jobId, location string) LoadJobID {
+	return LoadJobID(NewExtractJobIDWithLocation(jobId, location))
+}
+
+func (j LoadJobID) String() string {
+	return string(j)
+}
+
+func (j LoadJobID) HasLocation() bool {
+	return ExtractJobID(j).HasLocation()
+}
+
+func (j LoadJobID) Location() string {
+	return ExtractJobID(j).Location()
+}
+
+func (j LoadJobID) JobID() string {
+	return ExtractJobID(j).JobID()
+}
+
+// RestoreForeignJobID restore job id for a specific technology
+type RestoreForeignJobID struct {
+	BigQueryID     LoadJobID     `pg:"bigquery_load_job_id"`
+	CloudStorageID TransferJobID `pg:"cloudstorage_transfer_job_id"`
+}
+
+// RestoreJob a restore unit of work, restore jobs started together share the same RestoreID
+type RestoreJob struct {
+	//lint:ignore U1000 makes sure to have correct table name
+	tableName struct{} `pg:"restore_jobs,alias:rj"`
+
+	ID            string           `pg:"id,pk"`
+	RestoreID     string           `pg:"restore_id"`
+	BackupID      string           `pg:"backup_id"`
+	BackupJobID   string           `pg:"backup_job_id"`
+	Type          BackupType       `pg:"type"`
+	Status        RestoreJobStatus `pg:"status"`
+	Source        string           `pg:"source"`
+	TargetProject string           `pg:"target_project"`
+	TargetDataset string           `pg:"target_dataset"`
+	TargetTable   string           `pg:"target_table"`
+	TargetBucket  string           `pg:"target_bucket"`
+	ErrorMessage  string           `pg:"error_message"`
+	RestoreForeignJobID
+	EntityAudit
+}
+
+func (j RestoreJob) String() string {
+	if j.Type == CloudStorage {
+		return fmt.Sprintf("backupID=%s restoreID=%s restoreJobID=%s type=%s status=%s source=%s target=%s:%s transferJobID=%s",
+			j.BackupID, j.RestoreID, j.ID, j.Type, j.Status, j.Source, j.TargetProject, j.TargetBucket, j.CloudStorageID)
+	}
+	return fmt.Sprintf("backupID=%s restoreID=%s restoreJobID=%s type=%s status=%s source=%s target=%s.%s.%s loadJobID=%s",
+		j.BackupID, j.RestoreID, j.ID, j.Type, j.Status, j.Source, j.TargetProject, j.TargetDataset, j.TargetTable, j.BigQueryID)
+}
+
+// RestoreDrill a periodic test restore of a backup sample into a scratch project
+// ExpectedCount is nil if the count of the source could not be determined reliably
+type RestoreDrill struct {
+	//lint:ignore U1000 makes sure to have correct table name
+	tableName struct{} `pg:"restore_drills,alias:rd"`
+
+	ID                    string             `pg:"id,pk"`
+	BackupID              string             `pg:"backup_id"`
+	RestoreJobID          string             `pg:"restore_job_id"`
+	Type                  BackupType         `pg:"type"`
+	Status                RestoreDrillStatus `pg:"status"`
+	AvailabilityClass     string             `pg:"availability_class"`
+	Source                string             `pg:"source"`
+	Target                string             `pg:"target"`
+	ExpectedCount         *int64             `pg:"expected_count"`
+	RestoredCount         *int64             `pg:"restored_count"`
+	ElapsedSeconds        int                `pg:"elapsed_seconds,use_zero"`
+	RecoveryTimeObjective int                `pg:"recovery_time_objective,use_zero"`
+	RTOBreached           bool               `pg:"rto_breached,use_zero"`
+	ErrorMessage          string             `pg:"error_message"`
+	EntityAudit
+}
+
+func (d RestoreDrill) String() string {
+	return fmt.Sprintf("backupID=%s restoreDrillID=%s restoreJobID=%s type=%s status=%s source=%s target=%s",
+		d.BackupID, d.ID, d.RestoreJobID, d.Type, d.Status, d.Source, d.Target)
+}
+
+// IsFlagged check if the drill proved that the backup can not be restored within its recovery time objective
+func (d RestoreDrill) IsFlagged() bool {
+	return d.Status == DrillFailed || d.RTOBreached
+}
+
+// TaskRun is one run of a background task, Errors keeps a summary of the first errors
+type TaskRun struct {
+	//lint:ignore U1000 makes sure to have correct table name
+	tableName struct{} `pg:"task_runs,alias:tr"`
+
+	ID             string        `pg:"id,pk"`
+	Task           string        `pg:"task"`
+	Status         TaskRunStatus `pg:"status"`
+	StartTime      time.Time     `pg:"start_timestamp"`
+	EndTime        time.Time     `pg:"end_timestamp"`
+	ProcessedCount int           `pg:"processed_count,use_zero"`
+	FailedCount    int           `pg:"failed_count,use_zero"`
+	Errors         []string      `pg:"errors"`
+	EntityAudit
+}
+
+func (r TaskRun) String() string {
+	return fmt.Sprintf("taskRunID=%s task=%s status=%s processed=%d failed=%d", r.ID, r.Task, r.Status, r.ProcessedCount, r.FailedCount)
+}
+
+// SourceFreshness is the newest recovery point of one source of a backup, the RPO is violated if it is older than the
+// recovery point objective of the backup
+type SourceFreshness struct {
+	//lint:ignore U1000 makes sure to have correct table name
+	tableName struct{} `pg:"source_freshness,alias:sf"`
+
+	BackupID               string    `pg:"backup_id,pk"`
+	Source                 string    `pg:"source,pk"`
+	LastRecoveryPoint      time.Time `pg:"last_recovery_point_timestamp"`
+	RecoveryPointObjective int       `pg:"recovery_point_objective,use_zero"`
+	RPOViolated            bool      `pg:"rpo_violated,use_zero"`
+	CheckedTimestamp       time.Time `pg:"checked_timestamp"`
+}
+
+func (f SourceFreshness) String() string {
+	return fmt.Sprintf("backupID=%s source=%s lastRecoveryPoint=%s rpo=%dh violated=%t", f.BackupID, f.Source, f.LastRecoveryPoint, f.RecoveryPointObjective, f.RPOViolated)
+}
+
+// TaskLock is held by the replica running a task, it is stale once the heartbeat stops
+type TaskLock struct {
+	//lint:ignore U1000 makes sure to have correct table name
+	tableName struct{} `pg:"task_locks,alias:tl"`
+
+	Task               string    `pg:"task,pk"`
+	Holder             string    `pg:"holder"`
+	AcquiredTimestamp  time.Time `pg:"acquired_timestamp"`
+	HeartbeatTimestamp time.Time `pg:"heartbeat_timestamp"`
+}
+
+// Notification is the last delivery of a notification, notifications with the same key are throttled
+type Notification struct {
+	//lint:ignore U1000 makes sure to have correct table name
+	tableName struct{} `pg:"notifications,alias:n"`
+
+	Key           string    `pg:"key,pk"`
+	Kind          string    `pg:"kind"`
+	Project       string    `pg:"project"`
+	SentTimestamp time.Time `pg:"sent_timestamp"`
+}
+
 // SourceMetadata for a BigQuery mirroring
 type SourceMetadata struct {
 	//lint:ignore U1000 makes sure to have correct table name
@@ -249,12 +556,16 @@ type SourceTrashcan struct {
 	CreatedTimestamp time.Time `pg:"audit_created_timestamp"`
 }
 
+// SinkComplianceCheck is one check of a sink project, every check is kept to show when a sink project drifted,
+// Reasons are the failed checks and SourceProjects the projects backed up into the sink project at the time
 type SinkComplianceCheck struct {
 	//lint:ignore U1000 makes sure to have correct table name
 	tableName struct{} `pg:"sink_compliance_checks,alias:scc"`
 
-	ProjectSink string    `pg:"project_sink,pk"`
-	Compliant   bool      `pg:"compliant"`
-	Reasons     []string  `pg:"reasons"`
-	LastCheck   time.Time `pg:"last_checked"`
+	ID             string    `pg:"id,pk"`
+	ProjectSink    string    `pg:"project_sink"`
+	SourceProjects []string  `pg:"source_projects"`
+	Compliant      bool      `pg:"compliant,use_zero"`
+	Reasons        []string  `pg:"reasons"`
+	LastCheck      time.Time `pg:"last_checked"`
 }
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// JobEventRepository defines operation with the event history of jobs
+type JobEventRepository interface {
+	AddJobEvent(ctxIn context.Context, event *JobEvent) error
+	GetForJobID(ctxIn context.Context, jobID string) ([]*JobEvent, error)
+}
+
+// defaultJobEventRepository implements JobEventRepository
+type defaultJobEventRepository struct {
+	storageService *service.Service
+}
+
+// NewJobEventRepository return instance of JobEventRepository
+func NewJobEventRepository(ctxIn context.Context, credentialsProvider secret.SecretProvider) (JobEventRepository, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewJobEventRepository")
+	defer span.End()
+
+	storageService, err := service.NewStorageService(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultJobEventRepository{storageService: storageService}, nil
+}
+
+// AddJobEvent add an event to the history of a job
+func (d *defaultJobEventRepository) AddJobEvent(ctxIn context.Context, event *JobEvent) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultJobEventRepository).AddJobEvent")
+	defer span.End()
+
+	_, err := d.storageService.DB().Model(event).Insert()
+	if err != nil {
+		return errors.Wrapf(err, "error during executing add event for job %s statement", event.JobID)
+	}
+
+	return nil
+}
+
+// GetForJobID list the events of a job, oldest first
+func (d *defaultJobEventRepository) GetForJobID(ctxIn context.Context, jobID string) ([]*JobEvent, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultJobEventRepository).GetForJobID")
+	defer span.End()
+
+	var events []*JobEvent
+	err := d.storageService.DB().Model(&events).
+		Where("job_id = ?", jobID).
+		Order("audit_created_timestamp ASC", "id ASC").
+		Select()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error during executing get events for job %s statement", jobID)
+	}
+
+	return events, nil
+}
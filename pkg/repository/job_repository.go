@@ -16,6 +16,43 @@ import (
 // JobStatistics for a job
 type JobStatistics map[JobStatus]uint64
 
+// InFlightJobCount is the number of Scheduled and Pending jobs of all backups from a source into a sink project
+type InFlightJobCount struct {
+	SourceProject string
+	TargetProject string
+	Count         int
+}
+
+// SourceRecoveryPoint summarises the jobs of one source (table, partition or bucket) of a backup
+type SourceRecoveryPoint struct {
+	Source string
+	// FirstJobTime is when the first job of the source was created
+	FirstJobTime time.Time
+	// LastFinishedOkTime is when the newest FinishedOk job of the source was created, zero if no job finished ok
+	LastFinishedOkTime time.Time
+	// LastUnfinishedTime is when the newest job of the source which did not finish ok was created
+	LastUnfinishedTime time.Time
+	// InProgress is true if a job of the source is waiting, running or retried
+	InProgress bool
+}
+
+// LastJobTime is when the newest job of the source was created
+func (p SourceRecoveryPoint) LastJobTime() time.Time {
+	if p.LastUnfinishedTime.After(p.LastFinishedOkTime) {
+		return p.LastUnfinishedTime
+	}
+	return p.LastFinishedOkTime
+}
+
+// NextJobsFilter ranks and restricts the NotScheduled jobs which are scheduled next
+type NextJobsFilter struct {
+	// SourceProjectPriorities rank the backups by their source project, higher first, a missing project ranks 0
+	SourceProjectPriorities map[string]int
+	// ExcludedSourceProjects and ExcludedTargetProjects have reached their max concurrent jobs
+	ExcludedSourceProjects []string
+	ExcludedTargetProjects []string
+}
+
 // AllJobs will fetch all jobs
 const AllJobs = -101
 
@@ -33,14 +70,21 @@ type JobRepository interface {
 	DeleteJob(context.Context, string) error
 	GetJob(context.Context, string) (*Job, error)
 	MarkDeleted(context.Context, string) error
-	ListByTypeAndStatusWithLimit(context.Context, BackupType, JobStatus, uint) ([]*Job, error)
+	ListNextJobs(ctx context.Context, backupType BackupType, filter NextJobsFilter, limit uint) ([]*Job, error)
 	GetByJobTypeAndStatus(context.Context, BackupType, ...JobStatus) ([]*Job, error)
 	GetByBackupIdAndSourceAndStatus(context.Context, string, string, ...JobStatus) ([]*Job, error)
 	GetByStatusAndBefore(context.Context, []JobStatus, int) ([]*Job, error)
 	PatchJobStatus(ctx context.Context, patch JobPatch) error
+	PatchJobRetry(ctx context.Context, jobID string, status JobStatus, retry JobRetry) error
+	GetJobsToRetry(ctx context.Context, before time.Time) ([]*Job, error)
+	RequeueJob(ctx context.Context, jobID string) error
+	GetInFlightJobCounts(ctx context.Context) ([]InFlightJobCount, error)
+	GetSourceProjectsOfJobs(ctx context.Context, backupType BackupType, status JobStatus) ([]string, error)
+	GetSourceRecoveryPoints(ctx context.Context, backupID string) ([]SourceRecoveryPoint, error)
 	GetJobsForBackupID(ctx context.Context, backupID string, jobPage Page, status ...JobStatus) ([]*Job, error)
 	GetMostRecentJobForBackupID(ctxIn context.Context, backupID string, status ...JobStatus) (*Job, error)
 	GetBackupRestoreJobs(ctx context.Context, backupID, jobID string) ([]*Job, error)
+	GetBackupRestoreJobsAt(ctx context.Context, backupID string, timestamp time.Time) ([]*Job, error)
 	GetStatisticsForBackupID(ctx context.Context, backupID string) (JobStatistics, error)
 	GetJobCountForBackupID(ctx context.Context, backupID string) (int, error)
 	GetRecoverableJobCountForBackupID(ctx context.Context, backupID string) (int, error)
@@ -207,7 +251,7 @@ func (d *defaultJobRepository) GetBackupRestoreJobs(ctxIn context.Context, backu
 	}
 
 	// For snapshot jobs
-	if backup.Strategy == Snapshot {
+	if backup.Strategy.IsSnapshot() {
 		// Get the reference job's timestamp based on whether jobID is provided
 		var referenceTimestampQuery *orm.Query
 		if jobID == "" {
@@ -303,23 +347,105 @@ func (d *defaultJobRepository) GetBackupRestoreJobs(ctxIn context.Context, backu
 	return jobs, nil
 }
 
-// GetByJobTypeAndStatusAndLimit filter backup jobs by status and type with limit
-func (d *defaultJobRepository) ListByTypeAndStatusWithLimit(ctxIn context.Context, backupType BackupType, status JobStatus, limit uint) ([]*Job, error) {
-	_, span := trace.StartSpan(ctxIn, "(*defaultJobRepository).ListByTypeAndStatusWithLimit")
+// GetBackupRestoreJobsAt get restore jobs for the state of a mirror backup at a point in time
+func (d *defaultJobRepository) GetBackupRestoreJobsAt(ctxIn context.Context, backupID string, timestamp time.Time) ([]*Job, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultJobRepository).GetBackupRestoreJobsAt")
 	defer span.End()
 
 	var jobs []*Job
 	db := d.storageService.DB()
 
-	err := db.Model(&jobs).
-		Where("type = ?", backupType.String()).
-		Where("audit_deleted_timestamp is null").
-		Where("status = ?", status).
+	// A source_metadata revision is valid from the last modification of its source, delete revisions carry no
+	// modification time and are valid from the time they were detected.
+	// For each source we take the newest revision valid at the given timestamp. Revisions without a successful job
+	// are skipped because there is no exported data for them, so the previous revision of the source is used instead.
+	// Finally, we drop sources whose revision is a delete or was expired and return the jobs of the remaining ones.
+	finishedJobQuery := db.ModelContext(ctxIn).
+		TableExpr("source_metadata_jobs smj_ok").
+		ColumnExpr("1").
+		Join("JOIN jobs j_ok ON j_ok.id = smj_ok.job_id").
+		Where("smj_ok.source_metadata_id = sm.id").
+		Where("j_ok.status = ?", FinishedOk).
+		Where("j_ok.audit_deleted_timestamp IS NULL")
+
+	smAtQuery := db.ModelContext(ctxIn).
+		TableExpr("source_metadata sm").
+		ColumnExpr("DISTINCT ON (sm.source) sm.id, sm.operation, sm.audit_deleted_timestamp").
+		Where("sm.backup_id = ?", backupID).
+		Where("COALESCE(sm.last_modified_time, sm.audit_created_timestamp) <= ?", timestamp.UTC()).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			return q.WhereOr("sm.operation = ?", Delete.String()).
+				WhereOr("EXISTS (?)", finishedJobQuery), nil
+		}).
+		OrderExpr("sm.source, COALESCE(sm.last_modified_time, sm.audit_created_timestamp) DESC, sm.id DESC")
+
+	err := db.ModelContext(ctxIn).
+		With("sm_at", smAtQuery).
+		TableExpr("sm_at").
+		Column("j.*").
+		Join("JOIN source_metadata_jobs smj ON smj.source_metadata_id = sm_at.id").
+		Join("JOIN jobs j ON smj.job_id = j.id").
+		Where("sm_at.operation != ?", Delete.String()).
+		Where("sm_at.audit_deleted_timestamp IS NULL").
+		Where("j.status = ?", FinishedOk).
+		Where("j.audit_deleted_timestamp IS NULL").
+		Order("j.source").
+		Select(&jobs)
+	if err != nil {
+		return jobs, fmt.Errorf("error during executing GetBackupRestoreJobsAt statement: %s", err)
+	}
+
+	return jobs, nil
+}
+
+// ListNextJobs list the NotScheduled jobs of a backup type in the order they should be scheduled. The jobs are taken
+// round-robin between the backups, within a round the backups are ordered by the priority of their source project and
+// then by the deadline of their recovery point objective. Jobs of excluded projects are left out before the limit applies.
+func (d *defaultJobRepository) ListNextJobs(ctxIn context.Context, backupType BackupType, filter NextJobsFilter, limit uint) ([]*Job, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultJobRepository).ListNextJobs")
+	defer span.End()
+
+	projects := make([]string, 0, len(filter.SourceProjectPriorities))
+	priorities := make([]int, 0, len(filter.SourceProjectPriorities))
+	for project, priority := range filter.SourceProjectPriorities {
+		projects = append(projects, project)
+		priorities = append(priorities, priority)
+	}
+
+	var jobs []*Job
+	db := d.storageService.DB()
+
+	candidates := db.Model((*Job)(nil)).
+		ColumnExpr("j.*").
+		ColumnExpr("row_number() over (partition by j.backup_id order by j.audit_created_timestamp, j.id) AS _backup_round").
+		ColumnExpr("coalesce((SELECT p.priority FROM unnest(?::text[], ?::int[]) AS p(project, priority) WHERE p.project = b.project), 0) AS _priority",
+			pg.Array(projects), pg.Array(priorities)).
+		ColumnExpr("CASE WHEN b.recovery_point_objective > 0 THEN "+
+			"coalesce((SELECT max(ok.audit_created_timestamp) FROM jobs AS ok WHERE ok.backup_id = b.id AND ok.status = ? AND ok.audit_deleted_timestamp IS NULL), b.audit_created_timestamp)"+
+			" + b.recovery_point_objective * interval '1 hour' END AS _rpo_deadline", FinishedOk).
+		Join("JOIN backups AS b ON b.id = j.backup_id").
+		Where("j.type = ?", backupType.String()).
+		Where("j.audit_deleted_timestamp is null").
+		Where("j.status = ?", NotScheduled)
+	if len(filter.ExcludedSourceProjects) > 0 {
+		candidates = candidates.Where("b.project NOT IN (?)", pg.In(filter.ExcludedSourceProjects))
+	}
+	if len(filter.ExcludedTargetProjects) > 0 {
+		candidates = candidates.Where("b.target_project NOT IN (?)", pg.In(filter.ExcludedTargetProjects))
+	}
+
+	err := db.Model().TableExpr("(?) AS j", candidates).
+		ColumnExpr("j.*").
+		OrderExpr("j._backup_round ASC").
+		OrderExpr("j._priority DESC").
+		OrderExpr("j._rpo_deadline ASC NULLS LAST").
+		OrderExpr("j.backup_id ASC").
+		OrderExpr("j.id ASC").
 		Limit(int(limit)).
-		Select()
+		Select(&jobs)
 
 	if err != nil {
-		return jobs, fmt.Errorf("error during executing get job by status statement: %s", err)
+		return jobs, fmt.Errorf("error during executing list next jobs statement: %s", err)
 	}
 
 	return jobs, nil
@@ -389,6 +515,8 @@ func (d *defaultJobRepository) PatchJobStatus(ctxIn context.Context, jobPatcher
 		ForeignJobID: ForeignJobID{
 			BigQueryID:     jobPatcher.ForeignJobID.BigQueryID,
 			CloudStorageID: jobPatcher.ForeignJobID.CloudStorageID,
+			FirestoreID:    jobPatcher.ForeignJobID.FirestoreID,
+			CloudSQLID:     jobPatcher.ForeignJobID.CloudSQLID,
 		},
 		EntityAudit: EntityAudit{
 			UpdatedTimestamp: time.Now(),
@@ -396,7 +524,7 @@ func (d *defaultJobRepository) PatchJobStatus(ctxIn context.Context, jobPatcher
 	}
 
 	_, err := d.storageService.DB().Model(job).
-		Column("status", "audit_updated_timestamp", "bigquery_extract_job_id", "cloudstorage_transfer_job_id").
+		Column("status", "audit_updated_timestamp", "bigquery_extract_job_id", "cloudstorage_transfer_job_id", "firestore_export_operation_id", "cloudsql_export_operation_id").
 		Where("audit_deleted_timestamp IS NULL").
 		Where("id = ?", jobPatcher.ID).
 		Update()
@@ -408,6 +536,151 @@ func (d *defaultJobRepository) PatchJobStatus(ctxIn context.Context, jobPatcher
 	return nil
 }
 
+// PatchJobRetry set status and retry bookkeeping of a failed job
+func (d *defaultJobRepository) PatchJobRetry(ctxIn context.Context, jobID string, status JobStatus, retry JobRetry) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultJobRepository).PatchJobRetry")
+	defer span.End()
+
+	job := &Job{
+		Status:   status,
+		JobRetry: retry,
+		EntityAudit: EntityAudit{
+			UpdatedTimestamp: time.Now(),
+		},
+	}
+
+	_, err := d.storageService.DB().Model(job).
+		Column("status", "attempts", "next_attempt_timestamp", "last_error_message", "last_error_code", "last_error_reason", "last_error_timestamp", "error_history", "audit_updated_timestamp").
+		Where("audit_deleted_timestamp IS NULL").
+		Where("id = ?", jobID).
+		Update()
+
+	if err != nil {
+		return fmt.Errorf("error during executing updating job retry statement: %s", err)
+	}
+
+	return nil
+}
+
+// GetJobsToRetry get failed jobs whose next attempt is due before given time
+func (d *defaultJobRepository) GetJobsToRetry(ctxIn context.Context, before time.Time) ([]*Job, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultJobRepository).GetJobsToRetry")
+	defer span.End()
+
+	var jobs []*Job
+	err := d.storageService.DB().Model(&jobs).
+		Where("audit_deleted_timestamp is null").
+		Where("status in (?)", pg.In([]JobStatus{Error, FinishedError})).
+		Where("next_attempt_timestamp is not null").
+		Where("next_attempt_timestamp <= ?", before).
+		Order("next_attempt_timestamp ASC").
+		Select()
+
+	if err != nil {
+		return jobs, fmt.Errorf("error during executing get jobs to retry statement: %s", err)
+	}
+
+	return jobs, nil
+}
+
+// GetInFlightJobCounts count the Scheduled and Pending jobs grouped by source and sink project of their backups
+func (d *defaultJobRepository) GetInFlightJobCounts(ctxIn context.Context) ([]InFlightJobCount, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultJobRepository).GetInFlightJobCounts")
+	defer span.End()
+
+	var counts []InFlightJobCount
+	err := d.storageService.DB().
+		Model((*Job)(nil)).
+		ColumnExpr("b.project AS source_project").
+		ColumnExpr("b.target_project AS target_project").
+		ColumnExpr("count(*) AS count").
+		Join("JOIN backups AS b ON b.id = j.backup_id").
+		Where("j.audit_deleted_timestamp is null").
+		Where("j.status in (?)", pg.In([]JobStatus{Scheduled, Pending})).
+		Group("b.project", "b.target_project").
+		Select(&counts)
+
+	if err != nil {
+		return counts, fmt.Errorf("error during executing get in-flight job counts statement: %s", err)
+	}
+
+	return counts, nil
+}
+
+// GetSourceRecoveryPoints summarise the jobs of a backup per source, deleted jobs are ignored
+func (d *defaultJobRepository) GetSourceRecoveryPoints(ctxIn context.Context, backupID string) ([]SourceRecoveryPoint, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultJobRepository).GetSourceRecoveryPoints")
+	defer span.End()
+
+	var points []SourceRecoveryPoint
+	err := d.storageService.DB().
+		Model((*Job)(nil)).
+		ColumnExpr("source").
+		ColumnExpr("min(audit_created_timestamp) AS first_job_time").
+		ColumnExpr("max(audit_created_timestamp) FILTER (WHERE status = ?) AS last_finished_ok_time", FinishedOk).
+		ColumnExpr("max(audit_created_timestamp) FILTER (WHERE status <> ?) AS last_unfinished_time", FinishedOk).
+		ColumnExpr("coalesce(bool_or(status IN (?)), false) AS in_progress", pg.In([]JobStatus{NotScheduled, Scheduled, Pending, Error, FinishedQuotaError})).
+		Where("backup_id = ?", backupID).
+		Where("status <> ?", JobDeleted).
+		Where("audit_deleted_timestamp IS NULL").
+		Group("source").
+		Order("source").
+		Select(&points)
+
+	if err != nil {
+		return nil, fmt.Errorf("error during executing get source recovery points statement: %s", err)
+	}
+
+	return points, nil
+}
+
+// GetSourceProjectsOfJobs list the distinct source projects of the backups which have jobs of the type and status
+func (d *defaultJobRepository) GetSourceProjectsOfJobs(ctxIn context.Context, backupType BackupType, status JobStatus) ([]string, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultJobRepository).GetSourceProjectsOfJobs")
+	defer span.End()
+
+	var projects []string
+	err := d.storageService.DB().
+		Model((*Job)(nil)).
+		ColumnExpr("DISTINCT b.project").
+		Join("JOIN backups AS b ON b.id = j.backup_id").
+		Where("j.type = ?", backupType.String()).
+		Where("j.audit_deleted_timestamp is null").
+		Where("j.status = ?", status).
+		Select(&projects)
+
+	if err != nil {
+		return projects, fmt.Errorf("error during executing get source projects of jobs statement: %s", err)
+	}
+
+	return projects, nil
+}
+
+// RequeueJob set a failed job back to NotScheduled and clear its next attempt, attempts and error history are kept
+func (d *defaultJobRepository) RequeueJob(ctxIn context.Context, jobID string) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultJobRepository).RequeueJob")
+	defer span.End()
+
+	job := &Job{
+		Status: NotScheduled,
+		EntityAudit: EntityAudit{
+			UpdatedTimestamp: time.Now(),
+		},
+	}
+
+	_, err := d.storageService.DB().Model(job).
+		Column("status", "next_attempt_timestamp", "bigquery_extract_job_id", "cloudstorage_transfer_job_id", "firestore_export_operation_id", "cloudsql_export_operation_id", "audit_updated_timestamp").
+		Where("audit_deleted_timestamp IS NULL").
+		Where("id = ?", jobID).
+		Update()
+
+	if err != nil {
+		return fmt.Errorf("error during executing requeue job statement: %s", err)
+	}
+
+	return nil
+}
+
 // MarkDeleted mark BigQuery job as deleted
 func (d *defaultJobRepository) MarkDeleted(ctxIn context.Context, id string) error {
 	_, span := trace.StartSpan(ctxIn, "(*defaultJobRepository).MarkDeleted")
@@ -1,10 +1,12 @@
 package repository
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultJobRepository_AddJob_Simple(t *testing.T) {
@@ -193,6 +195,65 @@ func TestDefaultJobRepository_GetByJobTypeAndStatus(t *testing.T) {
 	assert.Len(t, status, 0)
 }
 
+func TestDefaultJobRepository_ListNextJobs_SkipsSaturatedProjects(t *testing.T) {
+	ctx, storageService := prepareTest(t)
+
+	err := setBackups(storageService, []*Backup{
+		{ID: "busy-backup", SourceProject: "busy-project", SinkOptions: SinkOptions{TargetProject: "busy-sink"}},
+		{ID: "idle-backup", SourceProject: "idle-project", SinkOptions: SinkOptions{TargetProject: "idle-sink"}},
+	})
+	require.NoError(t, err)
+	repository := &defaultJobRepository{storageService: storageService}
+
+	jobs := []*Job{{ID: "busy-job-0", BackupID: "busy-backup", Status: Scheduled, Type: BigQuery}}
+	for i := 1; i <= 5; i++ {
+		jobs = append(jobs, &Job{ID: fmt.Sprintf("busy-job-%d", i), BackupID: "busy-backup", Status: NotScheduled, Type: BigQuery})
+	}
+	jobs = append(jobs, &Job{ID: "idle-job", BackupID: "idle-backup", Status: NotScheduled, Type: BigQuery})
+	err = repository.AddJobs(ctx, jobs)
+	require.NoError(t, err)
+
+	next, err := repository.ListNextJobs(ctx, BigQuery, NextJobsFilter{ExcludedSourceProjects: []string{"busy-project"}}, 2)
+	require.NoError(t, err)
+	require.Len(t, next, 1, "the jobs of the saturated project must not fill the batch")
+	assert.Equal(t, "idle-job", next[0].ID)
+
+	next, err = repository.ListNextJobs(ctx, BigQuery, NextJobsFilter{ExcludedTargetProjects: []string{"idle-sink"}}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"busy-job-1", "busy-job-2"}, jobIDs(next))
+}
+
+func TestDefaultJobRepository_ListNextJobs_Order(t *testing.T) {
+	ctx, storageService := prepareTest(t)
+
+	err := setBackups(storageService, []*Backup{
+		{ID: "a1-backup", SourceProject: "a1-project"},
+		{ID: "a1-urgent-backup", SourceProject: "a1-project", RecoveryPointObjective: 1},
+		{ID: "a4-backup", SourceProject: "a4-project"},
+	})
+	require.NoError(t, err)
+	repository := &defaultJobRepository{storageService: storageService}
+
+	err = repository.AddJobs(ctx, []*Job{
+		{ID: "a1-job-1", BackupID: "a1-backup", Status: NotScheduled, Type: BigQuery},
+		{ID: "a1-job-2", BackupID: "a1-backup", Status: NotScheduled, Type: BigQuery},
+		{ID: "a1-urgent-job-1", BackupID: "a1-urgent-backup", Status: NotScheduled, Type: BigQuery},
+		{ID: "a4-job-1", BackupID: "a4-backup", Status: NotScheduled, Type: BigQuery},
+		{ID: "a4-job-2", BackupID: "a4-backup", Status: NotScheduled, Type: BigQuery},
+		{ID: "a4-job-3", BackupID: "a4-backup", Status: NotScheduled, Type: CloudStorage},
+	})
+	require.NoError(t, err)
+
+	filter := NextJobsFilter{SourceProjectPriorities: map[string]int{"a1-project": 1, "a4-project": 4}}
+	next, err := repository.ListNextJobs(ctx, BigQuery, filter, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a4-job-1", "a1-urgent-job-1", "a1-job-1", "a4-job-2", "a1-job-2"}, jobIDs(next))
+
+	next, err = repository.ListNextJobs(ctx, BigQuery, filter, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a4-job-1", "a1-urgent-job-1"}, jobIDs(next))
+}
+
 func TestDefaultJobRepository_GetJobsForBackupID(t *testing.T) {
 	backupIDs := []string{"backup-id-1", "backup-id-2", "backup-id-3", "backup-id-4", "backup-id-5"}
 	jobs := []*Job{
@@ -461,6 +522,39 @@ func TestDefaultJobRepository_GetStatisticsForBackupID(t *testing.T) {
 	assert.Equal(t, 2, int(jobStatistics[NotScheduled]))
 }
 
+func TestDefaultJobRepository_GetSourceRecoveryPoints(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	jobs := []*Job{
+		{ID: "job-id-1", BackupID: "backup-id-1", Status: FinishedOk, Type: BigQuery, Source: "table_a", EntityAudit: EntityAudit{CreatedTimestamp: now.Add(-48 * time.Hour)}},
+		{ID: "job-id-2", BackupID: "backup-id-1", Status: FinishedOk, Type: BigQuery, Source: "table_a", EntityAudit: EntityAudit{CreatedTimestamp: now.Add(-24 * time.Hour)}},
+		{ID: "job-id-3", BackupID: "backup-id-1", Status: FinishedError, Type: BigQuery, Source: "table_a", EntityAudit: EntityAudit{CreatedTimestamp: now.Add(-time.Hour)}},
+		{ID: "job-id-4", BackupID: "backup-id-1", Status: NotScheduled, Type: BigQuery, Source: "table_b", EntityAudit: EntityAudit{CreatedTimestamp: now.Add(-2 * time.Hour)}},
+		{ID: "job-id-5", BackupID: "backup-id-1", Status: JobDeleted, Type: BigQuery, Source: "table_c", EntityAudit: EntityAudit{CreatedTimestamp: now}},
+		{ID: "job-id-6", BackupID: "backup-id-2", Status: FinishedOk, Type: BigQuery, Source: "table_a", EntityAudit: EntityAudit{CreatedTimestamp: now}},
+	}
+
+	ctx, storageService := prepareTest(t)
+	setBackupWithIDs(t, storageService, "backup-id-1", "backup-id-2")
+	repository := &defaultJobRepository{storageService: storageService}
+
+	err := repository.AddJobs(ctx, jobs)
+	require.NoError(t, err)
+
+	points, err := repository.GetSourceRecoveryPoints(ctx, "backup-id-1")
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+
+	assert.Equal(t, "table_a", points[0].Source)
+	assert.Equal(t, now.Add(-48*time.Hour), points[0].FirstJobTime.UTC())
+	assert.Equal(t, now.Add(-24*time.Hour), points[0].LastFinishedOkTime.UTC())
+	assert.Equal(t, now.Add(-time.Hour), points[0].LastUnfinishedTime.UTC())
+	assert.False(t, points[0].InProgress)
+
+	assert.Equal(t, "table_b", points[1].Source)
+	assert.True(t, points[1].LastFinishedOkTime.IsZero())
+	assert.True(t, points[1].InProgress)
+}
+
 func TestDefaultJobRepository_GetBackupRestoreJobs(t *testing.T) {
 	backups := []Backup{
 		{ID: "backup-id-1", Strategy: Mirror},
@@ -489,3 +583,58 @@ func TestDefaultJobRepository_GetBackupRestoreJobs(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, restoreJobs, 1)
 }
+
+func TestDefaultJobRepository_GetBackupRestoreJobsAt(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1, t2, t3 := t0.Add(1*time.Hour), t0.Add(2*time.Hour), t0.Add(3*time.Hour)
+
+	backups := []Backup{
+		{ID: "backup-id-1", Strategy: Mirror},
+	}
+	jobs := []Job{
+		{ID: "job-id-1", BackupID: "backup-id-1", Status: FinishedOk, Type: BigQuery, Source: "table_a"},
+		{ID: "job-id-2", BackupID: "backup-id-1", Status: FinishedOk, Type: BigQuery, Source: "table_a"},
+		{ID: "job-id-3", BackupID: "backup-id-1", Status: FinishedOk, Type: BigQuery, Source: "table_b"},
+		{ID: "job-id-5", BackupID: "backup-id-1", Status: Error, Type: BigQuery, Source: "table_c"},
+	}
+	metadata := []SourceMetadata{
+		{ID: 1, BackupID: "backup-id-1", Source: "table_a", Operation: Add.String(), LastModifiedTime: t0, CreatedTimestamp: t0},
+		{ID: 2, BackupID: "backup-id-1", Source: "table_a", Operation: Update.String(), LastModifiedTime: t2, CreatedTimestamp: t2},
+		{ID: 3, BackupID: "backup-id-1", Source: "table_b", Operation: Add.String(), LastModifiedTime: t0, CreatedTimestamp: t0},
+		{ID: 4, BackupID: "backup-id-1", Source: "table_b", Operation: Delete.String(), CreatedTimestamp: t3},
+		{ID: 5, BackupID: "backup-id-1", Source: "table_c", Operation: Add.String(), LastModifiedTime: t2, CreatedTimestamp: t2},
+	}
+	metadataJobs := []SourceMetadataJob{
+		{SourceMetadataID: 1, JobId: "job-id-1"},
+		{SourceMetadataID: 2, JobId: "job-id-2"},
+		{SourceMetadataID: 3, JobId: "job-id-3"},
+		{SourceMetadataID: 5, JobId: "job-id-5"},
+	}
+
+	ctx, storageService := prepareTest(t)
+
+	repository := &defaultJobRepository{storageService: storageService}
+
+	err := setDatabase(storageService, backups, jobs, metadata, metadataJobs)
+	assert.NoError(t, err)
+
+	restoreJobs, err := repository.GetBackupRestoreJobsAt(ctx, "backup-id-1", t1)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"job-id-1", "job-id-3"}, jobIDs(restoreJobs))
+
+	restoreJobs, err = repository.GetBackupRestoreJobsAt(ctx, "backup-id-1", t2)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"job-id-2", "job-id-3"}, jobIDs(restoreJobs))
+
+	restoreJobs, err = repository.GetBackupRestoreJobsAt(ctx, "backup-id-1", t3)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"job-id-2"}, jobIDs(restoreJobs))
+}
+
+func jobIDs(jobs []*Job) []string {
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	return ids
+}
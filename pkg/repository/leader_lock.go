@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service"
+	"go.opencensus.io/trace"
+)
+
+// LeaderLock elects one leader between replicas with a Postgres session advisory lock,
+// the lock is held as long as the connection that acquired it stays open
+type LeaderLock interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+// defaultLeaderLock implements LeaderLock
+type defaultLeaderLock struct {
+	storageService *service.Service
+	name           string
+
+	mu   sync.Mutex
+	conn *pg.Conn
+}
+
+// NewLeaderLock create a new LeaderLock, replicas using the same name compete for the same lock
+func NewLeaderLock(ctxIn context.Context, credentialsProvider secret.SecretProvider, name string) (LeaderLock, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewLeaderLock")
+	defer span.End()
+
+	storageService, err := service.NewStorageService(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultLeaderLock{storageService: storageService, name: name}, nil
+}
+
+// TryAcquire acquires the lock without waiting, it returns true as long as this replica is the leader
+func (l *defaultLeaderLock) TryAcquire(ctxIn context.Context) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultLeaderLock).TryAcquire")
+	defer span.End()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		// the lock is lost together with the connection
+		_, err := l.conn.ExecContext(ctx, "SELECT 1")
+		if err == nil {
+			return true, nil
+		}
+		_ = l.conn.Close()
+		l.conn = nil
+	}
+
+	conn := l.storageService.DB().Conn()
+	var acquired bool
+	_, err := conn.QueryOneContext(ctx, pg.Scan(&acquired), "SELECT pg_try_advisory_lock(hashtext(?))", l.name)
+	if err != nil {
+		_ = conn.Close()
+		return false, fmt.Errorf("error during executing try advisory lock statement: %s", err)
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Release gives up the leadership, it is a no-op if this replica is not the leader
+func (l *defaultLeaderLock) Release(ctxIn context.Context) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultLeaderLock).Release")
+	defer span.End()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	defer func() {
+		_ = l.conn.Close()
+		l.conn = nil
+	}()
+
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext(?))", l.name)
+	if err != nil {
+		return fmt.Errorf("error during executing advisory unlock statement: %s", err)
+	}
+	return nil
+}
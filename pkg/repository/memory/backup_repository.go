@@ -23,6 +23,16 @@ func (r *BackupRepository) MarkTrashcanCleanup(_ context.Context, id string, sta
 	return nil
 }
 
+func (r *BackupRepository) MarkRetentionLocked(_ context.Context, backupID string) error {
+	for _, backup := range r.backups {
+		if backup.ID == backupID {
+			backup.RetentionLocked = true
+			return nil
+		}
+	}
+	return nil
+}
+
 func (r *BackupRepository) GetBackupsByCleanupTrashcanStatus(_ context.Context, status repository.TrashcanCleanupStatus) ([]*repository.Backup, error) {
 	var backups []*repository.Backup
 	for _, backup := range r.backups {
@@ -51,18 +61,30 @@ func (r *BackupRepository) UpdateBackup(ctxIn context.Context, updateFields repo
 			backup.IncludePath = updateFields.IncludePath
 			backup.ExcludePath = updateFields.ExcludePath
 		}
+		if repository.Firestore == backup.Type {
+			backup.CollectionIDs = updateFields.CollectionIDs
+		}
+		if repository.CloudSQL == backup.Type {
+			backup.Databases = updateFields.Databases
+		}
+		if updateFields.MaxRetryAttempts > 0 {
+			backup.MaxRetryAttempts = updateFields.MaxRetryAttempts
+		}
+		if updateFields.Schedule != nil {
+			backup.ScheduleOptions = *updateFields.Schedule
+		}
 		return nil
 	}
 	return fmt.Errorf("backup %s not found", updateFields.BackupID)
 }
 
-// GetBigQueryOneShotSnapshots return backups that are BigQuery with strategy Snapshot
+// GetBigQueryOneShotSnapshots return backups that are BigQuery with strategy Snapshot or TableSnapshot
 func (r *BackupRepository) GetBigQueryOneShotSnapshots(ctxIn context.Context, status repository.BackupStatus) (backups []*repository.Backup, err error) {
 	_, span := trace.StartSpan(ctxIn, "(*BackupRepository).GetBigQueryOneShotSnapshots")
 	defer span.End()
 
 	for _, backup := range r.backups {
-		if backup.Strategy == repository.Snapshot && backup.SnapshotOptions.FrequencyInHours == 0 {
+		if backup.IsOneshot() {
 			backups = append(backups, backup)
 		}
 	}
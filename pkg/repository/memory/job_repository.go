@@ -38,21 +38,43 @@ func (r *JobRepository) GetBackupRestoreJobs(ctxIn context.Context, backupID, jo
 	panic("implement me")
 }
 
-// GetByJobTypeAndStatusAndLimit filter backup jobs by status and type with limit
-func (r *JobRepository) ListByTypeAndStatusWithLimit(ctxIn context.Context, backupType repository.BackupType, jobStatus repository.JobStatus, limit uint) (jobs []*repository.Job, err error) {
-	_, span := trace.StartSpan(ctxIn, "(*JobRepository).ListByTypeAndStatusWithLimit")
+// GetBackupRestoreJobsAt is not implemented
+func (r *JobRepository) GetBackupRestoreJobsAt(ctxIn context.Context, backupID string, timestamp time.Time) ([]*repository.Job, error) {
+	_, span := trace.StartSpan(ctxIn, "(*JobRepository).GetBackupRestoreJobsAt")
 	defer span.End()
+
+	panic("implement me")
+}
+
+// ListNextJobs takes the NotScheduled jobs round-robin between the backups, backups are unknown so the filter is ignored
+func (r *JobRepository) ListNextJobs(ctxIn context.Context, backupType repository.BackupType, filter repository.NextJobsFilter, limit uint) (jobs []*repository.Job, err error) {
+	_, span := trace.StartSpan(ctxIn, "(*JobRepository).ListNextJobs")
+	defer span.End()
+
+	var backupIDs []string
+	jobsByBackup := map[string][]*repository.Job{}
 	for _, j := range r.jobs {
-		if j.Type == backupType && j.Status == jobStatus {
-			jobs = append(jobs, j)
+		if j.Type != backupType || j.Status != repository.NotScheduled {
+			continue
+		}
+		if _, exists := jobsByBackup[j.BackupID]; !exists {
+			backupIDs = append(backupIDs, j.BackupID)
 		}
+		jobsByBackup[j.BackupID] = append(jobsByBackup[j.BackupID], j)
 	}
-	if len(jobs) == 0 {
-		return jobs, err
-	} else if uint(len(jobs)) < limit {
-		limit = uint(len(jobs))
+	for round := 0; uint(len(jobs)) < limit; round++ {
+		added := false
+		for _, backupID := range backupIDs {
+			if round < len(jobsByBackup[backupID]) && uint(len(jobs)) < limit {
+				jobs = append(jobs, jobsByBackup[backupID][round])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
 	}
-	return jobs[:limit], err
+	return jobs, err
 }
 
 // GetByJobTypeAndStatus filter backup jobs by status and type
@@ -207,6 +229,98 @@ func (r *JobRepository) PatchJobStatus(ctxIn context.Context, patch repository.J
 	return nil
 }
 
+// PatchJobRetry set status and retry bookkeeping of a failed job
+func (r *JobRepository) PatchJobRetry(ctxIn context.Context, jobID string, status repository.JobStatus, retry repository.JobRetry) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*JobRepository).PatchJobRetry")
+	defer span.End()
+
+	j, err := r.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	j.Status = status
+	j.JobRetry = retry
+	return nil
+}
+
+// GetJobsToRetry get failed jobs whose next attempt is due before given time
+func (r *JobRepository) GetJobsToRetry(ctxIn context.Context, before time.Time) (jobs []*repository.Job, err error) {
+	_, span := trace.StartSpan(ctxIn, "(*JobRepository).GetJobsToRetry")
+	defer span.End()
+
+	for _, j := range r.jobs {
+		if (j.Status == repository.Error || j.Status == repository.FinishedError) && !j.NextAttemptTime.IsZero() && !j.NextAttemptTime.After(before) {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs, nil
+}
+
+// RequeueJob set a failed job back to NotScheduled and clear its next attempt
+func (r *JobRepository) RequeueJob(ctxIn context.Context, jobID string) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*JobRepository).RequeueJob")
+	defer span.End()
+
+	j, err := r.GetJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	j.Status = repository.NotScheduled
+	j.NextAttemptTime = time.Time{}
+	j.ForeignJobID = repository.ForeignJobID{}
+	return nil
+}
+
+// GetInFlightJobCounts is not supported without backups, no job is counted
+func (r *JobRepository) GetInFlightJobCounts(ctxIn context.Context) ([]repository.InFlightJobCount, error) {
+	return nil, nil
+}
+
+// GetSourceRecoveryPoints summarise the jobs of a backup per source
+func (r *JobRepository) GetSourceRecoveryPoints(ctxIn context.Context, backupID string) ([]repository.SourceRecoveryPoint, error) {
+	_, span := trace.StartSpan(ctxIn, "(*JobRepository).GetSourceRecoveryPoints")
+	defer span.End()
+
+	var points []repository.SourceRecoveryPoint
+	indexBySource := map[string]int{}
+	for _, job := range r.jobs {
+		if job.BackupID != backupID || job.Status == repository.JobDeleted || !job.DeletedTimestamp.IsZero() {
+			continue
+		}
+		index, exists := indexBySource[job.Source]
+		if !exists {
+			index = len(points)
+			indexBySource[job.Source] = index
+			points = append(points, repository.SourceRecoveryPoint{Source: job.Source, FirstJobTime: job.CreatedTimestamp})
+		}
+		point := &points[index]
+		if job.CreatedTimestamp.Before(point.FirstJobTime) {
+			point.FirstJobTime = job.CreatedTimestamp
+		}
+		switch job.Status {
+		case repository.FinishedOk:
+			if job.CreatedTimestamp.After(point.LastFinishedOkTime) {
+				point.LastFinishedOkTime = job.CreatedTimestamp
+			}
+		case repository.FinishedError:
+			if job.CreatedTimestamp.After(point.LastUnfinishedTime) {
+				point.LastUnfinishedTime = job.CreatedTimestamp
+			}
+		default:
+			if job.CreatedTimestamp.After(point.LastUnfinishedTime) {
+				point.LastUnfinishedTime = job.CreatedTimestamp
+			}
+			point.InProgress = true
+		}
+	}
+	return points, nil
+}
+
+// GetSourceProjectsOfJobs is not supported without backups, no project is listed
+func (r *JobRepository) GetSourceProjectsOfJobs(ctxIn context.Context, backupType repository.BackupType, status repository.JobStatus) ([]string, error) {
+	return nil, nil
+}
+
 func (r *JobRepository) GetJobCountForBackupID(ctxIn context.Context, backupID string) (int, error) {
 	_, span := trace.StartSpan(ctxIn, "(*JobRepository).GetJobCountForBackupID")
 	defer span.End()
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// NotificationRepository defines operation with sent notifications, a notification key is sent at most once per throttle
+type NotificationRepository interface {
+	TryReserveNotification(ctxIn context.Context, notification *Notification, throttle time.Duration) (bool, error)
+	ReleaseNotification(ctxIn context.Context, key string) error
+}
+
+// defaultNotificationRepository implements NotificationRepository
+type defaultNotificationRepository struct {
+	storageService *service.Service
+}
+
+// NewNotificationRepository return instance of NotificationRepository
+func NewNotificationRepository(ctxIn context.Context, credentialsProvider secret.SecretProvider) (NotificationRepository, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewNotificationRepository")
+	defer span.End()
+
+	storageService, err := service.NewStorageService(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultNotificationRepository{storageService: storageService}, nil
+}
+
+// TryReserveNotification records that a notification is sent, false is returned if its key was sent within the throttle
+func (d *defaultNotificationRepository) TryReserveNotification(ctxIn context.Context, notification *Notification, throttle time.Duration) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultNotificationRepository).TryReserveNotification")
+	defer span.End()
+
+	// the timestamps are taken from the database, so the clocks of the replicas do not matter
+	var key string
+	_, err := d.storageService.DB().QueryOneContext(ctx, pg.Scan(&key), `
+		INSERT INTO notifications (key, kind, project, sent_timestamp)
+		VALUES (?0, ?1, ?2, now())
+		ON CONFLICT (key) DO UPDATE
+		SET kind = EXCLUDED.kind, project = EXCLUDED.project, sent_timestamp = EXCLUDED.sent_timestamp
+		WHERE notifications.sent_timestamp < now() - make_interval(secs => ?3)
+		RETURNING key`,
+		notification.Key, notification.Kind, notification.Project, throttle.Seconds())
+	if errors.Is(err, pg.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "error during executing reserve notification %s statement", notification.Key)
+	}
+
+	return true, nil
+}
+
+// ReleaseNotification forgets a reserved notification, so it is sent again by the next attempt
+func (d *defaultNotificationRepository) ReleaseNotification(ctxIn context.Context, key string) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultNotificationRepository).ReleaseNotification")
+	defer span.End()
+
+	_, err := d.storageService.DB().ExecContext(ctx, "DELETE FROM notifications WHERE key = ?", key)
+	if err != nil {
+		return errors.Wrapf(err, "error during executing release notification %s statement", key)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultNotificationRepository_TryReserveNotification(t *testing.T) {
+	ctx, repository := prepareTestForDefaultNotificationRepository(t)
+	notification := &Notification{Key: "job_failed/job-1", Kind: "job_failed", Project: "local-account"}
+
+	reserved, err := repository.TryReserveNotification(ctx, notification, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	reserved, err = repository.TryReserveNotification(ctx, notification, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, reserved, "notification was sent within the throttle")
+
+	reserved, err = repository.TryReserveNotification(ctx, &Notification{Key: "job_failed/job-2", Kind: "job_failed", Project: "local-account"}, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, reserved, "notifications with other keys are independent")
+
+	_, err = repository.storageService.DB().Model(&Notification{}).
+		Set("sent_timestamp = now() - interval '2 hours'").
+		Where("key = ?", notification.Key).
+		Update()
+	require.NoError(t, err)
+
+	reserved, err = repository.TryReserveNotification(ctx, notification, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, reserved, "throttle is over")
+}
+
+func TestDefaultNotificationRepository_ReleaseNotification(t *testing.T) {
+	ctx, repository := prepareTestForDefaultNotificationRepository(t)
+	notification := &Notification{Key: "job_stuck/job-1", Kind: "job_stuck", Project: "local-account"}
+
+	reserved, err := repository.TryReserveNotification(ctx, notification, time.Hour)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	require.NoError(t, repository.ReleaseNotification(ctx, notification.Key))
+
+	reserved, err = repository.TryReserveNotification(ctx, notification, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+}
+
+func prepareTestForDefaultNotificationRepository(t *testing.T) (context.Context, defaultNotificationRepository) {
+	ctx, storageService := prepareTest(t)
+	return ctx, defaultNotificationRepository{storageService: storageService}
+}
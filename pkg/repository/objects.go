@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/golang/glog"
@@ -13,6 +14,15 @@ type BackupStatus string
 // JobStatus for backup
 type JobStatus string
 
+// RestoreJobStatus for restore job
+type RestoreJobStatus string
+
+// RestoreDrillStatus for restore drill
+type RestoreDrillStatus string
+
+// TaskRunStatus for a run of a background task
+type TaskRunStatus string
+
 // TrashcanCleanupStatus status for scheduled cleanup of trashcan
 type TrashcanCleanupStatus string
 
@@ -49,14 +59,106 @@ const (
 	Snapshot Strategy = "Snapshot"
 	// Mirror data actively
 	Mirror Strategy = "Mirror"
+	// TableSnapshot will make a ontime or recurring BigQuery table snapshot in a dataset of the sink project
+	TableSnapshot Strategy = "TableSnapshot"
 )
 const (
 	// BigQuery type
 	BigQuery BackupType = "BigQuery"
 	// CloudStorage type
 	CloudStorage BackupType = "CloudStorage"
+	// Firestore type
+	Firestore BackupType = "Firestore"
+	// CloudSQL type
+	CloudSQL BackupType = "CloudSQL"
 )
 
+// FirestoreDefaultDatabase id of the database every project with Firestore has
+const FirestoreDefaultDatabase = "(default)"
+
+// ExportFormat file format of the BigQuery extract jobs
+type ExportFormat string
+
+const (
+	// AvroExport format is the default of BigQuery backups
+	AvroExport ExportFormat = "AVRO"
+	// ParquetExport format
+	ParquetExport ExportFormat = "PARQUET"
+	// JSONExport newline-delimited JSON format
+	JSONExport ExportFormat = "NEWLINE_DELIMITED_JSON"
+)
+
+func (f ExportFormat) String() string {
+	return string(f)
+}
+
+// ExportCompression compression of the BigQuery extract jobs
+type ExportCompression string
+
+const (
+	// NoCompression is the default of BigQuery backups
+	NoCompression ExportCompression = "NONE"
+	// SnappyCompression for Avro and Parquet
+	SnappyCompression ExportCompression = "SNAPPY"
+	// DeflateCompression for Avro
+	DeflateCompression ExportCompression = "DEFLATE"
+	// GzipCompression for Parquet and JSON
+	GzipCompression ExportCompression = "GZIP"
+	// ZstdCompression for Parquet
+	ZstdCompression ExportCompression = "ZSTD"
+)
+
+func (c ExportCompression) String() string {
+	return string(c)
+}
+
+// ExportCompressions compressions BigQuery supports per export format
+var ExportCompressions = map[ExportFormat][]ExportCompression{
+	AvroExport:    {NoCompression, SnappyCompression, DeflateCompression},
+	ParquetExport: {NoCompression, SnappyCompression, GzipCompression, ZstdCompression},
+	JSONExport:    {NoCompression, GzipCompression},
+}
+
+// IsSupportedExportFormat check if BigQuery can extract tables in format with compression
+func IsSupportedExportFormat(format ExportFormat, compression ExportCompression) bool {
+	for _, supported := range ExportCompressions[format] {
+		if supported == compression {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportFileExtension of the extracted files, empty values fall back to uncompressed Avro of backups created before formats were configurable
+// Avro and Parquet compress blocks inside the file so only gzipped JSON gets an additional suffix
+func ExportFileExtension(format ExportFormat, compression ExportCompression) string {
+	switch format {
+	case ParquetExport:
+		return "parquet"
+	case JSONExport:
+		if compression == GzipCompression {
+			return "json.gz"
+		}
+		return "json"
+	default:
+		return "avro"
+	}
+}
+
+// CloudSQLFileType file type of a Cloud SQL instance export
+type CloudSQLFileType string
+
+const (
+	// CloudSQLExportSQL export a database as SQL dump
+	CloudSQLExportSQL CloudSQLFileType = "SQL"
+	// CloudSQLExportCSV export the result of a query as CSV
+	CloudSQLExportCSV CloudSQLFileType = "CSV"
+)
+
+func (f CloudSQLFileType) String() string {
+	return string(f)
+}
+
 const (
 	// NotScheduled job is not scheduled
 	NotScheduled JobStatus = "NotScheduled"
@@ -76,6 +178,37 @@ const (
 	JobDeleted JobStatus = "JobDeleted"
 )
 
+const (
+	// RestoreNotScheduled restore job is not started yet
+	RestoreNotScheduled RestoreJobStatus = "NotScheduled"
+	// RestoreScheduled restore job was started
+	RestoreScheduled RestoreJobStatus = "Scheduled"
+	// RestoreError restore job could not be started
+	RestoreError RestoreJobStatus = "Error"
+	// RestoreFinishedOk restore job finished with success
+	RestoreFinishedOk RestoreJobStatus = "FinishedOk"
+	// RestoreFinishedError restore job finished with error
+	RestoreFinishedError RestoreJobStatus = "FinishedError"
+)
+
+const (
+	// DrillRunning restore drill waits for its restore job
+	DrillRunning RestoreDrillStatus = "Running"
+	// DrillSucceeded restore drill restored the expected data
+	DrillSucceeded RestoreDrillStatus = "Succeeded"
+	// DrillFailed restore drill could not restore the expected data
+	DrillFailed RestoreDrillStatus = "Failed"
+)
+
+const (
+	// TaskRunRunning task is still running
+	TaskRunRunning TaskRunStatus = "Running"
+	// TaskRunSucceeded task handled all items
+	TaskRunSucceeded TaskRunStatus = "Succeeded"
+	// TaskRunFailed task could not run or failed for at least one item
+	TaskRunFailed TaskRunStatus = "Failed"
+)
+
 const (
 	// NotStarted for a newly created backup
 	NotStarted BackupStatus = "NotStarted"
@@ -114,10 +247,10 @@ const (
 )
 
 // Strategies for a backups
-var Strategies = []Strategy{Snapshot, Mirror}
+var Strategies = []Strategy{Snapshot, Mirror, TableSnapshot}
 
 // BackupTypes source for a backup
-var BackupTypes = []BackupType{BigQuery, CloudStorage}
+var BackupTypes = []BackupType{BigQuery, CloudStorage, Firestore, CloudSQL}
 
 // JobStatuses available job statuses
 var JobStatuses = []JobStatus{NotScheduled, Scheduled, Error, Pending, FinishedOk, FinishedError, FinishedQuotaError, JobDeleted}
@@ -135,6 +268,23 @@ func (bs JobStatus) String() string {
 	return string(bs)
 }
 
+func (rs RestoreJobStatus) String() string {
+	return string(rs)
+}
+
+// IsFinal check if restore job will not change its status anymore
+func (rs RestoreJobStatus) IsFinal() bool {
+	return rs == RestoreError || rs == RestoreFinishedOk || rs == RestoreFinishedError
+}
+
+func (ds RestoreDrillStatus) String() string {
+	return string(ds)
+}
+
+func (ts TaskRunStatus) String() string {
+	return string(ts)
+}
+
 func (bs BackupStatus) String() string {
 	return string(bs)
 }
@@ -162,6 +312,11 @@ func (s Strategy) EqualTo(strategy string) bool {
 	return strings.EqualFold(strategy, s.String())
 }
 
+// IsSnapshot is true for strategies scheduled by SnapshotOptions
+func (s Strategy) IsSnapshot() bool {
+	return s == Snapshot || s == TableSnapshot
+}
+
 func (s Region) String() string {
 	return string(s)
 }
@@ -189,6 +344,9 @@ type MirrorRevision struct {
 	Source           string
 	TargetProject    string
 	TargetSink       string
+	// ExportFormat and ExportCompression of the backup the revision belongs to
+	BigqueryExportFormat      ExportFormat
+	BigqueryExportCompression ExportCompression
 }
 
 func (b MirrorRevision) String() string {
@@ -196,6 +354,11 @@ func (b MirrorRevision) String() string {
 		b.BackupID, b.JobID, b.SourceMetadataID, b.BigqueryDataset, b.Source, b.TargetProject, b.TargetSink)
 }
 
+// FileExtension of the files written by the extract job of the revision
+func (b MirrorRevision) FileExtension() string {
+	return ExportFileExtension(b.BigqueryExportFormat, b.BigqueryExportCompression)
+}
+
 // BuildStoragePath create a path for BigQuery dataset/table
 func BuildStoragePath(dataset, table string) string {
 	if table != "" {
@@ -205,13 +368,48 @@ func BuildStoragePath(dataset, table string) string {
 }
 
 // BuildFullObjectStoragePath create a sink's path for a GCS data
-func BuildFullObjectStoragePath(sink, dataset, table, jobID string) string {
-	return fmt.Sprintf("gs://%s/%s/%s-*.avro", sink, BuildStoragePath(dataset, table), jobID)
+func BuildFullObjectStoragePath(sink, dataset, table, jobID, extension string) string {
+	return fmt.Sprintf("gs://%s/%s/%s-*.%s", sink, BuildStoragePath(dataset, table), jobID, extension)
 }
 
 // BuildObjectStoragePathPattern create a sink's path for a BigQuery data
-func BuildObjectStoragePathPattern(dataset, table, jobID string) string {
-	return fmt.Sprintf("%s/%s-.*.avro", BuildStoragePath(dataset, table), jobID)
+func BuildObjectStoragePathPattern(dataset, table, jobID, extension string) string {
+	return fmt.Sprintf("%s/%s-.*\\.%s", BuildStoragePath(dataset, table), jobID, regexp.QuoteMeta(extension))
+}
+
+// BuildBigQueryManifestPath create a sink's path for the metadata manifest of a BigQuery dataset
+func BuildBigQueryManifestPath(dataset, manifestID string) string {
+	return fmt.Sprintf("%s/metadata/%s.json", BuildStoragePath(dataset, ""), manifestID)
+}
+
+// BuildTableSnapshotDataset create the name of the dataset holding the table snapshots of a backup, dataset names can not contain dashes
+func BuildTableSnapshotDataset(sink string) string {
+	return strings.ReplaceAll(sink, "-", "_")
+}
+
+// BuildTableSnapshotName create the name of a table snapshot of a job
+func BuildTableSnapshotName(table, jobID string) string {
+	return fmt.Sprintf("%s_%s", table, strings.ReplaceAll(jobID, "-", ""))
+}
+
+// BuildFirestoreExportPath create a sink's path for a managed export of a Firestore database
+func BuildFirestoreExportPath(database, jobID string) string {
+	return fmt.Sprintf("firestore/%s/%s", database, jobID)
+}
+
+// BuildFullFirestoreExportPath create a sink's URI for a managed export of a Firestore database
+func BuildFullFirestoreExportPath(sink, database, jobID string) string {
+	return fmt.Sprintf("gs://%s/%s", sink, BuildFirestoreExportPath(database, jobID))
+}
+
+// BuildCloudSQLExportPath create a sink's path for an export of a Cloud SQL database, exports are compressed by Cloud SQL because of the .gz suffix
+func BuildCloudSQLExportPath(instance, database, jobID string, fileType CloudSQLFileType) string {
+	return fmt.Sprintf("cloudsql/%s/%s/%s.%s.gz", instance, database, jobID, strings.ToLower(fileType.String()))
+}
+
+// BuildFullCloudSQLExportPath create a sink's URI for an export of a Cloud SQL database
+func BuildFullCloudSQLExportPath(sink, instance, database, jobID string, fileType CloudSQLFileType) string {
+	return fmt.Sprintf("gs://%s/%s", sink, BuildCloudSQLExportPath(instance, database, jobID, fileType))
 }
 
 func logQueryError(source string, err error, args ...interface{}) {
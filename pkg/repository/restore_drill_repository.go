@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// RestoreDrillPatch record the outcome of a restore drill
+type RestoreDrillPatch struct {
+	ID             string
+	Status         RestoreDrillStatus
+	RestoredCount  *int64
+	ElapsedSeconds int
+	RTOBreached    bool
+	ErrorMessage   string
+}
+
+// RestoreDrillRepository defines operation with restore drills
+type RestoreDrillRepository interface {
+	AddRestoreDrill(ctxIn context.Context, drill *RestoreDrill) error
+	GetByStatus(ctxIn context.Context, status ...RestoreDrillStatus) ([]*RestoreDrill, error)
+	GetLatestForBackupID(ctxIn context.Context, backupID string, limit int) ([]*RestoreDrill, error)
+	PatchRestoreDrill(ctxIn context.Context, patch RestoreDrillPatch) error
+}
+
+// defaultRestoreDrillRepository implements RestoreDrillRepository
+type defaultRestoreDrillRepository struct {
+	storageService *service.Service
+}
+
+// NewRestoreDrillRepository return instance of RestoreDrillRepository
+func NewRestoreDrillRepository(ctxIn context.Context, credentialsProvider secret.SecretProvider) (RestoreDrillRepository, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewRestoreDrillRepository")
+	defer span.End()
+
+	storageService, err := service.NewStorageService(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultRestoreDrillRepository{storageService: storageService}, nil
+}
+
+// AddRestoreDrill add new restore drill
+func (d *defaultRestoreDrillRepository) AddRestoreDrill(ctxIn context.Context, drill *RestoreDrill) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultRestoreDrillRepository).AddRestoreDrill")
+	defer span.End()
+
+	_, err := d.storageService.DB().Model(drill).Insert()
+	if err != nil {
+		return errors.Wrap(err, "error during executing add restore drill statement")
+	}
+
+	return nil
+}
+
+// GetByStatus list restore drills with one of the given statuses
+func (d *defaultRestoreDrillRepository) GetByStatus(ctxIn context.Context, status ...RestoreDrillStatus) ([]*RestoreDrill, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultRestoreDrillRepository).GetByStatus")
+	defer span.End()
+
+	var drills []*RestoreDrill
+	err := d.storageService.DB().Model(&drills).
+		WhereIn("status IN (?)", status).
+		Where("audit_deleted_timestamp IS NULL").
+		Order("audit_created_timestamp ASC").
+		Select()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error during executing get restore drills by status %v statement", status)
+	}
+
+	return drills, nil
+}
+
+// GetLatestForBackupID list the most recent restore drills of a backup, newest first
+func (d *defaultRestoreDrillRepository) GetLatestForBackupID(ctxIn context.Context, backupID string, limit int) ([]*RestoreDrill, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultRestoreDrillRepository).GetLatestForBackupID")
+	defer span.End()
+
+	var drills []*RestoreDrill
+	err := d.storageService.DB().Model(&drills).
+		Where("backup_id = ?", backupID).
+		Where("audit_deleted_timestamp IS NULL").
+		Order("audit_created_timestamp DESC").
+		Limit(limit).
+		Select()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error during executing get restore drills for backup %s statement", backupID)
+	}
+
+	return drills, nil
+}
+
+// PatchRestoreDrill change restore drill status and its measurements
+func (d *defaultRestoreDrillRepository) PatchRestoreDrill(ctxIn context.Context, patch RestoreDrillPatch) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultRestoreDrillRepository).PatchRestoreDrill")
+	defer span.End()
+
+	drill := &RestoreDrill{
+		Status:         patch.Status,
+		RestoredCount:  patch.RestoredCount,
+		ElapsedSeconds: patch.ElapsedSeconds,
+		RTOBreached:    patch.RTOBreached,
+		ErrorMessage:   patch.ErrorMessage,
+		EntityAudit: EntityAudit{
+			UpdatedTimestamp: time.Now(),
+		},
+	}
+
+	_, err := d.storageService.DB().Model(drill).
+		Column("status", "restored_count", "elapsed_seconds", "rto_breached", "error_message", "audit_updated_timestamp").
+		Where("audit_deleted_timestamp IS NULL").
+		Where("id = ?", patch.ID).
+		Update()
+	if err != nil {
+		return fmt.Errorf("error during executing updating restore drill statement: %s", err)
+	}
+
+	return nil
+}
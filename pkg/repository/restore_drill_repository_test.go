@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRestoreDrillRepository_AddRestoreDrill_GetLatestForBackupID(t *testing.T) {
+	const backupID = "restore-drill-backup-id-1"
+	ctx, repository := prepareTestForDefaultRestoreDrillRepository(t, backupID)
+
+	expectedCount := int64(42)
+	err := repository.AddRestoreDrill(ctx, &RestoreDrill{ID: "restore-drill-1", BackupID: backupID, Type: BigQuery, Status: DrillSucceeded, Source: "table_a", ExpectedCount: &expectedCount})
+	require.NoError(t, err)
+	err = repository.AddRestoreDrill(ctx, &RestoreDrill{ID: "restore-drill-2", BackupID: backupID, Type: BigQuery, Status: DrillRunning, Source: "table_b"})
+	require.NoError(t, err)
+
+	drills, err := repository.GetLatestForBackupID(ctx, backupID, 1)
+	require.NoError(t, err)
+	require.Len(t, drills, 1)
+	assert.Equal(t, "restore-drill-2", drills[0].ID)
+	assert.Nil(t, drills[0].ExpectedCount)
+
+	drills, err = repository.GetLatestForBackupID(ctx, backupID, 10)
+	require.NoError(t, err)
+	require.Len(t, drills, 2)
+	require.NotNil(t, drills[1].ExpectedCount)
+	assert.Equal(t, expectedCount, *drills[1].ExpectedCount)
+}
+
+func TestDefaultRestoreDrillRepository_PatchRestoreDrill(t *testing.T) {
+	const backupID = "restore-drill-backup-id-2"
+	ctx, repository := prepareTestForDefaultRestoreDrillRepository(t, backupID)
+
+	err := repository.AddRestoreDrill(ctx, &RestoreDrill{ID: "restore-drill-3", BackupID: backupID, Type: CloudStorage, Status: DrillRunning, Source: "gs://sink/prefix/", RecoveryTimeObjective: 10})
+	require.NoError(t, err)
+
+	drills, err := repository.GetByStatus(ctx, DrillRunning)
+	require.NoError(t, err)
+	assert.Len(t, drills, 1)
+
+	restoredCount := int64(7)
+	err = repository.PatchRestoreDrill(ctx, RestoreDrillPatch{
+		ID:             "restore-drill-3",
+		Status:         DrillSucceeded,
+		RestoredCount:  &restoredCount,
+		ElapsedSeconds: 900,
+		RTOBreached:    true,
+	})
+	require.NoError(t, err)
+
+	drills, err = repository.GetLatestForBackupID(ctx, backupID, 1)
+	require.NoError(t, err)
+	require.Len(t, drills, 1)
+	assert.Equal(t, DrillSucceeded, drills[0].Status)
+	assert.Equal(t, 900, drills[0].ElapsedSeconds)
+	assert.True(t, drills[0].IsFlagged())
+
+	drills, err = repository.GetByStatus(ctx, DrillRunning)
+	require.NoError(t, err)
+	assert.Empty(t, drills)
+}
+
+func prepareTestForDefaultRestoreDrillRepository(t *testing.T, backupIDs ...string) (context.Context, defaultRestoreDrillRepository) {
+	ctx, storageService := prepareTest(t)
+	setBackupWithIDs(t, storageService, backupIDs...)
+	return ctx, defaultRestoreDrillRepository{storageService: storageService}
+}
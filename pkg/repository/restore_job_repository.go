@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// RestoreJobPatch change restore job status
+type RestoreJobPatch struct {
+	ID           string
+	Status       RestoreJobStatus
+	ErrorMessage string
+	RestoreForeignJobID
+}
+
+// RestoreJobRepository defines operation with restore jobs
+type RestoreJobRepository interface {
+	AddRestoreJobs(ctxIn context.Context, jobs []*RestoreJob) error
+	GetRestoreJob(ctxIn context.Context, id string) (*RestoreJob, error)
+	GetByStatus(ctxIn context.Context, status ...RestoreJobStatus) ([]*RestoreJob, error)
+	GetForBackupID(ctxIn context.Context, backupID string, restoreID string) ([]*RestoreJob, error)
+	PatchRestoreJobStatus(ctxIn context.Context, patch RestoreJobPatch) error
+}
+
+// defaultRestoreJobRepository implements RestoreJobRepository
+type defaultRestoreJobRepository struct {
+	storageService *service.Service
+}
+
+// NewRestoreJobRepository return instance of RestoreJobRepository
+func NewRestoreJobRepository(ctxIn context.Context, credentialsProvider secret.SecretProvider) (RestoreJobRepository, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewRestoreJobRepository")
+	defer span.End()
+
+	storageService, err := service.NewStorageService(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultRestoreJobRepository{storageService: storageService}, nil
+}
+
+// AddRestoreJobs add new restore jobs
+func (d *defaultRestoreJobRepository) AddRestoreJobs(ctxIn context.Context, jobs []*RestoreJob) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultRestoreJobRepository).AddRestoreJobs")
+	defer span.End()
+
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	_, err := d.storageService.DB().Model(&jobs).Insert()
+	if err != nil {
+		return errors.Wrap(err, "error during executing add restore jobs statement")
+	}
+
+	return nil
+}
+
+// GetRestoreJob get restore job details
+func (d *defaultRestoreJobRepository) GetRestoreJob(ctxIn context.Context, id string) (*RestoreJob, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultRestoreJobRepository).GetRestoreJob")
+	defer span.End()
+
+	job := new(RestoreJob)
+	err := d.storageService.DB().Model(job).
+		Where("id = ?", id).
+		Where("audit_deleted_timestamp IS NULL").
+		Select()
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetByStatus list restore jobs with one of the given statuses
+func (d *defaultRestoreJobRepository) GetByStatus(ctxIn context.Context, status ...RestoreJobStatus) ([]*RestoreJob, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultRestoreJobRepository).GetByStatus")
+	defer span.End()
+
+	var jobs []*RestoreJob
+	err := d.storageService.DB().Model(&jobs).
+		WhereIn("status IN (?)", status).
+		Where("audit_deleted_timestamp IS NULL").
+		Order("audit_created_timestamp ASC").
+		Select()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error during executing get restore jobs by status %v statement", status)
+	}
+
+	return jobs, nil
+}
+
+// GetForBackupID list restore jobs for a backup, restoreID is optional and narrows the result to a single restore
+func (d *defaultRestoreJobRepository) GetForBackupID(ctxIn context.Context, backupID string, restoreID string) ([]*RestoreJob, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultRestoreJobRepository).GetForBackupID")
+	defer span.End()
+
+	var jobs []*RestoreJob
+	query := d.storageService.DB().Model(&jobs).
+		Where("backup_id = ?", backupID).
+		Where("audit_deleted_timestamp IS NULL")
+	if restoreID != "" {
+		query = query.Where("restore_id = ?", restoreID)
+	}
+
+	err := query.Order("audit_created_timestamp DESC", "source ASC").Select()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error during executing get restore jobs for backup %s statement", backupID)
+	}
+
+	return jobs, nil
+}
+
+// PatchRestoreJobStatus change restore job status
+func (d *defaultRestoreJobRepository) PatchRestoreJobStatus(ctxIn context.Context, patch RestoreJobPatch) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultRestoreJobRepository).PatchRestoreJobStatus")
+	defer span.End()
+
+	job := &RestoreJob{
+		Status:       patch.Status,
+		ErrorMessage: patch.ErrorMessage,
+		RestoreForeignJobID: RestoreForeignJobID{
+			BigQueryID:     patch.BigQueryID,
+			CloudStorageID: patch.CloudStorageID,
+		},
+		EntityAudit: EntityAudit{
+			UpdatedTimestamp: time.Now(),
+		},
+	}
+
+	_, err := d.storageService.DB().Model(job).
+		Column("status", "error_message", "audit_updated_timestamp", "bigquery_load_job_id", "cloudstorage_transfer_job_id").
+		Where("audit_deleted_timestamp IS NULL").
+		Where("id = ?", patch.ID).
+		Update()
+	if err != nil {
+		return fmt.Errorf("error during executing updating restore job statement: %s", err)
+	}
+
+	return nil
+}
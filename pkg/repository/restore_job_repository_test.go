@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRestoreJobRepository_AddRestoreJobs_GetForBackupID(t *testing.T) {
+	const backupID = "restore-backup-id-1"
+	ctx, repository := prepareTestForDefaultRestoreJobRepository(t, backupID)
+
+	err := repository.AddRestoreJobs(ctx, []*RestoreJob{
+		{ID: "restore-job-1", RestoreID: "restore-1", BackupID: backupID, Type: BigQuery, Status: RestoreNotScheduled, Source: "table_a"},
+		{ID: "restore-job-2", RestoreID: "restore-1", BackupID: backupID, Type: BigQuery, Status: RestoreNotScheduled, Source: "table_b"},
+		{ID: "restore-job-3", RestoreID: "restore-2", BackupID: backupID, Type: BigQuery, Status: RestoreNotScheduled, Source: "table_a"},
+	})
+	require.NoError(t, err)
+
+	jobs, err := repository.GetForBackupID(ctx, backupID, "")
+	require.NoError(t, err)
+	assert.Len(t, jobs, 3)
+
+	jobs, err = repository.GetForBackupID(ctx, backupID, "restore-1")
+	require.NoError(t, err)
+	assert.Len(t, jobs, 2)
+}
+
+func TestDefaultRestoreJobRepository_PatchRestoreJobStatus(t *testing.T) {
+	const backupID = "restore-backup-id-2"
+	ctx, repository := prepareTestForDefaultRestoreJobRepository(t, backupID)
+
+	err := repository.AddRestoreJobs(ctx, []*RestoreJob{
+		{ID: "restore-job-4", RestoreID: "restore-3", BackupID: backupID, Type: BigQuery, Status: RestoreNotScheduled, Source: "table_a"},
+		{ID: "restore-job-5", RestoreID: "restore-3", BackupID: backupID, Type: BigQuery, Status: RestoreNotScheduled, Source: "table_b"},
+	})
+	require.NoError(t, err)
+
+	err = repository.PatchRestoreJobStatus(ctx, RestoreJobPatch{
+		ID:                  "restore-job-4",
+		Status:              RestoreScheduled,
+		RestoreForeignJobID: RestoreForeignJobID{BigQueryID: "EU.load-job-id"},
+	})
+	require.NoError(t, err)
+
+	job, err := repository.GetRestoreJob(ctx, "restore-job-4")
+	require.NoError(t, err)
+	assert.Equal(t, RestoreScheduled, job.Status)
+	assert.Equal(t, "EU", job.BigQueryID.Location())
+	assert.Equal(t, "load-job-id", job.BigQueryID.JobID())
+
+	jobs, err := repository.GetByStatus(ctx, RestoreScheduled)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func prepareTestForDefaultRestoreJobRepository(t *testing.T, backupIDs ...string) (context.Context, defaultRestoreJobRepository) {
+	ctx, storageService := prepareTest(t)
+	setBackupWithIDs(t, storageService, backupIDs...)
+	return ctx, defaultRestoreJobRepository{storageService: storageService}
+}
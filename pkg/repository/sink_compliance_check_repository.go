@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// SinkComplianceCheckRepository defines operation with the compliance checks of sink projects, checks are only
+// added so the history of a sink project is kept
+type SinkComplianceCheckRepository interface {
+	AddSinkComplianceCheck(ctxIn context.Context, check *SinkComplianceCheck) error
+	GetLatestSinkComplianceChecks(ctxIn context.Context) ([]*SinkComplianceCheck, error)
+	GetSinkComplianceChecks(ctxIn context.Context, projectSink string, page Page) ([]*SinkComplianceCheck, error)
+}
+
+// defaultSinkComplianceCheckRepository implements SinkComplianceCheckRepository
+type defaultSinkComplianceCheckRepository struct {
+	storageService *service.Service
+}
+
+// NewSinkComplianceCheckRepository return instance of SinkComplianceCheckRepository
+func NewSinkComplianceCheckRepository(ctxIn context.Context, credentialsProvider secret.SecretProvider) (SinkComplianceCheckRepository, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewSinkComplianceCheckRepository")
+	defer span.End()
+
+	storageService, err := service.NewStorageService(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultSinkComplianceCheckRepository{storageService: storageService}, nil
+}
+
+// AddSinkComplianceCheck add the result of a check of a sink project
+func (d *defaultSinkComplianceCheckRepository) AddSinkComplianceCheck(ctxIn context.Context, check *SinkComplianceCheck) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultSinkComplianceCheckRepository).AddSinkComplianceCheck")
+	defer span.End()
+
+	_, err := d.storageService.DB().Model(check).Insert()
+	if err != nil {
+		return errors.Wrapf(err, "error during executing add sink compliance check of %s statement", check.ProjectSink)
+	}
+
+	return nil
+}
+
+// GetLatestSinkComplianceChecks get the newest check of every sink project
+func (d *defaultSinkComplianceCheckRepository) GetLatestSinkComplianceChecks(ctxIn context.Context) ([]*SinkComplianceCheck, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultSinkComplianceCheckRepository).GetLatestSinkComplianceChecks")
+	defer span.End()
+
+	var checks []*SinkComplianceCheck
+	err := d.storageService.DB().Model(&checks).
+		DistinctOn("project_sink").
+		Order("project_sink ASC", "last_checked DESC").
+		Select()
+	if err != nil {
+		return nil, errors.Wrap(err, "error during executing get latest sink compliance checks statement")
+	}
+
+	return checks, nil
+}
+
+// GetSinkComplianceChecks get the check history of a sink project, newest first
+func (d *defaultSinkComplianceCheckRepository) GetSinkComplianceChecks(ctxIn context.Context, projectSink string, page Page) ([]*SinkComplianceCheck, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultSinkComplianceCheckRepository).GetSinkComplianceChecks")
+	defer span.End()
+
+	var checks []*SinkComplianceCheck
+	query := d.storageService.DB().Model(&checks).
+		Where("project_sink = ?", projectSink).
+		Order("last_checked DESC")
+	if page.Size != AllJobs {
+		query = query.Offset(page.Number * page.Size).Limit(page.Size)
+	}
+	err := query.Select()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error during executing get sink compliance checks of %s statement", projectSink)
+	}
+
+	return checks, nil
+}
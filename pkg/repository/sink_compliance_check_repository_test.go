@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultSinkComplianceCheckRepository_KeepsHistory(t *testing.T) {
+	ctx, repository := prepareTestForDefaultSinkComplianceCheckRepository(t)
+
+	checked := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, repository.AddSinkComplianceCheck(ctx, &SinkComplianceCheck{ID: "check-1", ProjectSink: "sink-a", SourceProjects: []string{"source-a"}, Compliant: true, LastCheck: checked}))
+	require.NoError(t, repository.AddSinkComplianceCheck(ctx, &SinkComplianceCheck{ID: "check-2", ProjectSink: "sink-a", SourceProjects: []string{"source-a"}, Compliant: false, Reasons: []string{"Backup project should have single writer"}, LastCheck: checked.Add(24 * time.Hour)}))
+	require.NoError(t, repository.AddSinkComplianceCheck(ctx, &SinkComplianceCheck{ID: "check-3", ProjectSink: "sink-b", SourceProjects: []string{"source-b", "source-c"}, Compliant: true, LastCheck: checked}))
+
+	latest, err := repository.GetLatestSinkComplianceChecks(ctx)
+	require.NoError(t, err)
+	require.Len(t, latest, 2)
+	assert.Equal(t, "check-2", latest[0].ID)
+	assert.False(t, latest[0].Compliant)
+	assert.Equal(t, []string{"Backup project should have single writer"}, latest[0].Reasons)
+	assert.Equal(t, "check-3", latest[1].ID)
+	assert.Equal(t, []string{"source-b", "source-c"}, latest[1].SourceProjects)
+
+	history, err := repository.GetSinkComplianceChecks(ctx, "sink-a", Page{Size: AllJobs})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "check-2", history[0].ID)
+	assert.Equal(t, "check-1", history[1].ID)
+
+	history, err = repository.GetSinkComplianceChecks(ctx, "sink-a", Page{Size: 1, Number: 1})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "check-1", history[0].ID)
+}
+
+func prepareTestForDefaultSinkComplianceCheckRepository(t *testing.T) (context.Context, defaultSinkComplianceCheckRepository) {
+	ctx, storageService := prepareTest(t)
+	return ctx, defaultSinkComplianceCheckRepository{storageService: storageService}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// SourceFreshnessRepository defines operation with the freshness of backup sources
+type SourceFreshnessRepository interface {
+	ReplaceForBackupID(ctxIn context.Context, backupID string, freshness []*SourceFreshness) error
+	GetForBackupID(ctxIn context.Context, backupID string) ([]*SourceFreshness, error)
+	GetViolations(ctxIn context.Context) ([]*SourceFreshness, error)
+}
+
+// defaultSourceFreshnessRepository implements SourceFreshnessRepository
+type defaultSourceFreshnessRepository struct {
+	storageService *service.Service
+}
+
+// NewSourceFreshnessRepository return instance of SourceFreshnessRepository
+func NewSourceFreshnessRepository(ctxIn context.Context, credentialsProvider secret.SecretProvider) (SourceFreshnessRepository, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewSourceFreshnessRepository")
+	defer span.End()
+
+	storageService, err := service.NewStorageService(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultSourceFreshnessRepository{storageService: storageService}, nil
+}
+
+// ReplaceForBackupID replace the freshness of all sources of a backup, sources which are not given anymore are removed
+func (d *defaultSourceFreshnessRepository) ReplaceForBackupID(ctxIn context.Context, backupID string, freshness []*SourceFreshness) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultSourceFreshnessRepository).ReplaceForBackupID")
+	defer span.End()
+
+	err := d.storageService.DB().RunInTransaction(ctx, func(tx *pg.Tx) error {
+		_, err := tx.Model((*SourceFreshness)(nil)).Where("backup_id = ?", backupID).Delete()
+		if err != nil {
+			return err
+		}
+		if len(freshness) == 0 {
+			return nil
+		}
+		_, err = tx.Model(&freshness).Insert()
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error during executing replace source freshness for backup %s statement", backupID)
+	}
+
+	return nil
+}
+
+// GetForBackupID list the freshness of the sources of a backup
+func (d *defaultSourceFreshnessRepository) GetForBackupID(ctxIn context.Context, backupID string) ([]*SourceFreshness, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultSourceFreshnessRepository).GetForBackupID")
+	defer span.End()
+
+	var freshness []*SourceFreshness
+	err := d.storageService.DB().Model(&freshness).
+		Where("backup_id = ?", backupID).
+		Order("source ASC").
+		Select()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error during executing get source freshness for backup %s statement", backupID)
+	}
+
+	return freshness, nil
+}
+
+// GetViolations list the sources violating the RPO of their backup, backups which lost their RPO or are not active anymore are skipped
+func (d *defaultSourceFreshnessRepository) GetViolations(ctxIn context.Context) ([]*SourceFreshness, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultSourceFreshnessRepository).GetViolations")
+	defer span.End()
+
+	var freshness []*SourceFreshness
+	err := d.storageService.DB().Model(&freshness).
+		Join("JOIN backups AS b ON b.id = sf.backup_id").
+		Where("sf.rpo_violated").
+		Where("b.recovery_point_objective > 0").
+		Where("b.status IN (?)", pg.In([]BackupStatus{NotStarted, Prepared, Finished})).
+		Where("b.audit_deleted_timestamp IS NULL").
+		Order("sf.backup_id ASC", "sf.source ASC").
+		Select()
+	if err != nil {
+		return nil, errors.Wrap(err, "error during executing get source freshness violations statement")
+	}
+
+	return freshness, nil
+}
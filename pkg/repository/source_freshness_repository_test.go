@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultSourceFreshnessRepository_ReplaceForBackupID(t *testing.T) {
+	const backupID = "source-freshness-backup-id-1"
+	ctx, storageService := prepareTest(t)
+	setBackupWithIDs(t, storageService, backupID)
+	repository := defaultSourceFreshnessRepository{storageService: storageService}
+
+	checked := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	err := repository.ReplaceForBackupID(ctx, backupID, []*SourceFreshness{
+		{BackupID: backupID, Source: "table_b", RecoveryPointObjective: 24, RPOViolated: true, CheckedTimestamp: checked},
+		{BackupID: backupID, Source: "table_a", LastRecoveryPoint: checked.Add(-time.Hour), RecoveryPointObjective: 24, CheckedTimestamp: checked},
+	})
+	require.NoError(t, err)
+
+	freshness, err := repository.GetForBackupID(ctx, backupID)
+	require.NoError(t, err)
+	require.Len(t, freshness, 2)
+	assert.Equal(t, "table_a", freshness[0].Source)
+	assert.Equal(t, checked.Add(-time.Hour), freshness[0].LastRecoveryPoint.UTC())
+	assert.True(t, freshness[1].RPOViolated)
+	assert.True(t, freshness[1].LastRecoveryPoint.IsZero())
+
+	err = repository.ReplaceForBackupID(ctx, backupID, []*SourceFreshness{
+		{BackupID: backupID, Source: "table_a", RecoveryPointObjective: 24, CheckedTimestamp: checked.Add(time.Hour)},
+	})
+	require.NoError(t, err)
+
+	freshness, err = repository.GetForBackupID(ctx, backupID)
+	require.NoError(t, err)
+	require.Len(t, freshness, 1)
+	assert.Equal(t, "table_a", freshness[0].Source)
+}
+
+func TestDefaultSourceFreshnessRepository_GetViolations(t *testing.T) {
+	ctx, storageService := prepareTest(t)
+	err := setBackups(storageService, []*Backup{
+		{ID: "source-freshness-active", Status: Finished, RecoveryPointObjective: 24},
+		{ID: "source-freshness-paused", Status: Paused, RecoveryPointObjective: 24},
+	})
+	require.NoError(t, err)
+	repository := defaultSourceFreshnessRepository{storageService: storageService}
+
+	checked := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, backupID := range []string{"source-freshness-active", "source-freshness-paused"} {
+		err = repository.ReplaceForBackupID(ctx, backupID, []*SourceFreshness{
+			{BackupID: backupID, Source: "bucket", RecoveryPointObjective: 24, RPOViolated: true, CheckedTimestamp: checked},
+			{BackupID: backupID, Source: "other-bucket", RecoveryPointObjective: 24, CheckedTimestamp: checked},
+		})
+		require.NoError(t, err)
+	}
+
+	violations, err := repository.GetViolations(ctx)
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, "source-freshness-active", violations[0].BackupID)
+	assert.Equal(t, "bucket", violations[0].Source)
+}
@@ -134,6 +134,27 @@ func clearDatabase(client *service.Service) error {
 	if _, err := client.DB().Model(new(SourceMetadata)).Where("true").Delete(); err != nil {
 		return err
 	}
+	if _, err := client.DB().Model(new(TaskRun)).Where("true").Delete(); err != nil {
+		return err
+	}
+	if _, err := client.DB().Model(new(TaskLock)).Where("true").Delete(); err != nil {
+		return err
+	}
+	if _, err := client.DB().Model(new(SourceFreshness)).Where("true").Delete(); err != nil {
+		return err
+	}
+	if _, err := client.DB().Model(new(Notification)).Where("true").Delete(); err != nil {
+		return err
+	}
+	if _, err := client.DB().Model(new(SinkComplianceCheck)).Where("true").Delete(); err != nil {
+		return err
+	}
+	if _, err := client.DB().Model(new(RestoreDrill)).Where("true").Delete(); err != nil {
+		return err
+	}
+	if _, err := client.DB().Model(new(RestoreJob)).Where("true").Delete(); err != nil {
+		return err
+	}
 	if _, err := client.DB().Model(new(Job)).Where("true").Delete(); err != nil {
 		return err
 	}
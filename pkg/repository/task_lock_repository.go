@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// TaskLockRepository defines operation with the leases of tasks, a lease is held by one holder until it is released
+// or its heartbeat is older than staleAfter
+type TaskLockRepository interface {
+	TryAcquireTaskLock(ctxIn context.Context, task, holder string, staleAfter time.Duration) (bool, string, error)
+	HeartbeatTaskLock(ctxIn context.Context, task, holder string) (bool, error)
+	ReleaseTaskLock(ctxIn context.Context, task, holder string) error
+}
+
+// defaultTaskLockRepository implements TaskLockRepository
+type defaultTaskLockRepository struct {
+	storageService *service.Service
+}
+
+// NewTaskLockRepository return instance of TaskLockRepository
+func NewTaskLockRepository(ctxIn context.Context, credentialsProvider secret.SecretProvider) (TaskLockRepository, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewTaskLockRepository")
+	defer span.End()
+
+	storageService, err := service.NewStorageService(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultTaskLockRepository{storageService: storageService}, nil
+}
+
+// TryAcquireTaskLock acquires the lease of a task without waiting, a stale lease is taken over and its holder is returned
+func (d *defaultTaskLockRepository) TryAcquireTaskLock(ctxIn context.Context, task, holder string, staleAfter time.Duration) (bool, string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultTaskLockRepository).TryAcquireTaskLock")
+	defer span.End()
+
+	// the timestamps are taken from the database, so the clocks of the replicas do not matter
+	var staleHolder string
+	_, err := d.storageService.DB().QueryOneContext(ctx, pg.Scan(&staleHolder), `
+		WITH previous AS (SELECT holder FROM task_locks WHERE task = ?0)
+		INSERT INTO task_locks (task, holder, acquired_timestamp, heartbeat_timestamp)
+		VALUES (?0, ?1, now(), now())
+		ON CONFLICT (task) DO UPDATE
+		SET holder = EXCLUDED.holder, acquired_timestamp = EXCLUDED.acquired_timestamp, heartbeat_timestamp = EXCLUDED.heartbeat_timestamp
+		WHERE task_locks.heartbeat_timestamp < now() - make_interval(secs => ?2)
+		RETURNING coalesce((SELECT holder FROM previous), '')`,
+		task, holder, staleAfter.Seconds())
+	if errors.Is(err, pg.ErrNoRows) {
+		return false, "", nil
+	}
+	if err != nil {
+		return false, "", errors.Wrapf(err, "error during executing acquire task lock %s statement", task)
+	}
+
+	return true, staleHolder, nil
+}
+
+// HeartbeatTaskLock extends the lease of a task, false is returned if the holder lost the lease
+func (d *defaultTaskLockRepository) HeartbeatTaskLock(ctxIn context.Context, task, holder string) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultTaskLockRepository).HeartbeatTaskLock")
+	defer span.End()
+
+	result, err := d.storageService.DB().ExecContext(ctx, "UPDATE task_locks SET heartbeat_timestamp = now() WHERE task = ? AND holder = ?", task, holder)
+	if err != nil {
+		return false, errors.Wrapf(err, "error during executing heartbeat task lock %s statement", task)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// ReleaseTaskLock releases the lease of a task, it is a no-op if the holder lost the lease
+func (d *defaultTaskLockRepository) ReleaseTaskLock(ctxIn context.Context, task, holder string) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultTaskLockRepository).ReleaseTaskLock")
+	defer span.End()
+
+	_, err := d.storageService.DB().ExecContext(ctx, "DELETE FROM task_locks WHERE task = ? AND holder = ?", task, holder)
+	if err != nil {
+		return errors.Wrapf(err, "error during executing release task lock %s statement", task)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTaskLockRepository_TryAcquireTaskLock(t *testing.T) {
+	ctx, repository := prepareTestForDefaultTaskLockRepository(t)
+
+	acquired, staleHolder, err := repository.TryAcquireTaskLock(ctx, "run_new_jobs", "replica-1", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Empty(t, staleHolder)
+
+	acquired, _, err = repository.TryAcquireTaskLock(ctx, "run_new_jobs", "replica-2", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, acquired, "lock is held by replica-1")
+
+	acquired, _, err = repository.TryAcquireTaskLock(ctx, "cleanup_expired_sinks", "replica-2", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired, "locks of other tasks are independent")
+
+	require.NoError(t, repository.ReleaseTaskLock(ctx, "run_new_jobs", "replica-2"), "releasing a foreign lock is a no-op")
+	acquired, _, err = repository.TryAcquireTaskLock(ctx, "run_new_jobs", "replica-2", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	require.NoError(t, repository.ReleaseTaskLock(ctx, "run_new_jobs", "replica-1"))
+	acquired, staleHolder, err = repository.TryAcquireTaskLock(ctx, "run_new_jobs", "replica-2", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Empty(t, staleHolder)
+}
+
+func TestDefaultTaskLockRepository_TakesOverStaleTaskLock(t *testing.T) {
+	ctx, repository := prepareTestForDefaultTaskLockRepository(t)
+
+	acquired, _, err := repository.TryAcquireTaskLock(ctx, "check_jobs_status", "replica-1", time.Hour)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	_, err = repository.storageService.DB().Model(&TaskLock{}).
+		Set("heartbeat_timestamp = now() - interval '2 hours'").
+		Where("task = ?", "check_jobs_status").
+		Update()
+	require.NoError(t, err)
+
+	acquired, staleHolder, err := repository.TryAcquireTaskLock(ctx, "check_jobs_status", "replica-2", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, "replica-1", staleHolder)
+
+	alive, err := repository.HeartbeatTaskLock(ctx, "check_jobs_status", "replica-1")
+	require.NoError(t, err)
+	assert.False(t, alive, "replica-1 lost the lock")
+
+	alive, err = repository.HeartbeatTaskLock(ctx, "check_jobs_status", "replica-2")
+	require.NoError(t, err)
+	assert.True(t, alive)
+}
+
+func prepareTestForDefaultTaskLockRepository(t *testing.T) (context.Context, defaultTaskLockRepository) {
+	ctx, storageService := prepareTest(t)
+	return ctx, defaultTaskLockRepository{storageService: storageService}
+}
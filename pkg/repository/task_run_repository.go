@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// TaskRunPatch record the outcome of a task run
+type TaskRunPatch struct {
+	ID             string
+	Status         TaskRunStatus
+	EndTime        time.Time
+	ProcessedCount int
+	FailedCount    int
+	Errors         []string
+}
+
+// TaskRunRepository defines operation with task runs
+type TaskRunRepository interface {
+	AddTaskRun(ctxIn context.Context, run *TaskRun) error
+	PatchTaskRun(ctxIn context.Context, patch TaskRunPatch) error
+	GetTaskRun(ctxIn context.Context, id string) (*TaskRun, error)
+	ListTaskRuns(ctxIn context.Context, task string, page Page) ([]*TaskRun, error)
+}
+
+// defaultTaskRunRepository implements TaskRunRepository
+type defaultTaskRunRepository struct {
+	storageService *service.Service
+}
+
+// NewTaskRunRepository return instance of TaskRunRepository
+func NewTaskRunRepository(ctxIn context.Context, credentialsProvider secret.SecretProvider) (TaskRunRepository, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewTaskRunRepository")
+	defer span.End()
+
+	storageService, err := service.NewStorageService(ctx, credentialsProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &defaultTaskRunRepository{storageService: storageService}, nil
+}
+
+// AddTaskRun add new task run
+func (d *defaultTaskRunRepository) AddTaskRun(ctxIn context.Context, run *TaskRun) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultTaskRunRepository).AddTaskRun")
+	defer span.End()
+
+	_, err := d.storageService.DB().Model(run).Insert()
+	if err != nil {
+		return errors.Wrap(err, "error during executing add task run statement")
+	}
+
+	return nil
+}
+
+// PatchTaskRun change task run status and its counts
+func (d *defaultTaskRunRepository) PatchTaskRun(ctxIn context.Context, patch TaskRunPatch) error {
+	_, span := trace.StartSpan(ctxIn, "(*defaultTaskRunRepository).PatchTaskRun")
+	defer span.End()
+
+	run := &TaskRun{
+		Status:         patch.Status,
+		EndTime:        patch.EndTime,
+		ProcessedCount: patch.ProcessedCount,
+		FailedCount:    patch.FailedCount,
+		Errors:         patch.Errors,
+		EntityAudit: EntityAudit{
+			UpdatedTimestamp: time.Now(),
+		},
+	}
+
+	_, err := d.storageService.DB().Model(run).
+		Column("status", "end_timestamp", "processed_count", "failed_count", "errors", "audit_updated_timestamp").
+		Where("audit_deleted_timestamp IS NULL").
+		Where("id = ?", patch.ID).
+		Update()
+	if err != nil {
+		return fmt.Errorf("error during executing updating task run statement: %s", err)
+	}
+
+	return nil
+}
+
+// GetTaskRun get a task run, pg.ErrNoRows is returned if it does not exist
+func (d *defaultTaskRunRepository) GetTaskRun(ctxIn context.Context, id string) (*TaskRun, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultTaskRunRepository).GetTaskRun")
+	defer span.End()
+
+	run := &TaskRun{}
+	err := d.storageService.DB().Model(run).
+		Where("id = ?", id).
+		Where("audit_deleted_timestamp IS NULL").
+		Select()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error during executing get task run %s statement", id)
+	}
+
+	return run, nil
+}
+
+// ListTaskRuns list the runs of a task or of all tasks if task is empty, newest first
+func (d *defaultTaskRunRepository) ListTaskRuns(ctxIn context.Context, task string, page Page) ([]*TaskRun, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultTaskRunRepository).ListTaskRuns")
+	defer span.End()
+
+	var runs []*TaskRun
+	query := d.storageService.DB().Model(&runs).
+		Where("audit_deleted_timestamp IS NULL").
+		Order("start_timestamp DESC")
+	if task != "" {
+		query = query.Where("task = ?", task)
+	}
+	if page.Size != AllJobs {
+		query = query.Offset(page.Number * page.Size).Limit(page.Size)
+	}
+	err := query.Select()
+	if err != nil {
+		return nil, errors.Wrap(err, "error during executing list task runs statement")
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTaskRunRepository_AddTaskRun_ListTaskRuns(t *testing.T) {
+	ctx, repository := prepareTestForDefaultTaskRunRepository(t)
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	err := repository.AddTaskRun(ctx, &TaskRun{ID: "task-run-1", Task: "run_new_jobs", Status: TaskRunSucceeded, StartTime: start})
+	require.NoError(t, err)
+	err = repository.AddTaskRun(ctx, &TaskRun{ID: "task-run-2", Task: "cleanup_expired_sinks", Status: TaskRunRunning, StartTime: start.Add(time.Minute)})
+	require.NoError(t, err)
+	err = repository.AddTaskRun(ctx, &TaskRun{ID: "task-run-3", Task: "run_new_jobs", Status: TaskRunRunning, StartTime: start.Add(2 * time.Minute)})
+	require.NoError(t, err)
+
+	runs, err := repository.ListTaskRuns(ctx, "", Page{Size: 2})
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "task-run-3", runs[0].ID)
+	assert.Equal(t, "task-run-2", runs[1].ID)
+
+	runs, err = repository.ListTaskRuns(ctx, "run_new_jobs", Page{Size: AllJobs})
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "task-run-1", runs[1].ID)
+}
+
+func TestDefaultTaskRunRepository_PatchTaskRun(t *testing.T) {
+	ctx, repository := prepareTestForDefaultTaskRunRepository(t)
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	err := repository.AddTaskRun(ctx, &TaskRun{ID: "task-run-4", Task: "cleanup_expired_sinks", Status: TaskRunRunning, StartTime: start})
+	require.NoError(t, err)
+
+	err = repository.PatchTaskRun(ctx, TaskRunPatch{
+		ID:             "task-run-4",
+		Status:         TaskRunFailed,
+		EndTime:        start.Add(time.Minute),
+		ProcessedCount: 3,
+		FailedCount:    1,
+		Errors:         []string{"could not delete bucket"},
+	})
+	require.NoError(t, err)
+
+	run, err := repository.GetTaskRun(ctx, "task-run-4")
+	require.NoError(t, err)
+	assert.Equal(t, TaskRunFailed, run.Status)
+	assert.Equal(t, start.Add(time.Minute), run.EndTime.UTC())
+	assert.Equal(t, 3, run.ProcessedCount)
+	assert.Equal(t, 1, run.FailedCount)
+	assert.Equal(t, []string{"could not delete bucket"}, run.Errors)
+
+	_, err = repository.GetTaskRun(ctx, "unknown-task-run")
+	assert.ErrorIs(t, err, pg.ErrNoRows)
+}
+
+func prepareTestForDefaultTaskRunRepository(t *testing.T) (context.Context, defaultTaskRunRepository) {
+	ctx, storageService := prepareTest(t)
+	return ctx, defaultTaskRunRepository{storageService: storageService}
+}
@@ -20,6 +20,23 @@ func (AvailabilityClass) ValidValues() []AvailabilityClass {
 	return []AvailabilityClass{A1Irrelevant, A2Aimed, A3Guaranteed, A4Resilient}
 }
 
+// RestoreWriteMode defines how a restore treats data which already exists in the target
+type RestoreWriteMode string
+
+const (
+	// RestoreWriteEmpty fails when the target already contains conflicting tables or objects
+	RestoreWriteEmpty RestoreWriteMode = "WRITE_EMPTY"
+	// RestoreWriteAppend adds restored data to existing tables and keeps existing objects
+	RestoreWriteAppend RestoreWriteMode = "WRITE_APPEND"
+	// RestoreWriteTruncate replaces existing tables and objects with the restored data, objects of the target bucket
+	// which are not in the backup are deleted as well, limited to the include prefixes
+	RestoreWriteTruncate RestoreWriteMode = "WRITE_TRUNCATE"
+)
+
+func (RestoreWriteMode) ValidValues() []RestoreWriteMode {
+	return []RestoreWriteMode{RestoreWriteEmpty, RestoreWriteAppend, RestoreWriteTruncate}
+}
+
 // Page is used for a subset selection
 type Page struct {
 	Size   int
@@ -46,10 +63,88 @@ type DeleteRequest struct {
 }
 
 // RestoreRequest get instruction for a backup restoration
-// only BigQuery is supported
+// Timestamp selects a point in time of a mirror backup
 type RestoreRequest struct {
 	BackupID          string
 	JobIDForTimestamp string
+	Timestamp         time.Time
+	TargetProject     string
+	TargetDataset     string
+	TargetBucket      string
+	TablePrefix       string
+	TableSuffix       string
+	WriteMode         RestoreWriteMode
+}
+
+// RestoreExecutionRequest start restoring a backup into a target
+// dataset and table options apply to BigQuery, bucket, trashcan and prefix options to Cloud Storage
+// WriteMode defaults to RestoreWriteEmpty
+type RestoreExecutionRequest struct {
+	BackupID          string           `json:"backup_id"`
+	JobIDForTimestamp string           `json:"job_id_for_timestamp,omitempty"`
+	Timestamp         time.Time        `json:"timestamp"`
+	TargetProject     string           `json:"target_project,omitempty"`
+	TargetDataset     string           `json:"target_dataset,omitempty"`
+	TargetTable       string           `json:"target_table,omitempty"`
+	Tables            []string         `json:"tables,omitempty"`
+	TablePrefix       string           `json:"table_prefix,omitempty"`
+	TableSuffix       string           `json:"table_suffix,omitempty"`
+	WriteMode         RestoreWriteMode `json:"write_mode,omitempty"`
+	TargetBucket      string           `json:"target_bucket,omitempty"`
+	IncludeTrashcan   bool             `json:"include_trashcan,omitempty"`
+	IncludePrefixes   []string         `json:"include_prefixes,omitempty"`
+	ExcludePrefixes   []string         `json:"exclude_prefixes,omitempty"`
+}
+
+// RestoreStatusRequest get progress of restore jobs for a backup
+type RestoreStatusRequest struct {
+	BackupID  string
+	RestoreID string
+}
+
+// RunRequest prepare jobs of a backup right away, tables or prefixes narrow the run to a part of a BigQuery or Cloud Storage backup
+type RunRequest struct {
+	BackupID string   `json:"-"`
+	Tables   []string `json:"tables,omitempty"`
+	Prefixes []string `json:"prefixes,omitempty"`
+}
+
+// RunResponse response for a RunRequest request
+type RunResponse struct {
+	BackupID string   `json:"backup_id"`
+	JobIDs   []string `json:"job_ids"`
+}
+
+// RetentionLockRequest lock the retention policy of an immutable backup, Confirm must repeat the backup id because
+// a locked retention policy can not be removed or shortened anymore
+type RetentionLockRequest struct {
+	BackupID string `json:"-"`
+	Confirm  string `json:"confirm"`
+}
+
+// RetentionLockResponse response for a RetentionLockRequest request
+type RetentionLockResponse struct {
+	BackupID        string `json:"backup_id"`
+	Sink            string `json:"sink"`
+	RetentionInDays uint   `json:"retention_in_days"`
+	RetentionLocked bool   `json:"retention_locked"`
+}
+
+// JobEventsRequest get the event history of a backup job
+type JobEventsRequest struct {
+	BackupID string
+	JobID    string
+}
+
+// TaskRunListingRequest list the runs of background tasks, newest first, Task filters the runs of one task
+type TaskRunListingRequest struct {
+	Task string
+	Page Page
+}
+
+// TaskRunGettingRequest get one run of a background task
+type TaskRunGettingRequest struct {
+	ID string
 }
 
 // UpdateRequest change backup
@@ -62,12 +157,19 @@ type UpdateRequest struct {
 	ArchiveTTM             uint   `json:"archive_ttm"`
 	RecoveryPointObjective int    `json:"recovery_point_objective,omitempty"`
 	RecoveryTimeObjective  int    `json:"recovery_time_objective,omitempty"`
+	MaxRetryAttempts       int    `json:"max_retry_attempts,omitempty"`
 	// only for GCS backups
 	IncludePath []string `json:"include_path,omitempty"`
 	ExcludePath []string `json:"exclude_path,omitempty"`
 	// only for BigQuery backups
 	Table          []string `json:"table,omitempty"`
 	ExcludedTables []string `json:"excluded_tables,omitempty"`
+	// only for Firestore backups
+	CollectionIDs []string `json:"collection_ids,omitempty"`
+	// only for CloudSQL backups
+	Databases []string `json:"databases,omitempty"`
+	// replaces the schedule if set, an empty schedule removes cron and windows
+	Schedule *ScheduleOptions `json:"schedule,omitempty"`
 }
 
 // CreateRequest make a new backup
@@ -78,19 +180,26 @@ type CreateRequest struct {
 	Project                string        `json:"project,omitempty"`
 	RecoveryPointObjective int           `json:"recovery_point_objective"`
 	RecoveryTimeObjective  int           `json:"recovery_time_objective"`
+	MaxRetryAttempts       int           `json:"max_retry_attempts,omitempty"`
 	TargetOptions          TargetOptions `json:"target,omitempty"`
 
-	SnapshotOptions SnapshotOptions `json:"snapshot_options,omitempty"`
-	MirrorOptions   MirrorOptions   `json:"mirror_options,omitempty"`
-	BigQueryOptions BigQueryOptions `json:"bigquery_options,omitempty"`
-	GCSOptions      GCSOptions      `json:"gcs_options,omitempty"`
+	SnapshotOptions  SnapshotOptions  `json:"snapshot_options,omitempty"`
+	ScheduleOptions  *ScheduleOptions `json:"schedule,omitempty"`
+	MirrorOptions    MirrorOptions    `json:"mirror_options,omitempty"`
+	BigQueryOptions  BigQueryOptions  `json:"bigquery_options,omitempty"`
+	GCSOptions       GCSOptions       `json:"gcs_options,omitempty"`
+	FirestoreOptions FirestoreOptions `json:"firestore_options,omitempty"`
+	CloudSQLOptions  CloudSQLOptions  `json:"cloudsql_options,omitempty"`
 }
 
 // BigQueryOptions specify backup for a source BigQuery datast or table(s)
+// ExportFormat is AVRO, PARQUET or NEWLINE_DELIMITED_JSON and defaults to uncompressed AVRO
 type BigQueryOptions struct {
-	Dataset        string   `json:"dataset,omitempty"`
-	Table          []string `json:"table,omitempty"`
-	ExcludedTables []string `json:"excluded_tables,omitempty"`
+	Dataset           string   `json:"dataset,omitempty"`
+	Table             []string `json:"table,omitempty"`
+	ExcludedTables    []string `json:"excluded_tables,omitempty"`
+	ExportFormat      string   `json:"export_format,omitempty"`
+	ExportCompression string   `json:"export_compression,omitempty"`
 }
 
 // GCSOptions specify backup for a source bucket
@@ -100,6 +209,21 @@ type GCSOptions struct {
 	ExcludePath []string `json:"exclude_prefixes,omitempty"`
 }
 
+// FirestoreOptions specify backup for a source Firestore database, all collections are exported if CollectionIDs is empty
+type FirestoreOptions struct {
+	Database      string   `json:"database,omitempty"`
+	CollectionIDs []string `json:"collection_ids,omitempty"`
+}
+
+// CloudSQLOptions specify backup for a source Cloud SQL instance, all databases are exported if Databases is empty
+// FileType is SQL or CSV, a CSV export writes the result of CSVQuery per database
+type CloudSQLOptions struct {
+	Instance  string   `json:"instance,omitempty"`
+	Databases []string `json:"databases,omitempty"`
+	FileType  string   `json:"file_type,omitempty"`
+	CSVQuery  string   `json:"csv_query,omitempty"`
+}
+
 // SnapshotOptions specify backup snapshot options
 type SnapshotOptions struct {
 	LifetimeInDays   uint   `json:"lifetime_in_days,omitempty"`
@@ -107,18 +231,32 @@ type SnapshotOptions struct {
 	LastScheduled    string `json:"last_scheduled,omitempty"`
 }
 
+// ScheduleOptions specify when a backup runs
+// Cron is a 5-field cron expression evaluated in Timezone (defaults to UTC) and replaces FrequencyInHours of snapshots
+// Windows like "01:00-05:00" restrict preparing and scheduling of jobs to these times of day in Timezone
+type ScheduleOptions struct {
+	Cron     string   `json:"cron,omitempty"`
+	Timezone string   `json:"timezone,omitempty"`
+	Windows  []string `json:"windows,omitempty"`
+}
+
 // MirrorOptions specify backup mirror options
 type MirrorOptions struct {
 	LifetimeInDays uint `json:"lifetime_in_days,omitempty"`
 }
 
 // TargetOptions specify backup sink options
+// Immutable sets a retention policy of the backup lifetime on the sink bucket, RetentionLocked is only set in responses
+// because the policy is locked by a separate RetentionLockRequest
 type TargetOptions struct {
-	Region         string `json:"region,omitempty"`
-	DualRegion     string `json:"dual_region,omitempty"`
-	StorageClass   string `json:"storage_class,omitempty"`
-	LifecycleCount uint   `json:"lifecycle_count,omitempty"`
-	ArchiveTTM     uint   `json:"archive_ttm"`
+	Region          string `json:"region,omitempty"`
+	DualRegion      string `json:"dual_region,omitempty"`
+	StorageClass    string `json:"storage_class,omitempty"`
+	LifecycleCount  uint   `json:"lifecycle_count,omitempty"`
+	ArchiveTTM      uint   `json:"archive_ttm"`
+	KMSKeyName      string `json:"kms_key_name,omitempty"`
+	Immutable       bool   `json:"immutable,omitempty"`
+	RetentionLocked bool   `json:"retention_locked,omitempty"`
 }
 
 // ListingResponse response for a ListRequest
@@ -149,6 +287,79 @@ type BackupResponse struct {
 	TrashcanCleanupStatus            string `json:"trashcan_cleanup_status,omitempty"`
 	TrashcanCleanupErrorMessage      string `json:"trashcan_cleanup_error_message,omitempty"`
 	TrashcanCleanupLastScheduledTime string `json:"trashcan_cleanup_last_scheduled_time,omitempty"`
+
+	RestoreDrills       []RestoreDrillResponse `json:"restore_drills,omitempty"`
+	RestoreDrillFlagged bool                   `json:"restore_drill_flagged,omitempty"`
+
+	RPOViolated     bool                      `json:"rpo_violated,omitempty"`
+	SourceFreshness []SourceFreshnessResponse `json:"source_freshness,omitempty"`
+
+	PolicyWarnings []PolicyViolationResponse `json:"policy_warnings,omitempty"`
+}
+
+// PolicyViolationResponse a policy rule violated by a backup
+type PolicyViolationResponse struct {
+	Rule        string `json:"rule"`
+	Description string `json:"description,omitempty"`
+	Severity    string `json:"severity"`
+}
+
+// SourceFreshnessResponse get the newest recovery point of a backup source, it is empty if the source never finished
+type SourceFreshnessResponse struct {
+	Source            string `json:"source"`
+	LastRecoveryPoint string `json:"last_recovery_point,omitempty"`
+	RPOViolated       bool   `json:"rpo_violated"`
+	CheckedTimestamp  string `json:"checked"`
+}
+
+// RPOViolationsResponse lists the backups whose sources violate the recovery point objective
+type RPOViolationsResponse struct {
+	Violations []RPOViolationResponse `json:"violations"`
+}
+
+// RPOViolationResponse get the sources of a backup violating its recovery point objective
+type RPOViolationResponse struct {
+	BackupID               string                    `json:"backup_id"`
+	Description            string                    `json:"description"`
+	Project                string                    `json:"project"`
+	Type                   string                    `json:"type"`
+	RecoveryPointObjective int                       `json:"recovery_point_objective"`
+	Sources                []SourceFreshnessResponse `json:"sources"`
+}
+
+// AvailabilityClassViolationsResponse lists the backups not satisfying the availability class of their project
+type AvailabilityClassViolationsResponse struct {
+	Violations []AvailabilityClassViolationResponse `json:"violations"`
+}
+
+// AvailabilityClassViolationResponse get the requirements of the availability class a backup does not satisfy
+type AvailabilityClassViolationResponse struct {
+	BackupID          string                     `json:"backup_id"`
+	Description       string                     `json:"description"`
+	Project           string                     `json:"project"`
+	Type              string                     `json:"type"`
+	Status            string                     `json:"status"`
+	AvailabilityClass provider.AvailabilityClass `json:"availability_class"`
+	Requirements      []PolicyViolationResponse  `json:"requirements"`
+}
+
+// RestoreDrillResponse get restore drill details, counts are omitted if unknown
+type RestoreDrillResponse struct {
+	ID                    string `json:"id"`
+	RestoreJobID          string `json:"restore_job_id"`
+	Status                string `json:"status"`
+	AvailabilityClass     string `json:"availability_class"`
+	Source                string `json:"source"`
+	Target                string `json:"target"`
+	ExpectedCount         *int64 `json:"expected_count,omitempty"`
+	RestoredCount         *int64 `json:"restored_count,omitempty"`
+	ElapsedSeconds        int    `json:"elapsed_seconds"`
+	RecoveryTimeObjective int    `json:"recovery_time_objective"`
+	RTOBreached           bool   `json:"rto_breached"`
+	ErrorMessage          string `json:"error_message,omitempty"`
+
+	CreatedTimestamp string `json:"created,omitempty"`
+	UpdatedTimestamp string `json:"updated,omitempty"`
 }
 
 // JobResponse get backup job details
@@ -160,11 +371,70 @@ type JobResponse struct {
 	Status string `json:"status"`
 	Source string `json:"source"`
 
+	Attempts         int                `json:"attempts,omitempty"`
+	NextAttempt      string             `json:"next_attempt,omitempty"`
+	LastErrorMessage string             `json:"last_error_message,omitempty"`
+	LastErrorCode    string             `json:"last_error_code,omitempty"`
+	LastErrorReason  string             `json:"last_error_reason,omitempty"`
+	LastErrorTime    string             `json:"last_error_timestamp,omitempty"`
+	ErrorHistory     []JobErrorResponse `json:"error_history,omitempty"`
+
 	CreatedTimestamp string `json:"created,omitempty"`
 	UpdatedTimestamp string `json:"updated,omitempty"`
 	DeletedTimestamp string `json:"deleted,omitempty"`
 }
 
+// JobErrorResponse get the error of a failed job attempt
+type JobErrorResponse struct {
+	Attempt   int    `json:"attempt"`
+	Message   string `json:"message"`
+	Code      string `json:"code,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Retryable bool   `json:"retryable"`
+	Timestamp string `json:"timestamp"`
+}
+
+// JobEventsResponse response for a JobEventsRequest request
+type JobEventsResponse struct {
+	BackupID string             `json:"backup_id"`
+	JobID    string             `json:"job_id"`
+	Events   []JobEventResponse `json:"events"`
+}
+
+// JobEventResponse get a status change or failure of a job
+type JobEventResponse struct {
+	Status      string `json:"status"`
+	Attempt     int    `json:"attempt"`
+	Message     string `json:"message,omitempty"`
+	ErrorCode   string `json:"error_code,omitempty"`
+	ErrorReason string `json:"error_reason,omitempty"`
+	Timestamp   string `json:"timestamp"`
+}
+
+// TaskTriggerResponse tells whether a triggered task was accepted or skipped because it is still running
+type TaskTriggerResponse struct {
+	Task   string `json:"task"`
+	Status string `json:"status"`
+}
+
+// TaskRunListingResponse response for a TaskRunListingRequest request
+type TaskRunListingResponse struct {
+	TaskRuns []TaskRunResponse `json:"task_runs"`
+}
+
+// TaskRunResponse get the outcome of a run of a background task
+type TaskRunResponse struct {
+	ID              string   `json:"id"`
+	Task            string   `json:"task"`
+	Status          string   `json:"status"`
+	StartTimestamp  string   `json:"start_timestamp"`
+	EndTimestamp    string   `json:"end_timestamp,omitempty"`
+	DurationSeconds int      `json:"duration_seconds,omitempty"`
+	ProcessedCount  int      `json:"processed_count"`
+	FailedCount     int      `json:"failed_count"`
+	Errors          []string `json:"errors"`
+}
+
 // UpdateResponse response for a UpdateRequest
 type UpdateResponse struct {
 	UpdateRequest
@@ -172,6 +442,8 @@ type UpdateResponse struct {
 	CreatedTimestamp string `json:"created,omitempty"`
 	UpdatedTimestamp string `json:"updated,omitempty"`
 	DeletedTimestamp string `json:"deleted,omitempty"`
+
+	PolicyWarnings []PolicyViolationResponse `json:"policy_warnings,omitempty"`
 }
 
 // DeleteResponse response for a UpdateRequest
@@ -198,6 +470,30 @@ type RestoreResponse struct {
 	RestoreActions []RestoreAction `json:"actions"`
 }
 
+// RestoreJobResponse get restore job details
+type RestoreJobResponse struct {
+	ID           string `json:"id"`
+	RestoreID    string `json:"restore_id"`
+	BackupJobID  string `json:"backup_job_id"`
+	ForeignJobID string `json:"foreign_job_id,omitempty"`
+
+	Status       string `json:"status"`
+	Source       string `json:"source"`
+	Target       string `json:"target"`
+	ErrorMessage string `json:"error_message,omitempty"`
+
+	CreatedTimestamp string `json:"created,omitempty"`
+	UpdatedTimestamp string `json:"updated,omitempty"`
+}
+
+// RestoreJobsResponse response for a RestoreExecutionRequest or RestoreStatusRequest request
+type RestoreJobsResponse struct {
+	BackupID   string               `json:"backup_id"`
+	RestoreID  string               `json:"restore_id,omitempty"`
+	Statistics map[string]uint64    `json:"statistics"`
+	Jobs       []RestoreJobResponse `json:"jobs"`
+}
+
 // CalculateRequest request cost calculation for a backup
 type CalculateRequest struct {
 	CreateRequest
@@ -279,16 +575,22 @@ type StorageClassListResponse struct {
 	StorageClasses []string `json:"storage_classes"`
 }
 
+// ProjectSinkComplianceCheck result of one compliance check of a sink project
 type ProjectSinkComplianceCheck struct {
-	Project   string    `json:"project"`
-	Compliant bool      `json:"compliant"`
-	Reasons   []string  `json:"reasons"`
-	LastCheck time.Time `json:"last_check"`
+	Project        string    `json:"project"`
+	SourceProjects []string  `json:"source_projects"`
+	Compliant      bool      `json:"compliant"`
+	Reasons        []string  `json:"reasons"`
+	LastCheck      time.Time `json:"last_check"`
 }
 
+// ProjectSinkComplianceRequest list the latest check of every sink project, or the check history of Project if set
 type ProjectSinkComplianceRequest struct {
+	Project string
+	Page    Page
 }
 
+// ProjectSinkComplianceResponse response for a ProjectSinkComplianceRequest request
 type ProjectSinkComplianceResponse struct {
 	Checks []ProjectSinkComplianceCheck `json:"checks"`
 }
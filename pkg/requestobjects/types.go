@@ -16,6 +16,8 @@ const (
 	Updating RequestType = "Updating"
 	// Restoring - preapre restore command for a backup
 	Restoring RequestType = "Restoring"
+	// RestoreExecuting - restore backup data into a target
+	RestoreExecuting RequestType = "RestoreExecuting"
 	// Calculating - calculate prize for a backup
 	Calculating RequestType = "Calculating"
 	// Compliance - calculate compliance checks for a backup
@@ -28,6 +30,10 @@ const (
 	SourceProjectGet RequestType = "SourceProjectGet"
 	// Cleanup - cleanup trash can for a backup
 	Cleanup RequestType = "Cleanup"
+	// Running - prepare jobs of a backup right away
+	Running RequestType = "Running"
+	// RetentionLocking - lock the retention policy of an immutable backup
+	RetentionLocking RequestType = "RetentionLocking"
 )
 
 func (s RequestType) String() string {
@@ -0,0 +1,128 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Window is a daily time range in which backup jobs are allowed to run, a window ending before it starts spans midnight
+type Window struct {
+	start int
+	end   int
+}
+
+// ParseWindow parses a window in the format "HH:MM-HH:MM"
+func ParseWindow(value string) (Window, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(value), "-")
+	if !ok {
+		return Window{}, fmt.Errorf("window %q must have format HH:MM-HH:MM", value)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid start of window %q: %s", value, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid end of window %q: %s", value, err)
+	}
+	if start == end {
+		return Window{}, fmt.Errorf("window %q must not be empty", value)
+	}
+	return Window{start: start, end: end}, nil
+}
+
+// Contains checks if the wall clock of t lies within the window
+func (w Window) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return w.start <= minute && minute < w.end
+	}
+	return minute >= w.start || minute < w.end
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.start/60, w.start%60, w.end/60, w.end%60)
+}
+
+func parseClock(value string) (int, error) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, fmt.Errorf("time %q must have format HH:MM", value)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil || h < 0 || h > 24 {
+		return 0, fmt.Errorf("invalid hour in %q", value)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid minute in %q", value)
+	}
+	return (h*60 + m) % (24 * 60), nil
+}
+
+// Schedule combines an optional cron expression with optional execution windows in a timezone
+type Schedule struct {
+	cron     *Cron
+	location *time.Location
+	windows  []Window
+}
+
+// New parses the schedule definition, an empty timezone defaults to UTC
+func New(cron string, timezone string, windows []string) (*Schedule, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %s", timezone, err)
+	}
+
+	s := &Schedule{location: location}
+	if cron != "" {
+		s.cron, err = ParseCron(cron)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, value := range windows {
+		window, err := ParseWindow(value)
+		if err != nil {
+			return nil, err
+		}
+		s.windows = append(s.windows, window)
+	}
+
+	return s, nil
+}
+
+// HasCron returns true if the schedule is driven by a cron expression
+func (s *Schedule) HasCron() bool {
+	return s.cron != nil
+}
+
+// Next returns the next cron activation after the given time
+func (s *Schedule) Next(after time.Time) (time.Time, bool) {
+	if s.cron == nil {
+		return time.Time{}, false
+	}
+	return s.cron.Next(after, s.location)
+}
+
+// IsDue checks if a cron activation happened between last run and now
+func (s *Schedule) IsDue(last time.Time, now time.Time) bool {
+	next, ok := s.Next(last)
+	return ok && !next.After(now)
+}
+
+// InWindow checks if now lies within one of the execution windows, a schedule without windows allows every time
+func (s *Schedule) InWindow(now time.Time) bool {
+	if len(s.windows) == 0 {
+		return true
+	}
+	local := now.In(s.location)
+	for _, window := range s.windows {
+		if window.Contains(local) {
+			return true
+		}
+	}
+	return false
+}
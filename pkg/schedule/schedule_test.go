@@ -0,0 +1,101 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		_, err := ParseCron(expression)
+		assert.Errorf(t, err, "expression %q should be invalid", expression)
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	kathmandu, err := time.LoadLocation("Asia/Kathmandu")
+	require.NoError(t, err)
+
+	tests := []struct {
+		expression string
+		location   *time.Location
+		after      time.Time
+		expected   time.Time
+	}{
+		{"30 2 * * *", time.UTC, time.Date(2024, 3, 1, 1, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 2, 30, 0, 0, time.UTC)},
+		{"30 2 * * *", time.UTC, time.Date(2024, 3, 1, 2, 30, 0, 0, time.UTC), time.Date(2024, 3, 2, 2, 30, 0, 0, time.UTC)},
+		{"@hourly", time.UTC, time.Date(2024, 3, 1, 1, 15, 0, 0, time.UTC), time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.UTC, time.Date(2024, 3, 1, 1, 16, 0, 0, time.UTC), time.Date(2024, 3, 1, 1, 30, 0, 0, time.UTC)},
+		{"0 3 * * mon-fri", time.UTC, time.Date(2024, 3, 1, 4, 0, 0, 0, time.UTC), time.Date(2024, 3, 4, 3, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan,jul *", time.UTC, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.UTC, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 1 * * 7", time.UTC, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 3, 1, 0, 0, 0, time.UTC)},
+		{"0 2 * * *", berlin, time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC), time.Date(2024, 1, 11, 1, 0, 0, 0, time.UTC)},
+		{"0 2 * * *", berlin, time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC), time.Date(2024, 7, 11, 0, 0, 0, 0, time.UTC)},
+		{"0 * * * *", kolkata, time.Date(2024, 3, 1, 1, 15, 0, 0, time.UTC), time.Date(2024, 3, 1, 1, 30, 0, 0, time.UTC)},
+		{"0 3 * * *", kathmandu, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 21, 15, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		cron, err := ParseCron(test.expression)
+		require.NoError(t, err)
+		next, ok := cron.Next(test.after, test.location)
+		assert.True(t, ok)
+		assert.Truef(t, test.expected.Equal(next), "%q after %s: expected %s but got %s", test.expression, test.after, test.expected, next)
+	}
+}
+
+func TestCron_NextNeverActivated(t *testing.T) {
+	cron, err := ParseCron("0 0 31 2 *")
+	require.NoError(t, err)
+	_, ok := cron.Next(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.UTC)
+	assert.False(t, ok)
+}
+
+func TestParseWindow(t *testing.T) {
+	window, err := ParseWindow("01:00-05:00")
+	require.NoError(t, err)
+	assert.Equal(t, "01:00-05:00", window.String())
+	assert.True(t, window.Contains(time.Date(2024, 3, 1, 1, 0, 0, 0, time.UTC)))
+	assert.True(t, window.Contains(time.Date(2024, 3, 1, 4, 59, 0, 0, time.UTC)))
+	assert.False(t, window.Contains(time.Date(2024, 3, 1, 5, 0, 0, 0, time.UTC)))
+
+	overnight, err := ParseWindow("22:00-02:00")
+	require.NoError(t, err)
+	assert.True(t, overnight.Contains(time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, overnight.Contains(time.Date(2024, 3, 1, 1, 0, 0, 0, time.UTC)))
+	assert.False(t, overnight.Contains(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)))
+
+	for _, value := range []string{"01:00", "01:00-01:00", "25:00-02:00", "01:60-02:00", "a-b"} {
+		_, err := ParseWindow(value)
+		assert.Errorf(t, err, "window %q should be invalid", value)
+	}
+}
+
+func TestSchedule_IsDueAndInWindow(t *testing.T) {
+	s, err := New("0 1 * * *", "Europe/Berlin", []string{"01:00-05:00"})
+	require.NoError(t, err)
+	assert.True(t, s.HasCron())
+
+	last := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC) // 01:00 in Berlin
+	assert.False(t, s.IsDue(last, time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, s.IsDue(last, time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)))
+
+	assert.True(t, s.InWindow(time.Date(2024, 3, 2, 3, 59, 0, 0, time.UTC)))
+	assert.False(t, s.InWindow(time.Date(2024, 3, 2, 4, 0, 0, 0, time.UTC)))
+
+	withoutWindows, err := New("", "", nil)
+	require.NoError(t, err)
+	assert.False(t, withoutWindows.HasCron())
+	assert.True(t, withoutWindows.InWindow(time.Now()))
+
+	_, err = New("0 1 * * *", "Mars/Olympus", nil)
+	assert.Error(t, err)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ottogroup/penelope/pkg/http/impersonate"
 	"github.com/ottogroup/penelope/pkg/repository"
 	"go.opencensus.io/trace"
+	"google.golang.org/api/googleapi"
 	gimpersonate "google.golang.org/api/impersonate"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
@@ -23,6 +24,7 @@ type Table struct {
 	Name             string
 	Checksum         string
 	SizeInBytes      float64
+	NumRows          int64
 	LastModifiedTime time.Time
 }
 
@@ -36,6 +38,7 @@ func newTableEntry(name string, t tablePartition) *Table {
 		Name:             name,
 		Checksum:         shortSHA,
 		SizeInBytes:      float64(t.TotalLogicalBytes),
+		NumRows:          t.TotalRows,
 		LastModifiedTime: t.LastModifiedTime,
 	}
 }
@@ -49,6 +52,7 @@ func newTableEntryFromMetadata(name string, t *bq.TableMetadata) *Table {
 		Name:             name,
 		Checksum:         shortSHA,
 		SizeInBytes:      float64(t.NumBytes),
+		NumRows:          int64(t.NumRows),
 		LastModifiedTime: t.LastModifiedTime,
 	}
 }
@@ -56,8 +60,10 @@ func newTableEntryFromMetadata(name string, t *bq.TableMetadata) *Table {
 // Client define operations for BigQuery
 type Client interface {
 	IsInitialized(ctxIn context.Context) bool
-	ExtractTableToGcsAsAvro(ctxIn context.Context, dataset, table, gcsURI string) *bq.Extractor
+	ExtractTableToGcs(ctxIn context.Context, dataset, table, gcsURI string, format repository.ExportFormat, compression repository.ExportCompression) *bq.Extractor
 	GetExtractJobStatus(ctxIn context.Context, extractJobID repository.ExtractJobID) (*bq.JobStatus, error)
+	LoadTableFromGcs(ctxIn context.Context, project, dataset, table, sourceURI string, format repository.ExportFormat, writeDisposition bq.TableWriteDisposition) *bq.Loader
+	GetLoadJobStatus(ctxIn context.Context, loadJobID repository.LoadJobID) (*bq.JobStatus, error)
 	DoesDatasetExists(ctxIn context.Context, project string, dataset string) (bool, error)
 	GetTable(ctxIn context.Context, project string, dataset string, table string) (*Table, error)
 	GetTablesInDataset(ctxIn context.Context, project string, dataset string) ([]*Table, error)
@@ -66,6 +72,17 @@ type Client interface {
 	GetDatasets(ctxIn context.Context, project string) ([]string, error)
 	DeleteExtractJob(ctxIn context.Context, extractJobID repository.ExtractJobID) error
 	GetDatasetDetails(ctxIn context.Context, project string, dataset string) (*bq.DatasetMetadata, error)
+	GetDatasetManifest(ctxIn context.Context, project string, dataset string) (*DatasetManifest, error)
+	CreateTableFromManifest(ctxIn context.Context, project, dataset, table string, manifest TableManifest) (bool, error)
+	CreateRoutineFromManifest(ctxIn context.Context, project, dataset, routine string, manifest RoutineManifest) (bool, error)
+	AddDatasetAccess(ctxIn context.Context, project, dataset string, entries []AccessEntryManifest) error
+	SnapshotTable(ctxIn context.Context, dataset, table, snapshotDataset, snapshotTable, kmsKeyName string) *bq.Copier
+	RestoreTableSnapshot(ctxIn context.Context, snapshotProject, snapshotDataset, snapshotTable, project, dataset, table string, writeDisposition bq.TableWriteDisposition) *bq.Copier
+	CreateDataset(ctxIn context.Context, project, dataset, location string, defaultTableExpiration time.Duration, labels map[string]string) (bool, error)
+	UpdateDefaultTableExpiration(ctxIn context.Context, project, dataset string, defaultTableExpiration time.Duration) error
+	DeleteDataset(ctxIn context.Context, project, dataset string) error
+	DeleteTable(ctxIn context.Context, project, dataset, table string) error
+	Close(ctxIn context.Context)
 }
 
 // defaultBigQueryClient represent BigqUEry Client implementation
@@ -137,16 +154,24 @@ func (d *defaultBigQueryClient) IsInitialized(ctxIn context.Context) bool {
 	return d.client != nil
 }
 
-// ExtractTableToGcsAsAvro will export data into GCS Bucket in AVRO format
+// Close terminates all resources in use
+func (d *defaultBigQueryClient) Close(ctxIn context.Context) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).Close")
+	defer span.End()
+
+	d.client.Close()
+}
+
+// ExtractTableToGcs will export data into GCS Bucket in the given format and compression
 // FIXME: method overlapping with ExtractJobHandler
-func (d *defaultBigQueryClient) ExtractTableToGcsAsAvro(ctxIn context.Context, dataset, table, sinkURI string) *bq.Extractor {
-	_, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).ExtractTableToGcsAsAvro")
+func (d *defaultBigQueryClient) ExtractTableToGcs(ctxIn context.Context, dataset, table, sinkURI string, format repository.ExportFormat, compression repository.ExportCompression) *bq.Extractor {
+	_, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).ExtractTableToGcs")
 	defer span.End()
 
 	gcsURI := bq.NewGCSReference(sinkURI)
-	extractor := d.client.DatasetInProject(d.sourceProjectID, dataset).Table(table).ExtractorTo(gcsURI)
-	extractor.Dst.DestinationFormat = bq.Avro
-	return extractor
+	gcsURI.DestinationFormat = bq.DataFormat(format)
+	gcsURI.Compression = bq.Compression(compression)
+	return d.client.DatasetInProject(d.sourceProjectID, dataset).Table(table).ExtractorTo(gcsURI)
 }
 
 // GetExtractJobStatus return status for extract job
@@ -173,6 +198,43 @@ func (d *defaultBigQueryClient) GetExtractJobStatus(ctxIn context.Context, extra
 	return status, nil
 }
 
+// LoadTableFromGcs will import data in the given format from GCS Bucket into a table
+// newline-delimited JSON does not carry a schema, it is detected from the data
+func (d *defaultBigQueryClient) LoadTableFromGcs(ctxIn context.Context, project, dataset, table, sourceURI string, format repository.ExportFormat, writeDisposition bq.TableWriteDisposition) *bq.Loader {
+	_, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).LoadTableFromGcs")
+	defer span.End()
+
+	gcsURI := bq.NewGCSReference(sourceURI)
+	gcsURI.SourceFormat = bq.DataFormat(format)
+	if format == repository.JSONExport {
+		gcsURI.AutoDetect = true
+	}
+	loader := d.client.DatasetInProject(project, dataset).Table(table).LoaderFrom(gcsURI)
+	loader.UseAvroLogicalTypes = format == repository.AvroExport
+	loader.CreateDisposition = bq.CreateIfNeeded
+	loader.WriteDisposition = writeDisposition
+	return loader
+}
+
+// GetLoadJobStatus return status for load job
+func (d *defaultBigQueryClient) GetLoadJobStatus(ctxIn context.Context, loadJobID repository.LoadJobID) (*bq.JobStatus, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).GetLoadJobStatus")
+	defer span.End()
+
+	var err error
+	var job *bq.Job
+	if loadJobID.HasLocation() {
+		job, err = d.client.JobFromIDLocation(ctx, loadJobID.JobID(), loadJobID.Location())
+	} else {
+		job, err = d.client.JobFromID(ctx, loadJobID.String())
+	}
+	if err != nil {
+		return &bq.JobStatus{}, err
+	}
+
+	return job.Status(ctx)
+}
+
 // DoesDatasetExists check if dataset exist
 func (d *defaultBigQueryClient) DoesDatasetExists(ctxIn context.Context, project string, dataset string) (bool, error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).DoesDatasetExists")
@@ -338,3 +400,219 @@ func (d *defaultBigQueryClient) GetDatasetDetails(ctxIn context.Context, project
 
 	return d.client.DatasetInProject(project, dataset).Metadata(ctx)
 }
+
+// GetDatasetManifest capture the metadata of the dataset with its tables, views and routines
+func (d *defaultBigQueryClient) GetDatasetManifest(ctxIn context.Context, project string, dataset string) (*DatasetManifest, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).GetDatasetManifest")
+	defer span.End()
+
+	oDataset := d.client.DatasetInProject(project, dataset)
+	datasetMetadata, err := oDataset.Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get metadata of dataset %s.%s: %s", project, dataset, err)
+	}
+	manifest := newDatasetManifest(project, dataset, datasetMetadata)
+
+	tableIt := oDataset.Tables(ctx)
+	for {
+		oTable, err := tableIt.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		tableMetadata, err := oTable.Metadata(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get metadata of table %s.%s.%s: %s", project, dataset, oTable.TableID, err)
+		}
+		tableManifest, err := newTableManifest(oTable.TableID, tableMetadata)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Tables = append(manifest.Tables, tableManifest)
+	}
+
+	routineIt := oDataset.Routines(ctx)
+	for {
+		oRoutine, err := routineIt.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		routineMetadata, err := oRoutine.Metadata(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("could not get metadata of routine %s.%s.%s: %s", project, dataset, oRoutine.RoutineID, err)
+		}
+		manifest.Routines = append(manifest.Routines, newRoutineManifest(oRoutine.RoutineID, routineMetadata))
+	}
+	return manifest, nil
+}
+
+// CreateTableFromManifest create a table, view or materialized view, false is returned if it already exists
+func (d *defaultBigQueryClient) CreateTableFromManifest(ctxIn context.Context, project, dataset, table string, manifest TableManifest) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).CreateTableFromManifest")
+	defer span.End()
+
+	metadata, err := manifest.toTableMetadata()
+	if err != nil {
+		return false, err
+	}
+	err = d.client.DatasetInProject(project, dataset).Table(table).Create(ctx, metadata)
+	if isAlreadyExists(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not create table %s.%s.%s: %s", project, dataset, table, err)
+	}
+	return true, nil
+}
+
+// CreateRoutineFromManifest create a routine, false is returned if it already exists
+func (d *defaultBigQueryClient) CreateRoutineFromManifest(ctxIn context.Context, project, dataset, routine string, manifest RoutineManifest) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).CreateRoutineFromManifest")
+	defer span.End()
+
+	err := d.client.DatasetInProject(project, dataset).Routine(routine).Create(ctx, manifest.toRoutineMetadata())
+	if isAlreadyExists(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not create routine %s.%s.%s: %s", project, dataset, routine, err)
+	}
+	return true, nil
+}
+
+// AddDatasetAccess grant the access entries missing on the dataset, existing entries are kept
+func (d *defaultBigQueryClient) AddDatasetAccess(ctxIn context.Context, project, dataset string, entries []AccessEntryManifest) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).AddDatasetAccess")
+	defer span.End()
+
+	oDataset := d.client.DatasetInProject(project, dataset)
+	metadata, err := oDataset.Metadata(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get metadata of dataset %s.%s: %s", project, dataset, err)
+	}
+
+	access := metadata.Access
+	for _, entry := range entries {
+		granted := false
+		for _, existing := range metadata.Access {
+			if newAccessEntryManifest(existing).equal(entry) {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			access = append(access, entry.toAccessEntry(d.client))
+		}
+	}
+	if len(access) == len(metadata.Access) {
+		return nil
+	}
+
+	_, err = oDataset.Update(ctx, bq.DatasetMetadataToUpdate{Access: access}, metadata.ETag)
+	if err != nil {
+		return fmt.Errorf("could not update access of dataset %s.%s: %s", project, dataset, err)
+	}
+	return nil
+}
+
+// SnapshotTable will create a read-only snapshot of a source table in a dataset of the target project
+// a snapshot only stores the bytes which differ from its base table, it is encrypted with kmsKeyName if set
+func (d *defaultBigQueryClient) SnapshotTable(ctxIn context.Context, dataset, table, snapshotDataset, snapshotTable, kmsKeyName string) *bq.Copier {
+	_, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).SnapshotTable")
+	defer span.End()
+
+	source := d.client.DatasetInProject(d.sourceProjectID, dataset).Table(table)
+	copier := d.client.DatasetInProject(d.targetProjectID, snapshotDataset).Table(snapshotTable).CopierFrom(source)
+	copier.OperationType = bq.SnapshotOperation
+	copier.CreateDisposition = bq.CreateIfNeeded
+	copier.WriteDisposition = bq.WriteEmpty
+	if kmsKeyName != "" {
+		copier.DestinationEncryptionConfig = &bq.EncryptionConfig{KMSKeyName: kmsKeyName}
+	}
+	return copier
+}
+
+// RestoreTableSnapshot will create a writable table from a table snapshot
+func (d *defaultBigQueryClient) RestoreTableSnapshot(ctxIn context.Context, snapshotProject, snapshotDataset, snapshotTable, project, dataset, table string, writeDisposition bq.TableWriteDisposition) *bq.Copier {
+	_, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).RestoreTableSnapshot")
+	defer span.End()
+
+	source := d.client.DatasetInProject(snapshotProject, snapshotDataset).Table(snapshotTable)
+	copier := d.client.DatasetInProject(project, dataset).Table(table).CopierFrom(source)
+	copier.OperationType = bq.RestoreOperation
+	copier.CreateDisposition = bq.CreateIfNeeded
+	copier.WriteDisposition = writeDisposition
+	return copier
+}
+
+// CreateDataset create a dataset whose tables expire after defaultTableExpiration, false is returned if it already exists
+func (d *defaultBigQueryClient) CreateDataset(ctxIn context.Context, project, dataset, location string, defaultTableExpiration time.Duration, labels map[string]string) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).CreateDataset")
+	defer span.End()
+
+	err := d.client.DatasetInProject(project, dataset).Create(ctx, &bq.DatasetMetadata{
+		Location:               location,
+		DefaultTableExpiration: defaultTableExpiration,
+		Labels:                 labels,
+	})
+	if isAlreadyExists(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not create dataset %s.%s: %s", project, dataset, err)
+	}
+	return true, nil
+}
+
+// UpdateDefaultTableExpiration change the expiration of tables created afterwards in the dataset
+func (d *defaultBigQueryClient) UpdateDefaultTableExpiration(ctxIn context.Context, project, dataset string, defaultTableExpiration time.Duration) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).UpdateDefaultTableExpiration")
+	defer span.End()
+
+	_, err := d.client.DatasetInProject(project, dataset).Update(ctx, bq.DatasetMetadataToUpdate{DefaultTableExpiration: defaultTableExpiration}, "")
+	if err != nil {
+		return fmt.Errorf("could not update default table expiration of dataset %s.%s: %s", project, dataset, err)
+	}
+	return nil
+}
+
+// DeleteDataset delete a dataset with all its tables
+// If dataset does not exist, it returns nil
+func (d *defaultBigQueryClient) DeleteDataset(ctxIn context.Context, project, dataset string) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).DeleteDataset")
+	defer span.End()
+
+	err := d.client.DatasetInProject(project, dataset).DeleteWithContents(ctx)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("could not delete dataset %s.%s: %s", project, dataset, err)
+	}
+	return nil
+}
+
+// DeleteTable delete a table or table snapshot
+// If table does not exist, it returns nil
+func (d *defaultBigQueryClient) DeleteTable(ctxIn context.Context, project, dataset, table string) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultBigQueryClient).DeleteTable")
+	defer span.End()
+
+	err := d.client.DatasetInProject(project, dataset).Table(table).Delete(ctx)
+	if err != nil && !isNotFound(err) {
+		return fmt.Errorf("could not delete table %s.%s.%s: %s", project, dataset, table, err)
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var googleAPIErr *googleapi.Error
+	return errors.As(err, &googleAPIErr) && googleAPIErr.Code == http.StatusNotFound
+}
+
+func isAlreadyExists(err error) bool {
+	var googleAPIErr *googleapi.Error
+	return errors.As(err, &googleAPIErr) && googleAPIErr.Code == http.StatusConflict
+}
@@ -33,12 +33,13 @@ func NewExtractJobHandler(ctxIn context.Context, tokenSourceProvider impersonate
 	return &ExtractJobHandler{bq: bgClient}, nil
 }
 
-// CreateAvroJob start a BigQuery job that export data in AVRO format
-func (e *ExtractJobHandler) CreateAvroJob(ctxIn context.Context, dataset, table, sinkURI string) (repository.ExtractJobID, error) {
-	ctx, span := trace.StartSpan(ctxIn, "(*ExtractJobHandler).CreateAvroJob")
+// CreateJob start a BigQuery job that export data in the given format and compression, extract jobs have no
+// destination encryption, the exported files get the default KMS key of the sink bucket
+func (e *ExtractJobHandler) CreateJob(ctxIn context.Context, dataset, table, sinkURI string, format repository.ExportFormat, compression repository.ExportCompression) (repository.ExtractJobID, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*ExtractJobHandler).CreateJob")
 	defer span.End()
 
-	extractor := e.bq.ExtractTableToGcsAsAvro(ctx, dataset, table, sinkURI)
+	extractor := e.bq.ExtractTableToGcs(ctx, dataset, table, sinkURI, format, compression)
 
 	job, err := extractor.Run(ctx)
 	if err != nil {
@@ -48,6 +49,38 @@ func (e *ExtractJobHandler) CreateAvroJob(ctxIn context.Context, dataset, table,
 	return repository.NewExtractJobIDWithLocation(job.ID(), job.Location()), nil
 }
 
+// CreateSnapshotJob start a BigQuery copy job that snapshot a table into the snapshot dataset of the target project,
+// the snapshot is encrypted with kmsKeyName if set
+func (e *ExtractJobHandler) CreateSnapshotJob(ctxIn context.Context, dataset, table, snapshotDataset, snapshotTable, kmsKeyName string) (repository.ExtractJobID, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*ExtractJobHandler).CreateSnapshotJob")
+	defer span.End()
+
+	copier := e.bq.SnapshotTable(ctx, dataset, table, snapshotDataset, snapshotTable, kmsKeyName)
+
+	job, err := copier.Run(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return repository.NewExtractJobIDWithLocation(job.ID(), job.Location()), nil
+}
+
+// DeleteTableSnapshot delete a table snapshot, if it already expired nil is returned
+func (e *ExtractJobHandler) DeleteTableSnapshot(ctxIn context.Context, project, snapshotDataset, snapshotTable string) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*ExtractJobHandler).DeleteTableSnapshot")
+	defer span.End()
+
+	return e.bq.DeleteTable(ctx, project, snapshotDataset, snapshotTable)
+}
+
+// DeleteSnapshotDataset delete the snapshot dataset with all table snapshots in it
+func (e *ExtractJobHandler) DeleteSnapshotDataset(ctxIn context.Context, project, snapshotDataset string) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*ExtractJobHandler).DeleteSnapshotDataset")
+	defer span.End()
+
+	return e.bq.DeleteDataset(ctx, project, snapshotDataset)
+}
+
 // GetStatusOfJob get actual status for a BigQuery job
 func (e *ExtractJobHandler) GetStatusOfJob(ctxIn context.Context, extractJobID repository.ExtractJobID) (ExtractJobState, error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*ExtractJobHandler).GetStatusOfJob")
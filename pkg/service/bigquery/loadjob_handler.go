@@ -0,0 +1,191 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	bq "cloud.google.com/go/bigquery"
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"go.opencensus.io/trace"
+)
+
+// LoadJobHandler represent importing backup data into BigQuery
+type LoadJobHandler struct {
+	bq Client
+}
+
+// NewLoadJobHandler create new instance of LoadJobHandler
+// Jobs are started in the sinkProjectID, so the impersonated account has access to the backup sink
+func NewLoadJobHandler(ctxIn context.Context, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, restoreProjectID, sinkProjectID string) (*LoadJobHandler, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewLoadJobHandler")
+	defer span.End()
+
+	bgClient, err := NewBigQueryClient(ctx, tokenSourceProvider, restoreProjectID, sinkProjectID)
+	if err != nil {
+		return &LoadJobHandler{}, fmt.Errorf("can not create instance of LoadJobHandler: %s", err)
+	}
+	if bgClient == nil || !bgClient.IsInitialized(ctx) {
+		return &LoadJobHandler{}, fmt.Errorf("can not create instance of LoadJobHandler with unititialized Client")
+	}
+
+	return &LoadJobHandler{bq: bgClient}, nil
+}
+
+// NewLoadJobHandlerWithClient create new instance of LoadJobHandler for a given Client
+func NewLoadJobHandlerWithClient(client Client) *LoadJobHandler {
+	return &LoadJobHandler{bq: client}
+}
+
+// Close terminates all resources in use
+func (l *LoadJobHandler) Close(ctxIn context.Context) {
+	l.bq.Close(ctxIn)
+}
+
+// DoesDatasetExists check if restore target dataset exist
+func (l *LoadJobHandler) DoesDatasetExists(ctxIn context.Context, project, dataset string) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*LoadJobHandler).DoesDatasetExists")
+	defer span.End()
+
+	return l.bq.DoesDatasetExists(ctx, project, dataset)
+}
+
+// GetTableNames list names of the regular tables in a dataset
+func (l *LoadJobHandler) GetTableNames(ctxIn context.Context, project, dataset string) ([]string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*LoadJobHandler).GetTableNames")
+	defer span.End()
+
+	tables, err := l.bq.GetTablesInDataset(ctx, project, dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, table := range tables {
+		names = append(names, table.Name)
+	}
+	return names, nil
+}
+
+// GetTable get metadata of a table or of a single partition if the table has a partition decorator
+func (l *LoadJobHandler) GetTable(ctxIn context.Context, project, dataset, table string) (*Table, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*LoadJobHandler).GetTable")
+	defer span.End()
+
+	baseTable, _, isPartition := strings.Cut(table, "$")
+	if !isPartition {
+		return l.bq.GetTable(ctx, project, dataset, table)
+	}
+
+	partitions, err := l.bq.GetTablePartitions(ctx, project, dataset, baseTable)
+	if err != nil {
+		return nil, err
+	}
+	for _, partition := range partitions {
+		if partition.Name == table {
+			return partition, nil
+		}
+	}
+	return nil, fmt.Errorf("partition %s.%s.%s does not exist", project, dataset, table)
+}
+
+// DeleteTable delete a table, e.g. the scratch table of a restore drill
+// If table does not exist, it returns nil
+func (l *LoadJobHandler) DeleteTable(ctxIn context.Context, project, dataset, table string) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*LoadJobHandler).DeleteTable")
+	defer span.End()
+
+	return l.bq.DeleteTable(ctx, project, dataset, table)
+}
+
+// RecreateObjects create the tables of the manifest before data is loaded, so schema, partitioning and clustering survive a restore
+// withDatasetObjects recreates views and routines as well, access entries only when restoring into the source dataset
+// views and routines of another target dataset query the restored tables instead of the source dataset
+func (l *LoadJobHandler) RecreateObjects(ctxIn context.Context, project, dataset string, manifest *DatasetManifest, tables []string, targetName func(string) string, withDatasetObjects bool) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*LoadJobHandler).RecreateObjects")
+	defer span.End()
+
+	for _, table := range tables {
+		tableManifest, found := manifest.FindTable(table)
+		if !found || tableManifest.IsView() {
+			continue
+		}
+		if _, err := l.bq.CreateTableFromManifest(ctx, project, dataset, targetName(tableManifest.Name), tableManifest); err != nil {
+			return err
+		}
+	}
+
+	if !withDatasetObjects {
+		return nil
+	}
+
+	// views can query routines, so routines are created first
+	for _, routine := range manifest.Routines {
+		routine = routine.withDatasetReferences(manifest.Project, manifest.Dataset, project, dataset)
+		if _, err := l.bq.CreateRoutineFromManifest(ctx, project, dataset, routine.Name, routine); err != nil {
+			return err
+		}
+	}
+	for _, tableManifest := range manifest.Tables {
+		if !tableManifest.IsView() {
+			continue
+		}
+		tableManifest = tableManifest.withDatasetReferences(manifest.Project, manifest.Dataset, project, dataset)
+		if _, err := l.bq.CreateTableFromManifest(ctx, project, dataset, targetName(tableManifest.Name), tableManifest); err != nil {
+			return err
+		}
+	}
+	if project == manifest.Project && dataset == manifest.Dataset && len(manifest.Access) > 0 {
+		return l.bq.AddDatasetAccess(ctx, project, dataset, manifest.Access)
+	}
+	return nil
+}
+
+// CreateLoadJob start a BigQuery job that import data in the format it was exported with
+// the job location is derived by BigQuery from the target dataset
+func (l *LoadJobHandler) CreateLoadJob(ctxIn context.Context, project, dataset, table, sourceURI string, format repository.ExportFormat, writeDisposition bq.TableWriteDisposition) (repository.LoadJobID, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*LoadJobHandler).CreateLoadJob")
+	defer span.End()
+
+	loader := l.bq.LoadTableFromGcs(ctx, project, dataset, table, sourceURI, format, writeDisposition)
+
+	job, err := loader.Run(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return repository.NewLoadJobIDWithLocation(job.ID(), job.Location()), nil
+}
+
+// CreateRestoreSnapshotJob start a BigQuery copy job that restore a table snapshot into a writable table
+func (l *LoadJobHandler) CreateRestoreSnapshotJob(ctxIn context.Context, snapshotProject, snapshotDataset, snapshotTable, project, dataset, table string, writeDisposition bq.TableWriteDisposition) (repository.LoadJobID, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*LoadJobHandler).CreateRestoreSnapshotJob")
+	defer span.End()
+
+	copier := l.bq.RestoreTableSnapshot(ctx, snapshotProject, snapshotDataset, snapshotTable, project, dataset, table, writeDisposition)
+
+	job, err := copier.Run(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return repository.NewLoadJobIDWithLocation(job.ID(), job.Location()), nil
+}
+
+// GetStatusOfJob get actual status for a BigQuery load job
+func (l *LoadJobHandler) GetStatusOfJob(ctxIn context.Context, loadJobID repository.LoadJobID) (ExtractJobState, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*LoadJobHandler).GetStatusOfJob")
+	defer span.End()
+
+	jobStatus, err := l.bq.GetLoadJobStatus(ctx, loadJobID)
+	if err != nil {
+		return StateUnspecified, err
+	}
+
+	if jobStatus.Err() != nil {
+		return Failed, jobStatus.Err()
+	}
+
+	return toJobState(jobStatus.State), nil
+}
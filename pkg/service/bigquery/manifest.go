@@ -0,0 +1,254 @@
+package bigquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	bq "cloud.google.com/go/bigquery"
+)
+
+// DatasetManifest captures everything of a dataset an extract job does not contain
+type DatasetManifest struct {
+	Project                string                `json:"project"`
+	Dataset                string                `json:"dataset"`
+	CreatedTimestamp       time.Time             `json:"created_timestamp"`
+	Description            string                `json:"description,omitempty"`
+	Location               string                `json:"location,omitempty"`
+	Labels                 map[string]string     `json:"labels,omitempty"`
+	DefaultTableExpiration time.Duration         `json:"default_table_expiration,omitempty"`
+	Access                 []AccessEntryManifest `json:"access,omitempty"`
+	Tables                 []TableManifest       `json:"tables,omitempty"`
+	Routines               []RoutineManifest     `json:"routines,omitempty"`
+}
+
+// AccessEntryManifest is a dataset access entry, Resource references a view, routine or dataset as project.dataset[.id]
+type AccessEntryManifest struct {
+	Role        string   `json:"role,omitempty"`
+	EntityType  int      `json:"entity_type"`
+	Entity      string   `json:"entity,omitempty"`
+	Resource    string   `json:"resource,omitempty"`
+	TargetTypes []string `json:"target_types,omitempty"`
+}
+
+// TableManifest describes a table, view or materialized view without its rows
+type TableManifest struct {
+	Name                   string                         `json:"name"`
+	Type                   string                         `json:"type"`
+	Description            string                         `json:"description,omitempty"`
+	Labels                 map[string]string              `json:"labels,omitempty"`
+	Schema                 json.RawMessage                `json:"schema,omitempty"`
+	ViewQuery              string                         `json:"view_query,omitempty"`
+	UseLegacySQL           bool                           `json:"use_legacy_sql,omitempty"`
+	MaterializedView       *bq.MaterializedViewDefinition `json:"materialized_view,omitempty"`
+	TimePartitioning       *bq.TimePartitioning           `json:"time_partitioning,omitempty"`
+	RangePartitioning      *bq.RangePartitioning          `json:"range_partitioning,omitempty"`
+	RequirePartitionFilter bool                           `json:"require_partition_filter,omitempty"`
+	Clustering             []string                       `json:"clustering,omitempty"`
+}
+
+// RoutineManifest describes a user-defined function or stored procedure
+type RoutineManifest struct {
+	Name              string                  `json:"name"`
+	Type              string                  `json:"type"`
+	Language          string                  `json:"language,omitempty"`
+	Description       string                  `json:"description,omitempty"`
+	Arguments         []*bq.RoutineArgument   `json:"arguments,omitempty"`
+	ReturnType        *bq.StandardSQLDataType `json:"return_type,omitempty"`
+	ImportedLibraries []string                `json:"imported_libraries,omitempty"`
+	Body              string                  `json:"body"`
+}
+
+// IsView is true for views and materialized views, they are recreated after the tables they query
+func (t TableManifest) IsView() bool {
+	return t.Type == string(bq.ViewTable) || t.Type == string(bq.MaterializedView)
+}
+
+// FindTable return the manifest of a table by name, partition decorators are ignored
+func (m *DatasetManifest) FindTable(name string) (TableManifest, bool) {
+	baseName, _, _ := strings.Cut(name, "$")
+	for _, table := range m.Tables {
+		if table.Name == baseName {
+			return table, true
+		}
+	}
+	return TableManifest{}, false
+}
+
+// withDatasetReferences point the queries of a view to the target dataset, tables of other datasets are kept
+func (t TableManifest) withDatasetReferences(fromProject, fromDataset, toProject, toDataset string) TableManifest {
+	t.ViewQuery = rewriteDatasetReferences(t.ViewQuery, fromProject, fromDataset, toProject, toDataset)
+	if t.MaterializedView != nil {
+		materializedView := *t.MaterializedView
+		materializedView.Query = rewriteDatasetReferences(materializedView.Query, fromProject, fromDataset, toProject, toDataset)
+		t.MaterializedView = &materializedView
+	}
+	return t
+}
+
+// withDatasetReferences point the body of a routine to the target dataset, tables of other datasets are kept
+func (r RoutineManifest) withDatasetReferences(fromProject, fromDataset, toProject, toDataset string) RoutineManifest {
+	r.Body = rewriteDatasetReferences(r.Body, fromProject, fromDataset, toProject, toDataset)
+	return r
+}
+
+// rewriteDatasetReferences replace references to fromProject.fromDataset in standard and legacy SQL, references without
+// project resolve to the project of the view and only get the dataset replaced
+func rewriteDatasetReferences(query, fromProject, fromDataset, toProject, toDataset string) string {
+	if query == "" || (fromProject == toProject && fromDataset == toDataset) {
+		return query
+	}
+
+	project, dataset := regexp.QuoteMeta(fromProject), regexp.QuoteMeta(fromDataset)
+	// pattern and replacement, the first group keeps the character in front of an unquoted reference
+	replacements := [][2]string{
+		{"`" + project + `\.` + dataset + "([.`])", "`" + toProject + "." + toDataset + "${1}"},
+		{"`" + project + "`\\.`" + dataset + "`", "`" + toProject + "`.`" + toDataset + "`"},
+		{`\[` + project + `:` + dataset + `\.`, "[" + toProject + ":" + toDataset + "."},
+		{`(^|[^\w.\-` + "`" + `])` + project + `\.` + dataset + `\.`, "${1}" + toProject + "." + toDataset + "."},
+	}
+	if fromDataset != toDataset {
+		replacements = append(replacements, [][2]string{
+			{"`" + dataset + "([.`])", "`" + toDataset + "${1}"},
+			{`(^|[^\w.\-` + "`" + `])` + dataset + `\.`, "${1}" + toDataset + "."},
+		}...)
+	}
+
+	for _, replacement := range replacements {
+		query = regexp.MustCompile(replacement[0]).ReplaceAllString(query, replacement[1])
+	}
+	return query
+}
+
+func newDatasetManifest(project, dataset string, metadata *bq.DatasetMetadata) *DatasetManifest {
+	manifest := &DatasetManifest{
+		Project:                project,
+		Dataset:                dataset,
+		CreatedTimestamp:       time.Now(),
+		Description:            metadata.Description,
+		Location:               metadata.Location,
+		Labels:                 metadata.Labels,
+		DefaultTableExpiration: metadata.DefaultTableExpiration,
+	}
+	for _, entry := range metadata.Access {
+		manifest.Access = append(manifest.Access, newAccessEntryManifest(entry))
+	}
+	return manifest
+}
+
+func newAccessEntryManifest(entry *bq.AccessEntry) AccessEntryManifest {
+	result := AccessEntryManifest{Role: string(entry.Role), EntityType: int(entry.EntityType), Entity: entry.Entity}
+	switch {
+	case entry.View != nil:
+		result.Resource = fmt.Sprintf("%s.%s.%s", entry.View.ProjectID, entry.View.DatasetID, entry.View.TableID)
+	case entry.Routine != nil:
+		result.Resource = fmt.Sprintf("%s.%s.%s", entry.Routine.ProjectID, entry.Routine.DatasetID, entry.Routine.RoutineID)
+	case entry.Dataset != nil && entry.Dataset.Dataset != nil:
+		result.Resource = fmt.Sprintf("%s.%s", entry.Dataset.Dataset.ProjectID, entry.Dataset.Dataset.DatasetID)
+		result.TargetTypes = entry.Dataset.TargetTypes
+	}
+	return result
+}
+
+func (a AccessEntryManifest) toAccessEntry(client *bq.Client) *bq.AccessEntry {
+	entry := &bq.AccessEntry{Role: bq.AccessRole(a.Role), EntityType: bq.EntityType(a.EntityType), Entity: a.Entity}
+	parts := strings.Split(a.Resource, ".")
+	switch {
+	case entry.EntityType == bq.ViewEntity && len(parts) == 3:
+		entry.View = client.DatasetInProject(parts[0], parts[1]).Table(parts[2])
+	case entry.EntityType == bq.RoutineEntity && len(parts) == 3:
+		entry.Routine = client.DatasetInProject(parts[0], parts[1]).Routine(parts[2])
+	case entry.EntityType == bq.DatasetEntity && len(parts) == 2:
+		entry.Dataset = &bq.DatasetAccessEntry{Dataset: client.DatasetInProject(parts[0], parts[1]), TargetTypes: a.TargetTypes}
+	}
+	return entry
+}
+
+// equal compare the granted entity and resource, Entity is empty for view, routine and dataset entries
+func (a AccessEntryManifest) equal(other AccessEntryManifest) bool {
+	return a.Role == other.Role && a.EntityType == other.EntityType && a.Entity == other.Entity && a.Resource == other.Resource
+}
+
+func newTableManifest(name string, metadata *bq.TableMetadata) (TableManifest, error) {
+	manifest := TableManifest{
+		Name:                   name,
+		Type:                   string(metadata.Type),
+		Description:            metadata.Description,
+		Labels:                 metadata.Labels,
+		ViewQuery:              metadata.ViewQuery,
+		UseLegacySQL:           metadata.UseLegacySQL,
+		MaterializedView:       metadata.MaterializedView,
+		TimePartitioning:       metadata.TimePartitioning,
+		RangePartitioning:      metadata.RangePartitioning,
+		RequirePartitionFilter: metadata.RequirePartitionFilter,
+	}
+	if metadata.Clustering != nil {
+		manifest.Clustering = metadata.Clustering.Fields
+	}
+	// the schema of views is derived from the query
+	if len(metadata.Schema) > 0 && metadata.Type == bq.RegularTable {
+		schema, err := metadata.Schema.ToJSONFields()
+		if err != nil {
+			return TableManifest{}, fmt.Errorf("could not serialize schema of table %s: %s", name, err)
+		}
+		manifest.Schema = schema
+	}
+	return manifest, nil
+}
+
+func (t TableManifest) toTableMetadata() (*bq.TableMetadata, error) {
+	metadata := &bq.TableMetadata{
+		Description:            t.Description,
+		Labels:                 t.Labels,
+		ViewQuery:              t.ViewQuery,
+		UseLegacySQL:           t.UseLegacySQL,
+		TimePartitioning:       t.TimePartitioning,
+		RangePartitioning:      t.RangePartitioning,
+		RequirePartitionFilter: t.RequirePartitionFilter,
+	}
+	if t.MaterializedView != nil {
+		metadata.MaterializedView = &bq.MaterializedViewDefinition{
+			Query:           t.MaterializedView.Query,
+			EnableRefresh:   t.MaterializedView.EnableRefresh,
+			RefreshInterval: t.MaterializedView.RefreshInterval,
+		}
+	}
+	if len(t.Clustering) > 0 {
+		metadata.Clustering = &bq.Clustering{Fields: t.Clustering}
+	}
+	if len(t.Schema) > 0 {
+		schema, err := bq.SchemaFromJSON(t.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse schema of table %s: %s", t.Name, err)
+		}
+		metadata.Schema = schema
+	}
+	return metadata, nil
+}
+
+func newRoutineManifest(name string, metadata *bq.RoutineMetadata) RoutineManifest {
+	return RoutineManifest{
+		Name:              name,
+		Type:              metadata.Type,
+		Language:          metadata.Language,
+		Description:       metadata.Description,
+		Arguments:         metadata.Arguments,
+		ReturnType:        metadata.ReturnType,
+		ImportedLibraries: metadata.ImportedLibraries,
+		Body:              metadata.Body,
+	}
+}
+
+func (r RoutineManifest) toRoutineMetadata() *bq.RoutineMetadata {
+	return &bq.RoutineMetadata{
+		Type:              r.Type,
+		Language:          r.Language,
+		Description:       r.Description,
+		Arguments:         r.Arguments,
+		ReturnType:        r.ReturnType,
+		ImportedLibraries: r.ImportedLibraries,
+		Body:              r.Body,
+	}
+}
@@ -0,0 +1,136 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	bq "cloud.google.com/go/bigquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableManifest_RoundTrip(t *testing.T) {
+	metadata := &bq.TableMetadata{
+		Type:        bq.RegularTable,
+		Description: "all orders",
+		Labels:      map[string]string{"team": "checkout"},
+		Schema: bq.Schema{
+			{Name: "id", Type: bq.IntegerFieldType, Required: true},
+			{Name: "created", Type: bq.TimestampFieldType},
+			{Name: "items", Type: bq.RecordFieldType, Repeated: true, Schema: bq.Schema{{Name: "sku", Type: bq.StringFieldType}}},
+		},
+		TimePartitioning: &bq.TimePartitioning{Type: bq.DayPartitioningType, Field: "created"},
+		Clustering:       &bq.Clustering{Fields: []string{"id"}},
+	}
+
+	manifest, err := newTableManifest("orders", metadata)
+	require.NoError(t, err)
+	content, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	var parsed TableManifest
+	require.NoError(t, json.Unmarshal(content, &parsed))
+	restored, err := parsed.toTableMetadata()
+	require.NoError(t, err)
+
+	assert.Equal(t, metadata.Description, restored.Description)
+	assert.Equal(t, metadata.Labels, restored.Labels)
+	assert.Equal(t, metadata.TimePartitioning, restored.TimePartitioning)
+	assert.Equal(t, metadata.Clustering, restored.Clustering)
+	require.Len(t, restored.Schema, 3)
+	assert.True(t, restored.Schema[0].Required)
+	assert.Equal(t, "sku", restored.Schema[2].Schema[0].Name)
+}
+
+func TestLoadJobHandler_RecreateObjects(t *testing.T) {
+	manifest := &DatasetManifest{
+		Project: "source-project",
+		Dataset: "shop",
+		Access:  []AccessEntryManifest{{Role: string(bq.ReaderRole), EntityType: int(bq.GroupEmailEntity), Entity: "analysts@example.com"}},
+		Tables: []TableManifest{
+			{Name: "orders", Type: string(bq.RegularTable)},
+			{Name: "customers", Type: string(bq.RegularTable)},
+			{Name: "open_orders", Type: string(bq.ViewTable), ViewQuery: "SELECT * FROM `source-project.shop.orders` WHERE open"},
+		},
+		Routines: []RoutineManifest{{Name: "net_price", Type: "SCALAR_FUNCTION", Body: "x * 0.81"}},
+	}
+	prefix := func(table string) string { return "tmp_" + table }
+
+	t.Run("single table into other dataset", func(t *testing.T) {
+		client := &recordingClient{}
+		err := NewLoadJobHandlerWithClient(client).RecreateObjects(context.Background(), "sandbox", "inspect", manifest, []string{"orders"}, prefix, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"sandbox.inspect.tmp_orders"}, client.created)
+		assert.Empty(t, client.granted)
+	})
+
+	t.Run("whole dataset into other dataset", func(t *testing.T) {
+		client := &recordingClient{}
+		identity := func(table string) string { return table }
+		err := NewLoadJobHandlerWithClient(client).RecreateObjects(context.Background(), "sandbox", "inspect", manifest, []string{"customers", "orders"}, identity, true)
+		require.NoError(t, err)
+		assert.Equal(t, "SELECT * FROM `sandbox.inspect.orders` WHERE open", client.viewQueries["open_orders"])
+		assert.Empty(t, client.granted, "access entries are only restored into the source dataset")
+	})
+
+	t.Run("whole dataset into source dataset", func(t *testing.T) {
+		client := &recordingClient{}
+		identity := func(table string) string { return table }
+		err := NewLoadJobHandlerWithClient(client).RecreateObjects(context.Background(), "source-project", "shop", manifest, []string{"customers", "orders"}, identity, true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"source-project.shop.customers", "source-project.shop.orders", "source-project.shop.net_price", "source-project.shop.open_orders"}, client.created)
+		assert.Equal(t, manifest.Access, client.granted)
+	})
+}
+
+func TestRewriteDatasetReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{name: "quoted", query: "SELECT * FROM `source-project.shop.orders`", expected: "SELECT * FROM `sandbox.inspect.orders`"},
+		{name: "quoted dataset", query: "SELECT * FROM `source-project.shop`.orders", expected: "SELECT * FROM `sandbox.inspect`.orders"},
+		{name: "quoted parts", query: "SELECT * FROM `source-project`.`shop`.orders", expected: "SELECT * FROM `sandbox`.`inspect`.orders"},
+		{name: "legacy sql", query: "SELECT * FROM [source-project:shop.orders]", expected: "SELECT * FROM [sandbox:inspect.orders]"},
+		{name: "without project", query: "SELECT o.id FROM shop.orders o JOIN `shop.customers` c ON o.id = c.id", expected: "SELECT o.id FROM inspect.orders o JOIN `inspect.customers` c ON o.id = c.id"},
+		{name: "other dataset", query: "SELECT * FROM `source-project.shop_archive.orders` JOIN `other-project.shop.orders` USING (id)", expected: "SELECT * FROM `source-project.shop_archive.orders` JOIN `other-project.shop.orders` USING (id)"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, rewriteDatasetReferences(test.query, "source-project", "shop", "sandbox", "inspect"))
+		})
+	}
+
+	query := "SELECT * FROM `source-project.shop.orders`"
+	assert.Equal(t, query, rewriteDatasetReferences(query, "source-project", "shop", "source-project", "shop"))
+}
+
+// recordingClient records created objects, all other operations of Client are not implemented
+type recordingClient struct {
+	Client
+	created     []string
+	granted     []AccessEntryManifest
+	viewQueries map[string]string
+}
+
+func (c *recordingClient) CreateTableFromManifest(ctxIn context.Context, project, dataset, table string, manifest TableManifest) (bool, error) {
+	c.created = append(c.created, project+"."+dataset+"."+table)
+	if manifest.IsView() {
+		if c.viewQueries == nil {
+			c.viewQueries = map[string]string{}
+		}
+		c.viewQueries[table] = manifest.ViewQuery
+	}
+	return true, nil
+}
+
+func (c *recordingClient) CreateRoutineFromManifest(ctxIn context.Context, project, dataset, routine string, manifest RoutineManifest) (bool, error) {
+	c.created = append(c.created, project+"."+dataset+"."+routine)
+	return true, nil
+}
+
+func (c *recordingClient) AddDatasetAccess(ctxIn context.Context, project, dataset string, entries []AccessEntryManifest) error {
+	c.granted = append(c.granted, entries...)
+	return nil
+}
@@ -1,7 +1,10 @@
 package bigquery
 
 import (
+	"errors"
+
 	bq "cloud.google.com/go/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/util"
 )
 
 const (
@@ -45,3 +48,32 @@ func toJobState(status bq.State) ExtractJobState {
 
 	return StateUnspecified
 }
+
+// retryableJobErrorReasons are reasons of BigQuery job errors which might succeed when the job is started again
+// see https://cloud.google.com/bigquery/docs/error-messages
+var retryableJobErrorReasons = map[string]bool{
+	"backendError":      true,
+	"internalError":     true,
+	"jobBackendError":   true,
+	"jobInternalError":  true,
+	"rateLimitExceeded": true,
+	"timeout":           true,
+}
+
+// IsRetryableError checks if a failed BigQuery request or job might succeed when it is started again
+func IsRetryableError(err error) bool {
+	var jobErr *bq.Error
+	if errors.As(err, &jobErr) {
+		return retryableJobErrorReasons[jobErr.Reason]
+	}
+	return util.IsRetryableAPIError(err)
+}
+
+// ErrorDetails extracts the code and reason of a failed BigQuery request or job, a job error only has a reason
+func ErrorDetails(err error) (code string, reason string) {
+	var jobErr *bq.Error
+	if errors.As(err, &jobErr) {
+		return "", jobErr.Reason
+	}
+	return util.APIErrorDetails(err)
+}
@@ -0,0 +1,250 @@
+package cloudsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"go.opencensus.io/trace"
+	"google.golang.org/api/googleapi"
+	gimpersonate "google.golang.org/api/impersonate"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AdminClient define operations with the Cloud SQL admin API
+type AdminClient interface {
+	DoesInstanceExist(ctxIn context.Context, project, instance string) (bool, error)
+	GetInstance(ctxIn context.Context, project, instance string) (*Instance, error)
+	ListDatabases(ctxIn context.Context, project, instance string) ([]string, error)
+	InstanceUsageInBytes(ctxIn context.Context, project, instance string) (float64, error)
+	ExportDatabase(ctxIn context.Context, project, instance string, request ExportRequest) (string, error)
+	GetOperation(ctxIn context.Context, project, name string) (*Operation, error)
+	Close(ctxIn context.Context)
+}
+
+// defaultAdminClient defines client to interact with the Cloud SQL admin API
+type defaultAdminClient struct {
+	service      *sqladmin.Service
+	metricClient *monitoring.MetricClient
+}
+
+// NewAdminClient create new instance of AdminClient impersonating the principal of targetProjectID
+func NewAdminClient(ctxIn context.Context, targetPrincipalProvider impersonate.TargetPrincipalForProjectProvider, targetProjectID string) (AdminClient, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewAdminClient")
+	defer span.End()
+
+	target, delegates, err := targetPrincipalProvider.GetTargetPrincipalForProject(ctx, targetProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []option.ClientOption
+	if config.UseDefaultHttpClient.GetBoolOrDefault(false) {
+		options = []option.ClientOption{
+			option.WithHTTPClient(http.DefaultClient),
+		}
+	} else {
+		tokenSource, err := gimpersonate.CredentialsTokenSource(ctx, gimpersonate.CredentialsConfig{
+			TargetPrincipal: target,
+			Scopes:          []string{cloudPlatformAPIScope, sqlAdminAPIScope},
+			Delegates:       delegates,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		options = []option.ClientOption{
+			option.WithTokenSource(tokenSource),
+		}
+	}
+	service, err := sqladmin.NewService(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sqladmin.Service: %v", err)
+	}
+
+	var monitoringOptions []option.ClientOption
+	if config.UseGrpcWithoutAuthentication.GetBoolOrDefault(false) {
+		monitoringOptions = []option.ClientOption{
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		}
+	} else {
+		tokenSource, err := gimpersonate.CredentialsTokenSource(ctx, gimpersonate.CredentialsConfig{
+			TargetPrincipal: target,
+			Scopes:          []string{cloudPlatformAPIScope, metricAPIScope},
+			Delegates:       delegates,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		monitoringOptions = []option.ClientOption{
+			option.WithTokenSource(tokenSource),
+		}
+	}
+	metricClient, err := monitoring.NewMetricClient(ctx, monitoringOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create monitoring.MetricClient: %v", err)
+	}
+
+	return &defaultAdminClient{service: service, metricClient: metricClient}, nil
+}
+
+// Close terminates all resources in use
+func (c *defaultAdminClient) Close(ctxIn context.Context) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).Close")
+	defer span.End()
+
+	c.metricClient.Close()
+}
+
+// DoesInstanceExist check if the Cloud SQL instance exists in the project
+func (c *defaultAdminClient) DoesInstanceExist(ctxIn context.Context, project, instance string) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).DoesInstanceExist")
+	defer span.End()
+
+	_, err := c.service.Instances.Get(project, instance).Context(ctx).Do()
+	var googleAPIErr *googleapi.Error
+	if errors.As(err, &googleAPIErr) && googleAPIErr.Code == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error getting cloudsql instance %s in project %s: %s", instance, project, err)
+	}
+	return true, nil
+}
+
+// GetInstance return the region and the service account of an instance
+func (c *defaultAdminClient) GetInstance(ctxIn context.Context, project, instance string) (*Instance, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).GetInstance")
+	defer span.End()
+
+	details, err := c.service.Instances.Get(project, instance).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("error getting cloudsql instance %s in project %s: %s", instance, project, err)
+	}
+	return &Instance{
+		Name:                       details.Name,
+		Region:                     details.Region,
+		ServiceAccountEmailAddress: details.ServiceAccountEmailAddress,
+	}, nil
+}
+
+// ListDatabases return the names of all databases of an instance
+func (c *defaultAdminClient) ListDatabases(ctxIn context.Context, project, instance string) ([]string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).ListDatabases")
+	defer span.End()
+
+	response, err := c.service.Databases.List(project, instance).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("error listing databases of cloudsql instance %s in project %s: %s", instance, project, err)
+	}
+	var databases []string
+	for _, database := range response.Items {
+		databases = append(databases, database.Name)
+	}
+	return databases, nil
+}
+
+// InstanceUsageInBytes report how many bytes the instance uses on its disk
+func (c *defaultAdminClient) InstanceUsageInBytes(ctxIn context.Context, project, instance string) (totalSize float64, err error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).InstanceUsageInBytes")
+	defer span.End()
+
+	startTime := time.Now().UTC().Add(time.Hour * -1)
+	endTime := time.Now().UTC()
+	req := &monitoringpb.ListTimeSeriesRequest{
+		Name:   "projects/" + project,
+		Filter: fmt.Sprintf(`metric.type="cloudsql.googleapis.com/database/disk/bytes_used" resource.type="cloudsql_database" resource.label.database_id="%s:%s"`, project, instance),
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: &timestamppb.Timestamp{
+				Seconds: startTime.Unix(),
+			},
+			EndTime: &timestamppb.Timestamp{
+				Seconds: endTime.Unix(),
+			},
+		},
+		View: monitoringpb.ListTimeSeriesRequest_FULL,
+	}
+	it := c.metricClient.ListTimeSeries(ctx, req)
+	for {
+		resp, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return totalSize, fmt.Errorf("ListTimeSeries failed: %s", err)
+		}
+		// points are returned in reverse time order, only the latest one is relevant
+		for _, point := range resp.GetPoints() {
+			totalSize += float64(point.GetValue().GetInt64Value()) + point.GetValue().GetDoubleValue()
+			break
+		}
+	}
+	return totalSize, nil
+}
+
+// ExportDatabase start an export of one database of the instance and return the name of the operation
+func (c *defaultAdminClient) ExportDatabase(ctxIn context.Context, project, instance string, request ExportRequest) (string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).ExportDatabase")
+	defer span.End()
+
+	exportContext := &sqladmin.ExportContext{
+		Databases: []string{request.Database},
+		FileType:  request.FileType,
+		Uri:       request.OutputURI,
+	}
+	if request.CSVQuery != "" {
+		exportContext.CsvExportOptions = &sqladmin.ExportContextCsvExportOptions{SelectQuery: request.CSVQuery}
+	}
+	operation, err := c.service.Instances.Export(project, instance, &sqladmin.InstancesExportRequest{
+		ExportContext: exportContext,
+	}).Context(ctx).Do()
+	var googleAPIErr *googleapi.Error
+	if errors.As(err, &googleAPIErr) && googleAPIErr.Code == http.StatusConflict {
+		return "", ErrOperationInProgress
+	}
+	if err != nil {
+		return "", fmt.Errorf("error starting export of database %s of cloudsql instance %s: %w", request.Database, instance, err)
+	}
+	return operation.Name, nil
+}
+
+// GetOperation return the actual state of an operation
+func (c *defaultAdminClient) GetOperation(ctxIn context.Context, project, name string) (*Operation, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).GetOperation")
+	defer span.End()
+
+	operation, err := c.service.Operations.Get(project, name).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("error getting cloudsql operation %s: %w", name, err)
+	}
+
+	result := &Operation{Name: operation.Name, Done: operation.Status == "DONE"}
+	if operation.Error != nil {
+		var messages []string
+		for _, operationError := range operation.Error.Errors {
+			messages = append(messages, operationError.Message)
+			if result.ErrorCode == "" {
+				result.ErrorCode = operationError.Code
+			}
+		}
+		result.ErrorMessage = strings.Join(messages, "; ")
+		if result.ErrorMessage == "" {
+			result.ErrorMessage = "unknown error"
+		}
+	}
+	return result, nil
+}
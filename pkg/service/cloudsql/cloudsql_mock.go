@@ -0,0 +1,101 @@
+package cloudsql
+
+import (
+	"context"
+	"fmt"
+)
+
+// MockAdminClient is a fake Cloud SQL admin API keeping the instances and started exports in memory
+type MockAdminClient struct {
+	Instances      map[string]*MockInstance
+	Operations     map[string]*Operation
+	ExportRequests []ExportRequest
+	ShouldFail     bool
+}
+
+// MockInstance is a Cloud SQL instance known to MockAdminClient
+type MockInstance struct {
+	Instance
+	Databases   []string
+	SizeInBytes int64
+	// Busy simulates an instance that already runs an operation
+	Busy bool
+}
+
+func (c *MockAdminClient) DoesInstanceExist(ctxIn context.Context, project, instance string) (bool, error) {
+	if c.ShouldFail {
+		return false, fmt.Errorf("mock error")
+	}
+	_, exists := c.Instances[instanceKey(project, instance)]
+	return exists, nil
+}
+
+func (c *MockAdminClient) GetInstance(ctxIn context.Context, project, instance string) (*Instance, error) {
+	mockInstance, err := c.getInstance(project, instance)
+	if err != nil {
+		return nil, err
+	}
+	return &mockInstance.Instance, nil
+}
+
+func (c *MockAdminClient) ListDatabases(ctxIn context.Context, project, instance string) ([]string, error) {
+	mockInstance, err := c.getInstance(project, instance)
+	if err != nil {
+		return nil, err
+	}
+	return mockInstance.Databases, nil
+}
+
+func (c *MockAdminClient) InstanceUsageInBytes(ctxIn context.Context, project, instance string) (float64, error) {
+	mockInstance, err := c.getInstance(project, instance)
+	if err != nil {
+		return 0, err
+	}
+	return float64(mockInstance.SizeInBytes), nil
+}
+
+func (c *MockAdminClient) ExportDatabase(ctxIn context.Context, project, instance string, request ExportRequest) (string, error) {
+	mockInstance, err := c.getInstance(project, instance)
+	if err != nil {
+		return "", err
+	}
+	if mockInstance.Busy {
+		return "", ErrOperationInProgress
+	}
+	c.ExportRequests = append(c.ExportRequests, request)
+	name := fmt.Sprintf("export-%d", len(c.ExportRequests))
+	if c.Operations == nil {
+		c.Operations = map[string]*Operation{}
+	}
+	c.Operations[name] = &Operation{Name: name}
+	return name, nil
+}
+
+func (c *MockAdminClient) GetOperation(ctxIn context.Context, project, name string) (*Operation, error) {
+	if c.ShouldFail {
+		return nil, fmt.Errorf("mock error")
+	}
+	operation, exists := c.Operations[name]
+	if !exists {
+		return nil, fmt.Errorf("operation %s not found", name)
+	}
+	return operation, nil
+}
+
+func (c *MockAdminClient) Close(ctxIn context.Context) {
+}
+
+func (c *MockAdminClient) getInstance(project, instance string) (*MockInstance, error) {
+	if c.ShouldFail {
+		return nil, fmt.Errorf("mock error")
+	}
+	mockInstance, exists := c.Instances[instanceKey(project, instance)]
+	if !exists {
+		return nil, fmt.Errorf("instance %s not found", instanceKey(project, instance))
+	}
+	return mockInstance, nil
+}
+
+func instanceKey(project, instance string) string {
+	return fmt.Sprintf("%s:%s", project, instance)
+}
@@ -0,0 +1,78 @@
+package cloudsql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"go.opencensus.io/trace"
+)
+
+// ExportJobHandler represent exports of Cloud SQL databases into a backup sink
+type ExportJobHandler struct {
+	client AdminClient
+}
+
+// NewExportJobHandler create new instance of ExportJobHandler
+func NewExportJobHandler(ctxIn context.Context, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, targetProjectID string) (*ExportJobHandler, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewExportJobHandler")
+	defer span.End()
+
+	client, err := NewAdminClient(ctx, tokenSourceProvider, targetProjectID)
+	if err != nil {
+		return &ExportJobHandler{}, fmt.Errorf("can not create instance of ExportJobHandler: %s", err)
+	}
+
+	return &ExportJobHandler{client: client}, nil
+}
+
+// NewExportJobHandlerWithClient create new instance of ExportJobHandler for a given AdminClient
+func NewExportJobHandlerWithClient(client AdminClient) *ExportJobHandler {
+	return &ExportJobHandler{client: client}
+}
+
+// Close terminates all resources in use
+func (e *ExportJobHandler) Close(ctxIn context.Context) {
+	e.client.Close(ctxIn)
+}
+
+// CreateExportJob start an export of one database of an instance into the sink below the path of the job
+// ErrOperationInProgress is returned as long as the instance runs another operation
+func (e *ExportJobHandler) CreateExportJob(ctxIn context.Context, srcProjectID string, options repository.CloudSQLOptions, database, sink, jobID string) (repository.ExportOperationID, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*ExportJobHandler).CreateExportJob")
+	defer span.End()
+
+	request := ExportRequest{
+		Database:  database,
+		FileType:  options.FileType.String(),
+		OutputURI: repository.BuildFullCloudSQLExportPath(sink, options.Instance, database, jobID, options.FileType),
+	}
+	if options.FileType == repository.CloudSQLExportCSV {
+		request.CSVQuery = options.CSVQuery
+	}
+	name, err := e.client.ExportDatabase(ctx, srcProjectID, options.Instance, request)
+	if err != nil {
+		return "", err
+	}
+	return repository.ExportOperationID(name), nil
+}
+
+// GetStatusOfJob return actual status of an export
+func (e *ExportJobHandler) GetStatusOfJob(ctxIn context.Context, srcProjectID string, operationID repository.ExportOperationID) (ExportJobState, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*ExportJobHandler).GetStatusOfJob")
+	defer span.End()
+
+	operation, err := e.client.GetOperation(ctx, srcProjectID, operationID.String())
+	if err != nil {
+		return StateUnspecified, err
+	}
+
+	if operation.ErrorMessage != "" {
+		return Failed, &ExportOperationError{OperationID: operationID.String(), Code: operation.ErrorCode, Message: operation.ErrorMessage}
+	}
+	if !operation.Done {
+		return Pending, nil
+	}
+	return Done, nil
+}
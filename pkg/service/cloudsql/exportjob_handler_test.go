@@ -0,0 +1,86 @@
+package cloudsql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockAdminClient() *MockAdminClient {
+	return &MockAdminClient{Instances: map[string]*MockInstance{
+		"source-project:orders-db": {Instance: Instance{Name: "orders-db", Region: "europe-west1"}, Databases: []string{"orders", "customers"}},
+	}}
+}
+
+func TestExportJobHandler_CreateExportJob(t *testing.T) {
+	client := newMockAdminClient()
+	handler := NewExportJobHandlerWithClient(client)
+	options := repository.CloudSQLOptions{Instance: "orders-db", FileType: repository.CloudSQLExportSQL, CSVQuery: "SELECT * FROM orders"}
+
+	operationID, err := handler.CreateExportJob(context.Background(), "source-project", options, "orders", "sink-bucket", "job-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, repository.ExportOperationID("export-1"), operationID)
+	require.Len(t, client.ExportRequests, 1)
+	assert.Equal(t, ExportRequest{Database: "orders", FileType: "SQL", OutputURI: "gs://sink-bucket/cloudsql/orders-db/orders/job-1.sql.gz"}, client.ExportRequests[0])
+}
+
+func TestExportJobHandler_CreateExportJob_CSV(t *testing.T) {
+	client := newMockAdminClient()
+	handler := NewExportJobHandlerWithClient(client)
+	options := repository.CloudSQLOptions{Instance: "orders-db", FileType: repository.CloudSQLExportCSV, CSVQuery: "SELECT * FROM orders"}
+
+	_, err := handler.CreateExportJob(context.Background(), "source-project", options, "orders", "sink-bucket", "job-1")
+	require.NoError(t, err)
+
+	require.Len(t, client.ExportRequests, 1)
+	assert.Equal(t, "SELECT * FROM orders", client.ExportRequests[0].CSVQuery)
+	assert.Equal(t, "gs://sink-bucket/cloudsql/orders-db/orders/job-1.csv.gz", client.ExportRequests[0].OutputURI)
+}
+
+func TestExportJobHandler_CreateExportJob_InstanceBusy(t *testing.T) {
+	client := newMockAdminClient()
+	client.Instances["source-project:orders-db"].Busy = true
+	handler := NewExportJobHandlerWithClient(client)
+	options := repository.CloudSQLOptions{Instance: "orders-db", FileType: repository.CloudSQLExportSQL}
+
+	_, err := handler.CreateExportJob(context.Background(), "source-project", options, "orders", "sink-bucket", "job-1")
+
+	assert.ErrorIs(t, err, ErrOperationInProgress)
+	assert.Empty(t, client.ExportRequests)
+}
+
+func TestExportJobHandler_GetStatusOfJob(t *testing.T) {
+	client := newMockAdminClient()
+	handler := NewExportJobHandlerWithClient(client)
+	ctx := context.Background()
+	options := repository.CloudSQLOptions{Instance: "orders-db", FileType: repository.CloudSQLExportSQL}
+
+	operationID, err := handler.CreateExportJob(ctx, "source-project", options, "orders", "sink-bucket", "job-1")
+	require.NoError(t, err)
+
+	state, err := handler.GetStatusOfJob(ctx, "source-project", operationID)
+	require.NoError(t, err)
+	assert.Equal(t, Pending, state)
+
+	client.Operations[operationID.String()].Done = true
+	state, err = handler.GetStatusOfJob(ctx, "source-project", operationID)
+	require.NoError(t, err)
+	assert.Equal(t, Done, state)
+
+	client.Operations[operationID.String()].ErrorCode = "ERROR_RDBMS"
+	client.Operations[operationID.String()].ErrorMessage = "access denied on sink-bucket"
+	state, err = handler.GetStatusOfJob(ctx, "source-project", operationID)
+	assert.Error(t, err)
+	assert.Equal(t, Failed, state)
+	assert.False(t, IsRetryableError(err))
+	code, _ := ErrorDetails(err)
+	assert.Equal(t, "ERROR_RDBMS", code)
+
+	client.Operations[operationID.String()].ErrorCode = "INTERNAL_ERROR"
+	_, err = handler.GetStatusOfJob(ctx, "source-project", operationID)
+	assert.True(t, IsRetryableError(err))
+}
@@ -0,0 +1,92 @@
+package cloudsql
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ottogroup/penelope/pkg/service/util"
+)
+
+const (
+	sqlAdminAPIScope      = "https://www.googleapis.com/auth/sqlservice.admin"
+	metricAPIScope        = "https://www.googleapis.com/auth/monitoring.read"
+	cloudPlatformAPIScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// ErrOperationInProgress is returned when an instance already runs an operation, Cloud SQL executes one export per instance at a time
+var ErrOperationInProgress = errors.New("another operation is in progress on the instance")
+
+// ExportJobState State is one of a sequence of states that an instance export progresses through as it is processed.
+type ExportJobState string
+
+const (
+	// StateUnspecified is the default export state.
+	StateUnspecified ExportJobState = "Unspecified"
+	// Pending is a state that describes that the export operation is still running.
+	Pending ExportJobState = "Pending"
+	// Done is a state that describes that the export operation is done.
+	Done ExportJobState = "Done"
+	// Failed is a state that describes that the export operation complete unsuccessfully.
+	Failed ExportJobState = "Failed"
+)
+
+// Instance holds the details of a Cloud SQL instance relevant for exports
+type Instance struct {
+	Name                       string
+	Region                     string
+	ServiceAccountEmailAddress string
+}
+
+// ExportRequest describes the export of one database of an instance
+type ExportRequest struct {
+	Database  string
+	FileType  string
+	CSVQuery  string
+	OutputURI string
+}
+
+// Operation is the state of a Cloud SQL admin operation, ErrorCode is the code of its first error
+type Operation struct {
+	Name         string
+	Done         bool
+	ErrorCode    string
+	ErrorMessage string
+}
+
+// retryableOperationErrorCodes are the codes of failed operations which might succeed when they are started again
+var retryableOperationErrorCodes = map[string]bool{
+	"INTERNAL_ERROR":     true,
+	"UNAVAILABLE":        true,
+	"DEADLINE_EXCEEDED":  true,
+	"RESOURCE_EXHAUSTED": true,
+	"ABORTED":            true,
+}
+
+// ExportOperationError is the error of a failed export operation, Code is the error code reported by Cloud SQL
+type ExportOperationError struct {
+	OperationID string
+	Code        string
+	Message     string
+}
+
+func (e *ExportOperationError) Error() string {
+	return fmt.Sprintf("export operation %s finished in failed state: %s", e.OperationID, e.Message)
+}
+
+// IsRetryableError checks if a failed Cloud SQL request or export operation might succeed when it is started again
+func IsRetryableError(err error) bool {
+	var operationErr *ExportOperationError
+	if errors.As(err, &operationErr) {
+		return retryableOperationErrorCodes[operationErr.Code]
+	}
+	return util.IsRetryableAPIError(err)
+}
+
+// ErrorDetails extracts the code and reason of a failed Cloud SQL request or export operation
+func ErrorDetails(err error) (code string, reason string) {
+	var operationErr *ExportOperationError
+	if errors.As(err, &operationErr) {
+		return operationErr.Code, ""
+	}
+	return util.APIErrorDetails(err)
+}
@@ -0,0 +1,70 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"go.opencensus.io/trace"
+)
+
+// ExportJobHandler represent managed exports of a Firestore database into a backup sink
+type ExportJobHandler struct {
+	client AdminClient
+}
+
+// NewExportJobHandler create new instance of ExportJobHandler
+func NewExportJobHandler(ctxIn context.Context, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, targetProjectID string) (*ExportJobHandler, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewExportJobHandler")
+	defer span.End()
+
+	client, err := NewAdminClient(ctx, tokenSourceProvider, targetProjectID)
+	if err != nil {
+		return &ExportJobHandler{}, fmt.Errorf("can not create instance of ExportJobHandler: %s", err)
+	}
+
+	return &ExportJobHandler{client: client}, nil
+}
+
+// NewExportJobHandlerWithClient create new instance of ExportJobHandler for a given AdminClient
+func NewExportJobHandlerWithClient(client AdminClient) *ExportJobHandler {
+	return &ExportJobHandler{client: client}
+}
+
+// Close terminates all resources in use
+func (e *ExportJobHandler) Close(ctxIn context.Context) {
+	e.client.Close(ctxIn)
+}
+
+// CreateExportJob start a managed export of a database into the sink below the path of the job
+func (e *ExportJobHandler) CreateExportJob(ctxIn context.Context, srcProjectID, database string, collectionIDs []string, sink, jobID string) (repository.ExportOperationID, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*ExportJobHandler).CreateExportJob")
+	defer span.End()
+
+	outputURIPrefix := repository.BuildFullFirestoreExportPath(sink, database, jobID)
+	name, err := e.client.ExportDocuments(ctx, srcProjectID, database, collectionIDs, outputURIPrefix)
+	if err != nil {
+		return "", err
+	}
+	return repository.ExportOperationID(name), nil
+}
+
+// GetStatusOfJob return actual status of a managed export
+func (e *ExportJobHandler) GetStatusOfJob(ctxIn context.Context, operationID repository.ExportOperationID) (ExportJobState, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*ExportJobHandler).GetStatusOfJob")
+	defer span.End()
+
+	operation, err := e.client.GetOperation(ctx, operationID.String())
+	if err != nil {
+		return StateUnspecified, err
+	}
+
+	if operation.ErrorMessage != "" {
+		return Failed, &ExportOperationError{OperationID: operationID.String(), Code: operation.ErrorCode, Message: operation.ErrorMessage}
+	}
+	if !operation.Done {
+		return Pending, nil
+	}
+	return Done, nil
+}
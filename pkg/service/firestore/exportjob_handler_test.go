@@ -0,0 +1,63 @@
+package firestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportJobHandler_CreateExportJob(t *testing.T) {
+	client := &MockAdminClient{Databases: map[string]int64{"projects/source-project/databases/(default)": 1024}}
+	handler := NewExportJobHandlerWithClient(client)
+
+	operationID, err := handler.CreateExportJob(context.Background(), "source-project", repository.FirestoreDefaultDatabase, []string{"users", "orders"}, "sink-bucket", "job-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, repository.ExportOperationID("projects/source-project/databases/(default)/operations/export-1"), operationID)
+	require.Len(t, client.ExportRequests, 1)
+	assert.Equal(t, []string{"users", "orders"}, client.ExportRequests[0].CollectionIDs)
+	assert.Equal(t, "gs://sink-bucket/firestore/(default)/job-1", client.ExportRequests[0].OutputURIPrefix)
+}
+
+func TestExportJobHandler_CreateExportJob_UnknownDatabase(t *testing.T) {
+	handler := NewExportJobHandlerWithClient(&MockAdminClient{})
+
+	_, err := handler.CreateExportJob(context.Background(), "source-project", "orders", nil, "sink-bucket", "job-1")
+
+	assert.Error(t, err)
+}
+
+func TestExportJobHandler_GetStatusOfJob(t *testing.T) {
+	client := &MockAdminClient{Databases: map[string]int64{"projects/source-project/databases/(default)": 1024}}
+	handler := NewExportJobHandlerWithClient(client)
+	ctx := context.Background()
+
+	operationID, err := handler.CreateExportJob(ctx, "source-project", repository.FirestoreDefaultDatabase, nil, "sink-bucket", "job-1")
+	require.NoError(t, err)
+
+	state, err := handler.GetStatusOfJob(ctx, operationID)
+	require.NoError(t, err)
+	assert.Equal(t, Pending, state)
+
+	client.Operations[operationID.String()].Done = true
+	state, err = handler.GetStatusOfJob(ctx, operationID)
+	require.NoError(t, err)
+	assert.Equal(t, Done, state)
+
+	client.Operations[operationID.String()].ErrorCode = 7
+	client.Operations[operationID.String()].ErrorMessage = "permission denied on sink-bucket"
+	state, err = handler.GetStatusOfJob(ctx, operationID)
+	assert.Error(t, err)
+	assert.Equal(t, Failed, state)
+	assert.False(t, IsRetryableError(err))
+	code, _ := ErrorDetails(err)
+	assert.Equal(t, "PermissionDenied", code)
+
+	client.Operations[operationID.String()].ErrorCode = 14
+	client.Operations[operationID.String()].ErrorMessage = "the service is currently unavailable"
+	_, err = handler.GetStatusOfJob(ctx, operationID)
+	assert.True(t, IsRetryableError(err))
+}
@@ -0,0 +1,212 @@
+package firestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"go.opencensus.io/trace"
+	firestoreapi "google.golang.org/api/firestore/v1"
+	"google.golang.org/api/googleapi"
+	gimpersonate "google.golang.org/api/impersonate"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AdminClient define operations with the Firestore admin API
+type AdminClient interface {
+	DoesDatabaseExist(ctxIn context.Context, project, database string) (bool, error)
+	GetDatabaseLocation(ctxIn context.Context, project, database string) (string, error)
+	DatabaseUsageInBytes(ctxIn context.Context, project, database string) (float64, error)
+	ExportDocuments(ctxIn context.Context, project, database string, collectionIDs []string, outputURIPrefix string) (string, error)
+	GetOperation(ctxIn context.Context, name string) (*Operation, error)
+	Close(ctxIn context.Context)
+}
+
+// defaultAdminClient defines client to interact with the Firestore admin API
+type defaultAdminClient struct {
+	service      *firestoreapi.Service
+	metricClient *monitoring.MetricClient
+}
+
+// NewAdminClient create new instance of AdminClient impersonating the principal of targetProjectID
+func NewAdminClient(ctxIn context.Context, targetPrincipalProvider impersonate.TargetPrincipalForProjectProvider, targetProjectID string) (AdminClient, error) {
+	ctx, span := trace.StartSpan(ctxIn, "NewAdminClient")
+	defer span.End()
+
+	target, delegates, err := targetPrincipalProvider.GetTargetPrincipalForProject(ctx, targetProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var options []option.ClientOption
+	if config.UseDefaultHttpClient.GetBoolOrDefault(false) {
+		options = []option.ClientOption{
+			option.WithHTTPClient(http.DefaultClient),
+		}
+	} else {
+		tokenSource, err := gimpersonate.CredentialsTokenSource(ctx, gimpersonate.CredentialsConfig{
+			TargetPrincipal: target,
+			Scopes:          []string{cloudPlatformAPIScope, datastoreAPIScope},
+			Delegates:       delegates,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		options = []option.ClientOption{
+			option.WithTokenSource(tokenSource),
+		}
+	}
+	service, err := firestoreapi.NewService(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create firestore.Service: %v", err)
+	}
+
+	var monitoringOptions []option.ClientOption
+	if config.UseGrpcWithoutAuthentication.GetBoolOrDefault(false) {
+		monitoringOptions = []option.ClientOption{
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		}
+	} else {
+		tokenSource, err := gimpersonate.CredentialsTokenSource(ctx, gimpersonate.CredentialsConfig{
+			TargetPrincipal: target,
+			Scopes:          []string{cloudPlatformAPIScope, metricAPIScope},
+			Delegates:       delegates,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		monitoringOptions = []option.ClientOption{
+			option.WithTokenSource(tokenSource),
+		}
+	}
+	metricClient, err := monitoring.NewMetricClient(ctx, monitoringOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create monitoring.MetricClient: %v", err)
+	}
+
+	return &defaultAdminClient{service: service, metricClient: metricClient}, nil
+}
+
+// Close terminates all resources in use
+func (c *defaultAdminClient) Close(ctxIn context.Context) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).Close")
+	defer span.End()
+
+	c.metricClient.Close()
+}
+
+// DoesDatabaseExist check if the Firestore database exists in the project
+func (c *defaultAdminClient) DoesDatabaseExist(ctxIn context.Context, project, database string) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).DoesDatabaseExist")
+	defer span.End()
+
+	_, err := c.service.Projects.Databases.Get(databaseName(project, database)).Context(ctx).Do()
+	var googleAPIErr *googleapi.Error
+	if errors.As(err, &googleAPIErr) && googleAPIErr.Code == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error getting firestore database %s: %s", databaseName(project, database), err)
+	}
+	return true, nil
+}
+
+// GetDatabaseLocation return the location id of the database, e.g. eur3 or europe-west1
+func (c *defaultAdminClient) GetDatabaseLocation(ctxIn context.Context, project, database string) (string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).GetDatabaseLocation")
+	defer span.End()
+
+	details, err := c.service.Projects.Databases.Get(databaseName(project, database)).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("error getting firestore database %s: %s", databaseName(project, database), err)
+	}
+	return details.LocationId, nil
+}
+
+// DatabaseUsageInBytes report how many data and index bytes are stored in the database
+func (c *defaultAdminClient) DatabaseUsageInBytes(ctxIn context.Context, project, database string) (totalSize float64, err error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).DatabaseUsageInBytes")
+	defer span.End()
+
+	startTime := time.Now().UTC().Add(time.Hour * -25) // storage metrics of firestore are written once a day
+	endTime := time.Now().UTC()
+	req := &monitoringpb.ListTimeSeriesRequest{
+		Name:   "projects/" + project,
+		Filter: fmt.Sprintf(`metric.type="firestore.googleapis.com/storage/data_and_index_storage_bytes" resource.type="firestore.googleapis.com/Database" resource.label.database_id="%s"`, database),
+		Interval: &monitoringpb.TimeInterval{
+			StartTime: &timestamppb.Timestamp{
+				Seconds: startTime.Unix(),
+			},
+			EndTime: &timestamppb.Timestamp{
+				Seconds: endTime.Unix(),
+			},
+		},
+		View: monitoringpb.ListTimeSeriesRequest_FULL,
+	}
+	it := c.metricClient.ListTimeSeries(ctx, req)
+	for {
+		resp, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return totalSize, fmt.Errorf("ListTimeSeries failed: %s", err)
+		}
+		// points are returned in reverse time order, only the latest one is relevant
+		for _, point := range resp.GetPoints() {
+			totalSize += float64(point.GetValue().GetInt64Value()) + point.GetValue().GetDoubleValue()
+			break
+		}
+	}
+	return totalSize, nil
+}
+
+// ExportDocuments start a managed export of the database into outputURIPrefix and return the name of the long-running operation
+func (c *defaultAdminClient) ExportDocuments(ctxIn context.Context, project, database string, collectionIDs []string, outputURIPrefix string) (string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).ExportDocuments")
+	defer span.End()
+
+	operation, err := c.service.Projects.Databases.ExportDocuments(databaseName(project, database), &firestoreapi.GoogleFirestoreAdminV1ExportDocumentsRequest{
+		CollectionIds:   collectionIDs,
+		OutputUriPrefix: outputURIPrefix,
+	}).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("error starting export of firestore database %s: %w", databaseName(project, database), err)
+	}
+	return operation.Name, nil
+}
+
+// GetOperation return the actual state of a long-running operation
+func (c *defaultAdminClient) GetOperation(ctxIn context.Context, name string) (*Operation, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultAdminClient).GetOperation")
+	defer span.End()
+
+	operation, err := c.service.Projects.Databases.Operations.Get(name).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("error getting firestore operation %s: %w", name, err)
+	}
+
+	result := &Operation{Name: operation.Name, Done: operation.Done}
+	if operation.Error != nil {
+		result.ErrorCode = operation.Error.Code
+		result.ErrorMessage = operation.Error.Message
+	}
+	return result, nil
+}
+
+func databaseName(project, database string) string {
+	return fmt.Sprintf("projects/%s/databases/%s", project, database)
+}
@@ -0,0 +1,78 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+)
+
+// MockAdminClient is a fake Firestore admin API keeping the started exports in memory
+type MockAdminClient struct {
+	Databases      map[string]int64
+	Operations     map[string]*Operation
+	ExportRequests []MockExportRequest
+	Location       string
+	ShouldFail     bool
+}
+
+// MockExportRequest records the arguments of an export started with MockAdminClient
+type MockExportRequest struct {
+	Project         string
+	Database        string
+	CollectionIDs   []string
+	OutputURIPrefix string
+}
+
+func (c *MockAdminClient) DoesDatabaseExist(ctxIn context.Context, project, database string) (bool, error) {
+	if c.ShouldFail {
+		return false, fmt.Errorf("mock error")
+	}
+	_, exists := c.Databases[databaseName(project, database)]
+	return exists, nil
+}
+
+func (c *MockAdminClient) GetDatabaseLocation(ctxIn context.Context, project, database string) (string, error) {
+	if c.ShouldFail {
+		return "", fmt.Errorf("mock error")
+	}
+	if _, exists := c.Databases[databaseName(project, database)]; !exists {
+		return "", fmt.Errorf("database %s not found", databaseName(project, database))
+	}
+	return c.Location, nil
+}
+
+func (c *MockAdminClient) DatabaseUsageInBytes(ctxIn context.Context, project, database string) (float64, error) {
+	if c.ShouldFail {
+		return 0, fmt.Errorf("mock error")
+	}
+	return float64(c.Databases[databaseName(project, database)]), nil
+}
+
+func (c *MockAdminClient) ExportDocuments(ctxIn context.Context, project, database string, collectionIDs []string, outputURIPrefix string) (string, error) {
+	if c.ShouldFail {
+		return "", fmt.Errorf("mock error")
+	}
+	if _, exists := c.Databases[databaseName(project, database)]; !exists {
+		return "", fmt.Errorf("database %s not found", databaseName(project, database))
+	}
+	c.ExportRequests = append(c.ExportRequests, MockExportRequest{Project: project, Database: database, CollectionIDs: collectionIDs, OutputURIPrefix: outputURIPrefix})
+	name := fmt.Sprintf("%s/operations/export-%d", databaseName(project, database), len(c.ExportRequests))
+	if c.Operations == nil {
+		c.Operations = map[string]*Operation{}
+	}
+	c.Operations[name] = &Operation{Name: name}
+	return name, nil
+}
+
+func (c *MockAdminClient) GetOperation(ctxIn context.Context, name string) (*Operation, error) {
+	if c.ShouldFail {
+		return nil, fmt.Errorf("mock error")
+	}
+	operation, exists := c.Operations[name]
+	if !exists {
+		return nil, fmt.Errorf("operation %s not found", name)
+	}
+	return operation, nil
+}
+
+func (c *MockAdminClient) Close(ctxIn context.Context) {
+}
@@ -0,0 +1,65 @@
+package firestore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ottogroup/penelope/pkg/service/util"
+)
+
+const (
+	datastoreAPIScope     = "https://www.googleapis.com/auth/datastore"
+	metricAPIScope        = "https://www.googleapis.com/auth/monitoring.read"
+	cloudPlatformAPIScope = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// ExportJobState State is one of a sequence of states that a managed export progresses through as it is processed.
+type ExportJobState string
+
+const (
+	// StateUnspecified is the default export state.
+	StateUnspecified ExportJobState = "Unspecified"
+	// Pending is a state that describes that the export operation is still running.
+	Pending ExportJobState = "Pending"
+	// Done is a state that describes that the export operation is done.
+	Done ExportJobState = "Done"
+	// Failed is a state that describes that the export operation complete unsuccessfully.
+	Failed ExportJobState = "Failed"
+)
+
+// Operation is the state of a long-running Firestore admin operation, ErrorCode is a google.rpc.Code
+type Operation struct {
+	Name         string
+	Done         bool
+	ErrorCode    int64
+	ErrorMessage string
+}
+
+// ExportOperationError is the error of a failed export operation, Code is a google.rpc.Code
+type ExportOperationError struct {
+	OperationID string
+	Code        int64
+	Message     string
+}
+
+func (e *ExportOperationError) Error() string {
+	return fmt.Sprintf("export operation %s finished in failed state: %s", e.OperationID, e.Message)
+}
+
+// IsRetryableError checks if a failed Firestore request or export operation might succeed when it is started again
+func IsRetryableError(err error) bool {
+	var operationErr *ExportOperationError
+	if errors.As(err, &operationErr) {
+		return util.IsRetryableRPCCode(operationErr.Code)
+	}
+	return util.IsRetryableAPIError(err)
+}
+
+// ErrorDetails extracts the code and reason of a failed Firestore request or export operation
+func ErrorDetails(err error) (code string, reason string) {
+	var operationErr *ExportOperationError
+	if errors.As(err, &operationErr) {
+		return util.RPCCodeName(operationErr.Code), ""
+	}
+	return util.APIErrorDetails(err)
+}
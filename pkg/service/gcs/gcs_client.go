@@ -61,10 +61,14 @@ func NewLabels(backupType string, id string, project string) LabelsProvider {
 	return labels{backupType: backupType, backupID: id, backupSourceProject: project}
 }
 
+// CloudStorageBucket describe a bucket to create, KMSKeyName is the default encryption key of its objects if set
+// and objects can not be deleted or replaced before they are RetentionInDays old if it is greater than zero
 type CloudStorageBucket struct {
 	Project, Bucket, Location, DualLocation, StorageClass string
 	LifetimeInDays, ArchiveTTM                            uint
 	Labels                                                LabelsProvider
+	KMSKeyName                                            string
+	RetentionInDays                                       uint
 }
 
 // CloudStorageClient define operations with the GCS
@@ -74,6 +78,7 @@ type CloudStorageClient interface {
 	BucketUsageInBytes(ctxIn context.Context, project string, bucket string) (float64, error)
 	CreateBucket(ctxIn context.Context, bucket CloudStorageBucket) error
 	GetProject(ctxIn context.Context, projectID string) (*resourcemanagerpb.Project, error)
+	GetBucketIAMPolicy(ctxIn context.Context, bucket string) (*iam.Policy, error)
 	SetBucketIAMPolicy(ctxIn context.Context, bucket string, policy *iam.Policy) error
 	CreateObject(ctxIn context.Context, bucketName, objectName, content string) error
 	DeleteBucket(ctxIn context.Context, bucket string) error
@@ -85,7 +90,14 @@ type CloudStorageClient interface {
 	Close(ctxIn context.Context)
 	UpdateBucket(ctxIn context.Context, bucket string, lifetimeInDays uint, archiveTTM uint, labels LabelsProvider) error
 	GetBucketDetails(ctxIn context.Context, bucket string) (*storage.BucketAttrs, error)
+	SetBucketDefaultKMSKey(ctxIn context.Context, bucket string, kmsKeyName string) error
+	SetBucketRetentionPolicy(ctxIn context.Context, bucket string, retentionInDays uint) error
+	LockBucketRetentionPolicy(ctxIn context.Context, bucket string) error
+	GetRetentionExpirationTime(ctxIn context.Context, bucket string) (time.Time, error)
 	DeleteObjectWithPrefix(ctxIn context.Context, bucket string, objectPrefixName string) error
+	HasObjectWithPrefix(ctxIn context.Context, bucket string, objectPrefixName string) (bool, error)
+	CountObjectsWithPrefix(ctxIn context.Context, bucket string, objectPrefixName string) (int64, error)
+	ListPrefixes(ctxIn context.Context, bucket string, objectPrefixName string) ([]string, error)
 }
 
 // CloudStorageClientFactory creates a CloudStorageClient with the credentails for a specified project
@@ -121,6 +133,72 @@ func (c *defaultGcsClient) DeleteObjectWithPrefix(ctxIn context.Context, bucket
 	return nil
 }
 
+// HasObjectWithPrefix check if at least one object with the prefix exists in the bucket
+func (c *defaultGcsClient) HasObjectWithPrefix(ctxIn context.Context, bucket string, objectPrefixName string) (bool, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).HasObjectWithPrefix")
+	defer span.End()
+
+	objectIterator := c.client.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: objectPrefixName})
+	_, err := objectIterator.Next()
+	if errors.Is(err, iterator.Done) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, fmt.Sprintf("HasObjectWithPrefix failed for bucket %s, prefix %s", bucket, objectPrefixName))
+	}
+
+	return true, nil
+}
+
+// CountObjectsWithPrefix count the objects with the prefix in the bucket
+func (c *defaultGcsClient) CountObjectsWithPrefix(ctxIn context.Context, bucket string, objectPrefixName string) (int64, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).CountObjectsWithPrefix")
+	defer span.End()
+
+	query := &storage.Query{Prefix: objectPrefixName}
+	if err := query.SetAttrSelection([]string{"Name"}); err != nil {
+		return 0, errors.Wrap(err, fmt.Sprintf("CountObjectsWithPrefix failed for bucket %s, prefix %s", bucket, objectPrefixName))
+	}
+
+	var count int64
+	objectIterator := c.client.Bucket(bucket).Objects(ctx, query)
+	for {
+		_, err := objectIterator.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return 0, errors.Wrap(err, fmt.Sprintf("CountObjectsWithPrefix failed for bucket %s, prefix %s", bucket, objectPrefixName))
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// ListPrefixes list the "directories" directly below the prefix in the bucket
+func (c *defaultGcsClient) ListPrefixes(ctxIn context.Context, bucket string, objectPrefixName string) ([]string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).ListPrefixes")
+	defer span.End()
+
+	var prefixes []string
+	objectIterator := c.client.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: objectPrefixName, Delimiter: "/"})
+	for {
+		objAttrs, err := objectIterator.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("ListPrefixes failed for bucket %s, prefix %s", bucket, objectPrefixName))
+		}
+		if objAttrs.Prefix != "" {
+			prefixes = append(prefixes, objAttrs.Prefix)
+		}
+	}
+
+	return prefixes, nil
+}
+
 func (c *defaultGcsClient) GetProject(ctxIn context.Context, projectID string) (*resourcemanagerpb.Project, error) {
 	_, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).GetProject")
 	defer span.End()
@@ -270,6 +348,13 @@ func createImpersonatedCloudStorageClient(ctxIn context.Context, targetPrincipal
 	return &defaultGcsClient{client: client, metricClient: metricClient, projectClient: projectsClient}, nil
 }
 
+func (c *defaultGcsClient) GetBucketIAMPolicy(ctxIn context.Context, bucket string) (*iam.Policy, error) {
+	_, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).GetBucketIAMPolicy")
+	defer span.End()
+
+	return c.client.Bucket(bucket).IAM().Policy(ctxIn)
+}
+
 func (c *defaultGcsClient) SetBucketIAMPolicy(ctxIn context.Context, bucket string, policy *iam.Policy) error {
 	_, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).SetBucketIAMPolicy")
 	defer span.End()
@@ -506,6 +591,14 @@ func (c *defaultGcsClient) CreateBucket(ctxIn context.Context, bucket CloudStora
 		bucketAttrs.Lifecycle.Rules = append(bucketAttrs.Lifecycle.Rules, ruleTTL)
 	}
 
+	if bucket.KMSKeyName != "" {
+		bucketAttrs.Encryption = &storage.BucketEncryption{DefaultKMSKeyName: bucket.KMSKeyName}
+	}
+
+	if bucket.RetentionInDays > 0 {
+		bucketAttrs.RetentionPolicy = &storage.RetentionPolicy{RetentionPeriod: RetentionPeriod(bucket.RetentionInDays)}
+	}
+
 	err := c.client.Bucket(bucket.Bucket).Create(ctx, bucket.Project, &bucketAttrs)
 	if err != nil {
 		return err
@@ -534,7 +627,7 @@ func (c *defaultGcsClient) ReadObject(ctxIn context.Context, bucketName, objectN
 
 	rc, err := c.client.Bucket(bucketName).Object(objectName).ReadCompressed(true).NewReader(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to open file from bucket %q, file %q: %v", bucketName, objectName, err)
+		return nil, fmt.Errorf("unable to open file from bucket %q, file %q: %w", bucketName, objectName, err)
 	}
 
 	slurp, err := io.ReadAll(rc)
@@ -636,6 +729,85 @@ func (c *defaultGcsClient) GetBuckets(ctxIn context.Context, project string) (bu
 	return buckets, err
 }
 
+// SetBucketDefaultKMSKey set the key new objects of the bucket are encrypted with, existing objects keep their key
+func (c *defaultGcsClient) SetBucketDefaultKMSKey(ctxIn context.Context, bucket string, kmsKeyName string) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).SetBucketDefaultKMSKey")
+	defer span.End()
+
+	_, err := c.client.Bucket(bucket).Update(ctx, storage.BucketAttrsToUpdate{
+		Encryption: &storage.BucketEncryption{DefaultKMSKeyName: kmsKeyName},
+	})
+	if err != nil {
+		return fmt.Errorf("could not set default KMS key of bucket %s: %s", bucket, err)
+	}
+
+	return nil
+}
+
+// SetBucketRetentionPolicy set the minimum age objects of the bucket must have before they can be deleted or replaced,
+// the period of a locked retention policy can only be increased
+func (c *defaultGcsClient) SetBucketRetentionPolicy(ctxIn context.Context, bucket string, retentionInDays uint) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).SetBucketRetentionPolicy")
+	defer span.End()
+
+	_, err := c.client.Bucket(bucket).Update(ctx, storage.BucketAttrsToUpdate{
+		RetentionPolicy: &storage.RetentionPolicy{RetentionPeriod: RetentionPeriod(retentionInDays)},
+	})
+	if err != nil {
+		return fmt.Errorf("could not set retention policy of bucket %s: %s", bucket, err)
+	}
+
+	return nil
+}
+
+// LockBucketRetentionPolicy lock the retention policy of the bucket, this is irreversible and the bucket can not be
+// deleted before all its objects reached the retention period
+func (c *defaultGcsClient) LockBucketRetentionPolicy(ctxIn context.Context, bucket string) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).LockBucketRetentionPolicy")
+	defer span.End()
+
+	attrs, err := c.client.Bucket(bucket).Attrs(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get attributes of bucket %s: %s", bucket, err)
+	}
+
+	err = c.client.Bucket(bucket).If(storage.BucketConditions{MetagenerationMatch: attrs.MetaGeneration}).LockRetentionPolicy(ctx)
+	if err != nil {
+		return fmt.Errorf("could not lock retention policy of bucket %s: %s", bucket, err)
+	}
+
+	return nil
+}
+
+// GetRetentionExpirationTime get the time when the last object of the bucket reaches the retention period, the
+// zero time is returned if no object of the bucket is retained
+func (c *defaultGcsClient) GetRetentionExpirationTime(ctxIn context.Context, bucket string) (time.Time, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).GetRetentionExpirationTime")
+	defer span.End()
+
+	var expiration time.Time
+	objectsIterator := c.client.Bucket(bucket).Objects(ctx, &storage.Query{})
+	for {
+		objAttr, err := objectsIterator.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("could not list objects of bucket %s: %s", bucket, err)
+		}
+		if objAttr.RetentionExpirationTime.After(expiration) {
+			expiration = objAttr.RetentionExpirationTime
+		}
+	}
+
+	return expiration, nil
+}
+
+// RetentionPeriod converts a retention in days into the period of a bucket retention policy
+func RetentionPeriod(retentionInDays uint) time.Duration {
+	return time.Duration(retentionInDays) * 24 * time.Hour
+}
+
 // GetBucketDetails get details of a bucket
 func (c *defaultGcsClient) GetBucketDetails(ctxIn context.Context, bucket string) (*storage.BucketAttrs, error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*defaultGcsClient).GetBucketDetails")
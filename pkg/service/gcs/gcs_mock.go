@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"time"
 
 	"cloud.google.com/go/iam"
 	"cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
@@ -21,10 +22,26 @@ func (c *MockGcsClient) DeleteObjectWithPrefix(ctxIn context.Context, bucket str
 	panic("implement me")
 }
 
+func (c *MockGcsClient) HasObjectWithPrefix(ctxIn context.Context, bucket string, objectPrefixName string) (bool, error) {
+	panic("implement me")
+}
+
+func (c *MockGcsClient) CountObjectsWithPrefix(ctxIn context.Context, bucket string, objectPrefixName string) (int64, error) {
+	panic("implement me")
+}
+
+func (c *MockGcsClient) ListPrefixes(ctxIn context.Context, bucket string, objectPrefixName string) ([]string, error) {
+	panic("implement me")
+}
+
 func (c *MockGcsClient) GetProject(ctxIn context.Context, projectID string) (*resourcemanagerpb.Project, error) {
 	panic("implement me")
 }
 
+func (c *MockGcsClient) GetBucketIAMPolicy(ctxIn context.Context, bucket string) (*iam.Policy, error) {
+	panic("implement me")
+}
+
 func (c *MockGcsClient) SetBucketIAMPolicy(ctxIn context.Context, bucket string, policy *iam.Policy) error {
 	panic("implement me")
 }
@@ -71,10 +88,26 @@ func (c *MockGcsClient) DeleteBucket(ctxIn context.Context, bucket string) error
 	panic("implement me")
 }
 
+func (c *MockGcsClient) GetRetentionExpirationTime(ctxIn context.Context, bucket string) (time.Time, error) {
+	panic("implement me")
+}
+
 func (c *MockGcsClient) GetBucketDetails(ctxIn context.Context, bucket string) (*storage.BucketAttrs, error) {
 	panic("implement me")
 }
 
+func (c *MockGcsClient) SetBucketDefaultKMSKey(ctxIn context.Context, bucket string, kmsKeyName string) error {
+	panic("implement me")
+}
+
+func (c *MockGcsClient) SetBucketRetentionPolicy(ctxIn context.Context, bucket string, retentionInDays uint) error {
+	panic("implement me")
+}
+
+func (c *MockGcsClient) LockBucketRetentionPolicy(ctxIn context.Context, bucket string) error {
+	panic("implement me")
+}
+
 func (c *MockGcsClient) IsInitialized(ctxIn context.Context) bool {
 	return c.ClientInitialized
 }
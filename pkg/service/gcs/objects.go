@@ -1,5 +1,12 @@
 package gcs
 
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ottogroup/penelope/pkg/service/util"
+)
+
 const (
 	defaultAPIScope       = "https://www.googleapis.com/auth/devstorage.full_control"
 	metricAPIScope        = "https://www.googleapis.com/auth/monitoring.read"
@@ -21,3 +28,50 @@ const (
 	// Failed is a state that describes that the job complete unsuccessfully.
 	Failed TransferJobState = "Failed"
 )
+
+// TransferOperationError is the error of a failed transfer operation, Code is a google.rpc.Code
+type TransferOperationError struct {
+	Code    int64
+	Message string
+}
+
+func (e *TransferOperationError) Error() string {
+	return fmt.Sprintf("transfer operation finished in failed state: %s", e.Message)
+}
+
+// IsRetryableError checks if a failed Storage Transfer request or operation might succeed when it is started again
+func IsRetryableError(err error) bool {
+	var operationErr *TransferOperationError
+	if errors.As(err, &operationErr) {
+		return util.IsRetryableRPCCode(operationErr.Code)
+	}
+	return util.IsRetryableAPIError(err)
+}
+
+// ErrorDetails extracts the code and reason of a failed Storage Transfer request or operation
+func ErrorDetails(err error) (code string, reason string) {
+	var operationErr *TransferOperationError
+	if errors.As(err, &operationErr) {
+		return util.RPCCodeName(operationErr.Code), ""
+	}
+	return util.APIErrorDetails(err)
+}
+
+// OverwriteMode defines when a transfer replaces objects which already exist in the sink bucket
+type OverwriteMode string
+
+const (
+	// OverwriteDifferent replaces existing objects with a different content, this is the transfer default.
+	OverwriteDifferent OverwriteMode = "DIFFERENT"
+	// OverwriteNever keeps existing objects untouched.
+	OverwriteNever OverwriteMode = "NEVER"
+	// OverwriteAlways replaces existing objects.
+	OverwriteAlways OverwriteMode = "ALWAYS"
+)
+
+// RestoreTransferOptions defines how a restore transfer treats the objects which already exist in the target bucket
+// DeleteUniqueInTarget deletes target objects below the include prefixes which are not in the restored source
+type RestoreTransferOptions struct {
+	Overwrite            OverwriteMode
+	DeleteUniqueInTarget bool
+}
@@ -21,6 +21,8 @@ import (
 type TransferJobHandler struct {
 	client                  CloudStorageClient
 	targetPrincipalProvider impersonate.TargetPrincipalForProjectProvider
+	// service is used instead of an impersonated Storage Transfer client if set
+	service *storagetransfer.Service
 }
 
 // NewTransferJobHandler create new TransferJobHandler
@@ -45,6 +47,10 @@ func (t *TransferJobHandler) createClient(ctxIn context.Context, targetProjectID
 	ctx, span := trace.StartSpan(ctxIn, "(*TransferJobHandler).createClient")
 	defer span.End()
 
+	if t.service != nil {
+		return t.service, nil
+	}
+
 	var options []option.ClientOption
 
 	target, delegates, err := t.targetPrincipalProvider.GetTargetPrincipalForProject(ctx, targetProjectID)
@@ -87,7 +93,7 @@ func (t *TransferJobHandler) ReuseTransferJob(ctxIn context.Context, srcProjectI
 	// Check if the job for reuse exists.
 	reusableJob, err := storageTransferService.TransferJobs.Get(transferJobID.String(), targetProjectID).Do()
 	if err != nil {
-		return "", fmt.Errorf("error reusing transfer job: %s", err)
+		return "", fmt.Errorf("error reusing transfer job: %w", err)
 	}
 	// If the projectID does not match, it is not possible to patch the job, because changing the projectID is not allowed.
 	if reusableJob.ProjectId != targetProjectID {
@@ -104,7 +110,7 @@ func (t *TransferJobHandler) ReuseTransferJob(ctxIn context.Context, srcProjectI
 	}).Context(ctx).Do()
 
 	if err != nil {
-		return "", fmt.Errorf("error reusing transfer job: %s", err)
+		return "", fmt.Errorf("error reusing transfer job: %w", err)
 	}
 
 	_, err = storageTransferService.TransferJobs.Run(transferJobID.String(), &storagetransfer.RunTransferJobRequest{
@@ -112,7 +118,7 @@ func (t *TransferJobHandler) ReuseTransferJob(ctxIn context.Context, srcProjectI
 	}).Context(ctx).Do()
 
 	if err != nil {
-		return "", fmt.Errorf("error starting run for re-used transfer job: %s", err)
+		return "", fmt.Errorf("error starting run for re-used transfer job: %w", err)
 	}
 
 	return resp.Name, nil
@@ -132,12 +138,47 @@ func (t *TransferJobHandler) CreateTransferJob(ctxIn context.Context, srcProject
 
 	resp, err := storageTransferService.TransferJobs.Create(rb).Context(ctx).Do()
 	if err != nil {
-		return "", fmt.Errorf("error creation transfer job: %s", err)
+		return "", fmt.Errorf("error creation transfer job: %w", err)
 	}
 
 	return resp.Name, nil
 }
 
+// CreateRestoreTransferJob create new transfer job copying objects below sourcePath of the sink bucket back into targetBucket below targetPath
+// the job runs in the sink project, include and exclude prefixes are relative to sourcePath
+func (t *TransferJobHandler) CreateRestoreTransferJob(ctxIn context.Context, sinkProjectID, sinkBucket, sourcePath, targetProjectID, targetBucket, targetPath string, includePath, excludePath []string, options RestoreTransferOptions) (string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*TransferJobHandler).CreateRestoreTransferJob")
+	defer span.End()
+
+	storageTransferService, err := t.createClient(ctx, sinkProjectID)
+	if err != nil {
+		return "", fmt.Errorf("failed to create new oauth2 client: %s", err)
+	}
+
+	rb := newRestoreTransferJobObject(sinkProjectID, sinkBucket, sourcePath, targetProjectID, targetBucket, targetPath, includePath, excludePath, options)
+
+	resp, err := storageTransferService.TransferJobs.Create(rb).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("error creation restore transfer job: %w", err)
+	}
+
+	return resp.Name, nil
+}
+
+func newRestoreTransferJobObject(sinkProjectID, sinkBucket, sourcePath, targetProjectID, targetBucket, targetPath string, includePath []string, excludePath []string, options RestoreTransferOptions) *storagetransfer.TransferJob {
+	appProjectID := config.GCPProjectId.GetOrDefault("")
+
+	rb := newTransferJobObject(sinkProjectID, sinkBucket, sinkProjectID, targetBucket, includePath, excludePath)
+	rb.Description = fmt.Sprintf("Job to restore %s:%s/%s to %s:%s/%s. Triggered by BackupApp in project %s", sinkProjectID, sinkBucket, sourcePath, targetProjectID, targetBucket, targetPath, appProjectID)
+	rb.TransferSpec.GcsDataSource.Path = sourcePath
+	rb.TransferSpec.GcsDataSink.Path = targetPath
+	rb.TransferSpec.TransferOptions = &storagetransfer.TransferOptions{
+		OverwriteWhen:             string(options.Overwrite),
+		DeleteObjectsUniqueInSink: options.DeleteUniqueInTarget,
+	}
+	return rb
+}
+
 func newTransferJobObject(srcProjectID string, srcBucket string, targetProjectID string, targetBucket string, includePath []string, excludePath []string) *storagetransfer.TransferJob {
 	appProjectID := config.GCPProjectId.GetOrDefault("")
 	description := fmt.Sprintf("Job to transfer %s:%s to %s:%s. Triggered by BackupApp in project %s", srcProjectID, srcBucket, targetProjectID, targetBucket, appProjectID)
@@ -186,17 +227,26 @@ func (t *TransferJobHandler) GetStatusOfJob(ctxIn context.Context, targetProject
 	fields := []googleapi.Field{"operations.done", "operations.response", "operations.error"}
 	operations, err := storageTransferService.TransferOperations.List("transferOperations", filterValue).Fields(fields...).Do()
 	if err != nil {
-		return StateUnspecified, fmt.Errorf("error listing transfer operations: %s", err)
+		return StateUnspecified, fmt.Errorf("error listing transfer operations: %w", err)
 	}
 
 	if operations == nil || reflect.ValueOf(operations).IsNil() {
 		return Pending, nil
 	}
 
-	for _, operation := range operations.Operations {
+	return stateOfOperations(operations.Operations)
+}
+
+// stateOfOperations derive the state of a transfer job from its operations, a job without operations has not started yet
+func stateOfOperations(operations []*storagetransfer.Operation) (TransferJobState, error) {
+	if len(operations) == 0 {
+		return Pending, nil
+	}
+
+	for _, operation := range operations {
 		if !operation.Done {
 			if operation.Error != nil && operation.Error.Message != "" {
-				return Failed, fmt.Errorf("transfer operation finished in failed state: %s", operation.Error.Message)
+				return Failed, &TransferOperationError{Code: operation.Error.Code, Message: operation.Error.Message}
 			}
 			return Pending, nil
 		}
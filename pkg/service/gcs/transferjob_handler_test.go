@@ -0,0 +1,87 @@
+package gcs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/api/storagetransfer/v1"
+)
+
+func TestNewRestoreTransferJobObject(t *testing.T) {
+	job := newRestoreTransferJobObject("sink-project", "sink-bucket", ".trashcan_backup-1/", "source-project", "source-bucket", "", []string{"data/"}, []string{"data/tmp/"}, RestoreTransferOptions{Overwrite: OverwriteNever})
+
+	assert.Equal(t, "sink-project", job.ProjectId)
+	assert.Equal(t, "sink-bucket", job.TransferSpec.GcsDataSource.BucketName)
+	assert.Equal(t, ".trashcan_backup-1/", job.TransferSpec.GcsDataSource.Path)
+	assert.Equal(t, "source-bucket", job.TransferSpec.GcsDataSink.BucketName)
+	assert.Empty(t, job.TransferSpec.GcsDataSink.Path)
+	assert.Equal(t, []string{"data/"}, job.TransferSpec.ObjectConditions.IncludePrefixes)
+	assert.Equal(t, []string{"data/tmp/"}, job.TransferSpec.ObjectConditions.ExcludePrefixes)
+	assert.Equal(t, "NEVER", job.TransferSpec.TransferOptions.OverwriteWhen)
+	assert.False(t, job.TransferSpec.TransferOptions.DeleteObjectsUniqueInSink)
+	assert.Contains(t, job.Description, "Job to restore sink-project:sink-bucket/.trashcan_backup-1/ to source-project:source-bucket")
+}
+
+func TestNewRestoreTransferJobObject_WithTargetPath(t *testing.T) {
+	job := newRestoreTransferJobObject("sink-project", "sink-bucket", "data/", "drill-project", "drill-bucket", "drill_1/", nil, nil, RestoreTransferOptions{Overwrite: OverwriteAlways, DeleteUniqueInTarget: true})
+
+	assert.Equal(t, "data/", job.TransferSpec.GcsDataSource.Path)
+	assert.Equal(t, "drill-bucket", job.TransferSpec.GcsDataSink.BucketName)
+	assert.Equal(t, "drill_1/", job.TransferSpec.GcsDataSink.Path)
+	assert.Equal(t, "ALWAYS", job.TransferSpec.TransferOptions.OverwriteWhen)
+	assert.True(t, job.TransferSpec.TransferOptions.DeleteObjectsUniqueInSink)
+	assert.Contains(t, job.Description, "to drill-project:drill-bucket/drill_1/")
+}
+
+func TestStateOfOperations(t *testing.T) {
+	state, err := stateOfOperations(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Pending, state, "a job without operations has not started yet")
+
+	state, err = stateOfOperations([]*storagetransfer.Operation{{Done: true}, {Done: false}})
+	assert.NoError(t, err)
+	assert.Equal(t, Pending, state)
+
+	state, err = stateOfOperations([]*storagetransfer.Operation{{Done: false, Error: &storagetransfer.Status{Code: 7, Message: "permission denied"}}})
+	assert.Error(t, err)
+	assert.Equal(t, Failed, state)
+
+	state, err = stateOfOperations([]*storagetransfer.Operation{{Done: true}, {Done: true}})
+	assert.NoError(t, err)
+	assert.Equal(t, Done, state)
+}
+
+func TestTransferJobHandler_UnavailableServiceIsRetryable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error": {"code": 503, "message": "The service is currently unavailable.", "errors": [{"reason": "backendError"}]}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	service, err := storagetransfer.NewService(ctx, option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()), option.WithoutAuthentication())
+	require.NoError(t, err)
+	handler := &TransferJobHandler{service: service}
+
+	_, err = handler.CreateTransferJob(ctx, "source-project", "sink-project", "source-bucket", "sink-bucket", nil, nil)
+	require.Error(t, err)
+	assert.True(t, IsRetryableError(err))
+	code, reason := ErrorDetails(err)
+	assert.Equal(t, "503", code)
+	assert.Equal(t, "backendError", reason)
+
+	_, err = handler.ReuseTransferJob(ctx, "source-project", "sink-project", "source-bucket", "sink-bucket", nil, nil, "transferJobs/123")
+	assert.True(t, IsRetryableError(err))
+
+	_, err = handler.CreateRestoreTransferJob(ctx, "sink-project", "sink-bucket", "", "source-project", "source-bucket", "", nil, nil, RestoreTransferOptions{})
+	assert.True(t, IsRetryableError(err))
+
+	_, err = handler.GetStatusOfJob(ctx, "sink-project", "transferJobs/123")
+	assert.True(t, IsRetryableError(err))
+}
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var retryableHTTPStatusCodes = map[int]bool{
+	http.StatusRequestTimeout:      true,
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+var retryableGRPCCodes = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.DeadlineExceeded:  true,
+	codes.Internal:          true,
+	codes.ResourceExhausted: true,
+	codes.Aborted:           true,
+}
+
+// IsRetryableAPIError checks if a Google API call failed temporarily, e.g. by a timeout, rate limit or backend error
+func IsRetryableAPIError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var googleAPIErr *googleapi.Error
+	if errors.As(err, &googleAPIErr) {
+		return retryableHTTPStatusCodes[googleAPIErr.Code]
+	}
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		return retryableGRPCCodes[s.Code()]
+	}
+	return false
+}
+
+// IsRetryableRPCCode checks if a google.rpc.Code reported by a long-running operation is temporary
+func IsRetryableRPCCode(code int64) bool {
+	return retryableGRPCCodes[codes.Code(code)]
+}
+
+// APIErrorDetails extracts the status code and reason of a failed Google API call, both are empty if unknown
+func APIErrorDetails(err error) (code string, reason string) {
+	if err == nil {
+		return "", ""
+	}
+	var googleAPIErr *googleapi.Error
+	if errors.As(err, &googleAPIErr) {
+		if len(googleAPIErr.Errors) > 0 {
+			reason = googleAPIErr.Errors[0].Reason
+		}
+		return strconv.Itoa(googleAPIErr.Code), reason
+	}
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		return s.Code().String(), ""
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return codes.DeadlineExceeded.String(), ""
+	}
+	return "", ""
+}
+
+// RPCCodeName returns the name of a google.rpc.Code reported by a long-running operation
+func RPCCodeName(code int64) string {
+	return codes.Code(code).String()
+}
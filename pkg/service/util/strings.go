@@ -6,7 +6,7 @@ import (
 )
 
 func PascalCaseToSnakeCase(s string) string {
-	re := regexp.MustCompile("([A-Z][a-z0-9]*)")
+	re := regexp.MustCompile("([A-Z][a-z0-9]+|[A-Z]+)")
 	snake := re.ReplaceAllStringFunc(s, func(sub string) string {
 		return "_" + strings.ToLower(sub)
 	})
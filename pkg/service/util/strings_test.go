@@ -9,4 +9,5 @@ import (
 func TestPascalCaseToSnakeCase(t *testing.T) {
 	assert.Equal(t, "cloud_storage", PascalCaseToSnakeCase("CloudStorage"))
 	assert.Equal(t, "big_query", PascalCaseToSnakeCase("BigQuery"))
+	assert.Equal(t, "cloud_sql", PascalCaseToSnakeCase("CloudSQL"))
 }
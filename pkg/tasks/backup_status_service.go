@@ -5,6 +5,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 )
 
@@ -37,12 +38,14 @@ func (b *oneShotBackupStatusService) Run(ctxIn context.Context) {
 	backups, err := b.backupRepository.GetBigQueryOneShotSnapshots(ctx, repository.Prepared)
 	if err != nil {
 		glog.Errorf("could not get prepared one shot snapshot backups: %s", err)
+		taskFailed(ctx, errors.Wrap(err, "could not get prepared one shot snapshot backups"))
 		return
 	}
 	for _, backup := range backups {
 		jobStatistics, err := b.jobRepository.GetStatisticsForBackupID(ctx, backup.ID)
 		if err != nil {
 			glog.Errorf("could not get job statistics for backup %s: %s", backup.ID, err)
+			itemFailed(ctx, errors.Wrapf(err, "could not get job statistics for backup %s", backup.ID))
 			continue
 		}
 
@@ -59,6 +62,12 @@ func (b *oneShotBackupStatusService) Run(ctxIn context.Context) {
 			continue
 		}
 
-		b.backupRepository.MarkStatus(ctx, backup.ID, repository.Finished)
+		err = b.backupRepository.MarkStatus(ctx, backup.ID, repository.Finished)
+		if err != nil {
+			glog.Errorf("could not mark backup %s as %s: %s", backup.ID, repository.Finished, err)
+			itemFailed(ctx, errors.Wrapf(err, "could not mark backup %s as %s", backup.ID, repository.Finished))
+			continue
+		}
+		itemProcessed(ctx)
 	}
 }
@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"time"
 
+	"cloud.google.com/go/storage"
 	"github.com/ottogroup/penelope/pkg/http/impersonate"
 	"github.com/ottogroup/penelope/pkg/secret"
 	bq "github.com/ottogroup/penelope/pkg/service/bigquery"
@@ -51,6 +53,7 @@ func (j *cleanupBackupService) Run(ctxIn context.Context) {
 
 		if repository.BigQuery.EqualTo(t.String()) {
 			j.handleBigQueryMirror(ctx, t)
+			j.handleBigQueryTableSnapshots(ctx, t)
 		} else if repository.CloudStorage.EqualTo(t.String()) {
 			j.handleCloudStorageMirror(ctx, t)
 		}
@@ -64,6 +67,7 @@ func (j *cleanupBackupService) handleExpiredBackups(ctxIn context.Context, t rep
 	backups, err := j.scheduleProcessor.GetExpired(ctx, t)
 	if err != nil {
 		glog.Errorf("could not get list of expired backup backups for backup type %s: %s", t.String(), err)
+		taskFailed(ctx, errors.Wrapf(err, "could not get list of expired backup backups for backup type %s", t.String()))
 	}
 	if len(backups) == 0 {
 		glog.Infof("No backups to clean up for type %s", t.String())
@@ -74,10 +78,15 @@ func (j *cleanupBackupService) handleExpiredBackups(ctxIn context.Context, t rep
 	for _, backup := range backups {
 		glog.Infof("[START] Deleting sink for backup %s", backup)
 		err := j.cleanupBackup(ctx, backup)
-		if err != nil {
+		var retained *sinkRetainedError
+		if errors.As(err, &retained) {
+			glog.Infof("[DEFERRED] Deleting sink for backup %s: %s", backup, err)
+		} else if err != nil {
 			glog.Warningf("[FAIL] Error deleting sink for backup %s: %s", backup, err)
+			itemFailed(ctx, errors.Wrapf(err, "error deleting sink for backup %s", backup.ID))
 		} else {
 			glog.Infof("[SUCCESS] Deleting sink finished for backup %s", backup)
+			itemProcessed(ctx)
 		}
 	}
 }
@@ -89,19 +98,151 @@ func (j *cleanupBackupService) handleBigQueryMirror(ctxIn context.Context, t rep
 	revisions, err := j.scheduleProcessor.GetExpiredBigQueryMirrorRevisions(ctx, maxMirrorRevisionLifetimeInWeeks)
 	if err != nil {
 		glog.Errorf("could not get list of expired mirror revisions for backup type %s: %s", t.String(), err)
+		taskFailed(ctx, errors.Wrapf(err, "could not get list of expired mirror revisions for backup type %s", t.String()))
 	} else if len(revisions) == 0 {
 		glog.Infof("No revisions to clean up for type %s", t.String())
 	} else {
+		deletedRevisions := map[string][]*repository.MirrorRevision{}
 		for _, revision := range revisions {
 			glog.Infof("[START] Deleting old BigQuery revision %s", revision)
 			err = j.deleteBigQueryRevision(ctx, revision)
 			if err != nil {
 				glog.Warningf("[FAIL] Error deleting old BigQuery revision %s: %s", revision, err)
+				itemFailed(ctx, errors.Wrapf(err, "error deleting old BigQuery revision of backup %s", revision.BackupID))
 			} else {
 				glog.Infof("[SUCCESS] Deleting old BigQuery revision finished %s", revision)
+				itemProcessed(ctx)
+				deletedRevisions[revision.BackupID] = append(deletedRevisions[revision.BackupID], revision)
+			}
+		}
+
+		for backupID, revisionsOfBackup := range deletedRevisions {
+			err = j.deleteUnreferencedManifests(ctx, revisionsOfBackup)
+			if err != nil {
+				glog.Warningf("[FAIL] Error deleting metadata manifests of backup %s: %s", backupID, err)
+				itemFailed(ctx, errors.Wrapf(err, "error deleting metadata manifests of backup %s", backupID))
+			}
+		}
+	}
+}
+
+// deleteUnreferencedManifests delete the metadata manifests of deleted mirror revisions which no other job references,
+// a mirror sink has no lifecycle rule which expires them like the manifests of a snapshot
+func (j *cleanupBackupService) deleteUnreferencedManifests(ctxIn context.Context, revisions []*repository.MirrorRevision) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*cleanupBackupService).deleteUnreferencedManifests")
+	defer span.End()
+
+	revision := revisions[0]
+	jobs, err := j.scheduleProcessor.GetJobsForBackupID(ctx, revision.BackupID, repository.Page{Size: repository.AllJobs})
+	if err != nil {
+		return err
+	}
+	var deletedJobIDs []string
+	for _, deletedRevision := range revisions {
+		deletedJobIDs = append(deletedJobIDs, deletedRevision.JobID)
+	}
+	manifestIDs := unreferencedManifestIDs(jobs, deletedJobIDs)
+	if len(manifestIDs) == 0 {
+		return nil
+	}
+
+	gcsClient, err := gcs.NewCloudStorageClient(ctx, j.tokenSourceProvider, revision.TargetProject)
+	if err != nil {
+		return fmt.Errorf("could not instantiate CloudStorageClient for project %s: %s", revision.TargetProject, err)
+	}
+	defer gcsClient.Close(ctx)
+
+	for _, manifestID := range manifestIDs {
+		err = gcsClient.DeleteObject(ctx, revision.TargetSink, repository.BuildBigQueryManifestPath(revision.BigqueryDataset, manifestID))
+		if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return fmt.Errorf("could not delete metadata manifest %s: %s", manifestID, err)
+		}
+	}
+	glog.Infof("deleted %d metadata manifests for backupID=%s", len(manifestIDs), revision.BackupID)
+	return nil
+}
+
+// unreferencedManifestIDs return the manifests of the deleted jobs which no job that is not deleted references
+func unreferencedManifestIDs(jobs []*repository.Job, deletedJobIDs []string) []string {
+	inUse := map[string]bool{}
+	for _, job := range jobs {
+		if job.ManifestID != "" && job.Status != repository.JobDeleted && !slices.Contains(deletedJobIDs, job.ID) {
+			inUse[job.ManifestID] = true
+		}
+	}
+
+	var manifestIDs []string
+	for _, job := range jobs {
+		if job.ManifestID == "" || inUse[job.ManifestID] || !slices.Contains(deletedJobIDs, job.ID) || slices.Contains(manifestIDs, job.ManifestID) {
+			continue
+		}
+		manifestIDs = append(manifestIDs, job.ManifestID)
+	}
+	return manifestIDs
+}
+
+// handleBigQueryTableSnapshots delete table snapshots which outlived the lifetime of their backup
+// BigQuery expires snapshots by the default table expiration of the snapshot dataset, but a shortened lifetime only applies to new snapshots
+func (j *cleanupBackupService) handleBigQueryTableSnapshots(ctxIn context.Context, t repository.BackupType) {
+	ctx, span := trace.StartSpan(ctxIn, "(*cleanupBackupService).handleBigQueryTableSnapshots")
+	defer span.End()
+
+	backups, err := j.scheduleProcessor.GetScheduledBackups(ctx, t)
+	if err != nil {
+		glog.Errorf("could not get list of scheduled backups for backup type %s: %s", t.String(), err)
+		taskFailed(ctx, errors.Wrapf(err, "could not get list of scheduled backups for backup type %s", t.String()))
+		return
+	}
+
+	for _, backup := range backups {
+		if repository.TableSnapshot != backup.Strategy {
+			continue
+		}
+		err = j.deleteExpiredTableSnapshots(ctx, backup)
+		if err != nil {
+			glog.Warningf("[FAIL] Error deleting expired table snapshots for backup %s: %s", backup, err)
+			itemFailed(ctx, errors.Wrapf(err, "error deleting expired table snapshots for backup %s", backup.ID))
+		} else {
+			itemProcessed(ctx)
+		}
+	}
+}
+
+func (j *cleanupBackupService) deleteExpiredTableSnapshots(ctxIn context.Context, backup *repository.Backup) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*cleanupBackupService).deleteExpiredTableSnapshots")
+	defer span.End()
+
+	jobPage := repository.Page{Size: repository.AllJobs}
+	jobs, err := j.scheduleProcessor.GetJobsForBackupID(ctx, backup.ID, jobPage)
+	if err != nil {
+		return err
+	}
+
+	expiredBefore := time.Now().AddDate(0, 0, -int(backup.SnapshotOptions.LifetimeInDays))
+	var jobHandler *bq.ExtractJobHandler
+	for _, job := range jobs {
+		if job.Status != repository.FinishedOk || !job.CreatedTimestamp.Before(expiredBefore) {
+			continue
+		}
+		if jobHandler == nil {
+			jobHandler, err = bq.NewExtractJobHandler(ctx, j.tokenSourceProvider, backup.SourceProject, backup.TargetProject)
+			if err != nil {
+				return fmt.Errorf("could not create ExtractJobHandler: %s", err)
 			}
 		}
+
+		snapshotTable := repository.BuildTableSnapshotName(job.Source, job.ID)
+		err = jobHandler.DeleteTableSnapshot(ctx, backup.TargetProject, repository.BuildTableSnapshotDataset(backup.Sink), snapshotTable)
+		if err != nil {
+			return err
+		}
+		err = j.scheduleProcessor.MarkJobDeleted(ctx, job.ID)
+		if err != nil {
+			return err
+		}
+		glog.Infof("[SUCCESS] Deleting expired table snapshot %s finished for backup %s", snapshotTable, backup.ID)
 	}
+	return nil
 }
 
 func (j *cleanupBackupService) handleCloudStorageMirror(ctxIn context.Context, t repository.BackupType) {
@@ -112,6 +253,7 @@ func (j *cleanupBackupService) handleCloudStorageMirror(ctxIn context.Context, t
 
 	if err != nil {
 		glog.Errorf("could not get list of scheduled backups for backup type %s: %s", t.String(), err)
+		taskFailed(ctx, errors.Wrapf(err, "could not get list of scheduled backups for backup type %s", t.String()))
 	} else if len(backups) == 0 {
 		glog.Infof("No CloudStorage objects to clean up for type %s", t.String())
 	} else {
@@ -119,8 +261,10 @@ func (j *cleanupBackupService) handleCloudStorageMirror(ctxIn context.Context, t
 		err = j.cleanupCloudStorageObjects(ctx, backups)
 		if err != nil {
 			glog.Warningf("[FAIL] Error deleting old CloudStorage revision: %s", err)
+			itemFailed(ctx, errors.Wrap(err, "error deleting old CloudStorage revision"))
 		} else {
 			glog.Info("[SUCCESS] Deleting old CloudStorage revision finished")
+			itemProcessed(ctx)
 		}
 	}
 }
@@ -129,6 +273,14 @@ func (j *cleanupBackupService) cleanupBackup(ctxIn context.Context, backup *repo
 	ctx, span := trace.StartSpan(ctxIn, "(*cleanupBackupService).cleanupBackup")
 	defer span.End()
 
+	if repository.TableSnapshot == backup.Strategy {
+		err := j.deleteSnapshotDataset(ctx, backup)
+		if err != nil {
+			return err
+		}
+		return j.deleteExtractJobs(ctx, backup)
+	}
+
 	err := j.deleteSink(ctx, backup)
 	if err != nil {
 		return err
@@ -138,6 +290,8 @@ func (j *cleanupBackupService) cleanupBackup(ctxIn context.Context, backup *repo
 		return j.deleteTransferJobs(ctx, backup)
 	} else if repository.BigQuery == backup.Type {
 		return j.deleteExtractJobs(ctx, backup)
+	} else if repository.Firestore == backup.Type || repository.CloudSQL == backup.Type {
+		return j.deleteExportJobs(ctx, backup)
 	}
 
 	return nil
@@ -153,6 +307,14 @@ func (j *cleanupBackupService) deleteSink(ctxIn context.Context, backup *reposit
 	}
 	defer gcsClient.Close(ctx)
 
+	retainedUntil, err := sinkRetainedUntil(ctx, gcsClient, backup)
+	if err != nil {
+		return err
+	}
+	if time.Now().Before(retainedUntil) {
+		return &sinkRetainedError{sink: backup.Sink, until: retainedUntil}
+	}
+
 	prefix := ""
 	if repository.BigQuery == backup.Type {
 		prefix = repository.BuildStoragePath(backup.BackupOptions.BigQueryOptions.Dataset, "")
@@ -179,6 +341,58 @@ func (j *cleanupBackupService) deleteSink(ctxIn context.Context, backup *reposit
 	return j.scheduleProcessor.MarkBackupDeleted(ctx, backup.ID)
 }
 
+// sinkRetainedError is returned while the retention policy of an immutable sink bucket prevents deleting it
+type sinkRetainedError struct {
+	sink  string
+	until time.Time
+}
+
+func (e *sinkRetainedError) Error() string {
+	return fmt.Sprintf("sink %s retains objects until %s", e.sink, e.until.Format(time.RFC3339))
+}
+
+// sinkRetainedUntil get the time until the retention policy of the sink bucket of an immutable backup keeps its objects,
+// the zero time is returned if the sink bucket has no retention policy
+func sinkRetainedUntil(ctxIn context.Context, gcsClient gcs.CloudStorageClient, backup *repository.Backup) (time.Time, error) {
+	ctx, span := trace.StartSpan(ctxIn, "sinkRetainedUntil")
+	defer span.End()
+
+	if !backup.Immutable {
+		return time.Time{}, nil
+	}
+
+	details, err := gcsClient.GetBucketDetails(ctx, backup.Sink)
+	if errors.Is(err, storage.ErrBucketNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not get details of sink %s: %s", backup.Sink, err)
+	}
+	if details.RetentionPolicy == nil {
+		return time.Time{}, nil
+	}
+
+	return gcsClient.GetRetentionExpirationTime(ctx, backup.Sink)
+}
+
+func (j *cleanupBackupService) deleteSnapshotDataset(ctxIn context.Context, backup *repository.Backup) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*cleanupBackupService).deleteSnapshotDataset")
+	defer span.End()
+
+	jobHandler, err := bq.NewExtractJobHandler(ctx, j.tokenSourceProvider, backup.SourceProject, backup.TargetProject)
+	if err != nil {
+		return fmt.Errorf("could not create ExtractJobHandler: %s", err)
+	}
+
+	snapshotDataset := repository.BuildTableSnapshotDataset(backup.Sink)
+	err = jobHandler.DeleteSnapshotDataset(ctx, backup.TargetProject, snapshotDataset)
+	if err != nil {
+		return fmt.Errorf("could not delete snapshot dataset %s for project %s: %s", snapshotDataset, backup.TargetProject, err)
+	}
+
+	return j.scheduleProcessor.MarkBackupDeleted(ctx, backup.ID)
+}
+
 func (j *cleanupBackupService) deleteTransferJobs(ctxIn context.Context, backup *repository.Backup) error {
 	ctx, span := trace.StartSpan(ctxIn, "(*cleanupBackupService).deleteTransferJobs")
 	defer span.End()
@@ -215,6 +429,26 @@ func (j *cleanupBackupService) deleteTransferJobs(ctxIn context.Context, backup
 	return err
 }
 
+// deleteExportJobs mark jobs of a Firestore or CloudSQL backup as deleted, finished export operations expire on their own
+func (j *cleanupBackupService) deleteExportJobs(ctxIn context.Context, backup *repository.Backup) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*cleanupBackupService).deleteExportJobs")
+	defer span.End()
+
+	jobPage := repository.Page{Size: repository.AllJobs}
+	jobs, err := j.scheduleProcessor.GetJobsForBackupID(ctx, backup.ID, jobPage)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		err = j.scheduleProcessor.MarkJobDeleted(ctx, job.ID)
+		if err != nil {
+			glog.Errorf("[FAIL] Error marking job %s as deleted: %s", job.ID, err)
+			return err
+		}
+	}
+	return nil
+}
+
 func (j *cleanupBackupService) deleteBigQueryRevision(ctxIn context.Context, revision *repository.MirrorRevision) error {
 	ctx, span := trace.StartSpan(ctxIn, "(*cleanupBackupService).deleteBigQueryRevision")
 	defer span.End()
@@ -398,7 +632,7 @@ func (j *cleanupBackupService) deleteObjects(ctxIn context.Context, revision *re
 	defer gcsClient.Close(ctx)
 
 	prefix := repository.BuildStoragePath(revision.BigqueryDataset, revision.Source)
-	objectPattern := regexp.MustCompile(repository.BuildObjectStoragePathPattern(revision.BigqueryDataset, revision.Source, revision.JobID))
+	objectPattern := regexp.MustCompile(repository.BuildObjectStoragePathPattern(revision.BigqueryDataset, revision.Source, revision.JobID, revision.FileExtension()))
 	deletedObjects, err := gcsClient.DeleteObjectsWithObjectMatch(ctx, revision.TargetSink, prefix, objectPattern)
 	if err != nil {
 		return err
@@ -6,11 +6,13 @@ import (
 	"testing"
 	"time"
 
+	"cloud.google.com/go/storage"
 	"github.com/go-pg/pg/v10"
 	"github.com/ottogroup/penelope/pkg/http/mock"
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
 	service2 "github.com/ottogroup/penelope/pkg/service"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -351,6 +353,43 @@ func TestCleanupExpiredSinkService_WithTrashcanedGcsMirrorRevisions(t *testing.T
 	//assert.Len(t, exists, 1)
 }
 
+func TestSinkRetainedUntil(t *testing.T) {
+	expiration := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	immutable := &repository.Backup{ID: "backup-1", SinkOptions: repository.SinkOptions{Sink: "bkp_gcs_1", Immutable: true}}
+	lockedPolicy := &storage.RetentionPolicy{RetentionPeriod: gcs.RetentionPeriod(30), IsLocked: true}
+
+	retained, err := sinkRetainedUntil(context.Background(), &retentionGcsClient{policy: lockedPolicy, expiration: expiration}, immutable)
+	require.NoError(t, err)
+	assert.Equal(t, expiration, retained)
+
+	retained, err = sinkRetainedUntil(context.Background(), &retentionGcsClient{expiration: expiration}, immutable)
+	require.NoError(t, err)
+	assert.True(t, retained.IsZero(), "a sink without retention policy is not retained")
+
+	mutable := &repository.Backup{ID: "backup-2", SinkOptions: repository.SinkOptions{Sink: "bkp_gcs_2"}}
+	retained, err = sinkRetainedUntil(context.Background(), &retentionGcsClient{policy: lockedPolicy, expiration: expiration}, mutable)
+	require.NoError(t, err)
+	assert.True(t, retained.IsZero(), "only sinks of immutable backups are checked")
+
+	assert.Contains(t, (&sinkRetainedError{sink: immutable.Sink, until: expiration}).Error(), "bkp_gcs_1 retains objects until")
+}
+
+func TestUnreferencedManifestIDs(t *testing.T) {
+	jobs := []*repository.Job{
+		{ID: "job-1", Status: repository.JobDeleted, ManifestID: "manifest-1"},
+		{ID: "job-2", Status: repository.FinishedOk, ManifestID: "manifest-1"},
+		{ID: "job-3", Status: repository.FinishedOk, ManifestID: "manifest-2"},
+		{ID: "job-4", Status: repository.FinishedOk, ManifestID: "manifest-2"},
+		{ID: "job-5", Status: repository.FinishedOk, ManifestID: "manifest-3"},
+		{ID: "job-6", Status: repository.FinishedOk},
+		{ID: "job-7", Status: repository.JobDeleted, ManifestID: "manifest-4"},
+	}
+
+	assert.Equal(t, []string{"manifest-1"}, unreferencedManifestIDs(jobs, []string{"job-2"}), "job-1 referencing manifest-1 is deleted already")
+	assert.Equal(t, []string{"manifest-2"}, unreferencedManifestIDs(jobs, []string{"job-3", "job-4", "job-6"}))
+	assert.Empty(t, unreferencedManifestIDs(jobs, []string{"job-3"}), "manifest-2 is still referenced by job-4")
+}
+
 func cleanupBackupServiceBackup(id string, status repository.BackupStatus) *repository.Backup {
 	return &repository.Backup{
 		ID:            id,
@@ -365,7 +404,7 @@ func cleanupBackupServiceBackup(id string, status repository.BackupStatus) *repo
 			StorageClass:  "NEARLINE",
 		},
 		BackupOptions: repository.BackupOptions{
-			BigQueryOptions: repository.BigQueryOptions{"demo_delete_me_backup_target", []string{"gcp_billing_budget_amount_plan"}, []string{}},
+			BigQueryOptions: repository.BigQueryOptions{Dataset: "demo_delete_me_backup_target", Table: []string{"gcp_billing_budget_amount_plan"}, ExcludedTables: []string{}},
 		},
 		EntityAudit: repository.EntityAudit{
 			CreatedTimestamp: time.Now(),
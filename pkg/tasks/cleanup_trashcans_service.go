@@ -8,6 +8,7 @@ import (
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 	"strings"
 	"time"
@@ -37,12 +38,14 @@ func (s *cleanupTrashcansService) Run(ctxIn context.Context) {
 	backups, err := s.backupRepository.GetBackupsByCleanupTrashcanStatus(ctx, repository.ScheduledTrashcanCleanupStatus)
 	if err != nil {
 		glog.Errorf("could not get list of backups scheduled to cleanup trashcan: %s", err)
+		taskFailed(ctx, errors.Wrap(err, "could not get list of backups scheduled to cleanup trashcan"))
 	}
 
 	for _, backup := range backups {
 		gcsClient, err := gcs.NewCloudStorageClient(ctx, s.tokenSourceProvider, backup.TargetProject)
 		if err != nil {
 			glog.Errorf("could not create new CloudStorageClient: %s", err)
+			itemFailed(ctx, errors.Wrapf(err, "could not create new CloudStorageClient for backup %s", backup.ID))
 			return
 		}
 		defer gcsClient.Close(ctx)
@@ -52,6 +55,7 @@ func (s *cleanupTrashcansService) Run(ctxIn context.Context) {
 			StartRunningTimestamp: time.Now(),
 		})
 		if err != nil {
+			itemFailed(ctx, errors.Wrapf(err, "could not mark trashcan cleanup status of backup %s to %s", backup.ID, repository.InProgressCleanupTrashcanCleanupStatus))
 			return
 		}
 
@@ -59,6 +63,7 @@ func (s *cleanupTrashcansService) Run(ctxIn context.Context) {
 		if strings.EqualFold(trashcanPath, "") {
 			errMsg := fmt.Sprintf("trashcan path is empty for backup with id %s", backup.ID)
 			glog.Errorf(errMsg)
+			itemFailed(ctx, errors.New(errMsg))
 			err = s.backupRepository.MarkTrashcanCleanup(ctx, backup.ID, repository.TrashcanCleanup{
 				Status:       repository.ErrorCleanupTrashcanCleanupStatus,
 				ErrorMessage: errMsg,
@@ -73,6 +78,7 @@ func (s *cleanupTrashcansService) Run(ctxIn context.Context) {
 		if err != nil {
 			errMsg := fmt.Sprintf("could not delete objects in trashcan for backup with id %s: %s", backup.ID, err)
 			glog.Errorf(errMsg)
+			itemFailed(ctx, errors.New(errMsg))
 			err = s.backupRepository.MarkTrashcanCleanup(ctx, backup.ID, repository.TrashcanCleanup{
 				Status:       repository.ErrorCleanupTrashcanCleanupStatus,
 				ErrorMessage: errMsg,
@@ -83,9 +89,10 @@ func (s *cleanupTrashcansService) Run(ctxIn context.Context) {
 			return
 		}
 
-		err = gcsClient.CreateObject(ctx, backup.Sink, fmt.Sprintf("%s/THIS_TRASHCAN_CONTAINS_DELETED_OBJECTS_FROM_SOURCE", trashcanPath), "")
+		err = gcsClient.CreateObject(ctx, backup.Sink, fmt.Sprintf("%s/%s", trashcanPath, repository.TrashcanMarkerObject), "")
 		if err != nil {
-			glog.Errorf("could not create THIS_TRASHCAN_CONTAINS_DELETED_OBJECTS_FROM_SOURCE object in trashcan: %s", err)
+			glog.Errorf("could not create %s object in trashcan: %s", repository.TrashcanMarkerObject, err)
+			itemFailed(ctx, errors.Wrapf(err, "could not create %s object in trashcan of backup %s", repository.TrashcanMarkerObject, backup.ID))
 			return
 		}
 
@@ -95,9 +102,11 @@ func (s *cleanupTrashcansService) Run(ctxIn context.Context) {
 		})
 		if err != nil {
 			glog.Errorf("could not mark trashcan cleanup status to %s: %s", repository.NoopCleanupTrashcanCleanupStatus, err)
+			itemFailed(ctx, errors.Wrapf(err, "could not mark trashcan cleanup status of backup %s to %s", backup.ID, repository.NoopCleanupTrashcanCleanupStatus))
 			return
 		}
 
 		glog.Infof("trashcan cleanup for backup completed: %s", backup.ID)
+		itemProcessed(ctx)
 	}
 }
@@ -0,0 +1,129 @@
+package tasks
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+)
+
+// availabilityClassPriority ranks the backups by the availability class of their source project, unknown classes rank 0
+var availabilityClassPriority = map[provider.AvailabilityClass]int{
+	provider.A4Resilient:  4,
+	provider.A3Guaranteed: 3,
+	provider.A2Aimed:      2,
+	provider.A1Irrelevant: 1,
+}
+
+// jobConcurrencyLimits are the max Scheduled and Pending jobs, a limit of 0 is unlimited
+type jobConcurrencyLimits struct {
+	global           int
+	perSourceProject int
+	perSinkProject   int
+}
+
+func jobConcurrencyLimitsFromEnv() jobConcurrencyLimits {
+	return jobConcurrencyLimits{
+		global:           concurrencyLimitFromEnv(config.MaxConcurrentJobs),
+		perSourceProject: concurrencyLimitFromEnv(config.MaxConcurrentJobsPerSourceProject),
+		perSinkProject:   concurrencyLimitFromEnv(config.MaxConcurrentJobsPerSinkProject),
+	}
+}
+
+func concurrencyLimitFromEnv(key config.EnvKey) int {
+	if !key.Exist() {
+		return 0
+	}
+	limit, err := strconv.Atoi(key.MustGet())
+	if err != nil || limit < 0 {
+		glog.Warningf("can not parse concurrency limit from environment variable %s", key)
+		return 0
+	}
+	return limit
+}
+
+func (l jobConcurrencyLimits) isUnlimited() bool {
+	return l.global == 0 && l.perSourceProject == 0 && l.perSinkProject == 0
+}
+
+// fairScheduler admits jobs as long as none of the concurrency limits is reached
+type fairScheduler struct {
+	limits          jobConcurrencyLimits
+	inFlight        int
+	bySourceProject map[string]int
+	bySinkProject   map[string]int
+}
+
+func newFairScheduler(limits jobConcurrencyLimits, counts []repository.InFlightJobCount) *fairScheduler {
+	scheduler := &fairScheduler{
+		limits:          limits,
+		bySourceProject: map[string]int{},
+		bySinkProject:   map[string]int{},
+	}
+	for _, count := range counts {
+		scheduler.inFlight += count.Count
+		scheduler.bySourceProject[count.SourceProject] += count.Count
+		scheduler.bySinkProject[count.TargetProject] += count.Count
+	}
+	return scheduler
+}
+
+// isFull is true if the global limit is reached
+func (f *fairScheduler) isFull() bool {
+	return f.limits.global > 0 && f.inFlight >= f.limits.global
+}
+
+// saturatedSourceProjects reached the limit per source project, their jobs are not candidates to be scheduled
+func (f *fairScheduler) saturatedSourceProjects() []string {
+	return saturatedProjects(f.bySourceProject, f.limits.perSourceProject)
+}
+
+// saturatedSinkProjects reached the limit per sink project, their jobs are not candidates to be scheduled
+func (f *fairScheduler) saturatedSinkProjects() []string {
+	return saturatedProjects(f.bySinkProject, f.limits.perSinkProject)
+}
+
+func saturatedProjects(inFlightByProject map[string]int, limit int) []string {
+	if limit == 0 {
+		return nil
+	}
+	var projects []string
+	for project, inFlight := range inFlightByProject {
+		if inFlight >= limit {
+			projects = append(projects, project)
+		}
+	}
+	sort.Strings(projects)
+	return projects
+}
+
+// acquire reserves a slot for a job of the backup, a job without backup is only checked against the global limit
+func (f *fairScheduler) acquire(backup *repository.Backup) bool {
+	if f.isFull() {
+		return false
+	}
+	if backup != nil {
+		if f.limits.perSourceProject > 0 && f.bySourceProject[backup.SourceProject] >= f.limits.perSourceProject {
+			return false
+		}
+		if f.limits.perSinkProject > 0 && f.bySinkProject[backup.TargetProject] >= f.limits.perSinkProject {
+			return false
+		}
+		f.bySourceProject[backup.SourceProject]++
+		f.bySinkProject[backup.TargetProject]++
+	}
+	f.inFlight++
+	return true
+}
+
+// release frees the slot of a job which could not be scheduled
+func (f *fairScheduler) release(backup *repository.Backup) {
+	f.inFlight--
+	if backup != nil {
+		f.bySourceProject[backup.SourceProject]--
+		f.bySinkProject[backup.TargetProject]--
+	}
+}
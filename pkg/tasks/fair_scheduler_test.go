@@ -0,0 +1,62 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFairScheduler_Acquire(t *testing.T) {
+	sourceA := &repository.Backup{SourceProject: "source-a", SinkOptions: repository.SinkOptions{TargetProject: "sink-a"}}
+	sourceB := &repository.Backup{SourceProject: "source-b", SinkOptions: repository.SinkOptions{TargetProject: "sink-a"}}
+	sourceC := &repository.Backup{SourceProject: "source-c", SinkOptions: repository.SinkOptions{TargetProject: "sink-c"}}
+	sourceD := &repository.Backup{SourceProject: "source-d", SinkOptions: repository.SinkOptions{TargetProject: "sink-a"}}
+
+	scheduler := newFairScheduler(
+		jobConcurrencyLimits{global: 4, perSourceProject: 1, perSinkProject: 2},
+		[]repository.InFlightJobCount{{SourceProject: "source-a", TargetProject: "sink-a", Count: 1}},
+	)
+
+	assert.False(t, scheduler.acquire(sourceA), "source project limit is reached")
+	assert.True(t, scheduler.acquire(sourceB))
+	assert.False(t, scheduler.acquire(sourceD), "sink project limit is reached")
+	assert.True(t, scheduler.acquire(sourceC))
+	assert.True(t, scheduler.acquire(nil))
+	assert.False(t, scheduler.acquire(nil), "global limit is reached")
+
+	scheduler.release(sourceB)
+	assert.True(t, scheduler.acquire(sourceD))
+}
+
+func TestFairScheduler_AcquireWithoutLimits(t *testing.T) {
+	scheduler := newFairScheduler(jobConcurrencyLimits{}, []repository.InFlightJobCount{{SourceProject: "source-a", TargetProject: "sink-a", Count: 100}})
+
+	assert.True(t, scheduler.acquire(&repository.Backup{SourceProject: "source-a", SinkOptions: repository.SinkOptions{TargetProject: "sink-a"}}))
+}
+
+func TestFairScheduler_SaturatedProjects(t *testing.T) {
+	scheduler := newFairScheduler(
+		jobConcurrencyLimits{global: 10, perSourceProject: 2, perSinkProject: 3},
+		[]repository.InFlightJobCount{
+			{SourceProject: "source-b", TargetProject: "sink-a", Count: 2},
+			{SourceProject: "source-a", TargetProject: "sink-a", Count: 2},
+			{SourceProject: "source-c", TargetProject: "sink-c", Count: 1},
+		},
+	)
+
+	assert.False(t, scheduler.isFull())
+	assert.Equal(t, []string{"source-a", "source-b"}, scheduler.saturatedSourceProjects())
+	assert.Equal(t, []string{"sink-a"}, scheduler.saturatedSinkProjects())
+
+	assert.True(t, scheduler.acquire(&repository.Backup{SourceProject: "source-c", SinkOptions: repository.SinkOptions{TargetProject: "sink-c"}}))
+	assert.Equal(t, []string{"source-a", "source-b", "source-c"}, scheduler.saturatedSourceProjects(), "an acquired slot counts for the next backup type")
+}
+
+func TestFairScheduler_SaturatedProjectsWithoutLimits(t *testing.T) {
+	scheduler := newFairScheduler(jobConcurrencyLimits{}, []repository.InFlightJobCount{{SourceProject: "source-a", TargetProject: "sink-a", Count: 100}})
+
+	assert.False(t, scheduler.isFull())
+	assert.Empty(t, scheduler.saturatedSourceProjects())
+	assert.Empty(t, scheduler.saturatedSinkProjects())
+}
@@ -58,6 +58,8 @@ func init() {
 	storageService.DB().Model(&repository.SourceTrashcan{}).Where("true").Delete()
 	storageService.DB().Model(&repository.SourceMetadata{}).Where("true").Delete()
 	storageService.DB().Model(&repository.SourceMetadataJob{}).Where("true").Delete()
+	storageService.DB().Model(&repository.RestoreDrill{}).Where("true").Delete()
+	storageService.DB().Model(&repository.RestoreJob{}).Where("true").Delete()
 	storageService.DB().Model(&repository.Job{}).Where("true").Delete()
 	storageService.DB().Model(&repository.Backup{}).Where("true").Delete()
 }
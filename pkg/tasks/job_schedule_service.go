@@ -8,20 +8,25 @@ import (
 	"github.com/golang/glog"
 	"github.com/ottogroup/penelope/pkg/http/impersonate"
 	"github.com/ottogroup/penelope/pkg/processor"
+	"github.com/ottogroup/penelope/pkg/provider"
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
 	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 )
 
 type jobScheduleService struct {
-	scheduleProcessor   processor.ScheduleProcessor
-	tokenSourceProvider impersonate.TargetPrincipalForProjectProvider
+	scheduleProcessor        processor.ScheduleProcessor
+	tokenSourceProvider      impersonate.TargetPrincipalForProjectProvider
+	sourceGCPProjectProvider provider.SourceGCPProjectProvider
+	limits                   jobConcurrencyLimits
 }
 
-func newJobScheduleService(ctxIn context.Context, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) (*jobScheduleService, error) {
+func newJobScheduleService(ctxIn context.Context, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider) (*jobScheduleService, error) {
 	ctx, span := trace.StartSpan(ctxIn, "newJobScheduleService")
 	defer span.End()
 
@@ -31,8 +36,10 @@ func newJobScheduleService(ctxIn context.Context, tokenSourceProvider impersonat
 	}
 
 	return &jobScheduleService{
-		scheduleProcessor:   scheduleProcessor,
-		tokenSourceProvider: tokenSourceProvider,
+		scheduleProcessor:        scheduleProcessor,
+		tokenSourceProvider:      tokenSourceProvider,
+		sourceGCPProjectProvider: sourceGCPProjectProvider,
+		limits:                   jobConcurrencyLimitsFromEnv(),
 	}, nil
 }
 
@@ -40,10 +47,35 @@ func (j *jobScheduleService) Run(ctxIn context.Context) {
 	ctx, span := trace.StartSpan(ctxIn, "(*jobScheduleService).Run")
 	defer span.End()
 
+	var inFlightJobCounts []repository.InFlightJobCount
+	if !j.limits.isUnlimited() {
+		var err error
+		inFlightJobCounts, err = j.scheduleProcessor.GetInFlightJobCounts(ctx)
+		if err != nil {
+			glog.Errorf("could not count in-flight jobs: %s", err)
+			taskFailed(ctx, errors.Wrap(err, "could not count in-flight jobs"))
+			return
+		}
+	}
+	scheduler := newFairScheduler(j.limits, inFlightJobCounts)
+	classByProject := map[string]provider.AvailabilityClass{}
+	backupByID := map[string]*repository.Backup{}
+
 	for _, t := range repository.BackupTypes {
-		jobs, err := j.scheduleProcessor.GetNextBackupJobs(ctx, t)
+		if scheduler.isFull() {
+			glog.Infof("No more jobs are scheduled, the max concurrent jobs are reached")
+			return
+		}
+		filter, err := j.nextJobsFilter(ctx, t, scheduler, classByProject)
+		if err != nil {
+			glog.Errorf("could not get source projects of next backup jobs for backup type %s: %s", t.String(), err)
+			taskFailed(ctx, errors.Wrapf(err, "could not get source projects of next backup jobs for backup type %s", t.String()))
+			return
+		}
+		jobs, err := j.scheduleProcessor.GetNextBackupJobs(ctx, t, filter)
 		if err != nil {
 			glog.Errorf("could not get next backup jobs for backup type %s: %s", t.String(), err)
+			taskFailed(ctx, errors.Wrapf(err, "could not get next backup jobs for backup type %s", t.String()))
 			return
 		}
 		if len(jobs) == 0 {
@@ -51,13 +83,107 @@ func (j *jobScheduleService) Run(ctxIn context.Context) {
 			continue
 		}
 		glog.Infof("Scheduling %d new jobs for type %s", len(jobs), t.String())
+		windowByBackup := map[string]bool{}
 		for _, job := range jobs {
+			if !j.isInExecutionWindow(ctx, job, windowByBackup) {
+				glog.Infof("Job %s is outside of the execution windows of backup %s and stays %s", job.ID, job.BackupID, job.Status)
+				continue
+			}
+			backup := j.cachedBackup(ctx, job.BackupID, backupByID)
+			if !scheduler.acquire(backup) {
+				glog.Infof("Job %s of backup %s stays %s, the max concurrent jobs are reached", job.ID, job.BackupID, job.Status)
+				continue
+			}
 			err = j.scheduleJob(ctx, job)
+			if err != nil {
+				scheduler.release(backup)
+			}
 			j.handleJobSchedulingError(ctx, err, job)
 		}
 	}
 }
 
+// nextJobsFilter leaves out the projects which reached their max concurrent jobs and ranks the backups by the
+// availability class of their source project
+func (j *jobScheduleService) nextJobsFilter(ctxIn context.Context, backupType repository.BackupType, scheduler *fairScheduler, classByProject map[string]provider.AvailabilityClass) (repository.NextJobsFilter, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobScheduleService).nextJobsFilter")
+	defer span.End()
+
+	filter := repository.NextJobsFilter{
+		ExcludedSourceProjects: scheduler.saturatedSourceProjects(),
+		ExcludedTargetProjects: scheduler.saturatedSinkProjects(),
+	}
+	if j.sourceGCPProjectProvider == nil {
+		return filter, nil
+	}
+
+	projects, err := j.scheduleProcessor.GetNextBackupJobSourceProjects(ctx, backupType)
+	if err != nil {
+		return filter, err
+	}
+	filter.SourceProjectPriorities = map[string]int{}
+	for _, project := range projects {
+		filter.SourceProjectPriorities[project] = availabilityClassPriority[j.availabilityClass(ctx, project, classByProject)]
+	}
+	return filter, nil
+}
+
+// cachedBackup of the job for the concurrency limits, the result is cached per backup for one run
+func (j *jobScheduleService) cachedBackup(ctxIn context.Context, backupID string, backupByID map[string]*repository.Backup) *repository.Backup {
+	if backup, ok := backupByID[backupID]; ok {
+		return backup
+	}
+	backup, err := j.getBackup(ctxIn, backupID)
+	if err != nil {
+		glog.Warningf("Jobs of backup %s are only checked against the global limit: %s", backupID, err)
+	}
+	backupByID[backupID] = backup
+	return backup
+}
+
+// availabilityClass of the source project, the result is cached per project for one run
+func (j *jobScheduleService) availabilityClass(ctxIn context.Context, project string, classByProject map[string]provider.AvailabilityClass) provider.AvailabilityClass {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobScheduleService).availabilityClass")
+	defer span.End()
+
+	if class, ok := classByProject[project]; ok {
+		return class
+	}
+	class := provider.A0Invalid
+	sourceProject, err := j.sourceGCPProjectProvider.GetSourceGCPProject(ctx, project)
+	if err != nil {
+		glog.Warningf("could not get availability class of project %s: %s", project, err)
+	} else {
+		class = sourceProject.AvailabilityClass
+	}
+	classByProject[project] = class
+	return class
+}
+
+// isInExecutionWindow checks the execution windows of the job's backup, the result is cached per backup for one run
+func (j *jobScheduleService) isInExecutionWindow(ctxIn context.Context, job *repository.Job, windowByBackup map[string]bool) bool {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobScheduleService).isInExecutionWindow")
+	defer span.End()
+
+	if inWindow, ok := windowByBackup[job.BackupID]; ok {
+		return inWindow
+	}
+
+	inWindow := true
+	backup, err := j.getBackup(ctx, job.BackupID)
+	if err == nil && len(backup.Windows) > 0 {
+		backupSchedule, err := backup.ScheduleOptions.Parse()
+		if err != nil {
+			glog.Warningf("Backup with id %s has invalid schedule: %s", backup.ID, err)
+		} else {
+			inWindow = backupSchedule.InWindow(getCurrentTime())
+		}
+	}
+	windowByBackup[job.BackupID] = inWindow
+
+	return inWindow
+}
+
 func (j *jobScheduleService) scheduleJob(ctxIn context.Context, job *repository.Job) error {
 	ctx, span := trace.StartSpan(ctxIn, "(*jobScheduleService).scheduleJob")
 	defer span.End()
@@ -68,6 +194,10 @@ func (j *jobScheduleService) scheduleJob(ctxIn context.Context, job *repository.
 		return j.scheduleBigQueryBackupJob(ctx, job)
 	case repository.CloudStorage:
 		return j.scheduleCloudStorageBackupJob(ctx, job)
+	case repository.Firestore:
+		return j.scheduleFirestoreBackupJob(ctx, job)
+	case repository.CloudSQL:
+		return j.scheduleCloudSQLBackupJob(ctx, job)
 	default:
 		return &repository.InvalidBackupType{Type: job.Type}
 	}
@@ -79,12 +209,20 @@ func (j *jobScheduleService) handleJobSchedulingError(ctxIn context.Context, err
 
 	if err != nil {
 		glog.Warningf("[FAIL] Error scheduling backup job %s: %s", job, err)
-		err = j.scheduleProcessor.UpdateJob(ctx, job.Type, job.ID, repository.Error, "")
-		if err != nil {
-			glog.Warningf("[FAIL] Error marking backup job as failed %s: %s", job, err)
+		itemFailed(ctx, errors.Wrapf(err, "error scheduling backup job %s", job.ID))
+		markErr := j.scheduleProcessor.MarkJobFailed(ctx, job, repository.Error, err)
+		if markErr != nil {
+			glog.Warningf("[FAIL] Error marking backup job as failed %s: %s", job, markErr)
+			return
 		}
+		recordJobEvent(ctx, j.scheduleProcessor, job, repository.Error, err)
+		if !job.NextAttemptTime.IsZero() {
+			glog.Infof("Job %s failed %d times and is retried at %s", job.ID, job.Attempts, job.NextAttemptTime)
+		}
+		notifyJobFailedWithoutRetry(ctx, j.scheduleProcessor, job)
 	} else {
 		glog.Infof("[SUCCESS] Scheduling finished for job %s", job)
+		itemProcessed(ctx)
 	}
 }
 
@@ -102,12 +240,16 @@ func (j *jobScheduleService) scheduleBigQueryBackupJob(ctxIn context.Context, jo
 		return fmt.Errorf("could not create ExtractJobHandler: %s", err)
 	}
 
+	if backup.Strategy == repository.TableSnapshot {
+		return j.startTableSnapshotJob(ctx, jobHandler, backup, job)
+	}
+
 	bigQueryOptions := backup.BackupOptions.BigQueryOptions
-	sinkURI := repository.BuildFullObjectStoragePath(backup.Sink, bigQueryOptions.Dataset, job.Source, job.ID)
+	sinkURI := repository.BuildFullObjectStoragePath(backup.Sink, bigQueryOptions.Dataset, job.Source, job.ID, bigQueryOptions.FileExtension())
 	glog.Infof("Creating bigquery extractJob with sink %s for job %s", sinkURI, job.ID)
-	extractJobID, err := jobHandler.CreateAvroJob(ctx, bigQueryOptions.Dataset, job.Source, sinkURI)
+	extractJobID, err := jobHandler.CreateJob(ctx, bigQueryOptions.Dataset, job.Source, sinkURI, bigQueryOptions.GetExportFormat(), bigQueryOptions.GetExportCompression())
 	if err != nil {
-		return fmt.Errorf("could not create avro job: %s", err)
+		return fmt.Errorf("could not create %s extract job: %w", bigQueryOptions.GetExportFormat(), err)
 	}
 	glog.Infof("Successfully created bigquery extractJob with id %s for job %s", extractJobID, job.ID)
 
@@ -116,6 +258,32 @@ func (j *jobScheduleService) scheduleBigQueryBackupJob(ctxIn context.Context, jo
 	if err != nil {
 		return fmt.Errorf("could not update status of job with id %s to %s: %s", job.ID, state, err)
 	}
+	recordJobEvent(ctx, j.scheduleProcessor, job, state, nil)
+	glog.Infof("Updating state job %s to %s of", state.String(), job.ID)
+
+	return nil
+}
+
+// startTableSnapshotJob snapshot a table into the snapshot dataset, the dataset expires the snapshot after the lifetime of the backup
+func (j *jobScheduleService) startTableSnapshotJob(ctxIn context.Context, jobHandler *bigquery.ExtractJobHandler, backup *repository.Backup, job *repository.Job) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobScheduleService).startTableSnapshotJob")
+	defer span.End()
+
+	snapshotDataset := repository.BuildTableSnapshotDataset(backup.Sink)
+	snapshotTable := repository.BuildTableSnapshotName(job.Source, job.ID)
+	glog.Infof("Creating bigquery table snapshot %s.%s for job %s", snapshotDataset, snapshotTable, job.ID)
+	snapshotJobID, err := jobHandler.CreateSnapshotJob(ctx, backup.BackupOptions.BigQueryOptions.Dataset, job.Source, snapshotDataset, snapshotTable, backup.KMSKeyName)
+	if err != nil {
+		return fmt.Errorf("could not create table snapshot job: %w", err)
+	}
+	glog.Infof("Successfully created bigquery table snapshot job with id %s for job %s", snapshotJobID, job.ID)
+
+	state := repository.Scheduled
+	err = j.scheduleProcessor.UpdateJob(ctx, job.Type, job.ID, state, snapshotJobID.String())
+	if err != nil {
+		return fmt.Errorf("could not update status of job with id %s to %s: %s", job.ID, state, err)
+	}
+	recordJobEvent(ctx, j.scheduleProcessor, job, state, nil)
 	glog.Infof("Updating state job %s to %s of", state.String(), job.ID)
 
 	return nil
@@ -138,20 +306,24 @@ func (j *jobScheduleService) scheduleCloudStorageBackupJob(ctxIn context.Context
 
 	var transferJobID string
 
+	includePath := backup.IncludePath
+	if len(job.IncludePrefixes) > 0 {
+		includePath = job.IncludePrefixes
+	}
 	if job.CloudStorageID != "" {
 		glog.Infof("Reusing cloudstorage transferJob %s with source %s for job %s", job.CloudStorageID, backup.Bucket, job.ID)
-		transferJobID, err = jobHandler.ReuseTransferJob(ctx, backup.SourceProject, backup.TargetProject, backup.Bucket, backup.Sink, backup.IncludePath, backup.ExcludePath, job.CloudStorageID)
+		transferJobID, err = jobHandler.ReuseTransferJob(ctx, backup.SourceProject, backup.TargetProject, backup.Bucket, backup.Sink, includePath, backup.ExcludePath, job.CloudStorageID)
 		if err != nil {
 			glog.Warningf("Reusing cloudstorage transferJob %s failed with source %s for job %s - error: %s", job.CloudStorageID, backup.Bucket, job.ID, err)
 		}
 	}
 	if job.CloudStorageID == "" || err != nil {
 		glog.Infof("Creating cloudstorage transferJob with source %s for job %s", backup.Bucket, job.ID)
-		transferJobID, err = jobHandler.CreateTransferJob(ctx, backup.SourceProject, backup.TargetProject, backup.Bucket, backup.Sink, backup.IncludePath, backup.ExcludePath)
+		transferJobID, err = jobHandler.CreateTransferJob(ctx, backup.SourceProject, backup.TargetProject, backup.Bucket, backup.Sink, includePath, backup.ExcludePath)
 	}
 
 	if err != nil {
-		return fmt.Errorf("could not create transferJob: %s", err)
+		return fmt.Errorf("could not create transferJob: %w", err)
 	}
 	glog.Infof("Successfully created cloudstorage transferJob with id %s for job %s", transferJobID, job.ID)
 
@@ -160,6 +332,86 @@ func (j *jobScheduleService) scheduleCloudStorageBackupJob(ctxIn context.Context
 	if err != nil {
 		return fmt.Errorf("could not update status of job with id %s to %s: %s", job.ID, state, err)
 	}
+	recordJobEvent(ctx, j.scheduleProcessor, job, state, nil)
+	glog.Infof("Updating state job %s to %s of", state.String(), job.ID)
+
+	return nil
+}
+
+func (j *jobScheduleService) scheduleFirestoreBackupJob(ctxIn context.Context, job *repository.Job) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobScheduleService).scheduleFirestoreBackupJob")
+	defer span.End()
+
+	backup, err := j.getBackup(ctx, job.BackupID)
+	if err != nil {
+		return errors.Wrap(err, "getting backup failed")
+	}
+
+	jobHandler, err := firestore.NewExportJobHandler(ctx, j.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return fmt.Errorf("could not create ExportJobHandler: %s", err)
+	}
+	defer jobHandler.Close(ctx)
+
+	firestoreOptions := backup.BackupOptions.FirestoreOptions
+	glog.Infof("Creating firestore export of database %s for job %s", firestoreOptions.Database, job.ID)
+	operationID, err := jobHandler.CreateExportJob(ctx, backup.SourceProject, firestoreOptions.Database, firestoreOptions.CollectionIDs, backup.Sink, job.ID)
+	if err != nil {
+		return fmt.Errorf("could not create export: %w", err)
+	}
+	glog.Infof("Successfully created firestore export with operation %s for job %s", operationID, job.ID)
+
+	state := repository.Scheduled
+	err = j.scheduleProcessor.UpdateJob(ctx, job.Type, job.ID, state, operationID.String())
+	if err != nil {
+		return fmt.Errorf("could not update status of job with id %s to %s: %s", job.ID, state, err)
+	}
+	recordJobEvent(ctx, j.scheduleProcessor, job, state, nil)
+	glog.Infof("Updating state job %s to %s of", state.String(), job.ID)
+
+	return nil
+}
+
+func (j *jobScheduleService) scheduleCloudSQLBackupJob(ctxIn context.Context, job *repository.Job) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobScheduleService).scheduleCloudSQLBackupJob")
+	defer span.End()
+
+	backup, err := j.getBackup(ctx, job.BackupID)
+	if err != nil {
+		return errors.Wrap(err, "getting backup failed")
+	}
+
+	jobHandler, err := cloudsql.NewExportJobHandler(ctx, j.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return fmt.Errorf("could not create ExportJobHandler: %s", err)
+	}
+	defer jobHandler.Close(ctx)
+
+	return j.startCloudSQLBackupJob(ctx, jobHandler, backup, job)
+}
+
+func (j *jobScheduleService) startCloudSQLBackupJob(ctxIn context.Context, jobHandler *cloudsql.ExportJobHandler, backup *repository.Backup, job *repository.Job) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobScheduleService).startCloudSQLBackupJob")
+	defer span.End()
+
+	cloudSQLOptions := backup.BackupOptions.CloudSQLOptions
+	glog.Infof("Creating cloudsql export of database %s of instance %s for job %s", job.Source, cloudSQLOptions.Instance, job.ID)
+	operationID, err := jobHandler.CreateExportJob(ctx, backup.SourceProject, cloudSQLOptions, job.Source, backup.Sink, job.ID)
+	if errors.Is(err, cloudsql.ErrOperationInProgress) {
+		glog.Infof("Instance %s is busy, job %s stays %s until the next run", cloudSQLOptions.Instance, job.ID, job.Status)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not create export: %w", err)
+	}
+	glog.Infof("Successfully created cloudsql export with operation %s for job %s", operationID, job.ID)
+
+	state := repository.Scheduled
+	err = j.scheduleProcessor.UpdateJob(ctx, job.Type, job.ID, state, operationID.String())
+	if err != nil {
+		return fmt.Errorf("could not update status of job with id %s to %s: %s", job.ID, state, err)
+	}
+	recordJobEvent(ctx, j.scheduleProcessor, job, state, nil)
 	glog.Infof("Updating state job %s to %s of", state.String(), job.ID)
 
 	return nil
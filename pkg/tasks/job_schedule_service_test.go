@@ -11,10 +11,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
 
 	"github.com/ottogroup/penelope/pkg/http/mock"
 	"github.com/ottogroup/penelope/pkg/processor"
+	"github.com/ottogroup/penelope/pkg/provider"
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
 	"github.com/ottogroup/penelope/pkg/service"
@@ -93,11 +96,19 @@ func (m *MockScheduleProcessor) CreateCloudStorageJobCreator(ctxIn context.Conte
 	panic("implement me")
 }
 
+func (m *MockScheduleProcessor) CreateFirestoreJobCreator(ctxIn context.Context, adminClient firestore.AdminClient) *processor.FirestoreJobCreator {
+	panic("implement me")
+}
+
+func (m *MockScheduleProcessor) CreateCloudSQLJobCreator(ctxIn context.Context, adminClient cloudsql.AdminClient) *processor.CloudSQLJobCreator {
+	panic("implement me")
+}
+
 func (m *MockScheduleProcessor) GetByStatusAndAfter(context.Context, []repository.JobStatus, int) ([]*repository.Job, error) {
 	panic("implement me")
 }
 
-func (m *MockScheduleProcessor) CreateBigQueryJobCreator(c context.Context, bigQueryClient bigquery.Client) *processor.BigQueryJobCreator {
+func (m *MockScheduleProcessor) CreateBigQueryJobCreator(c context.Context, bigQueryClient bigquery.Client, gcsClient gcs.CloudStorageClient) *processor.BigQueryJobCreator {
 	panic("implement me")
 }
 
@@ -127,7 +138,7 @@ func (m *MockScheduleProcessor) GetExpired(ctxIn context.Context, backupType rep
 				StorageClass:  "NEARLINE",
 			},
 			BackupOptions: repository.BackupOptions{
-				BigQueryOptions: repository.BigQueryOptions{"demo_delete_me_backup_target", []string{"gcp_billing_budget_amount_plan"}, []string{}},
+				BigQueryOptions: repository.BigQueryOptions{Dataset: "demo_delete_me_backup_target", Table: []string{"gcp_billing_budget_amount_plan"}, ExcludedTables: []string{}},
 			},
 		}}, nil
 	}
@@ -135,6 +146,34 @@ func (m *MockScheduleProcessor) GetExpired(ctxIn context.Context, backupType rep
 }
 
 func (m *MockScheduleProcessor) UpdateJob(ctxIn context.Context, backupType repository.BackupType, jobID string, status repository.JobStatus, externalID string) error {
+	m.updatedStatus = status
+	m.updatedExternalID = externalID
+	return nil
+}
+
+func (m *MockScheduleProcessor) MarkJobFailed(ctxIn context.Context, job *repository.Job, status repository.JobStatus, jobErr error) error {
+	m.updatedStatus = status
+	return nil
+}
+
+func (m *MockScheduleProcessor) GetJobsToRetry(ctxIn context.Context, before time.Time) ([]*repository.Job, error) {
+	return nil, nil
+}
+
+func (m *MockScheduleProcessor) RequeueJob(ctxIn context.Context, jobID string) error {
+	m.updatedStatus = repository.NotScheduled
+	return nil
+}
+
+func (m *MockScheduleProcessor) GetInFlightJobCounts(ctxIn context.Context) ([]repository.InFlightJobCount, error) {
+	return nil, nil
+}
+
+func (m *MockScheduleProcessor) GetNextBackupJobSourceProjects(ctxIn context.Context, backupType repository.BackupType) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockScheduleProcessor) AddJobEvent(ctxIn context.Context, job *repository.Job, status repository.JobStatus, jobErr error) error {
 	return nil
 }
 
@@ -169,7 +208,7 @@ func (m *MockScheduleProcessor) GetScheduledBackupJobs(ctxIn context.Context, ba
 	return nil, fmt.Errorf("implement me")
 }
 
-func (m *MockScheduleProcessor) GetNextBackupJobs(ctxIn context.Context, backupType repository.BackupType) ([]*repository.Job, error) {
+func (m *MockScheduleProcessor) GetNextBackupJobs(ctxIn context.Context, backupType repository.BackupType, filter repository.NextJobsFilter) ([]*repository.Job, error) {
 	if backupType == repository.BigQuery {
 		if m.shouldReturnValidJob {
 			return []*repository.Job{{
@@ -211,7 +250,7 @@ func (m *MockScheduleProcessor) GetBackupForID(ctxIn context.Context, id string)
 				StorageClass:  "NEARLINE",
 			},
 			BackupOptions: repository.BackupOptions{
-				BigQueryOptions: repository.BigQueryOptions{"demo_delete_me_backup_target", []string{"gcp_billing_budget_amount_plan"}, []string{}},
+				BigQueryOptions: repository.BigQueryOptions{Dataset: "demo_delete_me_backup_target", Table: []string{"gcp_billing_budget_amount_plan"}, ExcludedTables: []string{}},
 			},
 		}, nil
 	}
@@ -227,7 +266,7 @@ func (m *MockScheduleProcessor) UpdateBackupJob(backupType repository.BackupType
 
 func TestJobScheduleService_WithoutValidJob(t *testing.T) {
 	ctx := context.Background()
-	s, _ := newJobScheduleService(ctx, nil, secret.NewEnvSecretProvider())
+	s, _ := newJobScheduleService(ctx, nil, secret.NewEnvSecretProvider(), nil)
 	s.scheduleProcessor = &MockScheduleProcessor{
 		shouldReturnValidJob:    false,
 		shouldReturnValidBackup: false,
@@ -243,9 +282,59 @@ func TestJobScheduleService_WithoutValidJob(t *testing.T) {
 	assert.Containsf(t, strings.TrimSpace(stdErr), logMsg, "Run should write log message %q but it logged\n\t%s", logMsg, stdErr)
 }
 
+type saturatedScheduleProcessor struct {
+	processor.ScheduleProcessor
+	inFlight []repository.InFlightJobCount
+	filters  map[repository.BackupType]repository.NextJobsFilter
+}
+
+func (s *saturatedScheduleProcessor) GetInFlightJobCounts(context.Context) ([]repository.InFlightJobCount, error) {
+	return s.inFlight, nil
+}
+
+func (s *saturatedScheduleProcessor) GetNextBackupJobSourceProjects(context.Context, repository.BackupType) ([]string, error) {
+	return []string{"busy-project", "idle-project"}, nil
+}
+
+func (s *saturatedScheduleProcessor) GetNextBackupJobs(_ context.Context, backupType repository.BackupType, filter repository.NextJobsFilter) ([]*repository.Job, error) {
+	s.filters[backupType] = filter
+	return nil, nil
+}
+
+type availabilityClassProvider map[string]provider.AvailabilityClass
+
+func (p availabilityClassProvider) GetSourceGCPProject(_ context.Context, gcpProjectID string) (provider.SourceGCPProject, error) {
+	return provider.SourceGCPProject{AvailabilityClass: p[gcpProjectID]}, nil
+}
+
+func TestJobScheduleService_ExcludesSaturatedProjects(t *testing.T) {
+	ctx := context.Background()
+	scheduleProcessor := &saturatedScheduleProcessor{
+		inFlight: []repository.InFlightJobCount{{SourceProject: "busy-project", TargetProject: "busy-sink", Count: 2}},
+		filters:  map[repository.BackupType]repository.NextJobsFilter{},
+	}
+	s := &jobScheduleService{
+		scheduleProcessor: scheduleProcessor,
+		sourceGCPProjectProvider: availabilityClassProvider{
+			"busy-project": provider.A1Irrelevant,
+			"idle-project": provider.A4Resilient,
+		},
+		limits: jobConcurrencyLimits{perSourceProject: 2, perSinkProject: 5},
+	}
+
+	s.Run(ctx)
+
+	require.Len(t, scheduleProcessor.filters, len(repository.BackupTypes))
+	for _, filter := range scheduleProcessor.filters {
+		assert.Equal(t, []string{"busy-project"}, filter.ExcludedSourceProjects)
+		assert.Empty(t, filter.ExcludedTargetProjects)
+		assert.Equal(t, map[string]int{"busy-project": 1, "idle-project": 4}, filter.SourceProjectPriorities)
+	}
+}
+
 func TestJobScheduleService_WithValidJobInvalidBackup(t *testing.T) {
 	ctx := context.Background()
-	s, _ := newJobScheduleService(ctx, nil, secret.NewEnvSecretProvider())
+	s, _ := newJobScheduleService(ctx, nil, secret.NewEnvSecretProvider(), nil)
 	s.scheduleProcessor = &MockScheduleProcessor{
 		shouldReturnValidJob:    true,
 		shouldReturnValidBackup: false,
@@ -277,7 +366,7 @@ func TestJobScheduleService_WithValidJobValidBigQueryBackup(t *testing.T) {
 	jobRepository, err := repository.NewJobRepository(ctx, secret.NewEnvSecretProvider())
 	require.NoErrorf(t, err, "JobRepository should be instantiate")
 
-	s, err := newJobScheduleService(ctx, configProvider, secret.NewEnvSecretProvider())
+	s, err := newJobScheduleService(ctx, configProvider, secret.NewEnvSecretProvider(), nil)
 	require.NoErrorf(t, err, "JobScheduleService should be instantiate")
 
 	backup := repository.Backup{
@@ -293,7 +382,7 @@ func TestJobScheduleService_WithValidJobValidBigQueryBackup(t *testing.T) {
 			StorageClass:  "NEARLINE",
 		},
 		BackupOptions: repository.BackupOptions{
-			BigQueryOptions: repository.BigQueryOptions{"demo_delete_me_backup_target", []string{"gcp_billing_budget_amount_plan"}, []string{}},
+			BigQueryOptions: repository.BigQueryOptions{Dataset: "demo_delete_me_backup_target", Table: []string{"gcp_billing_budget_amount_plan"}, ExcludedTables: []string{}},
 		},
 	}
 	_, err = backupRepository.AddBackup(ctx, &backup)
@@ -356,7 +445,7 @@ func TestJobScheduleService_WithValidJobValidCloudStorageBackupAndReusableJob(t
 	jobRepository, err := repository.NewJobRepository(ctx, secret.NewEnvSecretProvider())
 	require.NoErrorf(t, err, "JobRepository should be instantiate")
 
-	scheduleService, err := newJobScheduleService(ctx, configProvider, secret.NewEnvSecretProvider())
+	scheduleService, err := newJobScheduleService(ctx, configProvider, secret.NewEnvSecretProvider(), nil)
 	require.NoErrorf(t, err, "JobScheduleService should be instantiate")
 
 	backup := repository.Backup{
@@ -443,7 +532,7 @@ func TestJobScheduleService_WithValidJobValidCloudStorageBackupAndNotReusableJob
 	jobRepository, err := repository.NewJobRepository(ctx, secret.NewEnvSecretProvider())
 	require.NoErrorf(t, err, "JobRepository should be instantiate")
 
-	scheduleService, err := newJobScheduleService(ctx, configProvider, secret.NewEnvSecretProvider())
+	scheduleService, err := newJobScheduleService(ctx, configProvider, secret.NewEnvSecretProvider(), nil)
 	require.NoErrorf(t, err, "JobScheduleService should be instantiate")
 
 	backup := repository.Backup{
@@ -519,7 +608,7 @@ func TestJobScheduleService_WithValidJobValidCloudStorageBackup(t *testing.T) {
 	jobRepository, err := repository.NewJobRepository(ctx, secret.NewEnvSecretProvider())
 	require.NoErrorf(t, err, "JobRepository should be instantiate")
 
-	scheduleService, err := newJobScheduleService(ctx, configProvider, secret.NewEnvSecretProvider())
+	scheduleService, err := newJobScheduleService(ctx, configProvider, secret.NewEnvSecretProvider(), nil)
 	require.NoErrorf(t, err, "JobScheduleService should be instantiate")
 
 	backup := repository.Backup{
@@ -690,3 +779,35 @@ var cloudStorageRunResponseTpl = `{
     "@type": "type.googleapis.com/google.protobuf.Empty"
   }
 }`
+
+func TestJobScheduleService_StartCloudSQLBackupJob(t *testing.T) {
+	ctx := context.Background()
+	adminClient := &cloudsql.MockAdminClient{Instances: map[string]*cloudsql.MockInstance{sourceProject + ":orders-db": {Databases: []string{"orders"}}}}
+	jobHandler := cloudsql.NewExportJobHandlerWithClient(adminClient)
+	scheduleProcessor := &MockScheduleProcessor{ctx: ctx}
+	service := &jobScheduleService{scheduleProcessor: scheduleProcessor}
+	backup := &repository.Backup{ID: scheduleServiceBackupID, Type: repository.CloudSQL, SourceProject: sourceProject, SinkOptions: repository.SinkOptions{Sink: "bucket"},
+		BackupOptions: repository.BackupOptions{CloudSQLOptions: repository.CloudSQLOptions{Instance: "orders-db", FileType: repository.CloudSQLExportSQL}}}
+	job := &repository.Job{ID: scheduleServiceJobID, BackupID: scheduleServiceBackupID, Type: repository.CloudSQL, Status: repository.NotScheduled, Source: "orders"}
+
+	err := service.startCloudSQLBackupJob(ctx, jobHandler, backup, job)
+	require.NoError(t, err)
+	assert.Equal(t, repository.Scheduled, scheduleProcessor.updatedStatus)
+	assert.Equal(t, "export-1", scheduleProcessor.updatedExternalID)
+	assert.Equal(t, "gs://bucket/cloudsql/orders-db/orders/"+scheduleServiceJobID+".sql.gz", adminClient.ExportRequests[0].OutputURI)
+}
+
+func TestJobScheduleService_StartCloudSQLBackupJob_InstanceBusy(t *testing.T) {
+	ctx := context.Background()
+	adminClient := &cloudsql.MockAdminClient{Instances: map[string]*cloudsql.MockInstance{sourceProject + ":orders-db": {Databases: []string{"orders"}, Busy: true}}}
+	jobHandler := cloudsql.NewExportJobHandlerWithClient(adminClient)
+	scheduleProcessor := &MockScheduleProcessor{ctx: ctx}
+	service := &jobScheduleService{scheduleProcessor: scheduleProcessor}
+	backup := &repository.Backup{ID: scheduleServiceBackupID, Type: repository.CloudSQL, SourceProject: sourceProject, SinkOptions: repository.SinkOptions{Sink: "bucket"},
+		BackupOptions: repository.BackupOptions{CloudSQLOptions: repository.CloudSQLOptions{Instance: "orders-db", FileType: repository.CloudSQLExportSQL}}}
+	job := &repository.Job{ID: scheduleServiceJobID, BackupID: scheduleServiceBackupID, Type: repository.CloudSQL, Status: repository.NotScheduled, Source: "orders"}
+
+	err := service.startCloudSQLBackupJob(ctx, jobHandler, backup, job)
+	require.NoError(t, err)
+	assert.Empty(t, scheduleProcessor.updatedStatus, "job of a busy instance should not be updated")
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
 	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
@@ -44,6 +46,7 @@ func (j *jobStatusService) Run(ctxIn context.Context) {
 		jobs, err := j.scheduleProcessor.GetScheduledBackupJobs(ctx, t)
 		if err != nil {
 			glog.Errorf("could not get scheduled backup jobs for backup type %s: %s", t.String(), err)
+			taskFailed(ctx, errors.Wrapf(err, "could not get scheduled backup jobs for backup type %s", t.String()))
 			return
 		}
 		if len(jobs) == 0 {
@@ -67,16 +70,40 @@ func (j *jobStatusService) checkJobStatus(ctxIn context.Context, backupType repo
 		err := j.checkBigQueryBackupJob(ctx, job, backupType)
 		if err != nil {
 			glog.Warningf("[FAIL] Error checking status of bigquery backup job %s: %s", job, err)
+			itemFailed(ctx, errors.Wrapf(err, "error checking status of bigquery backup job %s", job.ID))
 		} else {
 			glog.Infof("[SUCCESS] Checking status finished for bigquery job %s", job)
+			itemProcessed(ctx)
 		}
 	case repository.CloudStorage:
 		glog.Infof("[START] Checking status of cloudstorage job %s", job)
 		err := j.checkCloudStorageBackupJob(ctx, job, backupType)
 		if err != nil {
 			glog.Warningf("[FAIL] Error checking status of cloudstorage backup job %s: %s", job, err)
+			itemFailed(ctx, errors.Wrapf(err, "error checking status of cloudstorage backup job %s", job.ID))
 		} else {
 			glog.Infof("[SUCCESS] Checking status finished for cloudstorage job %s", job)
+			itemProcessed(ctx)
+		}
+	case repository.Firestore:
+		glog.Infof("[START] Checking status of firestore job %s", job)
+		err := j.checkFirestoreBackupJob(ctx, job, backupType)
+		if err != nil {
+			glog.Warningf("[FAIL] Error checking status of firestore backup job %s: %s", job, err)
+			itemFailed(ctx, errors.Wrapf(err, "error checking status of firestore backup job %s", job.ID))
+		} else {
+			glog.Infof("[SUCCESS] Checking status finished for firestore job %s", job)
+			itemProcessed(ctx)
+		}
+	case repository.CloudSQL:
+		glog.Infof("[START] Checking status of cloudsql job %s", job)
+		err := j.checkCloudSQLBackupJob(ctx, job, backupType)
+		if err != nil {
+			glog.Warningf("[FAIL] Error checking status of cloudsql backup job %s: %s", job, err)
+			itemFailed(ctx, errors.Wrapf(err, "error checking status of cloudsql backup job %s", job.ID))
+		} else {
+			glog.Infof("[SUCCESS] Checking status finished for cloudsql job %s", job)
+			itemProcessed(ctx)
 		}
 	}
 }
@@ -101,9 +128,9 @@ func (j *jobStatusService) checkBigQueryBackupJob(ctxIn context.Context, job *re
 	}
 
 	glog.Infof("Checking status of bigquery extractJob with extractJobStatus %s for job %s", extractJobID, job.ID)
-	extractJobStatus, err := jobHandler.GetStatusOfJob(ctx, extractJobID)
-	if extractJobStatus == bigquery.StateUnspecified && err != nil {
-		return fmt.Errorf("error getting status of extract job %s: %s", extractJobID, err)
+	extractJobStatus, jobErr := jobHandler.GetStatusOfJob(ctx, extractJobID)
+	if extractJobStatus == bigquery.StateUnspecified && jobErr != nil {
+		return fmt.Errorf("error getting status of extract job %s: %w", extractJobID, jobErr)
 	}
 	glog.Infof("Successfully checked status of bigquery extractJob with id %s and status %s for job %s", extractJobStatus, extractJobID, job.ID)
 
@@ -121,16 +148,16 @@ func (j *jobStatusService) checkBigQueryBackupJob(ctxIn context.Context, job *re
 		return fmt.Errorf("extract job %s has unpredictable state for job with id %s to %s", extractJobID, state.String(), job.ID)
 	}
 
-	err = j.scheduleProcessor.UpdateJob(ctx, backupType, job.ID, state, extractJobID.String())
+	err = j.updateJobStatus(ctx, backupType, job, state, extractJobID.String(), jobErr)
 	if err != nil {
-		return fmt.Errorf("could not update status of job with id %s to %s: %s", job.ID, state, err)
+		return err
 	}
 	glog.Infof("Updating state to %s of job %s", state.String(), job.ID)
 	if state == repository.FinishedError {
-		glog.Infof("[FAIL] Job finished with error %s: %s", job, err)
+		glog.Infof("[FAIL] Job finished with error %s: %s", job, jobErr)
 	}
 	if state == repository.FinishedQuotaError {
-		glog.Warningf("[FAIL] Job finished with quota error %s: %s", job, err)
+		glog.Warningf("[FAIL] Job finished with quota error %s: %s", job, jobErr)
 	}
 
 	return nil
@@ -157,9 +184,9 @@ func (j *jobStatusService) checkCloudStorageBackupJob(ctxIn context.Context, job
 	defer jobHandler.Close(ctx)
 
 	glog.Infof("Checking status of cloudstorage transferJob with transferJobStatus %s for job %s", transferJobID, job.ID)
-	transferJobStatus, err := jobHandler.GetStatusOfJob(ctx, backup.TargetProject, transferJobID)
-	if transferJobStatus == gcs.StateUnspecified && err != nil {
-		return fmt.Errorf("error getting status of extract job %s: %s", transferJobID, err)
+	transferJobStatus, jobErr := jobHandler.GetStatusOfJob(ctx, backup.TargetProject, transferJobID)
+	if transferJobStatus == gcs.StateUnspecified && jobErr != nil {
+		return fmt.Errorf("error getting status of extract job %s: %w", transferJobID, jobErr)
 	}
 	glog.Infof("Successfully checked status of cloudstroage transferJob with id %s and status %s for job %s", transferJobStatus, transferJobID, job.ID)
 
@@ -176,12 +203,12 @@ func (j *jobStatusService) checkCloudStorageBackupJob(ctxIn context.Context, job
 	}
 
 	if jobStatus == repository.FinishedError {
-		glog.Errorf("[FAIL] Job finished with error %s: %s", job, err)
+		glog.Errorf("[FAIL] Job finished with error %s: %s", job, jobErr)
 	}
 
-	err = j.scheduleProcessor.UpdateJob(ctx, backupType, job.ID, jobStatus, transferJobID)
+	err = j.updateJobStatus(ctx, backupType, job, jobStatus, transferJobID, jobErr)
 	if err != nil {
-		return fmt.Errorf("could not update status of job with id %s to %s: %s", job.ID, jobStatus, err)
+		return err
 	}
 	glog.Infof("Updating jobStatus to %s of job %s", jobStatus.String(), job.ID)
 
@@ -196,6 +223,194 @@ func (j *jobStatusService) checkCloudStorageBackupJob(ctxIn context.Context, job
 	return nil
 }
 
+func (j *jobStatusService) checkFirestoreBackupJob(ctxIn context.Context, job *repository.Job, backupType repository.BackupType) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobStatusService).checkFirestoreBackupJob")
+	defer span.End()
+
+	operationID := job.ForeignJobID.FirestoreID
+	if len(operationID) == 0 {
+		return fmt.Errorf("could not check status of job with id %s without firestore export operation for backup with id %s ", job.ID, job.BackupID)
+	}
+
+	backup, err := j.getBackup(ctx, job.BackupID)
+	if err != nil {
+		return errors.Wrap(err, "getting backup failed")
+	}
+
+	jobHandler, err := firestore.NewExportJobHandler(ctx, j.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return fmt.Errorf("could not create ExportJobHandler: %s", err)
+	}
+	defer jobHandler.Close(ctx)
+
+	return j.updateFirestoreBackupJob(ctx, jobHandler, backup, job, backupType)
+}
+
+func (j *jobStatusService) updateFirestoreBackupJob(ctxIn context.Context, jobHandler *firestore.ExportJobHandler, backup *repository.Backup, job *repository.Job, backupType repository.BackupType) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobStatusService).updateFirestoreBackupJob")
+	defer span.End()
+
+	operationID := job.ForeignJobID.FirestoreID
+	glog.Infof("Checking status of firestore export operation %s for job %s", operationID, job.ID)
+	exportJobStatus, jobErr := jobHandler.GetStatusOfJob(ctx, operationID)
+	if exportJobStatus == firestore.StateUnspecified && jobErr != nil {
+		return fmt.Errorf("error getting status of export operation %s: %w", operationID, jobErr)
+	}
+	glog.Infof("Successfully checked status of firestore export operation %s with status %s for job %s", operationID, exportJobStatus, job.ID)
+
+	var jobStatus repository.JobStatus
+
+	if exportJobStatus == firestore.Done {
+		jobStatus = repository.FinishedOk
+	} else if exportJobStatus == firestore.Pending {
+		jobStatus = repository.Pending
+	} else if exportJobStatus == firestore.Failed {
+		jobStatus = repository.FinishedError
+	} else {
+		return fmt.Errorf("export operation %s has unpredictable jobStatus for job with id %s to %s", operationID, jobStatus.String(), job.ID)
+	}
+
+	if jobStatus == repository.FinishedError {
+		glog.Errorf("[FAIL] Job finished with error %s: %s", job, jobErr)
+	}
+
+	err := j.updateJobStatus(ctx, backupType, job, jobStatus, operationID.String(), jobErr)
+	if err != nil {
+		return err
+	}
+	glog.Infof("Updating jobStatus to %s of job %s", jobStatus.String(), job.ID)
+
+	//update status of backup if it is an oneshot snapshot
+	if jobStatus == repository.FinishedOk && backup.IsOneshot() {
+		err = j.scheduleProcessor.UpdateBackupStatus(ctx, backup.ID, repository.Finished)
+		if err != nil {
+			return fmt.Errorf("could not update status of backup with id %s to %s: %s", backup.ID, repository.Finished.String(), err)
+		}
+	}
+
+	return nil
+}
+
+func (j *jobStatusService) checkCloudSQLBackupJob(ctxIn context.Context, job *repository.Job, backupType repository.BackupType) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobStatusService).checkCloudSQLBackupJob")
+	defer span.End()
+
+	operationID := job.ForeignJobID.CloudSQLID
+	if len(operationID) == 0 {
+		return fmt.Errorf("could not check status of job with id %s without cloudsql export operation for backup with id %s ", job.ID, job.BackupID)
+	}
+
+	backup, err := j.getBackup(ctx, job.BackupID)
+	if err != nil {
+		return errors.Wrap(err, "getting backup failed")
+	}
+
+	jobHandler, err := cloudsql.NewExportJobHandler(ctx, j.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return fmt.Errorf("could not create ExportJobHandler: %s", err)
+	}
+	defer jobHandler.Close(ctx)
+
+	return j.updateCloudSQLBackupJob(ctx, jobHandler, backup, job, backupType)
+}
+
+func (j *jobStatusService) updateCloudSQLBackupJob(ctxIn context.Context, jobHandler *cloudsql.ExportJobHandler, backup *repository.Backup, job *repository.Job, backupType repository.BackupType) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobStatusService).updateCloudSQLBackupJob")
+	defer span.End()
+
+	operationID := job.ForeignJobID.CloudSQLID
+	glog.Infof("Checking status of cloudsql export operation %s for job %s", operationID, job.ID)
+	exportJobStatus, jobErr := jobHandler.GetStatusOfJob(ctx, backup.SourceProject, operationID)
+	if exportJobStatus == cloudsql.StateUnspecified && jobErr != nil {
+		return fmt.Errorf("error getting status of export operation %s: %w", operationID, jobErr)
+	}
+	glog.Infof("Successfully checked status of cloudsql export operation %s with status %s for job %s", operationID, exportJobStatus, job.ID)
+
+	var jobStatus repository.JobStatus
+
+	if exportJobStatus == cloudsql.Done {
+		jobStatus = repository.FinishedOk
+	} else if exportJobStatus == cloudsql.Pending {
+		jobStatus = repository.Pending
+	} else if exportJobStatus == cloudsql.Failed {
+		jobStatus = repository.FinishedError
+	} else {
+		return fmt.Errorf("export operation %s has unpredictable jobStatus for job with id %s to %s", operationID, jobStatus.String(), job.ID)
+	}
+
+	if jobStatus == repository.FinishedError {
+		glog.Errorf("[FAIL] Job finished with error %s: %s", job, jobErr)
+	}
+
+	err := j.updateJobStatus(ctx, backupType, job, jobStatus, operationID.String(), jobErr)
+	if err != nil {
+		return err
+	}
+	glog.Infof("Updating jobStatus to %s of job %s", jobStatus.String(), job.ID)
+
+	//update status of backup if it is an oneshot snapshot
+	if jobStatus == repository.FinishedOk && backup.IsOneshot() {
+		err = j.scheduleProcessor.UpdateBackupStatus(ctx, backup.ID, repository.Finished)
+		if err != nil {
+			return fmt.Errorf("could not update status of backup with id %s to %s: %s", backup.ID, repository.Finished.String(), err)
+		}
+	}
+
+	return nil
+}
+
+// updateJobStatus persists the status of a job, a failed job gets its error recorded to be retried
+func (j *jobStatusService) updateJobStatus(ctxIn context.Context, backupType repository.BackupType, job *repository.Job, status repository.JobStatus, externalID string, jobErr error) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*jobStatusService).updateJobStatus")
+	defer span.End()
+
+	previousStatus := job.Status
+	if status == repository.FinishedError {
+		err := j.scheduleProcessor.MarkJobFailed(ctx, job, status, jobErr)
+		if err != nil {
+			return fmt.Errorf("could not mark job with id %s as failed: %s", job.ID, err)
+		}
+		notifyJobFailedWithoutRetry(ctx, j.scheduleProcessor, job)
+	} else {
+		err := j.scheduleProcessor.UpdateJob(ctx, backupType, job.ID, status, externalID)
+		if err != nil {
+			return fmt.Errorf("could not update status of job with id %s to %s: %s", job.ID, status, err)
+		}
+	}
+
+	if status != previousStatus {
+		recordJobEvent(ctx, j.scheduleProcessor, job, status, jobErr)
+	}
+	return nil
+}
+
+// recordJobEvent adds a status change to the event history of a job, a failed write does not fail the job
+func recordJobEvent(ctxIn context.Context, scheduleProcessor processor.ScheduleProcessor, job *repository.Job, status repository.JobStatus, jobErr error) {
+	ctx, span := trace.StartSpan(ctxIn, "recordJobEvent")
+	defer span.End()
+
+	err := scheduleProcessor.AddJobEvent(ctx, job, status, jobErr)
+	if err != nil {
+		glog.Warningf("could not record %s event for job %s: %s", status, job.ID, err)
+	}
+}
+
+// notifyJobFailedWithoutRetry notifies the owners of the backup about a failed job once no further attempt is planned
+func notifyJobFailedWithoutRetry(ctxIn context.Context, scheduleProcessor processor.ScheduleProcessor, job *repository.Job) {
+	ctx, span := trace.StartSpan(ctxIn, "notifyJobFailedWithoutRetry")
+	defer span.End()
+
+	if !job.NextAttemptTime.IsZero() {
+		return
+	}
+	backup, err := scheduleProcessor.GetBackupForID(ctx, job.BackupID)
+	if err != nil || backup == nil {
+		glog.Warningf("could not notify about failed job %s, backup %s not found: %v", job.ID, job.BackupID, err)
+		return
+	}
+	notifyJobFailed(ctx, backup, job)
+}
+
 func (j *jobStatusService) getBackup(ctxIn context.Context, backupID string) (*repository.Backup, error) {
 	ctx, span := trace.StartSpan(ctxIn, "(*jobStatusService).getBackup")
 	defer span.End()
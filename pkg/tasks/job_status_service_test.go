@@ -11,6 +11,8 @@ import (
 	"github.com/ottogroup/penelope/pkg/http/mock"
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -195,7 +197,7 @@ func TestJobStatusService_WithValidJobValidBackup(t *testing.T) {
 			StorageClass:  "NEARLINE",
 		},
 		BackupOptions: repository.BackupOptions{
-			BigQueryOptions: repository.BigQueryOptions{"demo_delete_me_backup_target", []string{"gcp_billing_budget_amount_plan"}, []string{}},
+			BigQueryOptions: repository.BigQueryOptions{Dataset: "demo_delete_me_backup_target", Table: []string{"gcp_billing_budget_amount_plan"}, ExcludedTables: []string{}},
 		},
 		EntityAudit: repository.EntityAudit{
 			CreatedTimestamp: time.Now(),
@@ -362,3 +364,61 @@ var mockResponseBody = `{
 }`
 
 var errorMsg = `{ "code": 1234, "message": "An error occurred", "details": [ { "id": 1234, "@type": "types.example.com/standard/id" } ] }`
+
+func TestJobStatusService_UpdateFirestoreBackupJob(t *testing.T) {
+	ctx := context.Background()
+	adminClient := &firestore.MockAdminClient{Databases: map[string]int64{"projects/local-ability/databases/(default)": 1}}
+	jobHandler := firestore.NewExportJobHandlerWithClient(adminClient)
+	operationID, err := jobHandler.CreateExportJob(ctx, "local-ability", repository.FirestoreDefaultDatabase, nil, "bucket", statusServiceJobID)
+	require.NoError(t, err)
+
+	scheduleProcessor := &MockScheduleProcessor{ctx: ctx}
+	service := &jobStatusService{scheduleProcessor: scheduleProcessor}
+	backup := &repository.Backup{ID: statusServiceBackupID, Type: repository.Firestore, Strategy: repository.Snapshot, SnapshotOptions: repository.SnapshotOptions{FrequencyInHours: 24}}
+	job := &repository.Job{ID: statusServiceJobID, BackupID: statusServiceBackupID, Type: repository.Firestore, ForeignJobID: repository.ForeignJobID{FirestoreID: operationID}}
+
+	err = service.updateFirestoreBackupJob(ctx, jobHandler, backup, job, repository.Firestore)
+	require.NoError(t, err)
+	assert.Equal(t, repository.Pending, scheduleProcessor.updatedStatus)
+	assert.Equal(t, operationID.String(), scheduleProcessor.updatedExternalID)
+
+	adminClient.Operations[operationID.String()].Done = true
+	err = service.updateFirestoreBackupJob(ctx, jobHandler, backup, job, repository.Firestore)
+	require.NoError(t, err)
+	assert.Equal(t, repository.FinishedOk, scheduleProcessor.updatedStatus)
+
+	adminClient.Operations[operationID.String()].ErrorMessage = "export failed"
+	err = service.updateFirestoreBackupJob(ctx, jobHandler, backup, job, repository.Firestore)
+	require.NoError(t, err)
+	assert.Equal(t, repository.FinishedError, scheduleProcessor.updatedStatus)
+}
+
+func TestJobStatusService_UpdateCloudSQLBackupJob(t *testing.T) {
+	ctx := context.Background()
+	adminClient := &cloudsql.MockAdminClient{Instances: map[string]*cloudsql.MockInstance{"local-ability:orders-db": {Databases: []string{"orders"}}}}
+	jobHandler := cloudsql.NewExportJobHandlerWithClient(adminClient)
+	options := repository.CloudSQLOptions{Instance: "orders-db", FileType: repository.CloudSQLExportSQL}
+	operationID, err := jobHandler.CreateExportJob(ctx, "local-ability", options, "orders", "bucket", statusServiceJobID)
+	require.NoError(t, err)
+
+	scheduleProcessor := &MockScheduleProcessor{ctx: ctx}
+	service := &jobStatusService{scheduleProcessor: scheduleProcessor}
+	backup := &repository.Backup{ID: statusServiceBackupID, Type: repository.CloudSQL, Strategy: repository.Snapshot, SourceProject: "local-ability", SnapshotOptions: repository.SnapshotOptions{FrequencyInHours: 24},
+		BackupOptions: repository.BackupOptions{CloudSQLOptions: options}}
+	job := &repository.Job{ID: statusServiceJobID, BackupID: statusServiceBackupID, Type: repository.CloudSQL, Source: "orders", ForeignJobID: repository.ForeignJobID{CloudSQLID: operationID}}
+
+	err = service.updateCloudSQLBackupJob(ctx, jobHandler, backup, job, repository.CloudSQL)
+	require.NoError(t, err)
+	assert.Equal(t, repository.Pending, scheduleProcessor.updatedStatus)
+	assert.Equal(t, operationID.String(), scheduleProcessor.updatedExternalID)
+
+	adminClient.Operations[operationID.String()].Done = true
+	err = service.updateCloudSQLBackupJob(ctx, jobHandler, backup, job, repository.CloudSQL)
+	require.NoError(t, err)
+	assert.Equal(t, repository.FinishedOk, scheduleProcessor.updatedStatus)
+
+	adminClient.Operations[operationID.String()].ErrorMessage = "export failed"
+	err = service.updateCloudSQLBackupJob(ctx, jobHandler, backup, job, repository.CloudSQL)
+	require.NoError(t, err)
+	assert.Equal(t, repository.FinishedError, scheduleProcessor.updatedStatus)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/ottogroup/penelope/pkg/processor"
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 	"strings"
 )
@@ -37,6 +38,7 @@ func (j *jobStuckService) Run(ctxIn context.Context) {
 	jobs, err := j.scheduleProcessor.GetByStatusAndAfter(ctx, statuses, deltaHours)
 	if err != nil {
 		glog.Errorf("could not get list of jobs with status %v before %d hours: %s", statuses, deltaHours, err)
+		taskFailed(ctx, errors.Wrapf(err, "could not get list of jobs with status %v before %d hours", statuses, deltaHours))
 		return
 	}
 	glog.Infof("[START] Checking stucked jobs")
@@ -49,6 +51,22 @@ func (j *jobStuckService) Run(ctxIn context.Context) {
 	logMessage := "[FAIL] stuck jobs:"
 	logMessage += strings.Join(toString(jobs), "|")
 	glog.Info(logMessage)
+	backups := map[string]*repository.Backup{}
+	for _, job := range jobs {
+		itemFailed(ctx, fmt.Errorf("job %s of backup %s is stuck in status %s", job.ID, job.BackupID, job.Status))
+
+		backup, exists := backups[job.BackupID]
+		if !exists {
+			backup, err = j.scheduleProcessor.GetBackupForID(ctx, job.BackupID)
+			if err != nil {
+				glog.Warningf("could not notify about stuck job %s, backup %s not found: %s", job.ID, job.BackupID, err)
+			}
+			backups[job.BackupID] = backup
+		}
+		if backup != nil {
+			notifyJobStuck(ctx, backup, job)
+		}
+	}
 }
 
 func toString(jobs []*repository.Job) (result []string) {
@@ -0,0 +1,104 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/notification"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"go.opencensus.io/trace"
+)
+
+// maxNotifiedSources is the number of violating sources listed in a RPO notification
+const maxNotifiedSources = 10
+
+type eventNotifierKey struct{}
+
+// eventNotifier sends events to the owners of a project, services reach it through the context passed to Run
+type eventNotifier interface {
+	Notify(ctxIn context.Context, event notification.Event)
+}
+
+// withEventNotifier adds the notification dispatcher to the context, tasks still run if it can not be created
+func withEventNotifier(ctxIn context.Context, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider, notificationChannelProvider provider.NotificationChannelProvider) context.Context {
+	ctx, span := trace.StartSpan(ctxIn, "withEventNotifier")
+	defer span.End()
+
+	dispatcher, err := notification.NewDispatcher(ctx, credentialsProvider, sourceGCPProjectProvider, notificationChannelProvider)
+	if err != nil {
+		glog.Warningf("could not create notification dispatcher, no notifications are sent: %s", err)
+		return ctxIn
+	}
+	return context.WithValue(ctxIn, eventNotifierKey{}, eventNotifier(dispatcher))
+}
+
+// notify sends the event if the context has a notifier
+func notify(ctx context.Context, event notification.Event) {
+	notifier, _ := ctx.Value(eventNotifierKey{}).(eventNotifier)
+	if notifier == nil {
+		return
+	}
+	notifier.Notify(ctx, event)
+}
+
+// notifyJobFailed is sent for a job which failed and has no retry planned
+func notifyJobFailed(ctx context.Context, backup *repository.Backup, job *repository.Job) {
+	notify(ctx, notification.NewEvent(notification.JobFailed, job.ID, backup.SourceProject, backup.ID,
+		fmt.Sprintf("Backup job of %s failed", backup.SourceProject),
+		fmt.Sprintf("Job %s of %s failed after %d attempts and is not retried: %s", job.ID, describeBackup(backup), job.Attempts, job.LastErrorMessage),
+	))
+}
+
+// notifyJobStuck is sent for a job which did not finish in time
+func notifyJobStuck(ctx context.Context, backup *repository.Backup, job *repository.Job) {
+	notify(ctx, notification.NewEvent(notification.JobStuck, job.ID, backup.SourceProject, backup.ID,
+		fmt.Sprintf("Backup job of %s is stuck", backup.SourceProject),
+		fmt.Sprintf("Job %s of %s is stuck in status %s since %s", job.ID, describeBackup(backup), job.Status, job.UpdatedTimestamp.Format("2006-01-02 15:04 MST")),
+	))
+}
+
+// notifyRPOViolated is sent for a backup with sources older than its recovery point objective
+func notifyRPOViolated(ctx context.Context, backup *repository.Backup, sources []string) {
+	listed := sources
+	if len(listed) > maxNotifiedSources {
+		listed = listed[:maxNotifiedSources]
+	}
+	message := fmt.Sprintf("%s has %d sources without recovery point in the last %dh:\n%s", describeBackup(backup), len(sources), backup.RecoveryPointObjective, strings.Join(listed, "\n"))
+	if len(sources) > len(listed) {
+		message += fmt.Sprintf("\nand %d more", len(sources)-len(listed))
+	}
+
+	notify(ctx, notification.NewEvent(notification.RPOViolated, backup.ID, backup.SourceProject, backup.ID,
+		fmt.Sprintf("Backup of %s violates its recovery point objective", backup.SourceProject),
+		message,
+	))
+}
+
+// notifyRestoreDrillFailed is sent for a restore drill which failed or exceeded the recovery time objective
+func notifyRestoreDrillFailed(ctx context.Context, backup *repository.Backup, drill *repository.RestoreDrill, reason string) {
+	notify(ctx, notification.NewEvent(notification.RestoreDrillFailed, drill.ID, backup.SourceProject, backup.ID,
+		fmt.Sprintf("Restore drill of %s failed", backup.SourceProject),
+		fmt.Sprintf("Restore drill %s of %s for source %s failed: %s", drill.ID, describeBackup(backup), drill.Source, reason),
+	))
+}
+
+// notifySinkProjectNotCompliant is sent to every source project backed up into a sink project which is not compliant
+func notifySinkProjectNotCompliant(ctx context.Context, check *repository.SinkComplianceCheck) {
+	for _, sourceProject := range check.SourceProjects {
+		notify(ctx, notification.NewEvent(notification.ComplianceFailed, fmt.Sprintf("%s/%s", check.ProjectSink, sourceProject), sourceProject, "",
+			fmt.Sprintf("Sink project of %s is not compliant", sourceProject),
+			fmt.Sprintf("Sink project %s with backups of %s does not keep backups immutable anymore:\n%s", check.ProjectSink, sourceProject, strings.Join(check.Reasons, "\n")),
+		))
+	}
+}
+
+func describeBackup(backup *repository.Backup) string {
+	if backup.Description == "" {
+		return fmt.Sprintf("%s backup %s", backup.Type, backup.ID)
+	}
+	return fmt.Sprintf("%s backup %s (%s)", backup.Type, backup.ID, backup.Description)
+}
@@ -0,0 +1,64 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/notification"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEventNotifier struct {
+	events []notification.Event
+}
+
+func (f *fakeEventNotifier) Notify(_ context.Context, event notification.Event) {
+	f.events = append(f.events, event)
+}
+
+func TestNotifyRPOViolated(t *testing.T) {
+	notifier := &fakeEventNotifier{}
+	ctx := context.WithValue(context.Background(), eventNotifierKey{}, eventNotifier(notifier))
+
+	var sources []string
+	for i := 0; i < maxNotifiedSources+2; i++ {
+		sources = append(sources, fmt.Sprintf("dataset.table_%d", i))
+	}
+	backup := &repository.Backup{ID: "backup-1", Type: repository.BigQuery, SourceProject: "local-account", RecoveryPointObjective: 24}
+	notifyRPOViolated(ctx, backup, sources)
+
+	require.Len(t, notifier.events, 1)
+	event := notifier.events[0]
+	assert.Equal(t, notification.RPOViolated, event.Kind)
+	assert.Equal(t, "rpo_violated/backup-1", event.Key)
+	assert.Equal(t, "local-account", event.Project)
+	assert.Contains(t, event.Message, "dataset.table_9")
+	assert.NotContains(t, event.Message, "dataset.table_10")
+	assert.Contains(t, event.Message, "and 2 more")
+}
+
+func TestNotifyJobFailedWithoutRetry(t *testing.T) {
+	notifier := &fakeEventNotifier{}
+	ctx := context.WithValue(context.Background(), eventNotifierKey{}, eventNotifier(notifier))
+	scheduleProcessor := &MockScheduleProcessor{shouldReturnValidBackup: true}
+
+	retriedJob := &repository.Job{ID: "job-1", BackupID: scheduleServiceBackupID, JobRetry: repository.JobRetry{Attempts: 1, NextAttemptTime: getCurrentTime()}}
+	notifyJobFailedWithoutRetry(ctx, scheduleProcessor, retriedJob)
+	assert.Empty(t, notifier.events, "a job which is retried is not notified")
+
+	failedJob := &repository.Job{ID: "job-2", BackupID: scheduleServiceBackupID, JobRetry: repository.JobRetry{Attempts: 3, LastErrorMessage: "access denied"}}
+	notifyJobFailedWithoutRetry(ctx, scheduleProcessor, failedJob)
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, "job_failed/job-2", notifier.events[0].Key)
+	assert.Equal(t, "local-ability", notifier.events[0].Project)
+	assert.Contains(t, notifier.events[0].Message, "access denied")
+}
+
+func TestNotify_WithoutNotifier(t *testing.T) {
+	assert.NotPanics(t, func() {
+		notify(context.Background(), notification.NewEvent(notification.JobStuck, "job-1", "local-account", "backup-1", "", ""))
+	})
+}
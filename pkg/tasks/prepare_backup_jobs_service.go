@@ -7,8 +7,11 @@ import (
 	"github.com/ottogroup/penelope/pkg/http/impersonate"
 	"github.com/ottogroup/penelope/pkg/processor"
 	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/schedule"
 	"github.com/ottogroup/penelope/pkg/secret"
 	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/cloudsql"
+	"github.com/ottogroup/penelope/pkg/service/firestore"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
@@ -40,6 +43,7 @@ func (j *prepareBackupJobsService) Run(ctxIn context.Context) {
 		backups, err := j.scheduleProcessor.GetScheduledBackups(ctx, t)
 		if err != nil {
 			glog.Errorf("could not get list of scheduled backups for backup type %s: %s", t.String(), err)
+			taskFailed(ctx, errors.Wrapf(err, "could not get list of scheduled backups for backup type %s", t.String()))
 			return
 		}
 		if len(backups) == 0 {
@@ -62,6 +66,10 @@ func (j *prepareBackupJobsService) scheduleJob(ctxIn context.Context, backupType
 		j.createBigQueryBackupJobs(ctx, backup)
 	case repository.CloudStorage:
 		j.createCloudStorageBackupJobs(ctx, backup)
+	case repository.Firestore:
+		j.createFirestoreBackupJobs(ctx, backup)
+	case repository.CloudSQL:
+		j.createCloudSQLBackupJobs(ctx, backup)
 	}
 }
 
@@ -78,8 +86,16 @@ func (j *prepareBackupJobsService) createBigQueryBackupJobs(ctxIn context.Contex
 	bq, err := bigquery.NewBigQueryClient(ctx, j.tokenSourceProvider, backup.SourceProject, backup.SinkOptions.TargetProject)
 	if err != nil {
 		glog.Warningf("[FAIL] Error creating bigquery client for backup %s: %s", backup, err)
+		itemFailed(ctx, errors.Wrapf(err, "error creating bigquery client for backup %s", backup.ID))
+		return
+	}
+	gcsClient, err := gcs.NewCloudStorageClient(ctx, j.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		glog.Warningf("[FAIL] Error creating cloud storage client for backup %s: %s", backup, err)
+		itemFailed(ctx, errors.Wrapf(err, "error creating cloud storage client for backup %s", backup.ID))
 	} else {
-		err = j.scheduleProcessor.CreateBigQueryJobCreator(ctx, bq).PrepareJobs(ctx, backup)
+		defer gcsClient.Close(ctx)
+		err = j.scheduleProcessor.CreateBigQueryJobCreator(ctx, bq, gcsClient).PrepareJobs(ctx, backup)
 		if err != nil {
 			if errors.Is(err, processor.BackupSourceNotFoundErr) {
 				err := j.scheduleProcessor.MarkBackupSourceDeleted(ctx, backup.ID)
@@ -88,8 +104,10 @@ func (j *prepareBackupJobsService) createBigQueryBackupJobs(ctxIn context.Contex
 				}
 			}
 			glog.Warningf("[FAIL] Error preparing backup jobs for backup %s: %s", backup, err)
+			itemFailed(ctx, errors.Wrapf(err, "error preparing backup jobs for backup %s", backup.ID))
 		} else {
 			glog.Infof("[SUCCESS] Persisting backup job finished successfully for backup %s", backup)
+			itemProcessed(ctx)
 		}
 	}
 }
@@ -105,6 +123,7 @@ func (j *prepareBackupJobsService) createCloudStorageBackupJobs(ctxIn context.Co
 	gcsClient, err := gcs.NewCloudStorageClient(ctx, j.tokenSourceProvider, backup.TargetProject)
 	if err != nil {
 		glog.Warningf("[FAIL] Error creating cloud storage client for backup %s: %s", backup, err)
+		itemFailed(ctx, errors.Wrapf(err, "error creating cloud storage client for backup %s", backup.ID))
 	} else {
 		glog.Infof("[START] Preparing backup jobs for backup %s", backup)
 		err := j.scheduleProcessor.CreateCloudStorageJobCreator(ctx, gcsClient).PrepareJobs(ctx, backup)
@@ -116,21 +135,118 @@ func (j *prepareBackupJobsService) createCloudStorageBackupJobs(ctxIn context.Co
 				}
 			}
 			glog.Warningf("[FAIL] Error preparing backup jobs for backup %s: %s", backup, err)
+			itemFailed(ctx, errors.Wrapf(err, "error preparing backup jobs for backup %s", backup.ID))
 		} else {
 			glog.Infof("[SUCCESS] Persisting backup job finished successfully for backup %s", backup)
+			itemProcessed(ctx)
 		}
 	}
 
 }
 
+func (j *prepareBackupJobsService) createFirestoreBackupJobs(ctxIn context.Context, backup *repository.Backup) {
+	ctx, span := trace.StartSpan(ctxIn, "(*prepareBackupJobsService).createFirestoreBackupJobs")
+	defer span.End()
+
+	if !isNextScheduleTime(backup) {
+		glog.Infof("Backup with id %s don't need to be scheduled", backup.ID)
+		return
+	}
+	adminClient, err := firestore.NewAdminClient(ctx, j.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		glog.Warningf("[FAIL] Error creating firestore admin client for backup %s: %s", backup, err)
+		itemFailed(ctx, errors.Wrapf(err, "error creating firestore admin client for backup %s", backup.ID))
+		return
+	}
+	defer adminClient.Close(ctx)
+
+	glog.Infof("[START] Preparing backup jobs for backup %s", backup)
+	err = j.scheduleProcessor.CreateFirestoreJobCreator(ctx, adminClient).PrepareJobs(ctx, backup)
+	if err != nil {
+		if errors.Is(err, processor.BackupSourceNotFoundErr) {
+			err := j.scheduleProcessor.MarkBackupSourceDeleted(ctx, backup.ID)
+			if err != nil {
+				glog.Warningf("[FAIL] Error marking backup source as deleted %s: %s", backup, err)
+			}
+		}
+		glog.Warningf("[FAIL] Error preparing backup jobs for backup %s: %s", backup, err)
+		itemFailed(ctx, errors.Wrapf(err, "error preparing backup jobs for backup %s", backup.ID))
+	} else {
+		glog.Infof("[SUCCESS] Persisting backup job finished successfully for backup %s", backup)
+		itemProcessed(ctx)
+	}
+}
+
+func (j *prepareBackupJobsService) createCloudSQLBackupJobs(ctxIn context.Context, backup *repository.Backup) {
+	ctx, span := trace.StartSpan(ctxIn, "(*prepareBackupJobsService).createCloudSQLBackupJobs")
+	defer span.End()
+
+	if !isNextScheduleTime(backup) {
+		glog.Infof("Backup with id %s don't need to be scheduled", backup.ID)
+		return
+	}
+	adminClient, err := cloudsql.NewAdminClient(ctx, j.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		glog.Warningf("[FAIL] Error creating cloudsql admin client for backup %s: %s", backup, err)
+		itemFailed(ctx, errors.Wrapf(err, "error creating cloudsql admin client for backup %s", backup.ID))
+		return
+	}
+	defer adminClient.Close(ctx)
+
+	glog.Infof("[START] Preparing backup jobs for backup %s", backup)
+	err = j.scheduleProcessor.CreateCloudSQLJobCreator(ctx, adminClient).PrepareJobs(ctx, backup)
+	if err != nil {
+		if errors.Is(err, processor.BackupSourceNotFoundErr) {
+			err := j.scheduleProcessor.MarkBackupSourceDeleted(ctx, backup.ID)
+			if err != nil {
+				glog.Warningf("[FAIL] Error marking backup source as deleted %s: %s", backup, err)
+			}
+		}
+		glog.Warningf("[FAIL] Error preparing backup jobs for backup %s: %s", backup, err)
+		itemFailed(ctx, errors.Wrapf(err, "error preparing backup jobs for backup %s", backup.ID))
+	} else {
+		glog.Infof("[SUCCESS] Persisting backup job finished successfully for backup %s", backup)
+		itemProcessed(ctx)
+	}
+}
+
 var getCurrentTime = func() time.Time {
 	return time.Now().UTC()
 }
 
 func isNextScheduleTime(backup *repository.Backup) bool {
+	if backup.ScheduleOptions.IsEmpty() {
+		return isNextSnapshotTime(backup) || isNextMirrorTime(backup)
+	}
+
+	backupSchedule, err := backup.ScheduleOptions.Parse()
+	if err != nil {
+		glog.Warningf("Backup with id %s has invalid schedule: %s", backup.ID, err)
+		return false
+	}
+	now := getCurrentTime()
+	if !backupSchedule.InWindow(now) {
+		glog.Infof("Backup with id %s is outside of its execution windows %v", backup.ID, backup.Windows)
+		return false
+	}
+	if backupSchedule.HasCron() {
+		return backup.Strategy.IsSnapshot() && isNextCronTime(backup, backupSchedule, now)
+	}
 	return isNextSnapshotTime(backup) || isNextMirrorTime(backup)
 }
 
+// isNextCronTime checks if the cron expression fired since the last scheduling, new backups wait for the first activation after creation
+func isNextCronTime(backup *repository.Backup, backupSchedule *schedule.Schedule, now time.Time) bool {
+	last := backup.LastScheduledTime
+	if last.IsZero() {
+		last = backup.CreatedTimestamp
+	}
+	if last.IsZero() {
+		return true
+	}
+	return backupSchedule.IsDue(last, now)
+}
+
 func isNextMirrorTime(backup *repository.Backup) bool {
 	// partitions are made on hourly basis
 	nextScheduledTime := backup.LastScheduledTime.Add(time.Hour)
@@ -140,6 +256,6 @@ func isNextMirrorTime(backup *repository.Backup) bool {
 
 func isNextSnapshotTime(backup *repository.Backup) bool {
 	nextScheduledTime := backup.LastScheduledTime.Add(time.Hour * time.Duration(backup.FrequencyInHours))
-	return backup.Strategy == repository.Snapshot && ((backup.FrequencyInHours == 0) || //is one-shot
+	return backup.Strategy.IsSnapshot() && ((backup.FrequencyInHours == 0) || //is one-shot
 		(backup.LastScheduledTime.IsZero() || nextScheduledTime.Before(getCurrentTime())))
 }
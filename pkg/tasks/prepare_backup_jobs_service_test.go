@@ -34,7 +34,7 @@ func prepareBackupServiceBigQueryBackup() *repository.Backup {
 			StorageClass:  "NEARLINE",
 		},
 		BackupOptions: repository.BackupOptions{
-			BigQueryOptions: repository.BigQueryOptions{"demo_delete_me_backup_target", []string{"gcp_billing_budget_amount_plan"}, []string{}},
+			BigQueryOptions: repository.BigQueryOptions{Dataset: "demo_delete_me_backup_target", Table: []string{"gcp_billing_budget_amount_plan"}, ExcludedTables: []string{}},
 		},
 	}
 }
@@ -53,7 +53,7 @@ func prepareBackupServiceBigQueryMirrorBackup() *repository.Backup {
 			StorageClass:  "NEARLINE",
 		},
 		BackupOptions: repository.BackupOptions{
-			BigQueryOptions: repository.BigQueryOptions{"demo_delete_me_backup_target", []string{"gcp_billing_budget_amount_plan"}, []string{}},
+			BigQueryOptions: repository.BigQueryOptions{Dataset: "demo_delete_me_backup_target", Table: []string{"gcp_billing_budget_amount_plan"}, ExcludedTables: []string{}},
 		},
 	}
 }
@@ -430,3 +430,34 @@ func TestBackUpSourceNotFoundForCloudStorage(t *testing.T) {
 	logMsg := "[FAIL] Error preparing backup jobs for backup "
 	assert.Containsf(t, strings.TrimSpace(stdErr), logMsg, "Run should write log message %q but it logged\n\t%s", logMsg, stdErr)
 }
+
+func TestIsNextScheduleTime_Cron(t *testing.T) {
+	bkpGetCurrentTime := getCurrentTime
+	defer func() { getCurrentTime = bkpGetCurrentTime }()
+
+	backup := prepareBackupServiceBigQueryBackup()
+	backup.Strategy = repository.Snapshot
+	backup.CreatedTimestamp = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	backup.ScheduleOptions = repository.ScheduleOptions{
+		Cron:     "30 1 * * *",
+		Timezone: "Europe/Berlin",
+		Windows:  []string{"01:00-05:00"},
+	}
+
+	getCurrentTime = func() time.Time { return time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC) } // 01:00 in Berlin
+	assert.False(t, isNextScheduleTime(backup), "cron did not fire since creation")
+
+	getCurrentTime = func() time.Time { return time.Date(2024, 3, 2, 0, 30, 0, 0, time.UTC) }
+	assert.True(t, isNextScheduleTime(backup), "cron fired since creation")
+
+	backup.LastScheduledTime = time.Date(2024, 3, 2, 0, 31, 0, 0, time.UTC)
+	getCurrentTime = func() time.Time { return time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC) }
+	assert.False(t, isNextScheduleTime(backup), "cron did not fire since last scheduling")
+
+	backup.ScheduleOptions.Cron = "0 23 * * *"
+	getCurrentTime = func() time.Time { return time.Date(2024, 3, 2, 23, 30, 0, 0, time.UTC) }
+	assert.False(t, isNextScheduleTime(backup), "cron fired but outside of execution window")
+
+	getCurrentTime = func() time.Time { return time.Date(2024, 3, 3, 1, 0, 0, 0, time.UTC) }
+	assert.True(t, isNextScheduleTime(backup), "cron fired and inside of execution window")
+}
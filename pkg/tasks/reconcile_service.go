@@ -11,6 +11,7 @@ import (
 	"github.com/ottogroup/penelope/pkg/secret"
 	"github.com/ottogroup/penelope/pkg/service/gcs"
 	"github.com/ottogroup/penelope/pkg/service/util"
+	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 )
 
@@ -40,6 +41,7 @@ func (j *reconcileService) Run(ctxIn context.Context) {
 	backups, err := j.db.GetBackups(ctx, repository.BackupFilter{})
 	if err != nil {
 		glog.Errorf("could not get list of backups: %s", err)
+		taskFailed(ctx, errors.Wrap(err, "could not get list of backups"))
 	}
 	glog.Infof("[START] Reconcile backup")
 	var failedBackups []string
@@ -49,13 +51,19 @@ func (j *reconcileService) Run(ctxIn context.Context) {
 		if !hasActiveStatus {
 			continue
 		}
+		if backup.Strategy == repository.TableSnapshot {
+			// table snapshots are kept in a dataset of the sink project, there is no sink bucket
+			continue
+		}
 		err = j.syncSinkBucket(ctx, backup)
 		if err != nil {
 			glog.Errorf("could not sync bucket labels and lifecycle for backup %s: %s", backup.ID, err)
 			failedBackups = append(failedBackups, backup.ID)
+			itemFailed(ctx, errors.Wrapf(err, "could not sync bucket labels and lifecycle for backup %s", backup.ID))
 			continue
 		}
 		successBackups = append(successBackups, backup.ID)
+		itemProcessed(ctx)
 	}
 
 	// cleanup clients
@@ -97,16 +105,21 @@ func (j *reconcileService) syncSinkBucket(ctx context.Context, backup *repositor
 
 	if !exist {
 		err = cloudStorageClient.CreateBucket(ctx, gcs.CloudStorageBucket{
-			Project:        backup.TargetProject,
-			Bucket:         bucketName,
-			Location:       backup.Region,
-			DualLocation:   backup.DualRegion,
-			StorageClass:   backup.StorageClass,
-			LifetimeInDays: lifetimeInDays,
-			ArchiveTTM:     backup.ArchiveTTM,
-			Labels:         gcs.NewLabels(util.PascalCaseToSnakeCase(backup.Type.String()), backup.ID, backup.SourceProject),
+			Project:         backup.TargetProject,
+			Bucket:          bucketName,
+			Location:        backup.Region,
+			DualLocation:    backup.DualRegion,
+			StorageClass:    backup.StorageClass,
+			LifetimeInDays:  lifetimeInDays,
+			ArchiveTTM:      backup.ArchiveTTM,
+			Labels:          gcs.NewLabels(util.PascalCaseToSnakeCase(backup.Type.String()), backup.ID, backup.SourceProject),
+			KMSKeyName:      backup.KMSKeyName,
+			RetentionInDays: backup.RetentionInDays(),
 		})
-		return fmt.Errorf("failed to create bucket %s: %s", bucketName, err)
+		if err != nil {
+			return fmt.Errorf("failed to create bucket %s: %s", bucketName, err)
+		}
+		return syncSinkBucketRetention(ctx, cloudStorageClient, backup)
 	}
 
 	err = cloudStorageClient.UpdateBucket(
@@ -119,6 +132,67 @@ func (j *reconcileService) syncSinkBucket(ctx context.Context, backup *repositor
 	if err != nil {
 		return fmt.Errorf("could not update bucket %s lifecycle: %s", backup.Sink, err)
 	}
+	err = syncSinkBucketEncryption(ctx, cloudStorageClient, backup)
+	if err != nil {
+		return err
+	}
+	return syncSinkBucketRetention(ctx, cloudStorageClient, backup)
+}
+
+// syncSinkBucketEncryption makes sure the sink bucket of a backup with a KMS key has the key as default encryption,
+// a bucket encrypted with a customer-managed key is not changed back for a backup without key
+func syncSinkBucketEncryption(ctx context.Context, cloudStorageClient gcs.CloudStorageClient, backup *repository.Backup) error {
+	if backup.KMSKeyName == "" {
+		return nil
+	}
+
+	details, err := cloudStorageClient.GetBucketDetails(ctx, backup.Sink)
+	if err != nil {
+		return fmt.Errorf("could not get bucket %s details: %s", backup.Sink, err)
+	}
+	if details.Encryption != nil && details.Encryption.DefaultKMSKeyName == backup.KMSKeyName {
+		return nil
+	}
+
+	glog.Warningf("Sink bucket %s of backup %s is not encrypted with KMS key %s, setting it as default key", backup.Sink, backup.ID, backup.KMSKeyName)
+	err = cloudStorageClient.SetBucketDefaultKMSKey(ctx, backup.Sink, backup.KMSKeyName)
+	if err != nil {
+		return fmt.Errorf("could not set KMS key of bucket %s: %s", backup.Sink, err)
+	}
+	return nil
+}
+
+// syncSinkBucketRetention makes sure the sink bucket of an immutable backup has a retention policy of the backup lifetime
+// and locks it again if the owner confirmed locking, a locked policy with a longer period can not be shortened and is kept
+func syncSinkBucketRetention(ctx context.Context, cloudStorageClient gcs.CloudStorageClient, backup *repository.Backup) error {
+	retentionInDays := backup.RetentionInDays()
+	if retentionInDays == 0 {
+		return nil
+	}
+
+	details, err := cloudStorageClient.GetBucketDetails(ctx, backup.Sink)
+	if err != nil {
+		return fmt.Errorf("could not get bucket %s details: %s", backup.Sink, err)
+	}
+
+	policy := details.RetentionPolicy
+	period := gcs.RetentionPeriod(retentionInDays)
+	shortenLocked := policy != nil && policy.IsLocked && policy.RetentionPeriod > period
+	if (policy == nil || policy.RetentionPeriod != period) && !shortenLocked {
+		glog.Warningf("Sink bucket %s of backup %s has no retention policy of %d days, setting it", backup.Sink, backup.ID, retentionInDays)
+		err = cloudStorageClient.SetBucketRetentionPolicy(ctx, backup.Sink, retentionInDays)
+		if err != nil {
+			return fmt.Errorf("could not set retention policy of bucket %s: %s", backup.Sink, err)
+		}
+	}
+
+	if backup.RetentionLocked && (policy == nil || !policy.IsLocked) {
+		glog.Warningf("Retention policy of sink bucket %s of backup %s is not locked, locking it", backup.Sink, backup.ID)
+		err = cloudStorageClient.LockBucketRetentionPolicy(ctx, backup.Sink)
+		if err != nil {
+			return fmt.Errorf("could not lock retention policy of bucket %s: %s", backup.Sink, err)
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,112 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptionGcsClient fakes the bucket encryption of a CloudStorageClient, other methods are not implemented
+type encryptionGcsClient struct {
+	gcs.CloudStorageClient
+	defaultKMSKeyName string
+	setKMSKeyNames    []string
+}
+
+func (c *encryptionGcsClient) GetBucketDetails(_ context.Context, _ string) (*storage.BucketAttrs, error) {
+	if c.defaultKMSKeyName == "" {
+		return &storage.BucketAttrs{}, nil
+	}
+	return &storage.BucketAttrs{Encryption: &storage.BucketEncryption{DefaultKMSKeyName: c.defaultKMSKeyName}}, nil
+}
+
+func (c *encryptionGcsClient) SetBucketDefaultKMSKey(_ context.Context, _ string, kmsKeyName string) error {
+	c.setKMSKeyNames = append(c.setKMSKeyNames, kmsKeyName)
+	return nil
+}
+
+func TestSyncSinkBucketEncryption(t *testing.T) {
+	kmsKeyName := "projects/local-kms/locations/europe-west3/keyRings/backups/cryptoKeys/local-account"
+	backup := &repository.Backup{ID: "backup-1", SinkOptions: repository.SinkOptions{Sink: "bkp_bq_1", KMSKeyName: kmsKeyName}}
+
+	withoutKey := &encryptionGcsClient{}
+	require.NoError(t, syncSinkBucketEncryption(context.Background(), withoutKey, backup))
+	assert.Equal(t, []string{kmsKeyName}, withoutKey.setKMSKeyNames, "a bucket with Google-managed encryption gets the key")
+
+	otherKey := &encryptionGcsClient{defaultKMSKeyName: "projects/local-kms/locations/europe-west3/keyRings/backups/cryptoKeys/other"}
+	require.NoError(t, syncSinkBucketEncryption(context.Background(), otherKey, backup))
+	assert.Equal(t, []string{kmsKeyName}, otherKey.setKMSKeyNames)
+
+	sameKey := &encryptionGcsClient{defaultKMSKeyName: kmsKeyName}
+	require.NoError(t, syncSinkBucketEncryption(context.Background(), sameKey, backup))
+	assert.Empty(t, sameKey.setKMSKeyNames)
+
+	googleManaged := &encryptionGcsClient{}
+	require.NoError(t, syncSinkBucketEncryption(context.Background(), googleManaged, &repository.Backup{ID: "backup-2"}))
+	assert.Empty(t, googleManaged.setKMSKeyNames, "a backup without key is not checked")
+}
+
+// retentionGcsClient fakes the bucket retention policy of a CloudStorageClient, other methods are not implemented
+type retentionGcsClient struct {
+	gcs.CloudStorageClient
+	policy             *storage.RetentionPolicy
+	setRetentionInDays []uint
+	lockCalls          int
+	expiration         time.Time
+}
+
+func (c *retentionGcsClient) GetBucketDetails(_ context.Context, _ string) (*storage.BucketAttrs, error) {
+	return &storage.BucketAttrs{RetentionPolicy: c.policy}, nil
+}
+
+func (c *retentionGcsClient) SetBucketRetentionPolicy(_ context.Context, _ string, retentionInDays uint) error {
+	c.setRetentionInDays = append(c.setRetentionInDays, retentionInDays)
+	return nil
+}
+
+func (c *retentionGcsClient) GetRetentionExpirationTime(_ context.Context, _ string) (time.Time, error) {
+	return c.expiration, nil
+}
+
+func (c *retentionGcsClient) LockBucketRetentionPolicy(_ context.Context, _ string) error {
+	c.lockCalls++
+	return nil
+}
+
+func TestSyncSinkBucketRetention(t *testing.T) {
+	immutable := &repository.Backup{
+		ID:              "backup-1",
+		Strategy:        repository.Snapshot,
+		SinkOptions:     repository.SinkOptions{Sink: "bkp_gcs_1", Immutable: true},
+		SnapshotOptions: repository.SnapshotOptions{LifetimeInDays: 30},
+	}
+
+	withoutPolicy := &retentionGcsClient{}
+	require.NoError(t, syncSinkBucketRetention(context.Background(), withoutPolicy, immutable))
+	assert.Equal(t, []uint{30}, withoutPolicy.setRetentionInDays)
+	assert.Zero(t, withoutPolicy.lockCalls, "a policy is only locked after confirmation")
+
+	samePolicy := &retentionGcsClient{policy: &storage.RetentionPolicy{RetentionPeriod: gcs.RetentionPeriod(30)}}
+	require.NoError(t, syncSinkBucketRetention(context.Background(), samePolicy, immutable))
+	assert.Empty(t, samePolicy.setRetentionInDays)
+
+	longerLockedPolicy := &retentionGcsClient{policy: &storage.RetentionPolicy{RetentionPeriod: gcs.RetentionPeriod(60), IsLocked: true}}
+	require.NoError(t, syncSinkBucketRetention(context.Background(), longerLockedPolicy, immutable))
+	assert.Empty(t, longerLockedPolicy.setRetentionInDays, "a locked policy can not be shortened")
+
+	locked := *immutable
+	locked.RetentionLocked = true
+	unlockedPolicy := &retentionGcsClient{policy: &storage.RetentionPolicy{RetentionPeriod: gcs.RetentionPeriod(30)}}
+	require.NoError(t, syncSinkBucketRetention(context.Background(), unlockedPolicy, &locked))
+	assert.Equal(t, 1, unlockedPolicy.lockCalls)
+
+	mutable := &retentionGcsClient{}
+	require.NoError(t, syncSinkBucketRetention(context.Background(), mutable, &repository.Backup{ID: "backup-2", Strategy: repository.Snapshot}))
+	assert.Empty(t, mutable.setRetentionInDays, "a backup which is not immutable is not checked")
+}
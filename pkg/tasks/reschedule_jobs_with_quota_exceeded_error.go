@@ -6,6 +6,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
 	"go.opencensus.io/trace"
 	"time"
 )
@@ -41,6 +42,7 @@ func (r *rescheduleJobsWithQuotaErrorService) Run(ctxIn context.Context) {
 	jobs, err := r.jobRepository.GetByJobTypeAndStatus(ctx, repository.BigQuery, repository.FinishedQuotaError)
 	if err != nil {
 		glog.Infof("[FAIL] GetByJobTypeAndStatus failed: %s", err)
+		taskFailed(ctx, errors.Wrap(err, "GetByJobTypeAndStatus failed"))
 		return
 	}
 	if len(jobs) == 0 {
@@ -55,6 +57,7 @@ func (r *rescheduleJobsWithQuotaErrorService) Run(ctxIn context.Context) {
 		isQuotaRenewed, err := hasQuotaRenewedForJob(job)
 		if err != nil {
 			glog.Warningf("[FAIL] not able to calculate job next quota time with ID: %s", job.ID)
+			itemFailed(ctx, errors.Wrapf(err, "not able to calculate job next quota time with ID: %s", job.ID))
 			continue
 		}
 		if !isQuotaRenewed {
@@ -64,8 +67,11 @@ func (r *rescheduleJobsWithQuotaErrorService) Run(ctxIn context.Context) {
 		err = r.jobRepository.PatchJobStatus(ctx, patch)
 		if err != nil {
 			glog.Warningf("[FAIL] not able to reschedule job with ID: %s", job.ID)
+			itemFailed(ctx, errors.Wrapf(err, "not able to reschedule job with ID: %s", job.ID))
 			failedToRescheduleCount++
+			continue
 		}
+		itemProcessed(ctx)
 	}
 	if failedToRescheduleCount != 0 {
 		glog.Infof("[FAIL] %d jobs where not rescheduled", failedToRescheduleCount)
@@ -0,0 +1,539 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	bq "cloud.google.com/go/bigquery"
+	"github.com/golang/glog"
+	"github.com/google/uuid"
+	"github.com/ottogroup/penelope/pkg/config"
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// maxRestoreDrillsPerRun limits the drills started by a single run, each drill starts a load or transfer job
+	maxRestoreDrillsPerRun = 5
+	// restoreDrillTimeout marks drills as failed whose restore job did not finish in time
+	restoreDrillTimeout = 24 * time.Hour
+	// defaultRestoreDrillDataset scratch dataset in the drill project, it should expire its tables
+	defaultRestoreDrillDataset = "penelope_restore_drills"
+)
+
+// restoreDrillIntervals how often backups of an availability class are drilled, other classes are never drilled
+var restoreDrillIntervals = map[provider.AvailabilityClass]time.Duration{
+	provider.A4Resilient:  7 * 24 * time.Hour,
+	provider.A3Guaranteed: 30 * 24 * time.Hour,
+	provider.A2Aimed:      90 * 24 * time.Hour,
+}
+
+// restoreDrillBackupStatuses backups in these statuses hold data which can be restored
+var restoreDrillBackupStatuses = []repository.BackupStatus{repository.Prepared, repository.Finished, repository.Paused}
+
+var errNothingToDrill = errors.New("backup has no data to restore")
+
+type restoreDrillService struct {
+	backupRepository         repository.BackupRepository
+	jobRepository            repository.JobRepository
+	restoreJobRepository     repository.RestoreJobRepository
+	restoreDrillRepository   repository.RestoreDrillRepository
+	tokenSourceProvider      impersonate.TargetPrincipalForProjectProvider
+	sourceGCPProjectProvider provider.SourceGCPProjectProvider
+	drillProject             string
+	drillDataset             string
+	drillBucket              string
+}
+
+func newRestoreDrillService(ctxIn context.Context, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider) (*restoreDrillService, error) {
+	ctx, span := trace.StartSpan(ctxIn, "newRestoreDrillService")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &restoreDrillService{}, fmt.Errorf("could not instantiate new BackupRepository: %s", err)
+	}
+	jobRepository, err := repository.NewJobRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &restoreDrillService{}, fmt.Errorf("could not instantiate new JobRepository: %s", err)
+	}
+	restoreJobRepository, err := repository.NewRestoreJobRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &restoreDrillService{}, fmt.Errorf("could not instantiate new RestoreJobRepository: %s", err)
+	}
+	restoreDrillRepository, err := repository.NewRestoreDrillRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &restoreDrillService{}, fmt.Errorf("could not instantiate new RestoreDrillRepository: %s", err)
+	}
+
+	return &restoreDrillService{
+		backupRepository:         backupRepository,
+		jobRepository:            jobRepository,
+		restoreJobRepository:     restoreJobRepository,
+		restoreDrillRepository:   restoreDrillRepository,
+		tokenSourceProvider:      tokenSourceProvider,
+		sourceGCPProjectProvider: sourceGCPProjectProvider,
+		drillProject:             config.RestoreDrillProject.GetOrDefault(""),
+		drillDataset:             config.RestoreDrillDataset.GetOrDefault(defaultRestoreDrillDataset),
+		drillBucket:              config.RestoreDrillBucket.GetOrDefault(""),
+	}, nil
+}
+
+// Run evaluates drills whose restore job finished and starts drills for backups which are due
+// the restore jobs of drills are tracked by the check_restore_jobs_status task
+func (r *restoreDrillService) Run(ctxIn context.Context) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).Run")
+	defer span.End()
+
+	if r.drillProject == "" {
+		glog.Infof("Restore drills are disabled, %s is not set", config.RestoreDrillProject)
+		return
+	}
+
+	r.evaluateDrills(ctx)
+	r.startDrills(ctx)
+}
+
+func (r *restoreDrillService) evaluateDrills(ctxIn context.Context) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).evaluateDrills")
+	defer span.End()
+
+	drills, err := r.restoreDrillRepository.GetByStatus(ctx, repository.DrillRunning)
+	if err != nil {
+		glog.Errorf("could not get running restore drills: %s", err)
+		taskFailed(ctx, fmt.Errorf("could not get running restore drills: %s", err))
+		return
+	}
+
+	for _, drill := range drills {
+		err := r.evaluateDrill(ctx, drill)
+		if err != nil {
+			glog.Warningf("[FAIL] Error evaluating restore drill %s: %s", drill, err)
+			itemFailed(ctx, fmt.Errorf("error evaluating restore drill %s: %s", drill.ID, err))
+		} else {
+			itemProcessed(ctx)
+		}
+	}
+}
+
+func (r *restoreDrillService) evaluateDrill(ctxIn context.Context, drill *repository.RestoreDrill) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).evaluateDrill")
+	defer span.End()
+
+	restoreJob, err := r.restoreJobRepository.GetRestoreJob(ctx, drill.RestoreJobID)
+	if err != nil {
+		return fmt.Errorf("could not get restore job %s: %s", drill.RestoreJobID, err)
+	}
+
+	var patch repository.RestoreDrillPatch
+	if !restoreJob.Status.IsFinal() {
+		if time.Since(drill.CreatedTimestamp) < restoreDrillTimeout {
+			return nil
+		}
+		patch = restoreDrillResult(drill, time.Since(drill.CreatedTimestamp), nil, fmt.Errorf("restore job did not finish within %s", restoreDrillTimeout))
+	} else if restoreJob.Status != repository.RestoreFinishedOk {
+		patch = restoreDrillResult(drill, restoreJob.UpdatedTimestamp.Sub(drill.CreatedTimestamp), nil, fmt.Errorf("restore job finished in status %s: %s", restoreJob.Status, restoreJob.ErrorMessage))
+	} else {
+		restoredCount, err := r.countRestored(ctx, drill, restoreJob)
+		if err != nil {
+			err = fmt.Errorf("could not count restored data: %s", err)
+		}
+		patch = restoreDrillResult(drill, restoreJob.UpdatedTimestamp.Sub(drill.CreatedTimestamp), restoredCount, err)
+	}
+
+	if restoreJob.Status.IsFinal() {
+		r.cleanupDrill(ctx, drill, restoreJob)
+	}
+
+	err = r.restoreDrillRepository.PatchRestoreDrill(ctx, patch)
+	if err != nil {
+		return err
+	}
+
+	if patch.Status == repository.DrillFailed {
+		glog.Warningf("[FAIL] Restore drill %s failed: %s", drill, patch.ErrorMessage)
+		r.notifyRestoreDrillFailed(ctx, drill, patch.ErrorMessage)
+	} else if patch.RTOBreached {
+		glog.Warningf("[FAIL] Restore drill %s took %ds and exceeded the recovery time objective of %d minutes", drill, patch.ElapsedSeconds, drill.RecoveryTimeObjective)
+		r.notifyRestoreDrillFailed(ctx, drill, fmt.Sprintf("restore took %ds and exceeded the recovery time objective of %d minutes", patch.ElapsedSeconds, drill.RecoveryTimeObjective))
+	} else {
+		glog.Infof("[SUCCESS] Restore drill %s restored %d in %ds", drill, *patch.RestoredCount, patch.ElapsedSeconds)
+	}
+	return nil
+}
+
+func (r *restoreDrillService) notifyRestoreDrillFailed(ctxIn context.Context, drill *repository.RestoreDrill, reason string) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).notifyRestoreDrillFailed")
+	defer span.End()
+
+	backup, err := r.backupRepository.GetBackup(ctx, drill.BackupID)
+	if err != nil {
+		glog.Warningf("could not notify about failed restore drill %s, backup %s not found: %s", drill.ID, drill.BackupID, err)
+		return
+	}
+	notifyRestoreDrillFailed(ctx, backup, drill, reason)
+}
+
+// restoreDrillResult judge a drill by its restored data and elapsed time, restoredCount is nil if nothing could be counted
+func restoreDrillResult(drill *repository.RestoreDrill, elapsed time.Duration, restoredCount *int64, err error) repository.RestoreDrillPatch {
+	patch := repository.RestoreDrillPatch{
+		ID:             drill.ID,
+		Status:         repository.DrillSucceeded,
+		RestoredCount:  restoredCount,
+		ElapsedSeconds: int(elapsed.Seconds()),
+		RTOBreached:    drill.RecoveryTimeObjective > 0 && elapsed > time.Duration(drill.RecoveryTimeObjective)*time.Minute,
+	}
+
+	if err != nil {
+		patch.Status = repository.DrillFailed
+		patch.ErrorMessage = err.Error()
+	} else if restoredCount == nil {
+		patch.Status = repository.DrillFailed
+		patch.ErrorMessage = "restored data could not be counted"
+	} else if drill.ExpectedCount != nil && *drill.ExpectedCount != *restoredCount {
+		patch.Status = repository.DrillFailed
+		patch.ErrorMessage = fmt.Sprintf("restored %d but expected %d", *restoredCount, *drill.ExpectedCount)
+	}
+
+	return patch
+}
+
+func (r *restoreDrillService) countRestored(ctxIn context.Context, drill *repository.RestoreDrill, restoreJob *repository.RestoreJob) (*int64, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).countRestored")
+	defer span.End()
+
+	backup, err := r.backupRepository.GetBackup(ctx, drill.BackupID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get backup %s: %s", drill.BackupID, err)
+	}
+
+	switch drill.Type {
+	case repository.BigQuery:
+		loadJobHandler, err := bigquery.NewLoadJobHandler(ctx, r.tokenSourceProvider, restoreJob.TargetProject, backup.TargetProject)
+		if err != nil {
+			return nil, err
+		}
+		defer loadJobHandler.Close(ctx)
+		table, err := loadJobHandler.GetTable(ctx, restoreJob.TargetProject, restoreJob.TargetDataset, restoreJob.TargetTable)
+		if err != nil {
+			return nil, err
+		}
+		return &table.NumRows, nil
+	case repository.CloudStorage:
+		gcsClient, err := gcs.NewCloudStorageClient(ctx, r.tokenSourceProvider, backup.TargetProject)
+		if err != nil {
+			return nil, err
+		}
+		defer gcsClient.Close(ctx)
+
+		count, err := gcsClient.CountObjectsWithPrefix(ctx, restoreJob.TargetBucket, restoreDrillPath(drill.ID))
+		if err != nil {
+			return nil, err
+		}
+		return &count, nil
+	}
+
+	return nil, fmt.Errorf("restore drill type %s is not supported", drill.Type)
+}
+
+// cleanupDrill removes the restored table or objects once the drill is judged, a restore job which is still running
+// could write them again, so a timed out drill leaves its scratch table to the expiration of the drill dataset
+func (r *restoreDrillService) cleanupDrill(ctxIn context.Context, drill *repository.RestoreDrill, restoreJob *repository.RestoreJob) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).cleanupDrill")
+	defer span.End()
+
+	backup, err := r.backupRepository.GetBackup(ctx, drill.BackupID)
+	if err != nil {
+		glog.Warningf("could not cleanup restore drill %s: %s", drill, err)
+		return
+	}
+
+	switch drill.Type {
+	case repository.BigQuery:
+		loadJobHandler, err := bigquery.NewLoadJobHandler(ctx, r.tokenSourceProvider, restoreJob.TargetProject, backup.TargetProject)
+		if err != nil {
+			glog.Warningf("could not cleanup restore drill %s: %s", drill, err)
+			return
+		}
+		defer loadJobHandler.Close(ctx)
+		if err := loadJobHandler.DeleteTable(ctx, restoreJob.TargetProject, restoreJob.TargetDataset, restoreJob.TargetTable); err != nil {
+			glog.Warningf("could not cleanup restore drill %s: %s", drill, err)
+		}
+	case repository.CloudStorage:
+		gcsClient, err := gcs.NewCloudStorageClient(ctx, r.tokenSourceProvider, backup.TargetProject)
+		if err != nil {
+			glog.Warningf("could not cleanup restore drill %s: %s", drill, err)
+			return
+		}
+		defer gcsClient.Close(ctx)
+
+		if err := gcsClient.DeleteObjectWithPrefix(ctx, r.drillBucket, restoreDrillPath(drill.ID)); err != nil {
+			glog.Warningf("could not cleanup restore drill %s: %s", drill, err)
+		}
+	}
+}
+
+func (r *restoreDrillService) startDrills(ctxIn context.Context) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).startDrills")
+	defer span.End()
+
+	var backups []*repository.Backup
+	for _, status := range restoreDrillBackupStatuses {
+		backupsForStatus, err := r.backupRepository.GetByBackupStatus(ctx, status)
+		if err != nil {
+			glog.Errorf("could not get backups with status %s: %s", status, err)
+			taskFailed(ctx, fmt.Errorf("could not get backups with status %s: %s", status, err))
+			return
+		}
+		backups = append(backups, backupsForStatus...)
+	}
+
+	started := 0
+	for _, backup := range backups {
+		if started >= maxRestoreDrillsPerRun {
+			glog.Infof("Started %d restore drills, remaining backups are drilled in the next run", started)
+			return
+		}
+
+		due, availabilityClass, err := r.isDrillDue(ctx, backup)
+		if err != nil {
+			glog.Warningf("could not check if restore drill is due for backup %s: %s", backup.ID, err)
+			itemFailed(ctx, fmt.Errorf("could not check if restore drill is due for backup %s: %s", backup.ID, err))
+			continue
+		}
+		if !due {
+			continue
+		}
+
+		glog.Infof("[START] Restore drill for backup %s", backup.ID)
+		drill, err := r.startDrill(ctx, backup, availabilityClass)
+		if errors.Is(err, errNothingToDrill) {
+			glog.Infof("Skipping restore drill for backup %s: %s", backup.ID, err)
+			continue
+		}
+		if err != nil {
+			glog.Warningf("[FAIL] Error starting restore drill for backup %s: %s", backup.ID, err)
+			itemFailed(ctx, fmt.Errorf("error starting restore drill for backup %s: %s", backup.ID, err))
+			continue
+		}
+		glog.Infof("[SUCCESS] Started restore drill %s", drill)
+		itemProcessed(ctx)
+		started++
+	}
+}
+
+func (r *restoreDrillService) isDrillDue(ctxIn context.Context, backup *repository.Backup) (bool, provider.AvailabilityClass, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).isDrillDue")
+	defer span.End()
+
+	if backup.Type != repository.BigQuery && backup.Type != repository.CloudStorage {
+		return false, "", nil
+	}
+	if backup.Type == repository.CloudStorage && r.drillBucket == "" {
+		return false, "", nil
+	}
+
+	sourceProject, err := r.sourceGCPProjectProvider.GetSourceGCPProject(ctx, backup.SourceProject)
+	if err != nil {
+		return false, "", err
+	}
+	interval, exists := restoreDrillIntervals[sourceProject.AvailabilityClass]
+	if !exists {
+		return false, sourceProject.AvailabilityClass, nil
+	}
+
+	drills, err := r.restoreDrillRepository.GetLatestForBackupID(ctx, backup.ID, 1)
+	if err != nil {
+		return false, sourceProject.AvailabilityClass, err
+	}
+	due := len(drills) == 0 || time.Since(drills[0].CreatedTimestamp) >= interval
+	return due, sourceProject.AvailabilityClass, nil
+}
+
+// startDrill restore a sample of the backup, a drill which could not be started is stored as failed
+func (r *restoreDrillService) startDrill(ctxIn context.Context, backup *repository.Backup, availabilityClass provider.AvailabilityClass) (*repository.RestoreDrill, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).startDrill")
+	defer span.End()
+
+	drill := &repository.RestoreDrill{
+		ID:                    uuid.New().String(),
+		BackupID:              backup.ID,
+		Type:                  backup.Type,
+		Status:                repository.DrillRunning,
+		AvailabilityClass:     string(availabilityClass),
+		RecoveryTimeObjective: backup.RecoveryTimeObjective,
+	}
+	restoreJob := &repository.RestoreJob{
+		ID:            uuid.New().String(),
+		RestoreID:     drill.ID,
+		BackupID:      backup.ID,
+		Type:          backup.Type,
+		Status:        repository.RestoreNotScheduled,
+		TargetProject: r.drillProject,
+	}
+	drill.RestoreJobID = restoreJob.ID
+
+	var start func(context.Context) (repository.RestoreForeignJobID, error)
+	var err error
+	switch backup.Type {
+	case repository.BigQuery:
+		start, err = r.prepareBigQueryDrill(ctx, backup, drill, restoreJob)
+	case repository.CloudStorage:
+		start, err = r.prepareCloudStorageDrill(ctx, backup, drill, restoreJob)
+	default:
+		err = fmt.Errorf("restore drill type %s is not supported", backup.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.restoreJobRepository.AddRestoreJobs(ctx, []*repository.RestoreJob{restoreJob}); err != nil {
+		return nil, err
+	}
+	if err := r.restoreDrillRepository.AddRestoreDrill(ctx, drill); err != nil {
+		return nil, err
+	}
+
+	foreignJobID, startErr := start(ctx)
+	restoreJobPatch := repository.RestoreJobPatch{ID: restoreJob.ID, Status: repository.RestoreScheduled, RestoreForeignJobID: foreignJobID}
+	if startErr != nil {
+		restoreJobPatch.Status = repository.RestoreError
+		restoreJobPatch.ErrorMessage = startErr.Error()
+	}
+	if err := r.restoreJobRepository.PatchRestoreJobStatus(ctx, restoreJobPatch); err != nil {
+		return nil, err
+	}
+	if startErr != nil {
+		patch := restoreDrillResult(drill, 0, nil, fmt.Errorf("could not start restore job: %s", startErr))
+		if err := r.restoreDrillRepository.PatchRestoreDrill(ctx, patch); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("could not start restore job %s: %s", restoreJob.ID, startErr)
+	}
+
+	return drill, nil
+}
+
+// prepareBigQueryDrill pick a random table or partition of the latest backup state, the restored rows are compared with
+// the row count the backup job recorded when it was prepared, the live source may have changed since then
+func (r *restoreDrillService) prepareBigQueryDrill(ctxIn context.Context, backup *repository.Backup, drill *repository.RestoreDrill, restoreJob *repository.RestoreJob) (func(context.Context) (repository.RestoreForeignJobID, error), error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).prepareBigQueryDrill")
+	defer span.End()
+
+	jobs, err := r.jobRepository.GetBackupRestoreJobs(ctx, backup.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, errNothingToDrill
+	}
+	job := jobs[rand.Intn(len(jobs))]
+
+	drill.ExpectedCount = job.SourceRowCount
+	if drill.ExpectedCount == nil {
+		glog.Infof("Restore drill for backup %s can not compare row count, job %s recorded no row count of %s", backup.ID, job.ID, job.Source)
+	}
+
+	restoreJob.BackupJobID = job.ID
+	restoreJob.Source = job.Source
+	restoreJob.TargetDataset = r.drillDataset
+	restoreJob.TargetTable = restoreDrillTable(drill.ID)
+	drill.Source = fmt.Sprintf("%s.%s.%s", backup.SourceProject, backup.BigQueryOptions.Dataset, job.Source)
+	drill.Target = fmt.Sprintf("%s.%s.%s", restoreJob.TargetProject, restoreJob.TargetDataset, restoreJob.TargetTable)
+
+	if backup.Strategy == repository.TableSnapshot {
+		snapshotTable := repository.BuildTableSnapshotName(job.Source, job.ID)
+		return func(ctx context.Context) (repository.RestoreForeignJobID, error) {
+			loadJobHandler, err := bigquery.NewLoadJobHandler(ctx, r.tokenSourceProvider, r.drillProject, backup.TargetProject)
+			if err != nil {
+				return repository.RestoreForeignJobID{}, err
+			}
+			defer loadJobHandler.Close(ctx)
+
+			loadJobID, err := loadJobHandler.CreateRestoreSnapshotJob(ctx, backup.TargetProject, repository.BuildTableSnapshotDataset(backup.Sink), snapshotTable, restoreJob.TargetProject, restoreJob.TargetDataset, restoreJob.TargetTable, bq.WriteTruncate)
+			return repository.RestoreForeignJobID{BigQueryID: loadJobID}, err
+		}, nil
+	}
+	sourceURI := repository.BuildFullObjectStoragePath(backup.Sink, backup.BigQueryOptions.Dataset, job.Source, job.ID, backup.BigQueryOptions.FileExtension())
+	return func(ctx context.Context) (repository.RestoreForeignJobID, error) {
+		loadJobHandler, err := bigquery.NewLoadJobHandler(ctx, r.tokenSourceProvider, r.drillProject, backup.TargetProject)
+		if err != nil {
+			return repository.RestoreForeignJobID{}, err
+		}
+		defer loadJobHandler.Close(ctx)
+
+		loadJobID, err := loadJobHandler.CreateLoadJob(ctx, restoreJob.TargetProject, restoreJob.TargetDataset, restoreJob.TargetTable, sourceURI, backup.BigQueryOptions.GetExportFormat(), bq.WriteTruncate)
+		return repository.RestoreForeignJobID{BigQueryID: loadJobID}, err
+	}, nil
+}
+
+// prepareCloudStorageDrill pick a random top level prefix of the sink and count the objects in it
+func (r *restoreDrillService) prepareCloudStorageDrill(ctxIn context.Context, backup *repository.Backup, drill *repository.RestoreDrill, restoreJob *repository.RestoreJob) (func(context.Context) (repository.RestoreForeignJobID, error), error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreDrillService).prepareCloudStorageDrill")
+	defer span.End()
+
+	gcsClient, err := gcs.NewCloudStorageClient(ctx, r.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return nil, err
+	}
+	defer gcsClient.Close(ctx)
+
+	prefixes, err := gcsClient.ListPrefixes(ctx, backup.Sink, "")
+	if err != nil {
+		return nil, err
+	}
+	trashcanPath := backup.GetTrashcanPath() + "/"
+	var candidates []string
+	for _, prefix := range prefixes {
+		if prefix != trashcanPath {
+			candidates = append(candidates, prefix)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, errNothingToDrill
+	}
+	prefix := candidates[rand.Intn(len(candidates))]
+
+	expectedCount, err := gcsClient.CountObjectsWithPrefix(ctx, backup.Sink, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if expectedCount == 0 {
+		return nil, errNothingToDrill
+	}
+	drill.ExpectedCount = &expectedCount
+
+	targetPath := restoreDrillPath(drill.ID)
+	restoreJob.Source = fmt.Sprintf("gs://%s/%s", backup.Sink, prefix)
+	restoreJob.TargetBucket = r.drillBucket
+	drill.Source = restoreJob.Source
+	drill.Target = fmt.Sprintf("gs://%s/%s", r.drillBucket, targetPath)
+
+	return func(ctx context.Context) (repository.RestoreForeignJobID, error) {
+		transferJobHandler, err := gcs.NewTransferJobHandler(ctx, r.tokenSourceProvider, backup.TargetProject)
+		if err != nil {
+			return repository.RestoreForeignJobID{}, err
+		}
+		defer transferJobHandler.Close(ctx)
+
+		transferJobID, err := transferJobHandler.CreateRestoreTransferJob(ctx, backup.TargetProject, backup.Sink, prefix, restoreJob.TargetProject, restoreJob.TargetBucket, targetPath, nil, nil, gcs.RestoreTransferOptions{Overwrite: gcs.OverwriteAlways})
+		return repository.RestoreForeignJobID{CloudStorageID: repository.TransferJobID(transferJobID)}, err
+	}, nil
+}
+
+func restoreDrillTable(drillID string) string {
+	return "drill_" + strings.ReplaceAll(drillID, "-", "_")
+}
+
+func restoreDrillPath(drillID string) string {
+	return fmt.Sprintf("drill_%s/", drillID)
+}
@@ -0,0 +1,46 @@
+package tasks
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestoreDrillResult(t *testing.T) {
+	expected := int64(10)
+	matching := int64(10)
+	missing := int64(9)
+
+	for _, tc := range []struct {
+		name          string
+		drill         repository.RestoreDrill
+		elapsed       time.Duration
+		restoredCount *int64
+		err           error
+		status        repository.RestoreDrillStatus
+		rtoBreached   bool
+	}{
+		{name: "matching count", drill: repository.RestoreDrill{ExpectedCount: &expected, RecoveryTimeObjective: 60}, elapsed: 10 * time.Minute, restoredCount: &matching, status: repository.DrillSucceeded},
+		{name: "unknown expected count", drill: repository.RestoreDrill{}, elapsed: 10 * time.Minute, restoredCount: &missing, status: repository.DrillSucceeded},
+		{name: "missing rows", drill: repository.RestoreDrill{ExpectedCount: &expected}, elapsed: 10 * time.Minute, restoredCount: &missing, status: repository.DrillFailed},
+		{name: "restore error", drill: repository.RestoreDrill{ExpectedCount: &expected}, elapsed: 10 * time.Minute, err: errors.New("load failed"), status: repository.DrillFailed},
+		{name: "rto exceeded", drill: repository.RestoreDrill{ExpectedCount: &expected, RecoveryTimeObjective: 5}, elapsed: 10 * time.Minute, restoredCount: &matching, status: repository.DrillSucceeded, rtoBreached: true},
+		{name: "no rto", drill: repository.RestoreDrill{ExpectedCount: &expected}, elapsed: 48 * time.Hour, restoredCount: &matching, status: repository.DrillSucceeded},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			patch := restoreDrillResult(&tc.drill, tc.elapsed, tc.restoredCount, tc.err)
+			assert.Equal(t, tc.status, patch.Status)
+			assert.Equal(t, tc.rtoBreached, patch.RTOBreached)
+			assert.Equal(t, int(tc.elapsed.Seconds()), patch.ElapsedSeconds)
+			assert.Equal(t, tc.status == repository.DrillFailed, patch.ErrorMessage != "")
+		})
+	}
+}
+
+func TestRestoreDrillTable(t *testing.T) {
+	assert.Equal(t, "drill_0b4e_11aa", restoreDrillTable("0b4e-11aa"))
+	assert.Equal(t, "drill_0b4e-11aa/", restoreDrillPath("0b4e-11aa"))
+}
@@ -0,0 +1,199 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/ottogroup/penelope/pkg/service/gcs"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type restoreJobStatusService struct {
+	backupRepository     repository.BackupRepository
+	restoreJobRepository repository.RestoreJobRepository
+	tokenSourceProvider  impersonate.TargetPrincipalForProjectProvider
+	backups              map[string]*repository.Backup
+	loadJobHandlers      map[string]*bigquery.LoadJobHandler
+}
+
+func newRestoreJobStatusService(ctxIn context.Context, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) (*restoreJobStatusService, error) {
+	ctx, span := trace.StartSpan(ctxIn, "newRestoreJobStatusService")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &restoreJobStatusService{}, fmt.Errorf("could not instantiate new BackupRepository: %s", err)
+	}
+	restoreJobRepository, err := repository.NewRestoreJobRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &restoreJobStatusService{}, fmt.Errorf("could not instantiate new RestoreJobRepository: %s", err)
+	}
+
+	return &restoreJobStatusService{
+		backupRepository:     backupRepository,
+		restoreJobRepository: restoreJobRepository,
+		tokenSourceProvider:  tokenSourceProvider,
+		backups:              make(map[string]*repository.Backup),
+		loadJobHandlers:      make(map[string]*bigquery.LoadJobHandler),
+	}, nil
+}
+
+func (r *restoreJobStatusService) Run(ctxIn context.Context) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreJobStatusService).Run")
+	defer span.End()
+	defer r.closeLoadJobHandlers(ctx)
+
+	jobs, err := r.restoreJobRepository.GetByStatus(ctx, repository.RestoreScheduled)
+	if err != nil {
+		glog.Errorf("could not get scheduled restore jobs: %s", err)
+		taskFailed(ctx, errors.Wrap(err, "could not get scheduled restore jobs"))
+		return
+	}
+	if len(jobs) == 0 {
+		glog.Infof("No restore jobs to check status")
+		return
+	}
+
+	glog.Infof("Checking status of %d restore jobs", len(jobs))
+	for _, job := range jobs {
+		glog.Infof("[START] Checking status of restore job %s", job)
+		err := r.checkRestoreJob(ctx, job)
+		if err != nil {
+			glog.Warningf("[FAIL] Error checking status of restore job %s: %s", job, err)
+			itemFailed(ctx, errors.Wrapf(err, "error checking status of restore job %s", job.ID))
+		} else {
+			glog.Infof("[SUCCESS] Checking status finished for restore job %s", job)
+			itemProcessed(ctx)
+		}
+	}
+}
+
+func (r *restoreJobStatusService) checkRestoreJob(ctxIn context.Context, job *repository.RestoreJob) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreJobStatusService).checkRestoreJob")
+	defer span.End()
+
+	switch job.Type {
+	case repository.BigQuery:
+		return r.checkBigQueryRestoreJob(ctx, job)
+	case repository.CloudStorage:
+		return r.checkCloudStorageRestoreJob(ctx, job)
+	}
+
+	return fmt.Errorf("restore job type %s is not supported", job.Type)
+}
+
+func (r *restoreJobStatusService) checkBigQueryRestoreJob(ctxIn context.Context, job *repository.RestoreJob) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreJobStatusService).checkBigQueryRestoreJob")
+	defer span.End()
+
+	handler, err := r.loadJobHandler(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	state, err := handler.GetStatusOfJob(ctx, job.BigQueryID)
+	patch := repository.RestoreJobPatch{ID: job.ID, RestoreForeignJobID: job.RestoreForeignJobID}
+	switch state {
+	case bigquery.Done:
+		patch.Status = repository.RestoreFinishedOk
+	case bigquery.Failed:
+		patch.Status = repository.RestoreFinishedError
+		patch.ErrorMessage = err.Error()
+	default:
+		if err != nil {
+			return fmt.Errorf("could not get status of load job %s: %s", job.BigQueryID, err)
+		}
+		return nil
+	}
+
+	return r.restoreJobRepository.PatchRestoreJobStatus(ctx, patch)
+}
+
+func (r *restoreJobStatusService) checkCloudStorageRestoreJob(ctxIn context.Context, job *repository.RestoreJob) error {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreJobStatusService).checkCloudStorageRestoreJob")
+	defer span.End()
+
+	transferJobID := job.CloudStorageID.String()
+	if len(transferJobID) == 0 {
+		return fmt.Errorf("could not check status of restore job %s without cloudstorage transferJobID", job.ID)
+	}
+
+	backup, err := r.getBackup(ctx, job.BackupID)
+	if err != nil {
+		return err
+	}
+
+	// restore transfers run in the sink project like the backup transfers
+	jobHandler, err := gcs.NewTransferJobHandler(ctx, r.tokenSourceProvider, backup.TargetProject)
+	if err != nil {
+		return fmt.Errorf("could not create TransferJobHandler: %s", err)
+	}
+	defer jobHandler.Close(ctx)
+
+	state, err := jobHandler.GetStatusOfJob(ctx, backup.TargetProject, transferJobID)
+	patch := repository.RestoreJobPatch{ID: job.ID, RestoreForeignJobID: job.RestoreForeignJobID}
+	switch state {
+	case gcs.Done:
+		patch.Status = repository.RestoreFinishedOk
+	case gcs.Failed:
+		patch.Status = repository.RestoreFinishedError
+		patch.ErrorMessage = err.Error()
+	default:
+		if err != nil {
+			return fmt.Errorf("could not get status of transfer job %s: %s", transferJobID, err)
+		}
+		return nil
+	}
+
+	return r.restoreJobRepository.PatchRestoreJobStatus(ctx, patch)
+}
+
+func (r *restoreJobStatusService) getBackup(ctxIn context.Context, backupID string) (*repository.Backup, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreJobStatusService).getBackup")
+	defer span.End()
+
+	if backup, exists := r.backups[backupID]; exists {
+		return backup, nil
+	}
+	backup, err := r.backupRepository.GetBackup(ctx, backupID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get backup %s: %s", backupID, err)
+	}
+	r.backups[backupID] = backup
+	return backup, nil
+}
+
+// closeLoadJobHandlers closes the load job handlers cached during a run
+func (r *restoreJobStatusService) closeLoadJobHandlers(ctxIn context.Context) {
+	for key, handler := range r.loadJobHandlers {
+		handler.Close(ctxIn)
+		delete(r.loadJobHandlers, key)
+	}
+}
+
+func (r *restoreJobStatusService) loadJobHandler(ctxIn context.Context, job *repository.RestoreJob) (*bigquery.LoadJobHandler, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*restoreJobStatusService).loadJobHandler")
+	defer span.End()
+
+	backup, err := r.getBackup(ctx, job.BackupID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s/%s", job.TargetProject, backup.TargetProject)
+	if handler, exists := r.loadJobHandlers[key]; exists {
+		return handler, nil
+	}
+	handler, err := bigquery.NewLoadJobHandler(ctx, r.tokenSourceProvider, job.TargetProject, backup.TargetProject)
+	if err != nil {
+		return nil, err
+	}
+	r.loadJobHandlers[key] = handler
+	return handler, nil
+}
@@ -0,0 +1,42 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/service/bigquery"
+	"github.com/stretchr/testify/assert"
+)
+
+type closeCountingBigQueryClient struct {
+	bigquery.Client
+	closed int
+}
+
+func (c *closeCountingBigQueryClient) Close(context.Context) {
+	c.closed++
+}
+
+type noScheduledRestoreJobRepository struct {
+	repository.RestoreJobRepository
+}
+
+func (noScheduledRestoreJobRepository) GetByStatus(context.Context, ...repository.RestoreJobStatus) ([]*repository.RestoreJob, error) {
+	return nil, nil
+}
+
+func TestRestoreJobStatusService_ClosesCachedLoadJobHandlers(t *testing.T) {
+	client := &closeCountingBigQueryClient{}
+	service := &restoreJobStatusService{
+		restoreJobRepository: noScheduledRestoreJobRepository{},
+		loadJobHandlers: map[string]*bigquery.LoadJobHandler{
+			"restore-project/sink-project": bigquery.NewLoadJobHandlerWithClient(client),
+		},
+	}
+
+	service.Run(context.Background())
+
+	assert.Equal(t, 1, client.closed)
+	assert.Empty(t, service.loadJobHandlers)
+}
@@ -0,0 +1,91 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/processor"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+type retryFailedJobsService struct {
+	scheduleProcessor processor.ScheduleProcessor
+}
+
+func newRetryFailedJobsService(ctxIn context.Context, credentialsProvider secret.SecretProvider) (*retryFailedJobsService, error) {
+	ctx, span := trace.StartSpan(ctxIn, "newRetryFailedJobsService")
+	defer span.End()
+
+	scheduleProcessor, err := processor.NewScheduleProcessor(ctx, credentialsProvider)
+	if err != nil {
+		return &retryFailedJobsService{}, fmt.Errorf("could not instantiate new ScheduleProcessor: %s", err)
+	}
+
+	return &retryFailedJobsService{scheduleProcessor: scheduleProcessor}, nil
+}
+
+// Run requeue failed jobs whose next attempt is due, the backoff was planned when the job failed
+func (r *retryFailedJobsService) Run(ctxIn context.Context) {
+	ctx, span := trace.StartSpan(ctxIn, "(*retryFailedJobsService).Run")
+	defer span.End()
+
+	glog.Infof("[START] Retry failed jobs")
+	jobs, err := r.scheduleProcessor.GetJobsToRetry(ctx, getCurrentTime())
+	if err != nil {
+		glog.Errorf("[FAIL] could not get failed jobs to retry: %s", err)
+		taskFailed(ctx, errors.Wrap(err, "could not get failed jobs to retry"))
+		return
+	}
+	if len(jobs) == 0 {
+		glog.Infof("[SUCCESS] No failed jobs to retry")
+		return
+	}
+
+	glog.Infof("Retrying %d failed jobs", len(jobs))
+	activeByBackup := map[string]bool{}
+	failedToRequeueCount := 0
+	for _, job := range jobs {
+		active, ok := activeByBackup[job.BackupID]
+		if !ok {
+			active = r.isBackupActive(ctx, job.BackupID)
+			activeByBackup[job.BackupID] = active
+		}
+		if !active {
+			glog.Infof("Skipping retry of job %s because backup %s is not active", job.ID, job.BackupID)
+			continue
+		}
+
+		err = r.scheduleProcessor.RequeueJob(ctx, job.ID)
+		if err != nil {
+			glog.Warningf("[FAIL] not able to requeue job with ID %s: %s", job.ID, err)
+			itemFailed(ctx, errors.Wrapf(err, "not able to requeue job with ID %s", job.ID))
+			failedToRequeueCount++
+			continue
+		}
+		recordJobEvent(ctx, r.scheduleProcessor, job, repository.NotScheduled, nil)
+		itemProcessed(ctx)
+		glog.Infof("Requeued job %s for attempt %d after error: %s", job.ID, job.Attempts+1, job.LastErrorMessage)
+	}
+	if failedToRequeueCount != 0 {
+		glog.Infof("[FAIL] %d jobs where not requeued", failedToRequeueCount)
+		return
+	}
+	glog.Infof("[SUCCESS] Failed jobs where requeued")
+}
+
+func (r *retryFailedJobsService) isBackupActive(ctxIn context.Context, backupID string) bool {
+	ctx, span := trace.StartSpan(ctxIn, "(*retryFailedJobsService).isBackupActive")
+	defer span.End()
+
+	backup, err := r.scheduleProcessor.GetBackupForID(ctx, backupID)
+	if err != nil {
+		glog.Warningf("could not get backup with id %s: %s", backupID, err)
+		return false
+	}
+
+	return backup.Status == repository.NotStarted || backup.Status == repository.Prepared || backup.Status == repository.Finished
+}
@@ -0,0 +1,168 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// snapshotRunTolerance is how long before the last scheduling the jobs of a snapshot run may have been created,
+// a source without job in that time was not part of the last run anymore
+const snapshotRunTolerance = time.Hour
+
+// rpoBackupStatuses backups in these statuses are expected to keep their recovery point objective
+var rpoBackupStatuses = []repository.BackupStatus{repository.NotStarted, repository.Prepared, repository.Finished}
+
+type rpoViolationService struct {
+	backupRepository          repository.BackupRepository
+	jobRepository             repository.JobRepository
+	sourceFreshnessRepository repository.SourceFreshnessRepository
+}
+
+func newRPOViolationService(ctxIn context.Context, credentialsProvider secret.SecretProvider) (*rpoViolationService, error) {
+	ctx, span := trace.StartSpan(ctxIn, "newRPOViolationService")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &rpoViolationService{}, fmt.Errorf("could not instantiate new BackupRepository: %s", err)
+	}
+	jobRepository, err := repository.NewJobRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &rpoViolationService{}, fmt.Errorf("could not instantiate new JobRepository: %s", err)
+	}
+	sourceFreshnessRepository, err := repository.NewSourceFreshnessRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &rpoViolationService{}, fmt.Errorf("could not instantiate new SourceFreshnessRepository: %s", err)
+	}
+
+	return &rpoViolationService{
+		backupRepository:          backupRepository,
+		jobRepository:             jobRepository,
+		sourceFreshnessRepository: sourceFreshnessRepository,
+	}, nil
+}
+
+// Run records for every source of an active backup how old its newest recovery point is and whether it violates the RPO
+func (r *rpoViolationService) Run(ctxIn context.Context) {
+	ctx, span := trace.StartSpan(ctxIn, "(*rpoViolationService).Run")
+	defer span.End()
+
+	glog.Infof("[START] Check RPO violations")
+	violatingBackups := 0
+	for _, status := range rpoBackupStatuses {
+		backups, err := r.backupRepository.GetByBackupStatus(ctx, status)
+		if err != nil {
+			glog.Errorf("[FAIL] could not get backups with status %s: %s", status, err)
+			taskFailed(ctx, errors.Wrapf(err, "could not get backups with status %s", status))
+			return
+		}
+
+		for _, backup := range backups {
+			if backup.RecoveryPointObjective <= 0 || backup.IsOneshot() {
+				continue
+			}
+			violations, err := r.checkBackup(ctx, backup)
+			if err != nil {
+				glog.Warningf("[FAIL] Error checking RPO of backup %s: %s", backup.ID, err)
+				itemFailed(ctx, errors.Wrapf(err, "error checking RPO of backup %s", backup.ID))
+				continue
+			}
+			if len(violations) > 0 {
+				glog.Warningf("Backup %s violates its RPO of %dh for %d sources", backup.ID, backup.RecoveryPointObjective, len(violations))
+				notifyRPOViolated(ctx, backup, violations)
+				violatingBackups++
+			}
+			itemProcessed(ctx)
+		}
+	}
+	glog.Infof("[SUCCESS] Checked RPO violations, %d backups violate their RPO", violatingBackups)
+}
+
+// checkBackup records the freshness of the sources of the backup and returns the sources violating the RPO
+func (r *rpoViolationService) checkBackup(ctxIn context.Context, backup *repository.Backup) ([]string, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*rpoViolationService).checkBackup")
+	defer span.End()
+
+	points, err := r.jobRepository.GetSourceRecoveryPoints(ctx, backup.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	freshness := evaluateSourceFreshness(backup, points, getCurrentTime())
+	var violations []string
+	for _, source := range freshness {
+		if source.RPOViolated {
+			violations = append(violations, source.Source)
+		}
+	}
+	return violations, r.sourceFreshnessRepository.ReplaceForBackupID(ctx, backup.ID, freshness)
+}
+
+// evaluateSourceFreshness compares the newest recovery point of every source of the backup with its RPO,
+// a source which never finished is measured from its first job
+func evaluateSourceFreshness(backup *repository.Backup, points []repository.SourceRecoveryPoint, now time.Time) []*repository.SourceFreshness {
+	var freshness []*repository.SourceFreshness
+	for _, point := range points {
+		if isRetiredSnapshotSource(backup, point) {
+			continue
+		}
+
+		recoveryPoint := point.LastFinishedOkTime
+		if backup.Strategy == repository.Mirror && !recoveryPoint.IsZero() && !point.InProgress &&
+			!point.LastUnfinishedTime.After(recoveryPoint) && backup.LastScheduledTime.After(recoveryPoint) {
+			// a mirror only gets a new job once its source changed, an unchanged source is current as of the last check
+			recoveryPoint = backup.LastScheduledTime
+		}
+		freshness = append(freshness, newSourceFreshness(backup, point.Source, recoveryPoint, point.FirstJobTime, now))
+	}
+
+	if len(freshness) == 0 {
+		freshness = append(freshness, newSourceFreshness(backup, backupSource(backup), time.Time{}, backup.CreatedTimestamp, now))
+	}
+	return freshness
+}
+
+// isRetiredSnapshotSource a snapshot source without job in the last run was deleted or excluded from the backup
+func isRetiredSnapshotSource(backup *repository.Backup, point repository.SourceRecoveryPoint) bool {
+	if backup.Strategy == repository.Mirror || backup.LastScheduledTime.IsZero() || point.InProgress {
+		return false
+	}
+	return point.LastJobTime().Before(backup.LastScheduledTime.Add(-snapshotRunTolerance))
+}
+
+func newSourceFreshness(backup *repository.Backup, source string, recoveryPoint, since, now time.Time) *repository.SourceFreshness {
+	rpo := time.Duration(backup.RecoveryPointObjective) * time.Hour
+	if !recoveryPoint.IsZero() {
+		since = recoveryPoint
+	}
+	return &repository.SourceFreshness{
+		BackupID:               backup.ID,
+		Source:                 source,
+		LastRecoveryPoint:      recoveryPoint,
+		RecoveryPointObjective: backup.RecoveryPointObjective,
+		RPOViolated:            now.Sub(since) > rpo,
+		CheckedTimestamp:       now,
+	}
+}
+
+// backupSource names the source of a backup which has no job yet
+func backupSource(backup *repository.Backup) string {
+	switch backup.Type {
+	case repository.BigQuery:
+		return backup.BigQueryOptions.Dataset
+	case repository.CloudStorage:
+		return backup.CloudStorageOptions.Bucket
+	case repository.Firestore:
+		return backup.FirestoreOptions.Database
+	case repository.CloudSQL:
+		return backup.CloudSQLOptions.Instance
+	}
+	return backup.ID
+}
@@ -0,0 +1,68 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateSourceFreshness_Snapshot(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	backup := &repository.Backup{ID: "backup", Strategy: repository.Snapshot, RecoveryPointObjective: 24, LastScheduledTime: now.Add(-time.Hour)}
+	points := []repository.SourceRecoveryPoint{
+		{Source: "fresh", FirstJobTime: now.Add(-72 * time.Hour), LastFinishedOkTime: now.Add(-2 * time.Hour)},
+		{Source: "failing", FirstJobTime: now.Add(-72 * time.Hour), LastFinishedOkTime: now.Add(-30 * time.Hour), LastUnfinishedTime: now.Add(-90 * time.Minute)},
+		{Source: "never_finished", FirstJobTime: now.Add(-2 * time.Hour), LastUnfinishedTime: now.Add(-2 * time.Hour), InProgress: true},
+		{Source: "dropped", FirstJobTime: now.Add(-72 * time.Hour), LastFinishedOkTime: now.Add(-48 * time.Hour)},
+	}
+
+	freshness := evaluateSourceFreshness(backup, points, now)
+
+	require.Len(t, freshness, 3, "a source without job in the last run is not evaluated")
+	assert.Equal(t, "fresh", freshness[0].Source)
+	assert.False(t, freshness[0].RPOViolated)
+	assert.Equal(t, "failing", freshness[1].Source)
+	assert.True(t, freshness[1].RPOViolated)
+	assert.Equal(t, now.Add(-30*time.Hour), freshness[1].LastRecoveryPoint)
+	assert.Equal(t, "never_finished", freshness[2].Source)
+	assert.False(t, freshness[2].RPOViolated, "a new source is measured from its first job")
+	assert.True(t, freshness[2].LastRecoveryPoint.IsZero())
+	assert.Equal(t, now, freshness[2].CheckedTimestamp)
+}
+
+func TestEvaluateSourceFreshness_Mirror(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	backup := &repository.Backup{ID: "backup", Strategy: repository.Mirror, RecoveryPointObjective: 24, LastScheduledTime: now.Add(-time.Hour)}
+	points := []repository.SourceRecoveryPoint{
+		{Source: "unchanged", FirstJobTime: now.Add(-120 * time.Hour), LastFinishedOkTime: now.Add(-120 * time.Hour)},
+		{Source: "failed_after_change", FirstJobTime: now.Add(-120 * time.Hour), LastFinishedOkTime: now.Add(-120 * time.Hour), LastUnfinishedTime: now.Add(-100 * time.Hour)},
+	}
+
+	freshness := evaluateSourceFreshness(backup, points, now)
+
+	require.Len(t, freshness, 2)
+	assert.False(t, freshness[0].RPOViolated)
+	assert.Equal(t, backup.LastScheduledTime, freshness[0].LastRecoveryPoint, "an unchanged source is current as of the last check")
+	assert.True(t, freshness[1].RPOViolated)
+}
+
+func TestEvaluateSourceFreshness_WithoutJobs(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	backup := &repository.Backup{
+		ID:                     "backup",
+		Type:                   repository.CloudStorage,
+		Strategy:               repository.Snapshot,
+		RecoveryPointObjective: 24,
+		BackupOptions:          repository.BackupOptions{CloudStorageOptions: repository.CloudStorageOptions{Bucket: "source-bucket"}},
+		EntityAudit:            repository.EntityAudit{CreatedTimestamp: now.Add(-48 * time.Hour)},
+	}
+
+	freshness := evaluateSourceFreshness(backup, nil, now)
+
+	require.Len(t, freshness, 1)
+	assert.Equal(t, "source-bucket", freshness[0].Source)
+	assert.True(t, freshness[0].RPOViolated)
+}
@@ -0,0 +1,173 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/ottogroup/penelope/pkg/repository"
+)
+
+// TaskSchedule runs a task every interval
+type TaskSchedule struct {
+	Task     string
+	Interval time.Duration
+}
+
+// DefaultTaskSchedules are the intervals of cron.yaml
+var DefaultTaskSchedules = []TaskSchedule{
+	{Task: PrepareBackupJobs, Interval: 60 * time.Minute},
+	{Task: RunNewJobs, Interval: 10 * time.Minute},
+	{Task: CheckJobsStatus, Interval: 10 * time.Minute},
+	{Task: CheckJobsStuck, Interval: 10 * time.Minute},
+	{Task: CleanupExpiredSinks, Interval: 4 * time.Hour},
+	{Task: RescheduleJobsWithQuotaError, Interval: time.Hour},
+	{Task: RetryFailedJobs, Interval: 10 * time.Minute},
+	{Task: CheckOneShotBackupsStatus, Interval: 15 * time.Minute},
+	{Task: CleanupTrashcans, Interval: 60 * time.Minute},
+	{Task: CheckRestoreJobsStatus, Interval: 5 * time.Minute},
+	{Task: RestoreDrill, Interval: 60 * time.Minute},
+	{Task: CheckRPOViolations, Interval: 30 * time.Minute},
+	{Task: CheckSinkProjectCompliance, Interval: 24 * time.Hour},
+}
+
+// ParseTaskSchedules overrides the default schedules with a comma separated list of task=interval like
+// "run_new_jobs=5m,reconcile=24h", an interval of 0 disables the task
+func ParseTaskSchedules(raw string, defaults []TaskSchedule) ([]TaskSchedule, error) {
+	schedules := slices.Clone(defaults)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		task, rawInterval, found := strings.Cut(entry, "=")
+		task = strings.TrimSpace(task)
+		if !found || !slices.Contains(Tasks, task) {
+			return nil, fmt.Errorf("invalid task schedule %q, expected <task>=<interval> with a known task", entry)
+		}
+		interval, err := time.ParseDuration(strings.TrimSpace(rawInterval))
+		if err != nil || interval < 0 {
+			return nil, fmt.Errorf("invalid interval of task schedule %q", entry)
+		}
+
+		index := slices.IndexFunc(schedules, func(schedule TaskSchedule) bool { return schedule.Task == task })
+		if index < 0 {
+			schedules = append(schedules, TaskSchedule{Task: task, Interval: interval})
+		} else {
+			schedules[index].Interval = interval
+		}
+	}
+
+	return slices.DeleteFunc(schedules, func(schedule TaskSchedule) bool { return schedule.Interval == 0 }), nil
+}
+
+// Scheduler runs tasks in process as an alternative to cron, tasks are run by a bounded worker pool and
+// only by the replica holding the leader lock
+type Scheduler struct {
+	schedules  []TaskSchedule
+	workers    int
+	leaderLock repository.LeaderLock
+	run        func(task string)
+
+	queue      chan string
+	mu         sync.Mutex
+	pending    map[string]bool
+	tickers    sync.WaitGroup
+	workerPool sync.WaitGroup
+}
+
+// NewScheduler create a new Scheduler, run is called with the task on a worker
+func NewScheduler(schedules []TaskSchedule, workers int, leaderLock repository.LeaderLock, run func(task string)) *Scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Scheduler{
+		schedules:  schedules,
+		workers:    workers,
+		leaderLock: leaderLock,
+		run:        run,
+		pending:    map[string]bool{},
+	}
+}
+
+// Start the worker pool and a ticker for every task, no task is triggered anymore once ctx is done
+func (s *Scheduler) Start(ctx context.Context) {
+	// every task is queued at most once, so triggering never blocks
+	s.queue = make(chan string, len(s.schedules))
+
+	for i := 0; i < s.workers; i++ {
+		s.workerPool.Add(1)
+		go s.work()
+	}
+	for _, schedule := range s.schedules {
+		s.tickers.Add(1)
+		go s.tick(ctx, schedule)
+	}
+	go func() {
+		s.tickers.Wait()
+		close(s.queue)
+	}()
+	glog.Infof("Started embedded scheduler with %d tasks and %d workers", len(s.schedules), s.workers)
+}
+
+// Wait until ctx of Start is done and every running task finished, afterward the leadership is released
+func (s *Scheduler) Wait() {
+	s.tickers.Wait()
+	s.workerPool.Wait()
+
+	if err := s.leaderLock.Release(context.Background()); err != nil {
+		glog.Warningf("could not release leader lock of embedded scheduler: %s", err)
+	}
+	glog.Infoln("Stopped embedded scheduler")
+}
+
+func (s *Scheduler) tick(ctx context.Context, schedule TaskSchedule) {
+	defer s.tickers.Done()
+
+	ticker := time.NewTicker(schedule.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.trigger(ctx, schedule.Task)
+		}
+	}
+}
+
+func (s *Scheduler) trigger(ctx context.Context, task string) {
+	isLeader, err := s.leaderLock.TryAcquire(ctx)
+	if err != nil {
+		glog.Warningf("could not acquire leader lock of embedded scheduler for task %s: %s", task, err)
+		return
+	}
+	if !isLeader {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending[task] {
+		glog.Infof("Task %s is still running, skipping this run", task)
+		return
+	}
+	s.pending[task] = true
+	s.queue <- task
+}
+
+func (s *Scheduler) work() {
+	defer s.workerPool.Done()
+
+	for task := range s.queue {
+		s.run(task)
+
+		s.mu.Lock()
+		delete(s.pending, task)
+		s.mu.Unlock()
+	}
+}
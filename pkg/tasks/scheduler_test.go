@@ -0,0 +1,108 @@
+package tasks
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLeaderLock struct {
+	isLeader atomic.Bool
+	released atomic.Bool
+}
+
+func (f *fakeLeaderLock) TryAcquire(ctx context.Context) (bool, error) {
+	return f.isLeader.Load(), nil
+}
+
+func (f *fakeLeaderLock) Release(ctx context.Context) error {
+	f.released.Store(true)
+	return nil
+}
+
+func TestParseTaskSchedules(t *testing.T) {
+	defaults := []TaskSchedule{
+		{Task: RunNewJobs, Interval: 10 * time.Minute},
+		{Task: CheckJobsStatus, Interval: 10 * time.Minute},
+	}
+
+	schedules, err := ParseTaskSchedules(" run_new_jobs=5m, check_jobs_status=0,reconcile=24h", defaults)
+	require.NoError(t, err)
+	assert.Equal(t, []TaskSchedule{{Task: RunNewJobs, Interval: 5 * time.Minute}, {Task: Reconcile, Interval: 24 * time.Hour}}, schedules)
+	assert.Equal(t, 10*time.Minute, defaults[0].Interval, "defaults must not be changed")
+
+	schedules, err = ParseTaskSchedules("", defaults)
+	require.NoError(t, err)
+	assert.Equal(t, defaults, schedules)
+
+	_, err = ParseTaskSchedules("unknown_task=5m", defaults)
+	assert.Error(t, err)
+	_, err = ParseTaskSchedules("run_new_jobs", defaults)
+	assert.Error(t, err)
+	_, err = ParseTaskSchedules("run_new_jobs=often", defaults)
+	assert.Error(t, err)
+}
+
+func TestScheduler_RunsTasksOnlyAsLeader(t *testing.T) {
+	leaderLock := &fakeLeaderLock{}
+	var runs atomic.Int32
+	scheduler := NewScheduler([]TaskSchedule{{Task: RunNewJobs, Interval: 5 * time.Millisecond}}, 2, leaderLock, func(task string) {
+		runs.Add(1)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Start(ctx)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int32(0), runs.Load(), "no task should run without leadership")
+
+	leaderLock.isLeader.Store(true)
+	assert.Eventually(t, func() bool { return runs.Load() > 0 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	scheduler.Wait()
+	assert.True(t, leaderLock.released.Load())
+}
+
+func TestScheduler_SkipsRunningTaskAndWaitsOnShutdown(t *testing.T) {
+	leaderLock := &fakeLeaderLock{}
+	leaderLock.isLeader.Store(true)
+
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	var startOnce sync.Once
+	var runs, finished atomic.Int32
+	scheduler := NewScheduler([]TaskSchedule{{Task: RunNewJobs, Interval: 5 * time.Millisecond}}, 2, leaderLock, func(task string) {
+		runs.Add(1)
+		startOnce.Do(func() { close(started) })
+		<-finish
+		finished.Add(1)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Start(ctx)
+	<-started
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int32(1), runs.Load(), "a task must not run again while it is still running")
+
+	cancel()
+	waited := make(chan struct{})
+	go func() {
+		scheduler.Wait()
+		close(waited)
+	}()
+
+	select {
+	case <-waited:
+		t.Fatal("Wait returned before the running task finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(finish)
+	<-waited
+	assert.Equal(t, int32(1), finished.Load())
+}
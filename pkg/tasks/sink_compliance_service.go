@@ -0,0 +1,169 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/google/uuid"
+	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/processor"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// sinkComplianceBackupStatuses backups in these statuses still write into or keep data in their sink project
+var sinkComplianceBackupStatuses = []repository.BackupStatus{repository.NotStarted, repository.Prepared, repository.Finished, repository.Paused}
+
+type sinkComplianceService struct {
+	backupRepository              repository.BackupRepository
+	sinkComplianceCheckRepository repository.SinkComplianceCheckRepository
+	checks                        []processor.SinkProjectCheck
+	bucketChecks                  []processor.SinkBucketCheck
+}
+
+func newSinkComplianceService(ctxIn context.Context, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) (*sinkComplianceService, error) {
+	ctx, span := trace.StartSpan(ctxIn, "newSinkComplianceService")
+	defer span.End()
+
+	backupRepository, err := repository.NewBackupRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &sinkComplianceService{}, fmt.Errorf("could not instantiate new BackupRepository: %s", err)
+	}
+	sinkComplianceCheckRepository, err := repository.NewSinkComplianceCheckRepository(ctx, credentialsProvider)
+	if err != nil {
+		return &sinkComplianceService{}, fmt.Errorf("could not instantiate new SinkComplianceCheckRepository: %s", err)
+	}
+
+	return &sinkComplianceService{
+		backupRepository:              backupRepository,
+		sinkComplianceCheckRepository: sinkComplianceCheckRepository,
+		checks:                        processor.NewSinkProjectChecks(tokenSourceProvider),
+		bucketChecks:                  processor.NewSinkBucketChecks(tokenSourceProvider),
+	}, nil
+}
+
+// Run checks every sink project in use and records whether it still keeps its backups immutable
+func (s *sinkComplianceService) Run(ctxIn context.Context) {
+	ctx, span := trace.StartSpan(ctxIn, "(*sinkComplianceService).Run")
+	defer span.End()
+
+	glog.Infof("[START] Check sink project compliance")
+	sinkProjects, err := s.sinkProjectsInUse(ctx)
+	if err != nil {
+		glog.Errorf("[FAIL] could not get sink projects: %s", err)
+		taskFailed(ctx, err)
+		return
+	}
+
+	nonCompliant := 0
+	for _, sinkProject := range sinkProjects {
+		check := s.checkSinkProject(ctx, sinkProject)
+		if err := s.sinkComplianceCheckRepository.AddSinkComplianceCheck(ctx, check); err != nil {
+			glog.Warningf("[FAIL] Error saving compliance check of sink project %s: %s", sinkProject.project, err)
+			itemFailed(ctx, errors.Wrapf(err, "error saving compliance check of sink project %s", sinkProject.project))
+			continue
+		}
+		if !check.Compliant {
+			glog.Warningf("Sink project %s is not compliant: %s", check.ProjectSink, strings.Join(check.Reasons, "; "))
+			notifySinkProjectNotCompliant(ctx, check)
+			nonCompliant++
+		}
+		itemProcessed(ctx)
+	}
+	glog.Infof("[SUCCESS] Checked %d sink projects, %d are not compliant", len(sinkProjects), nonCompliant)
+}
+
+type sinkProjectUsage struct {
+	project        string
+	sourceProjects []string
+	backups        []*repository.Backup
+}
+
+// sinkProjectsInUse returns the sink projects of the active backups with the source projects and backups written into them
+func (s *sinkComplianceService) sinkProjectsInUse(ctxIn context.Context) ([]sinkProjectUsage, error) {
+	ctx, span := trace.StartSpan(ctxIn, "(*sinkComplianceService).sinkProjectsInUse")
+	defer span.End()
+
+	var usages []sinkProjectUsage
+	for _, status := range sinkComplianceBackupStatuses {
+		backups, err := s.backupRepository.GetByBackupStatus(ctx, status)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not get backups with status %s", status)
+		}
+
+		for _, backup := range backups {
+			if backup.TargetProject == "" {
+				continue
+			}
+			index := slices.IndexFunc(usages, func(usage sinkProjectUsage) bool { return usage.project == backup.TargetProject })
+			if index < 0 {
+				usages = append(usages, sinkProjectUsage{project: backup.TargetProject})
+				index = len(usages) - 1
+			}
+			if !slices.Contains(usages[index].sourceProjects, backup.SourceProject) {
+				usages[index].sourceProjects = append(usages[index].sourceProjects, backup.SourceProject)
+			}
+			usages[index].backups = append(usages[index].backups, backup)
+		}
+	}
+	return usages, nil
+}
+
+// checkSinkProject runs every check against the sink project and the sink buckets of its backups,
+// a check which could not be run counts as failed
+func (s *sinkComplianceService) checkSinkProject(ctxIn context.Context, usage sinkProjectUsage) *repository.SinkComplianceCheck {
+	ctx, span := trace.StartSpan(ctxIn, "(*sinkComplianceService).checkSinkProject")
+	defer span.End()
+
+	sinkProject := usage.project
+	result := &repository.SinkComplianceCheck{
+		ID:             uuid.New().String(),
+		ProjectSink:    sinkProject,
+		SourceProjects: usage.sourceProjects,
+		Compliant:      true,
+		Reasons:        []string{},
+		LastCheck:      getCurrentTime(),
+	}
+	for _, check := range s.checks {
+		res, err := check.CheckSinkProject(ctx, sinkProject)
+		if err != nil {
+			glog.Warningf("could not run compliance check of sink project %s: %s", sinkProject, err)
+			result.Compliant = false
+			result.Reasons = append(result.Reasons, fmt.Sprintf("Check could not be run: %s", err))
+			continue
+		}
+		if !res.Passed {
+			result.Compliant = false
+			result.Reasons = append(result.Reasons, complianceReason(res))
+		}
+	}
+	for _, backup := range usage.backups {
+		for _, check := range s.bucketChecks {
+			res, err := check.CheckSinkBucket(ctx, backup)
+			if err != nil {
+				glog.Warningf("could not run compliance check of sink %s for backup %s: %s", backup.Sink, backup.ID, err)
+				result.Compliant = false
+				result.Reasons = append(result.Reasons, fmt.Sprintf("Backup %s: Check could not be run: %s", backup.ID, err))
+				continue
+			}
+			if !res.Passed {
+				result.Compliant = false
+				result.Reasons = append(result.Reasons, fmt.Sprintf("Backup %s: %s", backup.ID, complianceReason(res)))
+			}
+		}
+	}
+	return result
+}
+
+func complianceReason(check requestobjects.ComplianceCheck) string {
+	if check.Details == "" {
+		return check.Description
+	}
+	return fmt.Sprintf("%s: %s", check.Description, check.Details)
+}
@@ -0,0 +1,151 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ottogroup/penelope/pkg/notification"
+	"github.com/ottogroup/penelope/pkg/processor"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/repository/memory"
+	"github.com/ottogroup/penelope/pkg/requestobjects"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSinkProjectCheck struct {
+	failing map[string]requestobjects.ComplianceCheck
+	broken  map[string]bool
+}
+
+func (f *fakeSinkProjectCheck) CheckSinkProject(_ context.Context, targetProject string) (requestobjects.ComplianceCheck, error) {
+	if f.broken[targetProject] {
+		return requestobjects.ComplianceCheck{}, errors.New("permission denied")
+	}
+	if check, exists := f.failing[targetProject]; exists {
+		return check, nil
+	}
+	return requestobjects.ComplianceCheck{Passed: true, Description: "Backup project has single writer"}, nil
+}
+
+type fakeSinkComplianceCheckRepository struct {
+	checks []*repository.SinkComplianceCheck
+}
+
+func (f *fakeSinkComplianceCheckRepository) AddSinkComplianceCheck(_ context.Context, check *repository.SinkComplianceCheck) error {
+	f.checks = append(f.checks, check)
+	return nil
+}
+
+func (f *fakeSinkComplianceCheckRepository) GetLatestSinkComplianceChecks(context.Context) ([]*repository.SinkComplianceCheck, error) {
+	return f.checks, nil
+}
+
+func (f *fakeSinkComplianceCheckRepository) GetSinkComplianceChecks(_ context.Context, _ string, _ repository.Page) ([]*repository.SinkComplianceCheck, error) {
+	return f.checks, nil
+}
+
+func TestSinkComplianceService_Run(t *testing.T) {
+	notifier := &fakeEventNotifier{}
+	ctx := context.WithValue(context.Background(), eventNotifierKey{}, eventNotifier(notifier))
+
+	backupRepository := &memory.BackupRepository{}
+	for _, backup := range []*repository.Backup{
+		{ID: "backup-1", Status: repository.Finished, SourceProject: "source-a", SinkOptions: repository.SinkOptions{TargetProject: "sink-compliant"}},
+		{ID: "backup-2", Status: repository.Prepared, SourceProject: "source-b", SinkOptions: repository.SinkOptions{TargetProject: "sink-drifted"}},
+		{ID: "backup-3", Status: repository.Paused, SourceProject: "source-c", SinkOptions: repository.SinkOptions{TargetProject: "sink-drifted"}},
+		{ID: "backup-4", Status: repository.NotStarted, SourceProject: "source-b", SinkOptions: repository.SinkOptions{TargetProject: "sink-drifted"}},
+		{ID: "backup-5", Status: repository.NotStarted, SourceProject: "source-d", SinkOptions: repository.SinkOptions{TargetProject: "sink-unreachable"}},
+		{ID: "backup-6", Status: repository.BackupDeleted, SourceProject: "source-e", SinkOptions: repository.SinkOptions{TargetProject: "sink-deleted"}},
+	} {
+		_, err := backupRepository.AddBackup(ctx, backup)
+		require.NoError(t, err)
+	}
+	checkRepository := &fakeSinkComplianceCheckRepository{}
+	service := &sinkComplianceService{
+		backupRepository:              backupRepository,
+		sinkComplianceCheckRepository: checkRepository,
+		checks: []processor.SinkProjectCheck{&fakeSinkProjectCheck{
+			failing: map[string]requestobjects.ComplianceCheck{
+				"sink-drifted": {Passed: false, Description: "Backup project should have only allowed services enabled", Details: "Not allowed services are enabled: compute.googleapis.com"},
+			},
+			broken: map[string]bool{"sink-unreachable": true},
+		}},
+	}
+
+	service.Run(ctx)
+
+	require.Len(t, checkRepository.checks, 3, "sink projects of deleted backups are not checked")
+	checks := map[string]*repository.SinkComplianceCheck{}
+	for _, check := range checkRepository.checks {
+		checks[check.ProjectSink] = check
+		assert.NotEmpty(t, check.ID)
+		assert.False(t, check.LastCheck.IsZero())
+	}
+
+	assert.True(t, checks["sink-compliant"].Compliant)
+	assert.Empty(t, checks["sink-compliant"].Reasons)
+	assert.False(t, checks["sink-drifted"].Compliant)
+	assert.Equal(t, []string{"source-b", "source-c"}, checks["sink-drifted"].SourceProjects)
+	assert.Equal(t, []string{"Backup project should have only allowed services enabled: Not allowed services are enabled: compute.googleapis.com"}, checks["sink-drifted"].Reasons)
+	assert.False(t, checks["sink-unreachable"].Compliant, "a check which could not be run is not compliant")
+	assert.Contains(t, checks["sink-unreachable"].Reasons[0], "permission denied")
+
+	var keys []string
+	for _, event := range notifier.events {
+		assert.Equal(t, notification.ComplianceFailed, event.Kind)
+		keys = append(keys, event.Key)
+	}
+	assert.ElementsMatch(t, []string{"compliance_failed/sink-drifted/source-b", "compliance_failed/sink-drifted/source-c", "compliance_failed/sink-unreachable/source-d"}, keys)
+}
+
+type fakeSinkBucketCheck struct {
+	failing map[string]requestobjects.ComplianceCheck
+}
+
+func (f *fakeSinkBucketCheck) CheckSinkBucket(_ context.Context, backup *repository.Backup) (requestobjects.ComplianceCheck, error) {
+	if check, exists := f.failing[backup.ID]; exists {
+		return check, nil
+	}
+	return requestobjects.ComplianceCheck{Passed: true, Description: "Backup is not immutable"}, nil
+}
+
+func TestSinkComplianceService_RunChecksSinkBuckets(t *testing.T) {
+	notifier := &fakeEventNotifier{}
+	ctx := context.WithValue(context.Background(), eventNotifierKey{}, eventNotifier(notifier))
+
+	backupRepository := &memory.BackupRepository{}
+	for _, backup := range []*repository.Backup{
+		{ID: "backup-1", Status: repository.Finished, SourceProject: "source-a", SinkOptions: repository.SinkOptions{TargetProject: "sink-a", Sink: "bkp_gcs_1", Immutable: true}},
+		{ID: "backup-2", Status: repository.Finished, SourceProject: "source-a", SinkOptions: repository.SinkOptions{TargetProject: "sink-a", Sink: "bkp_gcs_2"}},
+		{ID: "backup-3", Status: repository.Finished, SourceProject: "source-b", SinkOptions: repository.SinkOptions{TargetProject: "sink-b", Sink: "bkp_gcs_3", Immutable: true}},
+	} {
+		_, err := backupRepository.AddBackup(ctx, backup)
+		require.NoError(t, err)
+	}
+	checkRepository := &fakeSinkComplianceCheckRepository{}
+	service := &sinkComplianceService{
+		backupRepository:              backupRepository,
+		sinkComplianceCheckRepository: checkRepository,
+		checks:                        []processor.SinkProjectCheck{&fakeSinkProjectCheck{}},
+		bucketChecks: []processor.SinkBucketCheck{&fakeSinkBucketCheck{
+			failing: map[string]requestobjects.ComplianceCheck{
+				"backup-1": {Passed: false, Description: "Immutable backup should have a locked retention policy of its lifetime", Details: "Retention policy of sink bucket bkp_gcs_1 is not locked"},
+			},
+		}},
+	}
+
+	service.Run(ctx)
+
+	require.Len(t, checkRepository.checks, 2)
+	checks := map[string]*repository.SinkComplianceCheck{}
+	for _, check := range checkRepository.checks {
+		checks[check.ProjectSink] = check
+	}
+	assert.False(t, checks["sink-a"].Compliant)
+	assert.Equal(t, []string{"Backup backup-1: Immutable backup should have a locked retention policy of its lifetime: Retention policy of sink bucket bkp_gcs_1 is not locked"}, checks["sink-a"].Reasons)
+	assert.True(t, checks["sink-b"].Compliant)
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, "compliance_failed/sink-a/source-a", notifier.events[0].Key)
+}
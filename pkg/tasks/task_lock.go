@@ -0,0 +1,97 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/uuid"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// taskLockHeartbeatInterval is how often a running task extends its lock
+	taskLockHeartbeatInterval = time.Minute
+	// taskLockStaleAfter is how long after the last heartbeat a lock is treated as left behind by a crashed replica
+	taskLockStaleAfter = 5 * time.Minute
+)
+
+// taskLock is held while a task runs, so every task runs at most once at a time across all replicas
+type taskLock struct {
+	taskLockRepository repository.TaskLockRepository
+	task               string
+	holder             string
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// tryLockTask acquires the lock of a task and starts its heartbeat, nil is returned if the task is already running
+func tryLockTask(ctxIn context.Context, taskLockRepository repository.TaskLockRepository, task string, heartbeatInterval time.Duration) (*taskLock, error) {
+	ctx, span := trace.StartSpan(ctxIn, "tryLockTask")
+	defer span.End()
+
+	lock := &taskLock{
+		taskLockRepository: taskLockRepository,
+		task:               task,
+		holder:             newTaskLockHolder(),
+		stop:               make(chan struct{}),
+		stopped:            make(chan struct{}),
+	}
+	acquired, staleHolder, err := taskLockRepository.TryAcquireTaskLock(ctx, task, lock.holder, taskLockStaleAfter)
+	if err != nil {
+		return nil, fmt.Errorf("could not acquire lock of task %s: %s", task, err)
+	}
+	if !acquired {
+		return nil, nil
+	}
+	if staleHolder != "" {
+		glog.Warningf("Took over stale lock of task %s from %s", task, staleHolder)
+	}
+
+	go lock.heartbeat(heartbeatInterval)
+	return lock, nil
+}
+
+func (l *taskLock) heartbeat(interval time.Duration) {
+	defer close(l.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			alive, err := l.taskLockRepository.HeartbeatTaskLock(context.Background(), l.task, l.holder)
+			if err != nil {
+				glog.Warningf("could not extend lock of task %s: %s", l.task, err)
+			} else if !alive {
+				glog.Warningf("Lock of task %s was taken over by another run, the runs overlap", l.task)
+			}
+		}
+	}
+}
+
+// release stops the heartbeat and gives up the lock
+func (l *taskLock) release(ctxIn context.Context) {
+	ctx, span := trace.StartSpan(ctxIn, "(*taskLock).release")
+	defer span.End()
+
+	close(l.stop)
+	<-l.stopped
+	if err := l.taskLockRepository.ReleaseTaskLock(ctx, l.task, l.holder); err != nil {
+		glog.Warningf("could not release lock of task %s, it becomes stale after %s: %s", l.task, taskLockStaleAfter, err)
+	}
+}
+
+func newTaskLockHolder() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s/%s", hostname, uuid.New().String())
+}
@@ -0,0 +1,90 @@
+package tasks
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTaskLockRepository struct {
+	mu         sync.Mutex
+	holders    map[string]string
+	heartbeats int
+}
+
+func (f *fakeTaskLockRepository) TryAcquireTaskLock(ctx context.Context, task, holder string, staleAfter time.Duration) (bool, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, held := f.holders[task]; held {
+		return false, "", nil
+	}
+	f.holders[task] = holder
+	return true, "", nil
+}
+
+func (f *fakeTaskLockRepository) HeartbeatTaskLock(ctx context.Context, task, holder string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.heartbeats++
+	return f.holders[task] == holder, nil
+}
+
+func (f *fakeTaskLockRepository) ReleaseTaskLock(ctx context.Context, task, holder string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.holders[task] == holder {
+		delete(f.holders, task)
+	}
+	return nil
+}
+
+func (f *fakeTaskLockRepository) heartbeatCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.heartbeats
+}
+
+func TestTryLockTask_SkipsRunningTask(t *testing.T) {
+	repository := &fakeTaskLockRepository{holders: map[string]string{}}
+	ctx := context.Background()
+
+	lock, err := tryLockTask(ctx, repository, CheckJobsStatus, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+
+	skipped, err := tryLockTask(ctx, repository, CheckJobsStatus, time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, skipped, "task is already running")
+
+	other, err := tryLockTask(ctx, repository, CleanupExpiredSinks, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, other, "other tasks are not blocked")
+	other.release(ctx)
+
+	lock.release(ctx)
+	lock, err = tryLockTask(ctx, repository, CheckJobsStatus, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, lock, "task can run again once released")
+	lock.release(ctx)
+}
+
+func TestTryLockTask_HeartbeatsUntilReleased(t *testing.T) {
+	repository := &fakeTaskLockRepository{holders: map[string]string{}}
+	ctx := context.Background()
+
+	lock, err := tryLockTask(ctx, repository, CheckJobsStatus, 5*time.Millisecond)
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+
+	assert.Eventually(t, func() bool { return repository.heartbeatCount() >= 2 }, time.Second, 5*time.Millisecond)
+	lock.release(ctx)
+
+	heartbeats := repository.heartbeatCount()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, heartbeats, repository.heartbeatCount(), "no heartbeat after release")
+	assert.Empty(t, repository.holders)
+}
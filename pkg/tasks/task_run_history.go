@@ -0,0 +1,151 @@
+package tasks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/uuid"
+	"github.com/ottogroup/penelope/pkg/repository"
+	"github.com/ottogroup/penelope/pkg/secret"
+	"go.opencensus.io/trace"
+)
+
+const (
+	// maxTaskRunErrors is the number of errors kept in the summary of a task run
+	maxTaskRunErrors = 20
+	// maxTaskRunErrorLength is the max length of an error kept in the summary of a task run
+	maxTaskRunErrorLength = 500
+)
+
+type taskRunStatsKey struct{}
+
+// taskRunStats counts the items handled by one task run, services report to it through the context passed to Run
+type taskRunStats struct {
+	mu        sync.Mutex
+	processed int
+	failed    int
+	aborted   bool
+	errors    []string
+}
+
+func withTaskRunStats(ctx context.Context) (context.Context, *taskRunStats) {
+	stats := &taskRunStats{}
+	return context.WithValue(ctx, taskRunStatsKey{}, stats), stats
+}
+
+func taskRunStatsFromContext(ctx context.Context) *taskRunStats {
+	stats, _ := ctx.Value(taskRunStatsKey{}).(*taskRunStats)
+	return stats
+}
+
+// itemProcessed counts an item the task run handled successfully
+func itemProcessed(ctx context.Context) {
+	stats := taskRunStatsFromContext(ctx)
+	if stats == nil {
+		return
+	}
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	stats.processed++
+}
+
+// itemFailed counts an item the task run could not handle
+func itemFailed(ctx context.Context, err error) {
+	stats := taskRunStatsFromContext(ctx)
+	if stats == nil {
+		return
+	}
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	stats.failed++
+	stats.addError(err)
+}
+
+// taskFailed records an error which stopped the task run or a part of it
+func taskFailed(ctx context.Context, err error) {
+	stats := taskRunStatsFromContext(ctx)
+	if stats == nil {
+		return
+	}
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	stats.aborted = true
+	stats.addError(err)
+}
+
+func (s *taskRunStats) addError(err error) {
+	if err == nil || len(s.errors) >= maxTaskRunErrors {
+		return
+	}
+	message := err.Error()
+	if len(message) > maxTaskRunErrorLength {
+		message = message[:maxTaskRunErrorLength]
+	}
+	s.errors = append(s.errors, message)
+}
+
+// hasFailed is true if the task run was stopped by an error or failed for at least one item
+func (s *taskRunStats) hasFailed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.aborted || s.failed > 0
+}
+
+// taskRunHistory records one run of a task, a task still runs if its history can not be recorded
+type taskRunHistory struct {
+	taskRunRepository repository.TaskRunRepository
+	run               *repository.TaskRun
+}
+
+func startTaskRun(ctxIn context.Context, credentialsProvider secret.SecretProvider, task string) *taskRunHistory {
+	ctx, span := trace.StartSpan(ctxIn, "startTaskRun")
+	defer span.End()
+
+	run := &repository.TaskRun{
+		ID:        uuid.New().String(),
+		Task:      task,
+		Status:    repository.TaskRunRunning,
+		StartTime: time.Now(),
+	}
+	taskRunRepository, err := repository.NewTaskRunRepository(ctx, credentialsProvider)
+	if err != nil {
+		glog.Warningf("could not record run of task %s: %s", task, err)
+		return &taskRunHistory{run: run}
+	}
+	if err := taskRunRepository.AddTaskRun(ctx, run); err != nil {
+		glog.Warningf("could not record run of task %s: %s", task, err)
+		return &taskRunHistory{run: run}
+	}
+	return &taskRunHistory{taskRunRepository: taskRunRepository, run: run}
+}
+
+func (h *taskRunHistory) finish(ctxIn context.Context, stats *taskRunStats) {
+	ctx, span := trace.StartSpan(ctxIn, "(*taskRunHistory).finish")
+	defer span.End()
+
+	status := repository.TaskRunSucceeded
+	if stats.hasFailed() {
+		status = repository.TaskRunFailed
+	}
+
+	stats.mu.Lock()
+	patch := repository.TaskRunPatch{
+		ID:             h.run.ID,
+		Status:         status,
+		EndTime:        time.Now(),
+		ProcessedCount: stats.processed,
+		FailedCount:    stats.failed,
+		Errors:         stats.errors,
+	}
+	stats.mu.Unlock()
+
+	glog.Infof("Task %s finished with status %s after %s: %d processed, %d failed", h.run.Task, status, patch.EndTime.Sub(h.run.StartTime).Round(time.Second), patch.ProcessedCount, patch.FailedCount)
+	if h.taskRunRepository == nil {
+		return
+	}
+	if err := h.taskRunRepository.PatchTaskRun(ctx, patch); err != nil {
+		glog.Warningf("could not record outcome of task run %s: %s", h.run.ID, err)
+	}
+}
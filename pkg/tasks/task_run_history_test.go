@@ -0,0 +1,56 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskRunStats_CountsItems(t *testing.T) {
+	ctx, stats := withTaskRunStats(context.Background())
+
+	itemProcessed(ctx)
+	itemProcessed(ctx)
+	assert.False(t, stats.hasFailed())
+
+	itemFailed(ctx, errors.New("could not delete bucket"))
+	assert.True(t, stats.hasFailed())
+	assert.Equal(t, 2, stats.processed)
+	assert.Equal(t, 1, stats.failed)
+	assert.Equal(t, []string{"could not delete bucket"}, stats.errors)
+}
+
+func TestTaskRunStats_TaskFailed(t *testing.T) {
+	ctx, stats := withTaskRunStats(context.Background())
+
+	taskFailed(ctx, errors.New("could not get scheduled backups"))
+
+	assert.True(t, stats.hasFailed())
+	assert.Equal(t, 0, stats.failed)
+	assert.Equal(t, []string{"could not get scheduled backups"}, stats.errors)
+}
+
+func TestTaskRunStats_LimitsErrorSummary(t *testing.T) {
+	ctx, stats := withTaskRunStats(context.Background())
+
+	itemFailed(ctx, errors.New(strings.Repeat("x", 2*maxTaskRunErrorLength)))
+	for i := 0; i < 2*maxTaskRunErrors; i++ {
+		itemFailed(ctx, fmt.Errorf("error %d", i))
+	}
+
+	assert.Equal(t, 2*maxTaskRunErrors+1, stats.failed)
+	assert.Len(t, stats.errors, maxTaskRunErrors)
+	assert.Len(t, stats.errors[0], maxTaskRunErrorLength)
+}
+
+func TestTaskRunStats_WithoutTaskRun(t *testing.T) {
+	assert.NotPanics(t, func() {
+		itemProcessed(context.Background())
+		itemFailed(context.Background(), errors.New("error"))
+		taskFailed(context.Background(), errors.New("error"))
+	})
+}
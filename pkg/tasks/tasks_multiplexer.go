@@ -2,10 +2,14 @@ package tasks
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/golang/glog"
 	"github.com/ottogroup/penelope/pkg/http/impersonate"
+	"github.com/ottogroup/penelope/pkg/provider"
+	"github.com/ottogroup/penelope/pkg/repository"
 	"github.com/ottogroup/penelope/pkg/secret"
 	"go.opencensus.io/trace"
 )
@@ -31,86 +35,201 @@ const (
 	CleanupTrashcans = "cleanup_trashcans"
 	// Reconcile is handled by task that make usre that backup settings are in sync
 	Reconcile = "reconcile"
+	// CheckRestoreJobsStatus is handled by task that update started restore jobs status
+	CheckRestoreJobsStatus = "check_restore_jobs_status"
+	// RestoreDrill is handled by task that restores samples of backups to prove they are recoverable
+	RestoreDrill = "restore_drill"
+	// RetryFailedJobs is handled by task that requeues failed jobs with a retryable error after their backoff
+	RetryFailedJobs = "retry_failed_jobs"
+	// CheckRPOViolations is handled by task that records which backup sources violate their recovery point objective
+	CheckRPOViolations = "check_rpo_violations"
 )
 
+// Tasks lists every task handled by StartTask and RunTask
+var Tasks = []string{
+	RunNewJobs,
+	CheckJobsStatus,
+	CheckOneShotBackupsStatus,
+	CleanupExpiredSinks,
+	RescheduleJobsWithQuotaError,
+	PrepareBackupJobs,
+	CheckJobsStuck,
+	CleanupTrashcans,
+	Reconcile,
+	CheckRestoreJobsStatus,
+	RestoreDrill,
+	RetryFailedJobs,
+	CheckRPOViolations,
+	CheckSinkProjectCompliance,
+}
+
 // TaskRunner runs tasks
 type TaskRunner interface {
 	Run(context.Context)
 }
 
-// RunTask triggers specified task
-func RunTask(task string, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider) {
+// ErrUnknownTask is returned for a task which is not listed in Tasks
+var ErrUnknownTask = errors.New("unknown task")
+
+// StartTask triggers specified task in the background, false is returned if the task is already running and
+// this run is skipped
+func StartTask(task string, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider, notificationChannelProvider provider.NotificationChannelProvider) (bool, error) {
+	lock, err := lockTask(context.TODO(), task, credentialsProvider)
+	if err != nil || lock == nil {
+		return false, err
+	}
+
+	go func() {
+		defer lock.release(context.TODO())
+		runTask(task, tokenSourceProvider, credentialsProvider, sourceGCPProjectProvider, notificationChannelProvider)
+	}()
+	return true, nil
+}
+
+// RunTask triggers specified task and waits until it is finished, false is returned if the task is already running and
+// this run is skipped
+func RunTask(task string, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider, notificationChannelProvider provider.NotificationChannelProvider) (bool, error) {
+	lock, err := lockTask(context.TODO(), task, credentialsProvider)
+	if err != nil || lock == nil {
+		return false, err
+	}
+
+	defer lock.release(context.TODO())
+	runTask(task, tokenSourceProvider, credentialsProvider, sourceGCPProjectProvider, notificationChannelProvider)
+	return true, nil
+}
+
+// lockTask returns the lock of the task, or nil if the task is already running
+func lockTask(ctxIn context.Context, task string, credentialsProvider secret.SecretProvider) (*taskLock, error) {
+	ctx, span := trace.StartSpan(ctxIn, "lockTask")
+	defer span.End()
+
+	if !slices.Contains(Tasks, task) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTask, task)
+	}
+
+	taskLockRepository, err := repository.NewTaskLockRepository(ctx, credentialsProvider)
+	if err != nil {
+		return nil, fmt.Errorf("could not acquire lock of task %s: %s", task, err)
+	}
+	lock, err := tryLockTask(ctx, taskLockRepository, task, taskLockHeartbeatInterval)
+	if err != nil {
+		return nil, err
+	}
+	if lock == nil {
+		glog.Infof("Task %s is already running, skipping this run", task)
+	}
+	return lock, nil
+}
+
+// runTask runs specified task, records the run in the task run history and notifies about failures
+func runTask(task string, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider, notificationChannelProvider provider.NotificationChannelProvider) {
 	background := context.TODO()
 	ctx, span := trace.StartSpan(background, fmt.Sprintf("RunTask/%s", task))
 	defer span.End()
 
 	glog.Infof("Running task for action %s", task)
 
+	history := startTaskRun(ctx, credentialsProvider, task)
+	ctx, stats := withTaskRunStats(ctx)
+	ctx = withEventNotifier(ctx, credentialsProvider, sourceGCPProjectProvider, notificationChannelProvider)
+	service, err := newTaskRunner(ctx, task, tokenSourceProvider, credentialsProvider, sourceGCPProjectProvider)
+	if err != nil {
+		glog.Error(err)
+		taskFailed(ctx, err)
+	} else {
+		service.Run(ctx)
+	}
+	history.finish(ctx, stats)
+}
+
+func newTaskRunner(ctx context.Context, task string, tokenSourceProvider impersonate.TargetPrincipalForProjectProvider, credentialsProvider secret.SecretProvider, sourceGCPProjectProvider provider.SourceGCPProjectProvider) (TaskRunner, error) {
 	switch task {
 	case RunNewJobs:
-		service, err := newJobScheduleService(ctx, tokenSourceProvider, credentialsProvider)
+		service, err := newJobScheduleService(ctx, tokenSourceProvider, credentialsProvider, sourceGCPProjectProvider)
 		if err != nil {
-			glog.Errorf("could not instantiate new JobScheduleService: %s", err)
-			return
+			return nil, fmt.Errorf("could not instantiate new JobScheduleService: %s", err)
 		}
-		service.Run(ctx)
+		return service, nil
 	case CheckJobsStatus:
 		service, err := newJobStatusService(ctx, tokenSourceProvider, credentialsProvider)
 		if err != nil {
-			glog.Errorf("could not instantiate new JobStatusService: %s", err)
-			return
+			return nil, fmt.Errorf("could not instantiate new JobStatusService: %s", err)
 		}
-		service.Run(ctx)
+		return service, nil
 	case CheckOneShotBackupsStatus:
 		service, err := newOneShotBackupStatusService(ctx, credentialsProvider)
 		if err != nil {
-			glog.Errorf("could not instantiate new BackupStatusService: %s", err)
-			return
+			return nil, fmt.Errorf("could not instantiate new BackupStatusService: %s", err)
 		}
-		service.Run(ctx)
+		return service, nil
 	case CleanupExpiredSinks:
 		service, err := newCleanupExpiredSinkService(ctx, tokenSourceProvider, credentialsProvider)
 		if err != nil {
-			glog.Errorf("could not instantiate new CleanupBackupService: %s", err)
-			return
+			return nil, fmt.Errorf("could not instantiate new CleanupBackupService: %s", err)
 		}
-		service.Run(ctx)
+		return service, nil
 	case PrepareBackupJobs:
 		service, err := newPrepareBackupJobsService(ctx, tokenSourceProvider, credentialsProvider)
 		if err != nil {
-			glog.Errorf("could not instantiate new PrepareBackupJobsService: %s", err)
-			return
+			return nil, fmt.Errorf("could not instantiate new PrepareBackupJobsService: %s", err)
 		}
-		service.Run(ctx)
+		return service, nil
 	case CheckJobsStuck:
 		service, err := newJobsStuckService(ctx, credentialsProvider)
 		if err != nil {
-			glog.Errorf("could not instantiate new JobStuckService: %s", err)
-			return
+			return nil, fmt.Errorf("could not instantiate new JobStuckService: %s", err)
 		}
-		service.Run(ctx)
+		return service, nil
 	case RescheduleJobsWithQuotaError:
 		service, err := newRescheduleJobsWithQuotaError(ctx, credentialsProvider)
 		if err != nil {
-			glog.Errorf("could not instantiate new RescheduleJobsWithQuotaErrorService: %s", err)
-			return
+			return nil, fmt.Errorf("could not instantiate new RescheduleJobsWithQuotaErrorService: %s", err)
 		}
-		service.Run(ctx)
+		return service, nil
 	case CleanupTrashcans:
 		service, err := newCleanupTrashcansService(ctx, tokenSourceProvider, credentialsProvider)
 		if err != nil {
-			glog.Errorf("could not instantiate new CleanupTrashcansService: %s", err)
-			return
+			return nil, fmt.Errorf("could not instantiate new CleanupTrashcansService: %s", err)
 		}
-		service.Run(ctx)
+		return service, nil
 	case Reconcile:
 		service, err := newReconcileService(ctx, tokenSourceProvider, credentialsProvider)
 		if err != nil {
-			glog.Errorf("could not instantiate new ReconcileService: %s", err)
-			return
+			return nil, fmt.Errorf("could not instantiate new ReconcileService: %s", err)
 		}
-		service.Run(ctx)
+		return service, nil
+	case CheckRestoreJobsStatus:
+		service, err := newRestoreJobStatusService(ctx, tokenSourceProvider, credentialsProvider)
+		if err != nil {
+			return nil, fmt.Errorf("could not instantiate new RestoreJobStatusService: %s", err)
+		}
+		return service, nil
+	case RestoreDrill:
+		service, err := newRestoreDrillService(ctx, tokenSourceProvider, credentialsProvider, sourceGCPProjectProvider)
+		if err != nil {
+			return nil, fmt.Errorf("could not instantiate new RestoreDrillService: %s", err)
+		}
+		return service, nil
+	case RetryFailedJobs:
+		service, err := newRetryFailedJobsService(ctx, credentialsProvider)
+		if err != nil {
+			return nil, fmt.Errorf("could not instantiate new RetryFailedJobsService: %s", err)
+		}
+		return service, nil
+	case CheckRPOViolations:
+		service, err := newRPOViolationService(ctx, credentialsProvider)
+		if err != nil {
+			return nil, fmt.Errorf("could not instantiate new RPOViolationService: %s", err)
+		}
+		return service, nil
+	case CheckSinkProjectCompliance:
+		service, err := newSinkComplianceService(ctx, tokenSourceProvider, credentialsProvider)
+		if err != nil {
+			return nil, fmt.Errorf("could not instantiate new SinkComplianceService: %s", err)
+		}
+		return service, nil
 	default:
-		glog.Warningf("no Service found for action: %s", task)
+		return nil, fmt.Errorf("no Service found for action: %s", task)
 	}
 }
@@ -0,0 +1,8 @@
+alter table backups
+    add firestore_database text;
+
+alter table backups
+    add firestore_collection_ids text;
+
+alter table jobs
+    add firestore_export_operation_id text;
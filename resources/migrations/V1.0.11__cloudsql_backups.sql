@@ -0,0 +1,14 @@
+alter table backups
+    add cloudsql_instance text;
+
+alter table backups
+    add cloudsql_databases text;
+
+alter table backups
+    add cloudsql_file_type text;
+
+alter table backups
+    add cloudsql_csv_query text;
+
+alter table jobs
+    add cloudsql_export_operation_id text;
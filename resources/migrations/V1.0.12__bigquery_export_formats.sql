@@ -0,0 +1,5 @@
+alter table backups
+    add bigquery_export_format text;
+
+alter table backups
+    add bigquery_export_compression text;
@@ -0,0 +1,2 @@
+alter table jobs
+    add manifest_id text;
@@ -0,0 +1,4 @@
+alter table backups
+    add schedule_cron text,
+    add schedule_timezone text,
+    add schedule_windows text;
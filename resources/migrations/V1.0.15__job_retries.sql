@@ -0,0 +1,11 @@
+alter table jobs
+    add attempts int not null default 0,
+    add next_attempt_timestamp timestamp,
+    add last_error_message text,
+    add error_history text;
+
+alter table backups
+    add max_retry_attempts int;
+
+CREATE INDEX jobs_next_attempt_timestamp
+    ON jobs (next_attempt_timestamp) WHERE next_attempt_timestamp IS NOT NULL;
@@ -0,0 +1,26 @@
+alter table jobs
+    add last_error_code text,
+    add last_error_reason text,
+    add last_error_timestamp timestamp;
+
+create table job_events
+(
+    id serial not null
+        constraint job_events_pkey
+            primary key,
+    job_id text not null
+        constraint job_events_job_id_fkey
+            references jobs,
+    backup_id text
+        constraint job_events_backup_id_fkey
+            references backups,
+    status text not null,
+    attempt integer default 0 not null,
+    message text,
+    error_code text,
+    error_reason text,
+    audit_created_timestamp timestamp default now()
+);
+
+CREATE INDEX job_events_job_id
+    ON job_events (job_id);
@@ -0,0 +1,2 @@
+alter table jobs
+    add include_prefixes text;
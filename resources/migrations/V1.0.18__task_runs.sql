@@ -0,0 +1,19 @@
+create table task_runs
+(
+    id text not null
+        constraint task_runs_pkey
+            primary key,
+    task text not null,
+    status text not null,
+    start_timestamp timestamp not null,
+    end_timestamp timestamp,
+    processed_count integer default 0 not null,
+    failed_count integer default 0 not null,
+    errors text,
+    audit_created_timestamp timestamp default now(),
+    audit_updated_timestamp timestamp,
+    audit_deleted_timestamp timestamp
+);
+
+CREATE INDEX task_runs_task_start_timestamp
+    ON task_runs (task, start_timestamp);
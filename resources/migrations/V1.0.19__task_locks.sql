@@ -0,0 +1,9 @@
+create table task_locks
+(
+    task text not null
+        constraint task_locks_pkey
+            primary key,
+    holder text not null,
+    acquired_timestamp timestamp not null,
+    heartbeat_timestamp timestamp not null
+);
@@ -0,0 +1,16 @@
+create table source_freshness
+(
+    backup_id text not null
+        constraint source_freshness_backup_id_fkey
+            references backups,
+    source text not null,
+    last_recovery_point_timestamp timestamp,
+    recovery_point_objective integer not null,
+    rpo_violated boolean default false not null,
+    checked_timestamp timestamp not null,
+    constraint source_freshness_pkey
+        primary key (backup_id, source)
+);
+
+CREATE INDEX source_freshness_rpo_violated
+    ON source_freshness (rpo_violated);
@@ -0,0 +1,9 @@
+create table notifications
+(
+    key text not null
+        constraint notifications_pkey
+            primary key,
+    kind text not null,
+    project text not null,
+    sent_timestamp timestamp not null
+);
@@ -0,0 +1,14 @@
+create table sink_compliance_checks
+(
+    id text not null
+        constraint sink_compliance_checks_pkey
+            primary key,
+    project_sink text not null,
+    source_projects text,
+    compliant boolean not null,
+    reasons text,
+    last_checked timestamp not null
+);
+
+CREATE INDEX sink_compliance_checks_project_sink_last_checked
+    ON sink_compliance_checks (project_sink, last_checked);
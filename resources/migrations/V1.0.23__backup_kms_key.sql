@@ -0,0 +1,2 @@
+ALTER TABLE backups
+    ADD target_kms_key_name text;
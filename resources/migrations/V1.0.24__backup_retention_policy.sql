@@ -0,0 +1,5 @@
+ALTER TABLE backups
+    ADD target_immutable boolean default false not null;
+
+ALTER TABLE backups
+    ADD target_retention_locked boolean default false not null;
@@ -0,0 +1,2 @@
+alter table jobs
+    add source_row_count bigint;
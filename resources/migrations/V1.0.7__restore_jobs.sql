@@ -0,0 +1,30 @@
+create table restore_jobs
+(
+    id text not null
+        constraint restore_jobs_pkey
+            primary key,
+    restore_id text not null,
+    backup_id text
+        constraint restore_jobs_backup_id_fkey
+            references backups,
+    backup_job_id text
+        constraint restore_jobs_backup_job_id_fkey
+            references jobs,
+    type text,
+    status text not null,
+    source text,
+    target_project text,
+    target_dataset text,
+    target_table text,
+    bigquery_load_job_id text,
+    error_message text,
+    audit_created_timestamp timestamp default now(),
+    audit_updated_timestamp timestamp,
+    audit_deleted_timestamp timestamp
+);
+
+CREATE INDEX restore_jobs_backup_id
+    ON restore_jobs (backup_id);
+
+CREATE INDEX restore_jobs_status
+    ON restore_jobs (status);
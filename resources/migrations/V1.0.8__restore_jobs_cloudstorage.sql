@@ -0,0 +1,5 @@
+alter table restore_jobs
+    add target_bucket text;
+
+alter table restore_jobs
+    add cloudstorage_transfer_job_id text;
@@ -0,0 +1,32 @@
+create table restore_drills
+(
+    id text not null
+        constraint restore_drills_pkey
+            primary key,
+    backup_id text
+        constraint restore_drills_backup_id_fkey
+            references backups,
+    restore_job_id text
+        constraint restore_drills_restore_job_id_fkey
+            references restore_jobs,
+    type text,
+    status text not null,
+    availability_class text,
+    source text,
+    target text,
+    expected_count bigint,
+    restored_count bigint,
+    elapsed_seconds integer,
+    recovery_time_objective integer,
+    rto_breached boolean default false not null,
+    error_message text,
+    audit_created_timestamp timestamp default now(),
+    audit_updated_timestamp timestamp,
+    audit_deleted_timestamp timestamp
+);
+
+CREATE INDEX restore_drills_backup_id
+    ON restore_drills (backup_id);
+
+CREATE INDEX restore_drills_status
+    ON restore_drills (status);